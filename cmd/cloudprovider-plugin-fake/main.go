@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// cloudprovider-plugin-fake is the reference implementation of an out-of-process
+// cloud provider plugin. It serves the fake provider, so that plugin authors can
+// test the machine-controller integration locally.
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"log"
+	"net"
+	"os"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/plugin"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/fake"
+	machinecontrollerlog "k8c.io/machine-controller/pkg/log"
+)
+
+func main() {
+	var (
+		network       string
+		listenAddress string
+		tlsCertFile   string
+		tlsKeyFile    string
+		clientCAFile  string
+	)
+
+	logFlags := machinecontrollerlog.NewDefaultOptions()
+	logFlags.AddFlags(flag.CommandLine)
+
+	flag.StringVar(&network, "network", "tcp", "The network to listen on, either \"tcp\" or \"unix\"")
+	flag.StringVar(&listenAddress, "listen-address", "127.0.0.1:9090", "The address (or socket path for unix) on which the plugin will listen on")
+	flag.StringVar(&tlsCertFile, "tls-cert-file", "", "The serving certificate. If empty, the plugin is served without TLS, which is only safe on unix sockets")
+	flag.StringVar(&tlsKeyFile, "tls-key-file", "", "The key of the serving certificate")
+	flag.StringVar(&clientCAFile, "client-ca-file", "", "When set, require client certificates signed by this CA bundle")
+	flag.Parse()
+
+	if err := logFlags.Validate(); err != nil {
+		log.Fatalf("Invalid options: %v", err)
+	}
+
+	rawLog := machinecontrollerlog.New(logFlags.Debug, logFlags.Format)
+	log := rawLog.Sugar()
+
+	listener, err := net.Listen(network, listenAddress)
+	if err != nil {
+		log.Fatalw("Failed to listen", "address", listenAddress, zap.Error(err))
+	}
+
+	var opts []grpc.ServerOption
+	if tlsCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(tlsCertFile, tlsKeyFile)
+		if err != nil {
+			log.Fatalw("Failed to load serving certificate", zap.Error(err))
+		}
+		config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+		if clientCAFile != "" {
+			caPEM, err := os.ReadFile(clientCAFile)
+			if err != nil {
+				log.Fatalw("Failed to read client CA file", zap.Error(err))
+			}
+			config.ClientCAs = x509.NewCertPool()
+			if !config.ClientCAs.AppendCertsFromPEM(caPEM) {
+				log.Fatalw("Client CA file does not contain any certificate", "file", clientCAFile)
+			}
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(config)))
+	}
+
+	srv := grpc.NewServer(opts...)
+	plugin.RegisterProviderServer(srv, plugin.NewServer(log, fake.New(nil), nil))
+
+	log.Infow("Listening", "network", network, "address", listenAddress, "service", plugin.ServiceName, "tls", tlsCertFile != "")
+	if err := srv.Serve(listener); err != nil {
+		log.Fatalw("Failed to serve", zap.Error(err))
+	}
+}
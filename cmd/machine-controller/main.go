@@ -30,21 +30,34 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
+	"k8c.io/machine-controller/pkg/cloudprovider"
+	"k8c.io/machine-controller/pkg/cloudprovider/plugin"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins/redfish"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/simulator"
+	"k8c.io/machine-controller/pkg/cloudprovider/ratelimit"
 	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
 	"k8c.io/machine-controller/pkg/cloudprovider/util"
+	"k8c.io/machine-controller/pkg/cloudprovider/workloadidentity"
 	clusterinfo "k8c.io/machine-controller/pkg/clusterinfo"
 	machinecontroller "k8c.io/machine-controller/pkg/controller/machine"
 	machinedeploymentcontroller "k8c.io/machine-controller/pkg/controller/machinedeployment"
+	machinehealthcheckcontroller "k8c.io/machine-controller/pkg/controller/machinehealthcheck"
 	machinesetcontroller "k8c.io/machine-controller/pkg/controller/machineset"
 	"k8c.io/machine-controller/pkg/controller/nodecsrapprover"
+	"k8c.io/machine-controller/pkg/controller/orphancollector"
+	controllerutil "k8c.io/machine-controller/pkg/controller/util"
 	"k8c.io/machine-controller/pkg/health"
 	machinecontrollerlog "k8c.io/machine-controller/pkg/log"
 	"k8c.io/machine-controller/pkg/migrations"
 	"k8c.io/machine-controller/pkg/node"
+	"k8c.io/machine-controller/pkg/secretsource"
+	"k8c.io/machine-controller/pkg/tracing"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	machinesv1alpha1 "k8c.io/machine-controller/sdk/apis/machines/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig/configvar"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -69,17 +82,32 @@ var (
 	name                             string
 	joinClusterTimeout               string
 	workerCount                      int
+	providerWorkerCountConfigMap     string
 	bootstrapTokenServiceAccountName string
 	skipEvictionAfter                time.Duration
 	caBundleFile                     string
+	workloadIdentityTokenFile        string
+	redfishConfigDriveAddress        string
+	redfishConfigDriveURL            string
 	enableLeaderElection             bool
 	leaderElectionNamespace          string
 
 	useExternalBootstrap              bool
 	overrideBootstrapKubeletAPIServer string
 	nodeCSRApprover                   bool
+	machineHealthCheck                bool
+	nodeCSRApproveClientCerts         bool
+	nodeCSRAllowedSANs                sliceVar
+	nodeCSRServingSigners             sliceVar
 	nodePortRange                     string
 
+	clusterID                  string
+	orphanCollectorMode        string
+	orphanCollectorInterval    time.Duration
+	orphanCollectorGracePeriod time.Duration
+
+	simulatorKubeletStubInterval time.Duration
+
 	nodeHTTPProxy                 string
 	nodeNoProxy                   string
 	nodeInsecureRegistries        string
@@ -89,6 +117,11 @@ var (
 	nodeRegistryCredentialsSecret string
 	nodeContainerdVersion         string
 	nodeContainerdRegistryMirrors sliceVar
+
+	cloudProviderPlugins    = plugin.EndpointsFlag{}
+	pluginTransport         plugin.TransportOptions
+	cloudProviderRateLimits = ratelimit.BudgetsFlag{}
+	providerWorkerCounts    = machinecontroller.ProviderWorkerCounts{}
 )
 
 type sliceVar []string
@@ -141,6 +174,12 @@ type controllerRunOptions struct {
 	// Enable NodeCSRApprover controller to automatically approve node serving certificate requests.
 	nodeCSRApprover bool
 
+	// Enable the MachineHealthCheck controller. The MachineHealthCheck CRD must be installed.
+	machineHealthCheck bool
+
+	// nodeCSRApproverOptions configure which certificate requests the NodeCSRApprover approves.
+	nodeCSRApproverOptions nodecsrapprover.Options
+
 	node machinecontroller.NodeSettings
 
 	// A port range to reserve for services with NodePort visibility.
@@ -148,11 +187,38 @@ type controllerRunOptions struct {
 
 	overrideBootstrapKubeletAPIServer string
 
+	// rateLimiter limits the calls to the cloud providers. If nil, calls are not limited.
+	rateLimiter *ratelimit.Limiter
+
+	// providerWorkerCounts are the numbers of workers of the dedicated queues of cloud providers.
+	// The machines of all other providers are processed by the -worker-count workers.
+	providerWorkerCounts machinecontroller.ProviderWorkerCounts
+
+	// clusterID identifies the cluster at the cloud providers. It may be empty.
+	clusterID string
+
+	// orphanCollector configures the collector for instances whose machine does not exist anymore.
+	// It is disabled if no mode is set.
+	orphanCollector orphancollector.Options
+
+	// redfishConfigDriveAddress is the address on which the config drives of the Redfish driver
+	// are served. The config drive server is disabled if it is empty.
+	redfishConfigDriveAddress string
+
+	// simulatorKubeletStubInterval is the interval in which Nodes are registered for the running
+	// instances of the simulator cloud provider. The kubelet stub is disabled if it is zero.
+	simulatorKubeletStubInterval time.Duration
+
+	// configVarSources resolve config vars referencing values outside of the cluster.
+	configVarSources *configvar.Sources
+
 	log *zap.SugaredLogger
 }
 
 func main() {
 	nodeFlags := node.NewFlags(flag.CommandLine)
+	secretSourceFlags := secretsource.NewFlags(flag.CommandLine)
+	tracingFlags := tracing.NewFlags(flag.CommandLine)
 	logFlags := machinecontrollerlog.NewDefaultOptions()
 	logFlags.AddFlags(flag.CommandLine)
 
@@ -167,6 +233,8 @@ func main() {
 	}
 	flag.StringVar(&clusterDNSIPs, "cluster-dns", "", "DEPRECATED: This flag is no-op and will have no effect. This value should be configured in the user-data provider, such as operating-system-manager.")
 	flag.IntVar(&workerCount, "worker-count", 1, "Number of workers to process machines. Using a high number with a lot of machines might cause getting rate-limited from your cloud provider.")
+	flag.Var(providerWorkerCounts, "provider-worker-count", "Process the machines of a cloud provider by a dedicated queue with the given number of workers instead of the -worker-count workers, in <cloud-provider>=<workers> format. Can be given multiple times.")
+	flag.StringVar(&providerWorkerCountConfigMap, "provider-worker-count-configmap", "", "When set, read additional provider worker counts from the data of this ConfigMap, which maps cloud providers to worker counts. Passed in namespace/name format. Values of -provider-worker-count take precedence. The ConfigMap is only read on startup, so changes require a restart")
 	flag.StringVar(&healthProbeAddress, "health-probe-address", "127.0.0.1:8085", "The address on which the liveness check on /healthz and readiness check on /readyz will be available")
 	flag.StringVar(&metricsAddress, "metrics-address", "127.0.0.1:8080", "The address on which Prometheus metrics will be available under /metrics")
 	flag.StringVar(&name, "name", "", "When set, the controller will only process machines with the label \"machine.k8s.io/controller\": name")
@@ -180,8 +248,23 @@ func main() {
 	flag.BoolVar(&useExternalBootstrap, "use-external-bootstrap", true, "DEPRECATED: This flag is no-op and will have no effect since machine-controller only supports external bootstrap mechanism. This flag is only kept for backwards compatibility and will be removed in the future")
 	flag.StringVar(&overrideBootstrapKubeletAPIServer, "override-bootstrap-kubelet-apiserver", "", "Override for the API server address used in worker nodes bootstrap-kubelet.conf")
 	flag.StringVar(&caBundleFile, "ca-bundle", "", "path to a file containing all PEM-encoded CA certificates (will be used instead of the host's certificates if set)")
+	flag.StringVar(&redfishConfigDriveAddress, "redfish-config-drive-address", "", "The address on which the config drives passing the userdata to servers provisioned by the baremetal Redfish driver are served")
+	flag.StringVar(&redfishConfigDriveURL, "redfish-config-drive-url", "", "The URL under which the BMCs reach the -redfish-config-drive-address, e.g. http://10.0.0.5:8086")
+	flag.StringVar(&workloadIdentityTokenFile, "workload-identity-token-file", "", "path to a file containing a service account token, which is exchanged for short-lived cloud credentials by machines using workload identity federation")
 	flag.BoolVar(&nodeCSRApprover, "node-csr-approver", true, "Enable NodeCSRApprover controller to automatically approve node serving certificate requests")
+	flag.BoolVar(&machineHealthCheck, "machine-health-check", false, "Enable the MachineHealthCheck controller to delete unhealthy machines owned by a MachineSet. Requires the MachineHealthCheck CRD")
+	flag.BoolVar(&nodeCSRApproveClientCerts, "node-csr-approve-client-certs", false, "Enable the NodeCSRApprover to also approve kubelet client certificate renewals of nodes with a machine")
+	flag.Var(&nodeCSRAllowedSANs, "node-csr-allowed-san", "A glob pattern of DNS names or IP addresses the NodeCSRApprover allows in node serving certificates in addition to the machine addresses, e.g. \"*.compute.internal\". Can be given multiple times.")
+	flag.Var(&nodeCSRServingSigners, "node-csr-serving-signer", "The name of an external signer whose certificate requests the NodeCSRApprover handles like kubelet serving certificate requests. Can be given multiple times.")
 	flag.StringVar(&nodePortRange, "node-port-range", "30000-32767", "A port range to reserve for services with NodePort visibility")
+	flag.StringVar(&clusterID, "cluster-id", "", "Identifies the cluster at the cloud providers. Instances are tagged with it, so the orphan collector only collects instances of this cluster. Defaults to the UID of the kube-system namespace")
+	flag.StringVar(&orphanCollectorMode, "orphan-collector-mode", "", "When set, instances at the cloud provider whose machine does not exist anymore are collected. Either \"dry-run\" to only report them by events and metrics, or \"delete\" to also delete them")
+	flag.DurationVar(&orphanCollectorInterval, "orphan-collector-interval", 10*time.Minute, "The interval in which the orphan collector lists the instances at the cloud providers")
+	flag.DurationVar(&orphanCollectorGracePeriod, "orphan-collector-grace-period", time.Hour, "The time an instance must have been orphaned before the orphan collector deletes it")
+	flag.DurationVar(&simulatorKubeletStubInterval, "simulator-kubelet-stub-interval", 0, "When set, a kubelet stub registers a Ready Node for every running instance of the simulator cloud provider in this interval. Only meant for scale and chaos testing")
+	flag.Var(cloudProviderRateLimits, "cloud-provider-rate-limit", "Limit the calls to a cloud provider per account, in <cloud-provider>[.<get|create|cleanup>]=<qps>:<burst> format. Rate limited calls are requeued. Can be given multiple times.")
+	flag.Var(cloudProviderPlugins, "cloud-provider-plugin", "Serve the given cloud provider by an out-of-process gRPC plugin, in <cloud-provider>=<endpoint> format. Can be given multiple times.")
+	pluginTransport.AddFlags(flag.CommandLine)
 
 	flag.StringVar(&nodeHTTPProxy, "node-http-proxy", "", "DEPRECATED: This flag is no-op and will have no effect. This value should be configured in the user-data provider, such as operating-system-manager.")
 	flag.StringVar(&nodeNoProxy, "node-no-proxy", "", "DEPRECATED: This flag is no-op and will have no effect. This value should be configured in the user-data provider, such as operating-system-manager.")
@@ -239,6 +322,21 @@ func main() {
 		}
 	}
 
+	workloadidentity.SetTokenFile(workloadIdentityTokenFile)
+
+	if (redfishConfigDriveAddress == "") != (redfishConfigDriveURL == "") {
+		log.Fatal("-redfish-config-drive-address and -redfish-config-drive-url must be set together")
+	}
+	redfish.SetConfigDriveURL(redfishConfigDriveURL)
+
+	for provider, endpoint := range cloudProviderPlugins {
+		if err := cloudprovider.RegisterPlugin(provider, endpoint, pluginTransport); err != nil {
+			log.Fatalw("-cloud-provider-plugin is invalid", zap.Error(err))
+		}
+	}
+
+	configVarSources := secretSourceFlags.Sources(log)
+
 	// rest.Config has no DeepCopy() that returns another rest.Config, thus
 	// we simply build it twice
 	// We need a dedicated one for machines because we want to increase the
@@ -254,8 +352,35 @@ func main() {
 	}
 	kubeconfigProvider := clusterinfo.New(cfg, kubeClient)
 
+	if clusterID == "" {
+		namespace, err := kubeClient.CoreV1().Namespaces().Get(context.Background(), metav1.NamespaceSystem, metav1.GetOptions{})
+		if err != nil {
+			log.Warnw("Failed to get the kube-system namespace for the cluster ID, instances are not tagged with a cluster ID", zap.Error(err))
+		} else {
+			clusterID = string(namespace.UID)
+		}
+	}
+
+	// The ConfigMap is only read once, as the queues are set up on startup and cannot be
+	// changed while the controller runs.
+	if providerWorkerCountConfigMap != "" {
+		namespace, name, found := strings.Cut(providerWorkerCountConfigMap, "/")
+		if !found {
+			log.Fatalf("-provider-worker-count-configmap must be passed in namespace/name format, got %q", providerWorkerCountConfigMap)
+		}
+		configMap, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			log.Fatalw("Failed to get provider worker count ConfigMap", zap.Error(err))
+		}
+		if err := providerWorkerCounts.AddFromConfigMap(configMap); err != nil {
+			log.Fatalw("Failed to read provider worker counts", zap.Error(err))
+		}
+	}
+
 	ctrlMetrics := machinecontroller.NewMachineControllerMetrics()
 	ctrlMetrics.MustRegister(metrics.Registry)
+	// All providers record their calls, not only the ones of the machine controller.
+	cloudprovider.SetMetrics(ctrlMetrics.ProviderCalls)
 
 	runOptions := controllerRunOptions{
 		log:                               log,
@@ -267,8 +392,36 @@ func main() {
 		prometheusRegisterer:              metrics.Registry,
 		skipEvictionAfter:                 skipEvictionAfter,
 		nodeCSRApprover:                   nodeCSRApprover,
+		machineHealthCheck:                machineHealthCheck,
 		nodePortRange:                     nodePortRange,
 		overrideBootstrapKubeletAPIServer: overrideBootstrapKubeletAPIServer,
+		clusterID:                         clusterID,
+		providerWorkerCounts:              providerWorkerCounts,
+		orphanCollector: orphancollector.Options{
+			Mode:        orphancollector.Mode(orphanCollectorMode),
+			Interval:    orphanCollectorInterval,
+			GracePeriod: orphanCollectorGracePeriod,
+			ClusterID:   clusterID,
+			StateConfigMap: types.NamespacedName{
+				Namespace: metav1.NamespaceSystem,
+				Name:      "machine-controller-orphan-collector",
+			},
+			ConfigVarSources: configVarSources,
+		},
+		redfishConfigDriveAddress:    redfishConfigDriveAddress,
+		simulatorKubeletStubInterval: simulatorKubeletStubInterval,
+		configVarSources:             configVarSources,
+		nodeCSRApproverOptions: nodecsrapprover.Options{
+			ApproveClientCertificates: nodeCSRApproveClientCerts,
+			AllowedSANPatterns:        nodeCSRAllowedSANs,
+			ServingSignerNames:        nodeCSRServingSigners,
+		},
+	}
+
+	if len(cloudProviderRateLimits) > 0 {
+		rateLimitMetrics := ratelimit.NewMetrics()
+		rateLimitMetrics.MustRegister(metrics.Registry)
+		runOptions.rateLimiter = ratelimit.New(ratelimit.Budgets(cloudProviderRateLimits), rateLimitMetrics)
 	}
 
 	if err := nodeFlags.UpdateNodeSettings(&runOptions.node); err != nil {
@@ -293,6 +446,19 @@ func main() {
 		log.Info("Caught signal, shutting down...")
 	}()
 
+	shutdownTracing, err := tracingFlags.Setup(ctx, "machine-controller")
+	if err != nil {
+		log.Fatalw("Failed to set up tracing", zap.Error(err))
+	}
+	defer func() {
+		// The signal context is already done, but the remaining spans should still be exported.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Errorw("Failed to export remaining spans", zap.Error(err))
+		}
+	}()
+
 	mgr, err := createManager(5*time.Minute, runOptions)
 	if err != nil {
 		log.Fatalw("Failed to create runtime manager", zap.Error(err))
@@ -375,13 +541,14 @@ func (bs *controllerBootstrap) Start(ctx context.Context) error {
 	client := bs.mgr.GetClient()
 
 	providerData := &cloudprovidertypes.ProviderData{
-		Ctx:    ctx,
-		Update: cloudprovidertypes.GetMachineUpdater(ctx, client),
-		Client: client,
+		Ctx:       ctx,
+		Update:    cloudprovidertypes.GetMachineUpdater(ctx, client),
+		Client:    client,
+		ClusterID: bs.opt.clusterID,
 	}
 
 	// Migrate MachinesV1Alpha1Machine to ClusterV1Alpha1Machine.
-	if err := migrations.MigrateMachinesv1Alpha1MachineToClusterv1Alpha1MachineIfNecessary(ctx, bs.opt.log, client, providerData); err != nil {
+	if err := migrations.MigrateMachinesv1Alpha1MachineToClusterv1Alpha1MachineIfNecessary(ctx, bs.opt.log, client, providerData, bs.opt.configVarSources); err != nil {
 		return fmt.Errorf("migration to clusterv1alpha1 failed: %w", err)
 	}
 
@@ -390,7 +557,7 @@ func (bs *controllerBootstrap) Start(ctx context.Context) error {
 		return fmt.Errorf("migration of providerConfig field to providerSpec field failed: %w", err)
 	}
 
-	machineCollector := machinecontroller.NewMachineCollector(ctx, bs.mgr.GetClient())
+	machineCollector := machinecontroller.NewMachineCollector(ctx, bs.mgr.GetClient(), bs.opt.configVarSources)
 	metrics.Registry.MustRegister(machineCollector)
 
 	machineDeploymentCollector := machinedeploymentcontroller.NewCollector(ctx, bs.mgr.GetClient())
@@ -412,6 +579,9 @@ func (bs *controllerBootstrap) Start(ctx context.Context) error {
 		bs.opt.node,
 		bs.opt.nodePortRange,
 		bs.opt.overrideBootstrapKubeletAPIServer,
+		bs.opt.rateLimiter,
+		bs.opt.providerWorkerCounts,
+		bs.opt.configVarSources,
 	); err != nil {
 		return fmt.Errorf("failed to add Machine controller to manager: %w", err)
 	}
@@ -424,12 +594,51 @@ func (bs *controllerBootstrap) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to add MachineDeployment controller to manager: %w", err)
 	}
 
+	if bs.opt.machineHealthCheck || bs.opt.nodeCSRApprover {
+		if err := controllerutil.AddMachineNodeNameIndex(ctx, bs.mgr.GetFieldIndexer()); err != nil {
+			return err
+		}
+	}
+
+	if bs.opt.machineHealthCheck {
+		machineHealthCheckMetrics := machinehealthcheckcontroller.NewMetrics()
+		machineHealthCheckMetrics.MustRegister(metrics.Registry)
+
+		if err := machinehealthcheckcontroller.Add(bs.mgr, bs.opt.log, machineHealthCheckMetrics); err != nil {
+			return fmt.Errorf("failed to add MachineHealthCheck controller to manager: %w", err)
+		}
+	}
+
 	if bs.opt.nodeCSRApprover {
-		if err := nodecsrapprover.Add(bs.mgr, bs.opt.log); err != nil {
+		nodeCSRApproverMetrics := nodecsrapprover.NewMetrics()
+		nodeCSRApproverMetrics.MustRegister(metrics.Registry)
+
+		if err := nodecsrapprover.Add(bs.mgr, bs.opt.log, nodeCSRApproverMetrics, bs.opt.nodeCSRApproverOptions); err != nil {
 			return fmt.Errorf("failed to add NodeCSRApprover controller to manager: %w", err)
 		}
 	}
 
+	if bs.opt.orphanCollector.Mode != "" {
+		orphanCollectorMetrics := orphancollector.NewMetrics()
+		orphanCollectorMetrics.MustRegister(metrics.Registry)
+
+		if err := orphancollector.Add(bs.mgr, bs.opt.log, orphanCollectorMetrics, bs.opt.orphanCollector); err != nil {
+			return fmt.Errorf("failed to add orphan collector to manager: %w", err)
+		}
+	}
+
+	if bs.opt.redfishConfigDriveAddress != "" {
+		if err := redfish.AddConfigDriveServer(bs.mgr, bs.opt.log, bs.opt.redfishConfigDriveAddress); err != nil {
+			return fmt.Errorf("failed to add Redfish config drive server to manager: %w", err)
+		}
+	}
+
+	if bs.opt.simulatorKubeletStubInterval > 0 {
+		if err := simulator.AddKubeletStub(bs.mgr, bs.opt.log, bs.opt.simulatorKubeletStubInterval); err != nil {
+			return fmt.Errorf("failed to add simulator kubelet stub to manager: %w", err)
+		}
+	}
+
 	bs.opt.log.Info("Machine-controller startup complete")
 
 	return nil
@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/controller/util"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type options struct {
+	kubeconfig string
+	namespace  string
+	toRevision int64
+	output     string
+}
+
+func main() {
+	opt := &options{}
+
+	flag.StringVar(&opt.kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Defaults to $KUBECONFIG or ~/.kube/config.")
+	flag.StringVar(&opt.namespace, "namespace", metav1.NamespaceSystem, "The namespace of the MachineDeployment")
+	flag.Int64Var(&opt.toRevision, "to-revision", 0, "The revision to roll back to. Defaults to the last revision.")
+	flag.StringVar(&opt.output, "output", "yaml", "The output format of the plan, either yaml or json.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n  %[1]s [flags] rollout (history|undo) <machinedeployment>\n  %[1]s [flags] plan <manifest>\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	switch {
+	case len(args) == 3 && args[0] == "rollout":
+	case len(args) == 2 && args[0] == "plan":
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	client, err := newClient(opt.kubeconfig)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	switch {
+	case args[0] == "plan":
+		err = plan(ctx, client, args[1], opt.namespace, opt.output)
+	case args[1] == "history":
+		err = rolloutHistory(ctx, client, ctrlruntimeclient.ObjectKey{Namespace: opt.namespace, Name: args[2]})
+	case args[1] == "undo":
+		err = rolloutUndo(ctx, client, ctrlruntimeclient.ObjectKey{Namespace: opt.namespace, Name: args[2]}, opt.toRevision)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func newClient(kubeconfig string) (ctrlruntimeclient.Client, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubeconfig: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add kubernetes api to scheme: %w", err)
+	}
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to add api to scheme: %w", err)
+	}
+
+	return ctrlruntimeclient.New(cfg, ctrlruntimeclient.Options{Scheme: scheme})
+}
+
+// rolloutHistory prints the revisions of the MachineDeployment, similar to "kubectl rollout history".
+func rolloutHistory(ctx context.Context, client ctrlruntimeclient.Client, key ctrlruntimeclient.ObjectKey) error {
+	md := &clusterv1alpha1.MachineDeployment{}
+	if err := client.Get(ctx, key, md); err != nil {
+		return fmt.Errorf("failed to get MachineDeployment: %w", err)
+	}
+
+	machineSets := &clusterv1alpha1.MachineSetList{}
+	if err := client.List(ctx, machineSets, ctrlruntimeclient.InNamespace(md.Namespace)); err != nil {
+		return fmt.Errorf("failed to list MachineSets: %w", err)
+	}
+
+	var msList []*clusterv1alpha1.MachineSet
+	for i := range machineSets.Items {
+		if metav1.IsControlledBy(&machineSets.Items[i], md) {
+			msList = append(msList, &machineSets.Items[i])
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tMACHINESET\tREPLICAS\tCHANGE-CAUSE")
+	for _, revision := range util.RolloutHistory(zap.NewNop().Sugar(), msList) {
+		changeCause := revision.ChangeCause
+		if changeCause == "" {
+			changeCause = "<none>"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", revision.Revision, revision.MachineSet.Name, ptr.Deref(revision.MachineSet.Spec.Replicas, 0), changeCause)
+	}
+	return w.Flush()
+}
+
+// rolloutUndo requests a rollback of the MachineDeployment to the given revision, similar to "kubectl rollout undo".
+// The rollback is performed by the MachineDeployment controller.
+func rolloutUndo(ctx context.Context, client ctrlruntimeclient.Client, key ctrlruntimeclient.ObjectKey, revision int64) error {
+	md := &clusterv1alpha1.MachineDeployment{}
+	if err := client.Get(ctx, key, md); err != nil {
+		return fmt.Errorf("failed to get MachineDeployment: %w", err)
+	}
+
+	oldMD := md.DeepCopy()
+	md.Spec.RollbackTo = &clusterv1alpha1.RollbackConfig{Revision: revision}
+	if err := client.Patch(ctx, md, ctrlruntimeclient.MergeFrom(oldMD)); err != nil {
+		return fmt.Errorf("failed to request rollback: %w", err)
+	}
+
+	fmt.Printf("machinedeployment/%s rollback requested\n", md.Name)
+	return nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/dryrun"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// objectPlan is the dry run result of a Machine or MachineDeployment.
+type objectPlan struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	*dryrun.Result
+}
+
+// plan prints what the machine controller would create for the Machines and MachineDeployments
+// in the manifest, without creating anything at the cloud provider. Other objects in the
+// manifest are ignored.
+func plan(ctx context.Context, client ctrlruntimeclient.Client, manifest, namespace, output string) error {
+	if output != "yaml" && output != "json" {
+		return fmt.Errorf("invalid output format %q, expected yaml or json", output)
+	}
+
+	f, err := os.Open(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+	defer f.Close()
+
+	var results []*objectPlan
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read manifest: %w", err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		result, err := planObject(ctx, client, doc, namespace)
+		if err != nil {
+			return err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+	if len(results) == 0 {
+		return errors.New("manifest contains neither a Machine nor a MachineDeployment")
+	}
+
+	if output == "json" {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal plan: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	for i, result := range results {
+		out, err := yaml.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal plan: %w", err)
+		}
+		if i > 0 {
+			fmt.Println("---")
+		}
+		fmt.Print(string(out))
+	}
+	return nil
+}
+
+// planObject returns the dry run result for a Machine or MachineDeployment, or nil for other
+// objects.
+func planObject(ctx context.Context, client ctrlruntimeclient.Client, doc []byte, namespace string) (*objectPlan, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := yaml.Unmarshal(doc, &typeMeta); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	var (
+		objectMeta        metav1.ObjectMeta
+		spec              clusterv1alpha1.MachineSpec
+		machineDeployment string
+	)
+	switch typeMeta.Kind {
+	case "MachineDeployment":
+		md := &clusterv1alpha1.MachineDeployment{}
+		if err := yaml.Unmarshal(doc, md); err != nil {
+			return nil, fmt.Errorf("failed to parse MachineDeployment: %w", err)
+		}
+		objectMeta, spec, machineDeployment = md.ObjectMeta, md.Spec.Template.Spec, md.Name
+	case "Machine":
+		machine := &clusterv1alpha1.Machine{}
+		if err := yaml.Unmarshal(doc, machine); err != nil {
+			return nil, fmt.Errorf("failed to parse Machine: %w", err)
+		}
+		objectMeta, spec = machine.ObjectMeta, machine.Spec
+	default:
+		return nil, nil
+	}
+
+	if objectMeta.Namespace != "" {
+		namespace = objectMeta.Namespace
+	}
+	result, err := dryrun.Run(ctx, zap.NewNop().Sugar(), client, namespace, machineDeployment, spec)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s/%s: %w", typeMeta.Kind, namespace, objectMeta.Name, err)
+	}
+	return &objectPlan{Kind: typeMeta.Kind, Namespace: namespace, Name: objectMeta.Name, Result: result}, nil
+}
@@ -19,16 +19,27 @@ package main
 import (
 	"flag"
 	"log"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 
 	"k8c.io/machine-controller/pkg/admission"
+	"k8c.io/machine-controller/pkg/cloudprovider"
+	cloudprovidercache "k8c.io/machine-controller/pkg/cloudprovider/cache"
+	"k8c.io/machine-controller/pkg/cloudprovider/plugin"
 	"k8c.io/machine-controller/pkg/cloudprovider/util"
+	"k8c.io/machine-controller/pkg/cloudprovider/workloadidentity"
 	machinecontrollerlog "k8c.io/machine-controller/pkg/log"
 	"k8c.io/machine-controller/pkg/node"
+	"k8c.io/machine-controller/pkg/secretsource"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlruntimelog "sigs.k8s.io/controller-runtime/pkg/log"
@@ -36,24 +47,33 @@ import (
 )
 
 type options struct {
-	masterURL               string
-	kubeconfig              string
-	admissionListenAddress  string
-	admissionTLSCertPath    string
-	admissionTLSKeyPath     string
-	caBundleFile            string
-	useExternalBootstrap    bool
-	namespace               string
-	workerClusterKubeconfig string
-	versionConstraint       string
+	masterURL                 string
+	kubeconfig                string
+	admissionListenAddress    string
+	admissionTLSCertPath      string
+	admissionTLSKeyPath       string
+	caBundleFile              string
+	workloadIdentityTokenFile string
+	useExternalBootstrap      bool
+	namespace                 string
+	workerClusterKubeconfig   string
+	versionConstraint         string
+	cloudProviderPlugins      plugin.EndpointsFlag
+	pluginTransport           plugin.TransportOptions
+	validationCacheTTL        time.Duration
+	validationCacheSize       int
+	validationCacheConfigMap  string
 }
 
 func main() {
 	nodeFlags := node.NewFlags(flag.CommandLine)
+	secretSourceFlags := secretsource.NewFlags(flag.CommandLine)
 	logFlags := machinecontrollerlog.NewDefaultOptions()
 	logFlags.AddFlags(flag.CommandLine)
 
-	opt := &options{}
+	opt := &options{
+		cloudProviderPlugins: plugin.EndpointsFlag{},
+	}
 
 	if flag.Lookup("kubeconfig") == nil {
 		flag.StringVar(&opt.kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
@@ -65,9 +85,15 @@ func main() {
 	flag.StringVar(&opt.admissionTLSCertPath, "tls-cert-path", "/tmp/cert/tls.crt", "The path of the TLS cert for the MutatingWebhook")
 	flag.StringVar(&opt.admissionTLSKeyPath, "tls-key-path", "/tmp/cert/tls.key", "The path of the TLS key for the MutatingWebhook")
 	flag.StringVar(&opt.caBundleFile, "ca-bundle", "", "path to a file containing all PEM-encoded CA certificates (will be used instead of the host's certificates if set)")
+	flag.StringVar(&opt.workloadIdentityTokenFile, "workload-identity-token-file", "", "path to a file containing a service account token, which is exchanged for short-lived cloud credentials by machines using workload identity federation")
 	flag.StringVar(&opt.namespace, "namespace", "kubermatic", "The namespace where the webhooks will run")
 	flag.StringVar(&opt.workerClusterKubeconfig, "worker-cluster-kubeconfig", "", "Path to kubeconfig of worker/user cluster where machines and machinedeployments exist. If not specified, value from --kubeconfig or in-cluster config will be used")
 	flag.StringVar(&opt.versionConstraint, "kubernetes-version-constraints", ">=0.0.0", "")
+	flag.DurationVar(&opt.validationCacheTTL, "validation-cache-ttl", cloudprovidercache.DefaultTTL, "The time the results of cloud provider validations are cached")
+	flag.IntVar(&opt.validationCacheSize, "validation-cache-size", cloudprovidercache.DefaultSize, "The maximum number of cached cloud provider validation results")
+	flag.StringVar(&opt.validationCacheConfigMap, "validation-cache-flush-configmap", "machine-controller-webhook-validation-cache", "Name of a ConfigMap in -namespace. Every replica flushes its validation cache whenever the ConfigMap is created or changed. Set to an empty string to disable")
+	flag.Var(opt.cloudProviderPlugins, "cloud-provider-plugin", "Serve the given cloud provider by an out-of-process gRPC plugin, in <cloud-provider>=<endpoint> format. Can be given multiple times.")
+	opt.pluginTransport.AddFlags(flag.CommandLine)
 
 	flag.BoolVar(&opt.useExternalBootstrap, "use-external-bootstrap", true, "DEPRECATED: This flag is no-op and will have no effect since machine-controller only supports external bootstrap mechanism. This flag is only kept for backwards compatibility and will be removed in the future")
 
@@ -92,6 +118,21 @@ func main() {
 		}
 	}
 
+	workloadidentity.SetTokenFile(opt.workloadIdentityTokenFile)
+
+	for provider, endpoint := range opt.cloudProviderPlugins {
+		if err := cloudprovider.RegisterPlugin(provider, endpoint, opt.pluginTransport); err != nil {
+			log.Fatalw("-cloud-provider-plugin is invalid", zap.Error(err))
+		}
+	}
+
+	configVarSources := secretSourceFlags.Sources(log)
+
+	// Needed to read MachinePolicies.
+	if err := clusterv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		log.Fatalw("Failed to add api to scheme", "api", clusterv1alpha1.SchemeGroupVersion, zap.Error(err))
+	}
+
 	cfg, err := clientcmd.BuildConfigFromFlags(opt.masterURL, opt.kubeconfig)
 	if err != nil {
 		log.Fatalw("Failed to build kubeconfig", zap.Error(err))
@@ -125,6 +166,22 @@ func main() {
 		}
 	}
 
+	registry := prometheus.NewRegistry()
+	validationCacheMetrics := cloudprovidercache.NewMetrics()
+	validationCacheMetrics.MustRegister(registry)
+	providerMetrics := cloudprovider.NewMetrics()
+	providerMetrics.MustRegister(registry)
+	cloudprovider.SetMetrics(providerMetrics)
+
+	// The validation cache must know the worker cluster, because the ConfigVarResolver
+	// resolves Secrets and ConfigMaps there.
+	validationCache := cloudprovidercache.NewWithOptions(cloudprovidercache.Options{
+		TTL:     opt.validationCacheTTL,
+		Size:    opt.validationCacheSize,
+		Client:  workerClient,
+		Metrics: validationCacheMetrics,
+	})
+
 	srv, err := admission.Builder{
 		ListenAddress:      opt.admissionListenAddress,
 		Log:                log,
@@ -133,6 +190,8 @@ func main() {
 		NodeFlags:          nodeFlags,
 		Namespace:          opt.namespace,
 		VersionConstraints: constraint,
+		ValidationCache:    validationCache,
+		ConfigVarSources:   configVarSources,
 
 		// we could change this to get the CertDir from the configured CertName
 		// and KeyName, but doing so does not bring us any benefits but would
@@ -145,9 +204,21 @@ func main() {
 		log.Fatalw("Failed to create admission hook", zap.Error(err))
 	}
 
-	log.Infow("Listening", "address", opt.admissionListenAddress)
+	srv.Register("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
 	serverContext := signals.SetupSignalHandler()
+
+	if opt.validationCacheConfigMap != "" {
+		kubeClient, err := kubernetes.NewForConfig(cfg)
+		if err != nil {
+			log.Fatalw("Failed to build kubernetes client", zap.Error(err))
+		}
+		if err := validationCache.FlushOnConfigMapChange(serverContext, log, kubeClient, opt.namespace, opt.validationCacheConfigMap); err != nil {
+			log.Fatalw("Failed to watch validation cache ConfigMap", zap.Error(err))
+		}
+	}
+
+	log.Infow("Listening", "address", opt.admissionListenAddress)
 	if err := srv.Start(serverContext); err != nil {
 		log.Fatalw("Failed to start server", zap.Error(err))
 	}
@@ -31,11 +31,14 @@ import (
 	"go.uber.org/zap"
 	"gomodules.xyz/jsonpatch/v2"
 
+	cloudprovidercache "k8c.io/machine-controller/pkg/cloudprovider/cache"
 	machinecontroller "k8c.io/machine-controller/pkg/controller/machine"
 	"k8c.io/machine-controller/pkg/node"
+	"k8c.io/machine-controller/sdk/providerconfig/configvar"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -50,6 +53,11 @@ type admissionData struct {
 	nodeSettings machinecontroller.NodeSettings
 	namespace    string
 	constraints  *semver.Constraints
+	// validationCache caches the results of cloud provider validations. If nil,
+	// every validation calls the cloud provider.
+	validationCache *cloudprovidercache.CloudproviderCache
+	// configVarSources resolve config vars referencing values outside of the cluster.
+	configVarSources *configvar.Sources
 }
 
 var jsonPatch = admissionv1.PatchTypeJSONPatch
@@ -62,6 +70,8 @@ type Builder struct {
 	NodeFlags          *node.Flags
 	Namespace          string
 	VersionConstraints *semver.Constraints
+	ValidationCache    *cloudprovidercache.CloudproviderCache
+	ConfigVarSources   *configvar.Sources
 
 	CertDir  string
 	CertName string
@@ -75,6 +85,9 @@ func (build Builder) Build() (webhook.Server, error) {
 		workerClient: build.WorkerClient,
 		namespace:    build.Namespace,
 		constraints:  build.VersionConstraints,
+
+		validationCache:  build.ValidationCache,
+		configVarSources: build.ConfigVarSources,
 	}
 
 	if err := build.NodeFlags.UpdateNodeSettings(&ad.nodeSettings); err != nil {
@@ -180,6 +193,13 @@ func handleFuncFactory(log *zap.SugaredLogger, mutate mutator) http.HandlerFunc
 		if err != nil {
 			response = &admissionv1.AdmissionResponse{}
 			response.Result = &metav1.Status{Message: err.Error()}
+
+			// Keep the details of structured errors, e.g. the field errors of MachinePolicy violations.
+			var statusErr apierrors.APIStatus
+			if errors.As(err, &statusErr) {
+				status := statusErr.Status()
+				response.Result = &status
+			}
 		}
 		response.UID = review.Request.UID
 
@@ -25,6 +25,7 @@ import (
 
 	admissionv1 "k8s.io/api/admission/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -59,13 +60,17 @@ func (ad *admissionData) mutateMachineDeployments(ctx context.Context, ar admiss
 		return nil, fmt.Errorf("validation failed: %v", errs)
 	}
 
-	// Do not validate the spec if it hasn't changed
-	machineSpecNeedsValidation := true
+	var oldMachineDeployment *clusterv1alpha1.MachineDeployment
 	if ar.Operation == admissionv1.Update {
-		var oldMachineDeployment clusterv1alpha1.MachineDeployment
-		if err := json.Unmarshal(ar.OldObject.Raw, &oldMachineDeployment); err != nil {
+		oldMachineDeployment = &clusterv1alpha1.MachineDeployment{}
+		if err := json.Unmarshal(ar.OldObject.Raw, oldMachineDeployment); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal OldObject: %w", err)
 		}
+	}
+
+	// Do not validate the spec if it hasn't changed.
+	machineSpecNeedsValidation := true
+	if oldMachineDeployment != nil {
 		if equal := apiequality.Semantic.DeepEqual(oldMachineDeployment.Spec.Template.Spec, machineDeployment.Spec.Template.Spec); equal {
 			machineSpecNeedsValidation = false
 		}
@@ -77,5 +82,38 @@ func (ad *admissionData) mutateMachineDeployments(ctx context.Context, ar admiss
 		}
 	}
 
-	return createAdmissionResponse(log, machineDeploymentOriginal, &machineDeployment)
+	// Only check the MachinePolicies if the fields they apply to changed, so that policies created
+	// later on do not block other updates, e.g. the removal of finalizers or a rollback request.
+	var warnings []string
+	if oldMachineDeployment == nil || machinePolicyFieldsChanged(oldMachineDeployment, &machineDeployment) {
+		var err error
+		specPath := field.NewPath("spec")
+		warnings, err = ad.enforceMachinePolicies(ctx, "MachineDeployment", machineDeployment.Name, machinePolicyTarget{
+			namespace:    machineDeployment.Namespace,
+			labels:       machineDeployment.Spec.Template.Labels,
+			spec:         machineDeployment.Spec.Template.Spec,
+			replicas:     machineDeployment.Spec.Replicas,
+			labelsPath:   specPath.Child("template", "metadata", "labels"),
+			specPath:     specPath.Child("template", "spec"),
+			replicasPath: specPath.Child("replicas"),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := createAdmissionResponse(log, machineDeploymentOriginal, &machineDeployment)
+	if err != nil {
+		return nil, err
+	}
+	response.Warnings = warnings
+
+	return response, nil
+}
+
+// machinePolicyFieldsChanged returns whether any of the fields MachinePolicies apply to changed.
+func machinePolicyFieldsChanged(old, md *clusterv1alpha1.MachineDeployment) bool {
+	return !apiequality.Semantic.DeepEqual(old.Spec.Template.Labels, md.Spec.Template.Labels) ||
+		!apiequality.Semantic.DeepEqual(old.Spec.Template.Spec, md.Spec.Template.Spec) ||
+		!apiequality.Semantic.DeepEqual(old.Spec.Replicas, md.Spec.Replicas)
 }
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"go.uber.org/zap/zaptest"
@@ -63,6 +64,83 @@ func TestMachineDeploymentDefaulting(t *testing.T) {
 			},
 			isValid: true,
 		},
+		{
+			name: "MachineDeployment with negative rollback revision validation should fail",
+			machineDeployment: &clusterv1alpha1.MachineDeployment{
+				Spec: clusterv1alpha1.MachineDeploymentSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Template: clusterv1alpha1.MachineTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"foo": "bar"},
+						},
+					},
+					RollbackTo: &clusterv1alpha1.RollbackConfig{Revision: -1},
+				},
+			},
+			isValid: false,
+		},
+		{
+			name: "MachineDeployment with rollback to last revision validation should succeed",
+			machineDeployment: &clusterv1alpha1.MachineDeployment{
+				Spec: clusterv1alpha1.MachineDeploymentSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Template: clusterv1alpha1.MachineTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"foo": "bar"},
+						},
+					},
+					RollbackTo: &clusterv1alpha1.RollbackConfig{},
+				},
+			},
+			isValid: true,
+		},
+		{
+			name: "MachineDeployment with invalid drain policy validation should fail",
+			machineDeployment: &clusterv1alpha1.MachineDeployment{
+				Spec: clusterv1alpha1.MachineDeploymentSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Template: clusterv1alpha1.MachineTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"foo": "bar"},
+						},
+					},
+					DrainPolicy: &clusterv1alpha1.MachineDrainPolicy{
+						EmptyDir: "Ignore",
+						Timeout:  &metav1.Duration{Duration: -time.Minute},
+					},
+				},
+			},
+			isValid: false,
+		},
+		{
+			name: "MachineDeployment with drain policy validation should succeed",
+			machineDeployment: &clusterv1alpha1.MachineDeployment{
+				Spec: clusterv1alpha1.MachineDeploymentSpec{
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Template: clusterv1alpha1.MachineTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"foo": "bar"},
+						},
+					},
+					DrainPolicy: &clusterv1alpha1.MachineDrainPolicy{
+						SkipPodSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"app": "node-exporter"},
+						},
+						EmptyDir: clusterv1alpha1.EmptyDirDrainPolicyBlock,
+						Timeout:  &metav1.Duration{Duration: 30 * time.Minute},
+					},
+				},
+			},
+			isValid: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -82,10 +160,15 @@ func newTestAdmissionData(t *testing.T) *admissionData {
 	if err != nil {
 		t.Fatalf("constraint: %v", err)
 	}
+	// MachinePolicies are listed while admitting MachineDeployments.
+	scheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add types to scheme: %v", err)
+	}
 	return &admissionData{
 		log:          zaptest.NewLogger(t).Sugar(),
-		client:       clientfake.NewClientBuilder().Build(),
-		workerClient: clientfake.NewClientBuilder().Build(),
+		client:       clientfake.NewClientBuilder().WithScheme(scheme).Build(),
+		workerClient: clientfake.NewClientBuilder().WithScheme(scheme).Build(),
 		nodeSettings: machinecontroller.NodeSettings{},
 		namespace:    "kube-system",
 		constraints:  c,
@@ -57,16 +57,52 @@ func validateMachineDeploymentSpec(spec *clusterv1alpha1.MachineDeploymentSpec,
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), *spec.Replicas, "replicas must be specified and can not be negative"))
 	}
 	allErrs = append(allErrs, validateMachineDeploymentStrategy(spec.Strategy, fldPath.Child("strategy"))...)
+	if spec.RollbackTo != nil && spec.RollbackTo.Revision < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("rollbackTo", "revision"), spec.RollbackTo.Revision, "must not be negative"))
+	}
+	if spec.DrainPolicy != nil {
+		allErrs = append(allErrs, validateMachineDrainPolicy(spec.DrainPolicy, fldPath.Child("drainPolicy"))...)
+	}
+	return allErrs
+}
+
+func validateMachineDrainPolicy(policy *clusterv1alpha1.MachineDrainPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy.PodGracePeriodSeconds != nil && *policy.PodGracePeriodSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("podGracePeriodSeconds"), *policy.PodGracePeriodSeconds, "must not be negative"))
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(policy.SkipPodSelector, metav1validation.LabelSelectorValidationOptions{}, fldPath.Child("skipPodSelector"))...)
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(policy.DeletePodSelector, metav1validation.LabelSelectorValidationOptions{}, fldPath.Child("deletePodSelector"))...)
+	switch policy.EmptyDir {
+	case "", clusterv1alpha1.EmptyDirDrainPolicyEvict, clusterv1alpha1.EmptyDirDrainPolicySkip, clusterv1alpha1.EmptyDirDrainPolicyBlock:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("emptyDir"), policy.EmptyDir, []clusterv1alpha1.EmptyDirDrainPolicy{
+			clusterv1alpha1.EmptyDirDrainPolicyEvict,
+			clusterv1alpha1.EmptyDirDrainPolicySkip,
+			clusterv1alpha1.EmptyDirDrainPolicyBlock,
+		}))
+	}
+	if policy.Timeout != nil && policy.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), policy.Timeout.Duration.String(), "must be positive"))
+	}
 	return allErrs
 }
 
 func validateMachineDeploymentStrategy(strategy *clusterv1alpha1.MachineDeploymentStrategy, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	switch strategy.Type {
-	case common.RollingUpdateMachineDeploymentStrategyType:
+	case common.RollingUpdateMachineDeploymentStrategyType, common.InPlaceMachineDeploymentStrategyType:
+		if strategy.RollingUpdate != nil {
+			allErrs = append(allErrs, validateMachineRollingUpdateDeployment(strategy.RollingUpdate, fldPath.Child("rollingUpdate"))...)
+		}
+	case common.CanaryMachineDeploymentStrategyType:
 		if strategy.RollingUpdate != nil {
 			allErrs = append(allErrs, validateMachineRollingUpdateDeployment(strategy.RollingUpdate, fldPath.Child("rollingUpdate"))...)
 		}
+		if strategy.Canary != nil {
+			allErrs = append(allErrs, validateMachineCanaryDeployment(strategy.Canary, fldPath.Child("canary"))...)
+		}
+	case common.BlueGreenMachineDeploymentStrategyType:
 	default:
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("Type"), strategy.Type, "is an invalid type"))
 	}
@@ -96,6 +132,25 @@ func validateMachineRollingUpdateDeployment(rollingUpdate *clusterv1alpha1.Machi
 	return allErrs
 }
 
+func validateMachineCanaryDeployment(canary *clusterv1alpha1.MachineCanaryDeployment, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if canary.Replicas != nil {
+		allErrs = append(allErrs, validatePositiveIntOrPercent(canary.Replicas, fldPath.Child("replicas"))...)
+		replicas, err := getIntOrPercent(canary.Replicas, true)
+		if err == nil && replicas == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), canary.Replicas, "may not be 0"))
+		}
+		// Validate that Replicas is not more than 100%.
+		if len(utilvalidation.IsValidPercent(canary.Replicas.StrVal)) == 0 && replicas > 100 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), canary.Replicas, "should not be more than 100%"))
+		}
+	}
+	if canary.SoakSeconds != nil && *canary.SoakSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("soakSeconds"), *canary.SoakSeconds, "must not be negative"))
+	}
+	return allErrs
+}
+
 func validatePositiveIntOrPercent(s *intstr.IntOrString, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	if x, err := getIntOrPercent(s, false); err != nil {
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+
+	"k8c.io/machine-controller/pkg/cloudprovider"
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig"
+	"k8c.io/machine-controller/sdk/providerconfig/configvar"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// machinePolicyTarget is a Machine or the machine template of a MachineDeployment,
+// which is checked against the MachinePolicies of its namespace.
+type machinePolicyTarget struct {
+	namespace string
+	labels    map[string]string
+	spec      clusterv1alpha1.MachineSpec
+	// replicas is nil for Machines.
+	replicas *int32
+
+	labelsPath   *field.Path
+	specPath     *field.Path
+	replicasPath *field.Path
+}
+
+// enforceMachinePolicies returns an Invalid error listing all violations of MachinePolicies with
+// the Deny enforcement action. Otherwise it returns the violations of policies with the Warn
+// enforcement action as admission warnings.
+func (ad *admissionData) enforceMachinePolicies(ctx context.Context, kind, name string, target machinePolicyTarget) ([]string, error) {
+	warnings, errs, err := ad.evaluateMachinePolicies(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, apierrors.NewInvalid(clusterv1alpha1.SchemeGroupVersion.WithKind(kind).GroupKind(), name, errs)
+	}
+	return warnings, nil
+}
+
+// evaluateMachinePolicies evaluates all MachinePolicies in the namespace of the target, which are
+// read from the worker cluster like the Machines they restrict. Violations
+// of policies with the Warn enforcement action are returned as warnings, all other violations
+// as errors.
+func (ad *admissionData) evaluateMachinePolicies(ctx context.Context, target machinePolicyTarget) ([]string, field.ErrorList, error) {
+	policies := &clusterv1alpha1.MachinePolicyList{}
+	if err := ad.workerClient.List(ctx, policies, ctrlruntimeclient.InNamespace(target.namespace)); err != nil {
+		// Without the CRD there can't be any policies.
+		if meta.IsNoMatchError(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to list MachinePolicies: %w", err)
+	}
+	if len(policies.Items) == 0 {
+		return nil, nil, nil
+	}
+
+	providerConfig, err := providerconfig.GetConfig(target.spec.ProviderSpec)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read machine.spec.providerSpec: %w", err)
+	}
+
+	var attributes *cloudprovidertypes.MachineAttributes
+	for _, policy := range policies.Items {
+		if restrictsAttributes(policy.Spec) {
+			attributes, err = ad.machineAttributes(ctx, providerConfig.CloudProvider, target.spec)
+			if err != nil {
+				return nil, nil, err
+			}
+			break
+		}
+	}
+
+	sort.Slice(policies.Items, func(i, j int) bool { return policies.Items[i].Name < policies.Items[j].Name })
+
+	var (
+		warnings []string
+		allErrs  field.ErrorList
+	)
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		errs := validateMachinePolicy(policy, providerConfig.CloudProvider, attributes, target)
+		if policy.Spec.EnforcementAction == clusterv1alpha1.PolicyEnforcementActionWarn {
+			for _, violation := range errs {
+				warnings = append(warnings, violation.Error())
+			}
+			continue
+		}
+		allErrs = append(allErrs, errs...)
+	}
+
+	return warnings, allErrs, nil
+}
+
+// machineAttributes returns the attributes of the instance of the spec, or nil if the cloud
+// provider cannot report them.
+func (ad *admissionData) machineAttributes(ctx context.Context, cloudProvider providerconfig.CloudProvider, spec clusterv1alpha1.MachineSpec) (*cloudprovidertypes.MachineAttributes, error) {
+	prov, err := cloudprovider.ForProviderWithCache(cloudProvider, configvar.NewResolver(ctx, ad.workerClient, configvar.WithSources(ad.configVarSources)), ad.validationCache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cloud provider %q: %w", cloudProvider, err)
+	}
+
+	attributesProvider, ok := prov.(cloudprovidertypes.AttributesProvider)
+	if !ok {
+		return nil, nil
+	}
+	attributes, err := attributesProvider.MachineAttributes(spec)
+	if err != nil {
+		if errors.Is(err, cloudprovidererrors.ErrNotSupported) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get machine attributes: %w", err)
+	}
+	return &attributes, nil
+}
+
+// restrictsAttributes returns whether the policy restricts attributes reported by the cloud provider.
+func restrictsAttributes(spec clusterv1alpha1.MachinePolicySpec) bool {
+	return len(spec.AllowedInstanceTypes) > 0 ||
+		len(spec.AllowedImages) > 0 ||
+		len(spec.AllowedRegions) > 0 ||
+		len(spec.AllowedZones) > 0 ||
+		len(spec.AllowedTags) > 0 ||
+		len(spec.RequiredTags) > 0
+}
+
+// validateMachinePolicy checks the target against the policy. attributes is nil if the cloud
+// provider cannot report them, in which case a policy restricting them is violated.
+func validateMachinePolicy(policy *clusterv1alpha1.MachinePolicy, cloudProvider providerconfig.CloudProvider, attributes *cloudprovidertypes.MachineAttributes, target machinePolicyTarget) field.ErrorList {
+	allErrs := field.ErrorList{}
+	providerSpecPath := target.specPath.Child("providerSpec", "value")
+	cloudProviderSpecPath := providerSpecPath.Child("cloudProviderSpec")
+
+	allErrs = append(allErrs, validateAllowedValue(policy, providerSpecPath.Child("cloudProvider"), string(cloudProvider), policy.Spec.AllowedCloudProviders)...)
+
+	if restrictsAttributes(policy.Spec) {
+		if attributes == nil {
+			allErrs = append(allErrs, field.Forbidden(cloudProviderSpecPath, fmt.Sprintf("cloud provider %q does not support the restrictions of MachinePolicy %q", cloudProvider, policy.Name)))
+		} else {
+			allErrs = append(allErrs, validateAllowedValue(policy, cloudProviderSpecPath.Child("instanceType"), attributes.InstanceType, policy.Spec.AllowedInstanceTypes)...)
+			allErrs = append(allErrs, validateAllowedValue(policy, cloudProviderSpecPath.Child("image"), attributes.Image, policy.Spec.AllowedImages)...)
+			allErrs = append(allErrs, validateAllowedValue(policy, cloudProviderSpecPath.Child("region"), attributes.Region, policy.Spec.AllowedRegions)...)
+			for i, zone := range attributes.Zones {
+				allErrs = append(allErrs, validateAllowedValue(policy, cloudProviderSpecPath.Child("zones").Index(i), zone, policy.Spec.AllowedZones)...)
+			}
+			allErrs = append(allErrs, validateTags(policy, cloudProviderSpecPath.Child("tags"), attributes.Tags)...)
+		}
+	}
+
+	for _, key := range sortedKeys(policy.Spec.RequiredLabels) {
+		expected := policy.Spec.RequiredLabels[key]
+		value, ok := target.labels[key]
+		switch {
+		case !ok:
+			allErrs = append(allErrs, field.Required(target.labelsPath.Key(key), fmt.Sprintf("required by MachinePolicy %q", policy.Name)))
+		case expected != "" && value != expected:
+			allErrs = append(allErrs, field.Invalid(target.labelsPath.Key(key), value, fmt.Sprintf("must be %q as required by MachinePolicy %q", expected, policy.Name)))
+		}
+	}
+
+	if policy.Spec.MaxReplicas != nil && target.replicas != nil && *target.replicas > *policy.Spec.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(target.replicasPath, *target.replicas, fmt.Sprintf("must not be greater than %d as required by MachinePolicy %q", *policy.Spec.MaxReplicas, policy.Name)))
+	}
+
+	return allErrs
+}
+
+// validateAllowedValue checks that the value matches one of the allowed patterns. Empty values
+// are chosen by the cloud provider at runtime and thus not restricted.
+func validateAllowedValue(policy *clusterv1alpha1.MachinePolicy, fldPath *field.Path, value string, allowed []string) field.ErrorList {
+	if value == "" || len(allowed) == 0 || matchesAny(value, allowed) {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath, value, fmt.Sprintf("must match one of %q as required by MachinePolicy %q", allowed, policy.Name))}
+}
+
+func validateTags(policy *clusterv1alpha1.MachinePolicy, fldPath *field.Path, tags map[string]string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, key := range policy.Spec.RequiredTags {
+		if _, ok := tags[key]; !ok {
+			allErrs = append(allErrs, field.Required(fldPath.Key(key), fmt.Sprintf("required by MachinePolicy %q", policy.Name)))
+		}
+	}
+
+	if len(policy.Spec.AllowedTags) == 0 {
+		return allErrs
+	}
+	for _, key := range sortedKeys(tags) {
+		allowedValues, ok := policy.Spec.AllowedTags[key]
+		if !ok {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Key(key), fmt.Sprintf("tag is not allowed by MachinePolicy %q", policy.Name)))
+			continue
+		}
+		if len(allowedValues) > 0 && !matchesAny(tags[key], allowedValues) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), tags[key], fmt.Sprintf("must match one of %q as required by MachinePolicy %q", allowedValues, policy.Name)))
+		}
+	}
+
+	return allErrs
+}
+
+// matchesAny returns whether the value matches any of the glob patterns. Invalid patterns
+// match nothing.
+func matchesAny(value string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,404 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newMachinePolicy(name string, action clusterv1alpha1.PolicyEnforcementAction, spec clusterv1alpha1.MachinePolicySpec) *clusterv1alpha1.MachinePolicy {
+	spec.EnforcementAction = action
+	return &clusterv1alpha1.MachinePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system"},
+		Spec:       spec,
+	}
+}
+
+func TestMutateMachineDeploymentsEnforcesMachinePolicies(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add types to scheme: %v", err)
+	}
+
+	withReplicas := func(md *clusterv1alpha1.MachineDeployment, replicas int32) *clusterv1alpha1.MachineDeployment {
+		md.Spec.Replicas = ptr.To(replicas)
+		return md
+	}
+	withRollback := func(md *clusterv1alpha1.MachineDeployment) *clusterv1alpha1.MachineDeployment {
+		md.Spec.RollbackTo = &clusterv1alpha1.RollbackConfig{Revision: 1}
+		return md
+	}
+	// defaulted returns the MachineDeployment as it was stored after its admission.
+	defaulted := func(md *clusterv1alpha1.MachineDeployment) *clusterv1alpha1.MachineDeployment {
+		machineDeploymentDefaultingFunction(md)
+		if err := mutationsForMachineDeployment(md); err != nil {
+			t.Fatalf("failed to mutate MachineDeployment: %v", err)
+		}
+		return md
+	}
+
+	tests := []struct {
+		name          string
+		op            admissionv1.Operation
+		newMD         *clusterv1alpha1.MachineDeployment
+		oldMD         *clusterv1alpha1.MachineDeployment
+		policies      []ctrlruntimeclient.Object
+		wantFields    []string
+		wantWarnings  int
+		wantAllowed   bool
+		wantErrSubstr string
+	}{
+		{
+			name:        "no policies",
+			op:          admissionv1.Create,
+			newMD:       mdBuild().build(t),
+			wantAllowed: true,
+		},
+		{
+			name:  "cloud provider not allowed",
+			op:    admissionv1.Create,
+			newMD: mdBuild().build(t),
+			policies: []ctrlruntimeclient.Object{
+				newMachinePolicy("providers", clusterv1alpha1.PolicyEnforcementActionDeny, clusterv1alpha1.MachinePolicySpec{AllowedCloudProviders: []string{"aws", "hetzner"}}),
+			},
+			wantFields: []string{"spec.template.spec.providerSpec.value.cloudProvider"},
+		},
+		{
+			name:  "cloud provider allowed by pattern",
+			op:    admissionv1.Create,
+			newMD: mdBuild().build(t),
+			policies: []ctrlruntimeclient.Object{
+				newMachinePolicy("providers", "", clusterv1alpha1.MachinePolicySpec{AllowedCloudProviders: []string{"fa*"}}),
+			},
+			wantAllowed: true,
+		},
+		{
+			name:  "violations of multiple policies and fields are aggregated",
+			op:    admissionv1.Create,
+			newMD: withReplicas(mdBuild().build(t), 10),
+			policies: []ctrlruntimeclient.Object{
+				newMachinePolicy("replicas", clusterv1alpha1.PolicyEnforcementActionDeny, clusterv1alpha1.MachinePolicySpec{MaxReplicas: ptr.To[int32](5)}),
+				newMachinePolicy("labels", clusterv1alpha1.PolicyEnforcementActionDeny, clusterv1alpha1.MachinePolicySpec{RequiredLabels: map[string]string{"team": "", "foo": "baz"}}),
+			},
+			wantFields: []string{
+				"spec.template.metadata.labels[foo]",
+				"spec.template.metadata.labels[team]",
+				"spec.replicas",
+			},
+		},
+		{
+			name:  "warn only",
+			op:    admissionv1.Create,
+			newMD: withReplicas(mdBuild().build(t), 10),
+			policies: []ctrlruntimeclient.Object{
+				newMachinePolicy("replicas", clusterv1alpha1.PolicyEnforcementActionWarn, clusterv1alpha1.MachinePolicySpec{MaxReplicas: ptr.To[int32](5), AllowedCloudProviders: []string{"aws"}}),
+			},
+			wantWarnings: 2,
+			wantAllowed:  true,
+		},
+		{
+			name:  "attributes the cloud provider cannot report",
+			op:    admissionv1.Create,
+			newMD: mdBuild().build(t),
+			policies: []ctrlruntimeclient.Object{
+				newMachinePolicy("sizes", clusterv1alpha1.PolicyEnforcementActionDeny, clusterv1alpha1.MachinePolicySpec{AllowedInstanceTypes: []string{"t3.*"}}),
+			},
+			wantFields: []string{"spec.template.spec.providerSpec.value.cloudProviderSpec"},
+		},
+		{
+			name:  "policies of other namespaces are ignored",
+			op:    admissionv1.Create,
+			newMD: mdBuild().build(t),
+			policies: []ctrlruntimeclient.Object{
+				&clusterv1alpha1.MachinePolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "providers", Namespace: "default"},
+					Spec:       clusterv1alpha1.MachinePolicySpec{AllowedCloudProviders: []string{"aws"}},
+				},
+			},
+			wantAllowed: true,
+		},
+		{
+			name:  "scaling up is checked",
+			op:    admissionv1.Update,
+			newMD: withReplicas(mdBuild().build(t), 10),
+			oldMD: defaulted(mdBuild().build(t)),
+			policies: []ctrlruntimeclient.Object{
+				newMachinePolicy("replicas", clusterv1alpha1.PolicyEnforcementActionDeny, clusterv1alpha1.MachinePolicySpec{MaxReplicas: ptr.To[int32](5)}),
+			},
+			wantFields: []string{"spec.replicas"},
+		},
+		{
+			name:  "rollback requests are not checked",
+			op:    admissionv1.Update,
+			newMD: withRollback(defaulted(mdBuild().build(t))),
+			oldMD: defaulted(mdBuild().build(t)),
+			policies: []ctrlruntimeclient.Object{
+				newMachinePolicy("providers", clusterv1alpha1.PolicyEnforcementActionDeny, clusterv1alpha1.MachinePolicySpec{AllowedCloudProviders: []string{"aws"}}),
+			},
+			wantAllowed: true,
+		},
+		{
+			name:  "metadata updates are not checked",
+			op:    admissionv1.Update,
+			newMD: defaulted(mdBuild().build(t)),
+			oldMD: defaulted(mdBuild().build(t)),
+			policies: []ctrlruntimeclient.Object{
+				newMachinePolicy("providers", clusterv1alpha1.PolicyEnforcementActionDeny, clusterv1alpha1.MachinePolicySpec{AllowedCloudProviders: []string{"aws"}}),
+			},
+			wantAllowed: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ad := newTestAdmissionData(t)
+			ad.workerClient = clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.policies...).Build()
+
+			resp, err := ad.mutateMachineDeployments(ctx, newAdmissionRequest(t, tc.op, tc.newMD, tc.oldMD))
+			if len(tc.wantFields) > 0 {
+				if !apierrors.IsInvalid(err) {
+					t.Fatalf("expected an Invalid error, got %v", err)
+				}
+				causes := err.(apierrors.APIStatus).Status().Details.Causes
+				if len(causes) != len(tc.wantFields) {
+					t.Fatalf("expected %d causes, got %v", len(tc.wantFields), causes)
+				}
+				for i, cause := range causes {
+					if cause.Field != tc.wantFields[i] {
+						t.Errorf("expected cause %d to be for field %q, got %q", i, tc.wantFields[i], cause.Field)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if resp.Allowed != tc.wantAllowed {
+				t.Fatalf("Allowed: want %v, got %v", tc.wantAllowed, resp.Allowed)
+			}
+			if len(resp.Warnings) != tc.wantWarnings {
+				t.Fatalf("expected %d warnings, got %v", tc.wantWarnings, resp.Warnings)
+			}
+			for _, warning := range resp.Warnings {
+				if !strings.Contains(warning, `MachinePolicy "replicas"`) {
+					t.Errorf("expected warning to name the policy, got %q", warning)
+				}
+			}
+		})
+	}
+}
+
+func TestMutateMachinesSkipsMachinePoliciesForMachineSetMachines(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add types to scheme: %v", err)
+	}
+	policy := newMachinePolicy("providers", clusterv1alpha1.PolicyEnforcementActionDeny, clusterv1alpha1.MachinePolicySpec{AllowedCloudProviders: []string{"aws"}})
+
+	newMachine := func(owners ...metav1.OwnerReference) *clusterv1alpha1.Machine {
+		return &clusterv1alpha1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "kube-system", OwnerReferences: owners},
+			Spec:       machineSpecWithProviderConfig(t, providerconfig.CloudProviderFake, []byte(`{"passValidation":true}`)),
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		machine   *clusterv1alpha1.Machine
+		expectErr bool
+	}{
+		{
+			name:      "standalone machine is checked",
+			machine:   newMachine(),
+			expectErr: true,
+		},
+		{
+			name: "machine of a MachineSet is not checked",
+			machine: newMachine(metav1.OwnerReference{
+				APIVersion: "cluster.k8s.io/v1alpha1",
+				Kind:       "MachineSet",
+				Name:       "ms",
+				UID:        "ms-uid",
+				Controller: ptr.To(true),
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ad := newTestAdmissionData(t)
+			ad.workerClient = clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build()
+
+			raw, err := json.Marshal(tc.machine)
+			if err != nil {
+				t.Fatalf("failed to marshal machine: %v", err)
+			}
+			_, err = ad.mutateMachines(context.Background(), admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			if tc.expectErr != apierrors.IsInvalid(err) {
+				t.Errorf("expected an Invalid error: %t, got %v", tc.expectErr, err)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateMachinePolicyAttributes(t *testing.T) {
+	policy := newMachinePolicy("attributes", "", clusterv1alpha1.MachinePolicySpec{
+		AllowedInstanceTypes: []string{"t3.*", "m5.large"},
+		AllowedImages:        []string{"ami-*"},
+		AllowedRegions:       []string{"eu-*"},
+		AllowedZones:         []string{"eu-central-1a", "eu-central-1b"},
+		AllowedTags:          map[string][]string{"team": nil, "env": {"dev", "prod"}},
+		RequiredTags:         []string{"team"},
+	})
+	target := machinePolicyTarget{specPath: field.NewPath("spec")}
+
+	tests := []struct {
+		name       string
+		attributes cloudprovidertypes.MachineAttributes
+		wantFields []string
+	}{
+		{
+			name: "allowed",
+			attributes: cloudprovidertypes.MachineAttributes{
+				InstanceType: "t3.medium",
+				Image:        "ami-123",
+				Region:       "eu-central-1",
+				Zones:        []string{"eu-central-1a"},
+				Tags:         map[string]string{"team": "a", "env": "dev"},
+			},
+		},
+		{
+			name: "values chosen at runtime are not restricted",
+			attributes: cloudprovidertypes.MachineAttributes{
+				Tags: map[string]string{"team": "a"},
+			},
+		},
+		{
+			name: "not allowed",
+			attributes: cloudprovidertypes.MachineAttributes{
+				InstanceType: "m5.xlarge",
+				Image:        "custom",
+				Region:       "us-east-1",
+				Zones:        []string{"eu-central-1a", "eu-central-1c"},
+				Tags:         map[string]string{"env": "staging", "owner": "b"},
+			},
+			wantFields: []string{
+				"spec.providerSpec.value.cloudProviderSpec.instanceType",
+				"spec.providerSpec.value.cloudProviderSpec.image",
+				"spec.providerSpec.value.cloudProviderSpec.region",
+				"spec.providerSpec.value.cloudProviderSpec.zones[1]",
+				"spec.providerSpec.value.cloudProviderSpec.tags[team]",
+				"spec.providerSpec.value.cloudProviderSpec.tags[env]",
+				"spec.providerSpec.value.cloudProviderSpec.tags[owner]",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateMachinePolicy(policy, providerconfig.CloudProviderAWS, &tc.attributes, target)
+			if len(errs) != len(tc.wantFields) {
+				t.Fatalf("expected %d errors, got %v", len(tc.wantFields), errs)
+			}
+			for i, err := range errs {
+				if err.Field != tc.wantFields[i] {
+					t.Errorf("expected error %d to be for field %q, got %q", i, tc.wantFields[i], err.Field)
+				}
+			}
+		})
+	}
+}
+
+func TestMutateMachinesReadsMachinePoliciesFromWorkerCluster(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add types to scheme: %v", err)
+	}
+	policy := newMachinePolicy("providers", clusterv1alpha1.PolicyEnforcementActionDeny, clusterv1alpha1.MachinePolicySpec{AllowedCloudProviders: []string{"aws"}})
+
+	raw, err := json.Marshal(&clusterv1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "kube-system"},
+		Spec:       machineSpecWithProviderConfig(t, providerconfig.CloudProviderFake, []byte(`{"passValidation":true}`)),
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal machine: %v", err)
+	}
+	request := admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+
+	testCases := []struct {
+		name         string
+		client       ctrlruntimeclient.Client
+		workerClient ctrlruntimeclient.Client
+		expectErr    bool
+	}{
+		{
+			name:         "policy in the worker cluster is enforced",
+			client:       clientfake.NewClientBuilder().WithScheme(scheme).Build(),
+			workerClient: clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build(),
+			expectErr:    true,
+		},
+		{
+			name:         "policy in the cluster of the webhook is ignored",
+			client:       clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build(),
+			workerClient: clientfake.NewClientBuilder().WithScheme(scheme).Build(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ad := newTestAdmissionData(t)
+			ad.client = tc.client
+			ad.workerClient = tc.workerClient
+
+			_, err := ad.mutateMachines(context.Background(), request)
+			if tc.expectErr != apierrors.IsInvalid(err) {
+				t.Errorf("expected an Invalid error: %t, got %v", tc.expectErr, err)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
@@ -34,6 +34,10 @@ import (
 
 	admissionv1 "k8s.io/api/admission/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -42,6 +46,36 @@ import (
 // the `providerConfig` field to `providerSpec`.
 const BypassSpecNoModificationRequirementAnnotation = "kubermatic.io/bypass-no-spec-mutation-requirement"
 
+// updatedInPlace returns true if the machine is controlled by a MachineSet of a MachineDeployment
+// using the InPlace strategy. Both are read from the worker cluster.
+func (ad *admissionData) updatedInPlace(ctx context.Context, machine *clusterv1alpha1.Machine) (bool, error) {
+	msRef := metav1.GetControllerOf(machine)
+	if msRef == nil || msRef.Kind != "MachineSet" {
+		return false, nil
+	}
+	ms := &clusterv1alpha1.MachineSet{}
+	if err := ad.workerClient.Get(ctx, types.NamespacedName{Namespace: machine.Namespace, Name: msRef.Name}, ms); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get MachineSet %s: %w", msRef.Name, err)
+	}
+
+	mdRef := metav1.GetControllerOf(ms)
+	if mdRef == nil || mdRef.Kind != "MachineDeployment" {
+		return false, nil
+	}
+	md := &clusterv1alpha1.MachineDeployment{}
+	if err := ad.workerClient.Get(ctx, types.NamespacedName{Namespace: machine.Namespace, Name: mdRef.Name}, md); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get MachineDeployment %s: %w", mdRef.Name, err)
+	}
+
+	return md.Spec.Strategy != nil && md.Spec.Strategy.Type == common.InPlaceMachineDeploymentStrategyType, nil
+}
+
 func (ad *admissionData) mutateMachines(ctx context.Context, ar admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error) {
 	machine := clusterv1alpha1.Machine{}
 	if err := json.Unmarshal(ar.Object.Raw, &machine); err != nil {
@@ -75,6 +109,20 @@ func (ad *admissionData) mutateMachines(ctx context.Context, ar admissionv1.Admi
 			oldMachine.Spec.ProviderID = machine.Spec.ProviderID
 		}
 
+		// Allow mutation of the Node labels, annotations and taints if the machine belongs to a
+		// MachineDeployment using the InPlace strategy, which updates them in place.
+		if !apiequality.Semantic.DeepEqual(oldMachine.Spec.ObjectMeta, machine.Spec.ObjectMeta) || !apiequality.Semantic.DeepEqual(oldMachine.Spec.Taints, machine.Spec.Taints) {
+			inPlace, err := ad.updatedInPlace(ctx, &machine)
+			if err != nil {
+				return nil, err
+			}
+			if inPlace {
+				oldMachine.Spec.Labels = machine.Spec.Labels
+				oldMachine.Spec.Annotations = machine.Spec.Annotations
+				oldMachine.Spec.Taints = machine.Spec.Taints
+			}
+		}
+
 		// Allow mutation when:
 		// * machine has the `MigrationBypassSpecNoModificationRequirementAnnotation` annotation (used for type migration)
 		bypassValidationForMigration := machine.Annotations[BypassSpecNoModificationRequirementAnnotation] == "true"
@@ -94,11 +142,29 @@ func (ad *admissionData) mutateMachines(ctx context.Context, ar admissionv1.Admi
 
 	// Default and verify .Spec on CREATE only, its expensive and not required to do it on UPDATE
 	// as we disallow .Spec changes anyways.
+	var warnings []string
 	if ar.Operation == admissionv1.Create {
 		if err := ad.defaultAndValidateMachineSpec(ctx, &machine.Spec); err != nil {
 			return nil, err
 		}
 
+		// Machines of a MachineSet are created from its template, which the MachinePolicies were
+		// already checked against when the MachineDeployment was admitted. Checking them again
+		// would let policies created later on block the scaling and rollout of existing ones.
+		if owner := metav1.GetControllerOf(&machine); owner == nil || owner.Kind != "MachineSet" {
+			var err error
+			warnings, err = ad.enforceMachinePolicies(ctx, "Machine", machine.Name, machinePolicyTarget{
+				namespace:  machine.Namespace,
+				labels:     machine.Labels,
+				spec:       machine.Spec,
+				labelsPath: field.NewPath("metadata", "labels"),
+				specPath:   field.NewPath("spec"),
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		common.SetKubeletFeatureGates(&machine, ad.nodeSettings.KubeletFeatureGates)
 		common.SetKubeletFlags(&machine, map[common.KubeletFlags]string{
 			common.ExternalCloudProviderKubeletFlag: fmt.Sprintf("%t", ad.nodeSettings.ExternalCloudProvider),
@@ -114,7 +180,13 @@ func (ad *admissionData) mutateMachines(ctx context.Context, ar admissionv1.Admi
 		machine.Labels = make(map[string]string)
 	}
 
-	return createAdmissionResponse(log, machineOriginal, &machine)
+	response, err := createAdmissionResponse(log, machineOriginal, &machine)
+	if err != nil {
+		return nil, err
+	}
+	response.Warnings = warnings
+
+	return response, nil
 }
 
 func (ad *admissionData) defaultAndValidateMachineSpec(ctx context.Context, spec *clusterv1alpha1.MachineSpec) error {
@@ -138,8 +210,8 @@ func (ad *admissionData) defaultAndValidateMachineSpec(ctx context.Context, spec
 		}
 	}
 
-	configResolver := configvar.NewResolver(ctx, ad.workerClient)
-	prov, err := cloudprovider.ForProvider(providerConfig.CloudProvider, configResolver)
+	configResolver := configvar.NewResolver(ctx, ad.workerClient, configvar.WithSources(ad.configVarSources))
+	prov, err := cloudprovider.ForProviderWithCache(providerConfig.CloudProvider, configResolver, ad.validationCache)
 	if err != nil {
 		return fmt.Errorf("failed to get cloud provider %q: %w", providerConfig.CloudProvider, err)
 	}
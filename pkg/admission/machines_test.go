@@ -26,11 +26,17 @@ import (
 	"testing"
 
 	"k8c.io/machine-controller/pkg/cloudprovider/provider/fake"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	"k8c.io/machine-controller/sdk/providerconfig"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 const (
@@ -247,3 +253,90 @@ func TestValidatePublicKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestMutateMachinesAllowsInPlaceFieldsOnlyForInPlaceMachineDeployments(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add types to scheme: %v", err)
+	}
+
+	machineDeployment := func(strategy common.MachineDeploymentStrategyType) *clusterv1alpha1.MachineDeployment {
+		return &clusterv1alpha1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "md", Namespace: "kube-system", UID: "md-uid"},
+			Spec: clusterv1alpha1.MachineDeploymentSpec{
+				Strategy: &clusterv1alpha1.MachineDeploymentStrategy{Type: strategy},
+			},
+		}
+	}
+	machineSet := &clusterv1alpha1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "ms",
+			Namespace:       "kube-system",
+			UID:             "ms-uid",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "cluster.k8s.io/v1alpha1", Kind: "MachineDeployment", Name: "md", UID: "md-uid", Controller: ptr.To(true)}},
+		},
+	}
+	machine := func(labels map[string]string, owned bool) *clusterv1alpha1.Machine {
+		m := &clusterv1alpha1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "kube-system"},
+			Spec:       machineSpecWithProviderConfig(t, providerconfig.CloudProviderFake, []byte("{}")),
+		}
+		m.Spec.Name = m.Name
+		m.Spec.Labels = labels
+		if owned {
+			m.OwnerReferences = []metav1.OwnerReference{{APIVersion: "cluster.k8s.io/v1alpha1", Kind: "MachineSet", Name: "ms", UID: "ms-uid", Controller: ptr.To(true)}}
+		}
+		return m
+	}
+
+	testCases := []struct {
+		name      string
+		objects   []ctrlruntimeclient.Object
+		owned     bool
+		expectErr bool
+	}{
+		{
+			name:      "machine without MachineSet",
+			expectErr: true,
+		},
+		{
+			name:      "MachineDeployment using RollingUpdate",
+			objects:   []ctrlruntimeclient.Object{machineSet, machineDeployment(common.RollingUpdateMachineDeploymentStrategyType)},
+			owned:     true,
+			expectErr: true,
+		},
+		{
+			name:    "MachineDeployment using InPlace",
+			objects: []ctrlruntimeclient.Object{machineSet, machineDeployment(common.InPlaceMachineDeploymentStrategyType)},
+			owned:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ad := newTestAdmissionData(t)
+			// The MachineSets and MachineDeployments live in the worker cluster, not in the
+			// cluster of the webhook.
+			ad.client = clientfake.NewClientBuilder().WithScheme(scheme).Build()
+			ad.workerClient = clientfake.NewClientBuilder().WithScheme(scheme).WithObjects(tc.objects...).Build()
+
+			oldRaw, err := json.Marshal(machine(map[string]string{"a": "1"}, tc.owned))
+			if err != nil {
+				t.Fatalf("failed to marshal old machine: %v", err)
+			}
+			newRaw, err := json.Marshal(machine(map[string]string{"a": "2"}, tc.owned))
+			if err != nil {
+				t.Fatalf("failed to marshal machine: %v", err)
+			}
+
+			_, err = ad.mutateMachines(context.Background(), admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    runtime.RawExtension{Raw: newRaw},
+				OldObject: runtime.RawExtension{Raw: oldRaw},
+			})
+			if (err != nil) != tc.expectErr {
+				t.Errorf("expected error to be %t, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
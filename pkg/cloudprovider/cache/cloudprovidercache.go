@@ -17,70 +17,195 @@ limitations under the License.
 package cache
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
-	gocache "github.com/patrickmn/go-cache"
-
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/lru"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// DefaultTTL is the time a validation result is cached by default.
+	DefaultTTL = 5 * time.Minute
+	// DefaultSize is the number of validation results cached by default.
+	DefaultSize = 1024
+)
+
+// Options configure a CloudproviderCache.
+type Options struct {
+	// TTL is the time a validation result is cached. Defaults to DefaultTTL.
+	TTL time.Duration
+	// Size is the maximum number of cached validation results. When it is reached,
+	// the least recently used result is evicted. Defaults to DefaultSize.
+	Size int
+	// Client is used to get the resourceVersions of the Secrets and ConfigMaps
+	// referenced in provider specs, so that results are not reused once any of them
+	// changed. If nil, referenced objects are not considered.
+	Client ctrlruntimeclient.Reader
+	// Metrics are updated on every cache lookup, if set.
+	Metrics *Metrics
+}
+
 type CloudproviderCache struct {
-	cache *gocache.Cache
+	// lock makes checking for a full cache and adding an entry atomic, so
+	// evictions can be counted.
+	lock    sync.Mutex
+	entries *lru.Cache
+	ttl     time.Duration
+	size    int
+	client  ctrlruntimeclient.Reader
+	metrics *Metrics
+	now     func() time.Time
+}
+
+type entry struct {
+	val     error
+	expires time.Time
 }
 
-// New returns a new cloudproviderCache.
+// New returns a new cloudproviderCache with the default options.
 func New() *CloudproviderCache {
-	return &CloudproviderCache{cache: gocache.New(5*time.Minute, 5*time.Minute)}
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions returns a new cloudproviderCache with the given options.
+func NewWithOptions(opts Options) *CloudproviderCache {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.Size <= 0 {
+		opts.Size = DefaultSize
+	}
+
+	return &CloudproviderCache{
+		entries: lru.New(opts.Size),
+		ttl:     opts.TTL,
+		size:    opts.Size,
+		client:  opts.Client,
+		metrics: opts.Metrics,
+		now:     time.Now,
+	}
 }
 
 // Get returns an error indicating the result of the validation and a boolean indicating if
 // it got a cache hit or miss.
-func (c *CloudproviderCache) Get(machineSpec clusterv1alpha1.MachineSpec) (error, bool, error) {
-	id, err := getID(machineSpec)
+func (c *CloudproviderCache) Get(ctx context.Context, machineSpec clusterv1alpha1.MachineSpec) (error, bool, error) {
+	id, err := c.getID(ctx, machineSpec)
 	if err != nil {
 		return nil, false, err
 	}
 
-	val, found := c.cache.Get(id)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	val, found := c.entries.Get(id)
 	if !found {
+		c.metrics.miss()
 		return nil, false, nil
 	}
 
-	if val == nil {
-		return nil, true, nil
-	}
-
-	errVal, castable := val.(error)
+	e, castable := val.(entry)
 	if !castable {
-		return nil, false, fmt.Errorf("failed to cast val to err: %w", err)
+		return nil, false, fmt.Errorf("failed to cast cache value of type %T to entry", val)
 	}
-	return errVal, true, nil
+	if !c.now().Before(e.expires) {
+		c.entries.Remove(id)
+		c.metrics.evict(EvictionReasonExpired)
+		c.metrics.miss()
+		return nil, false, nil
+	}
+
+	c.metrics.hit()
+	return e.val, true, nil
 }
 
 // Set sets the passed value for the given machineSpec.
-func (c *CloudproviderCache) Set(machineSpec clusterv1alpha1.MachineSpec, val error) error {
-	id, err := getID(machineSpec)
+func (c *CloudproviderCache) Set(ctx context.Context, machineSpec clusterv1alpha1.MachineSpec, val error) error {
+	id, err := c.getID(ctx, machineSpec)
 	if err != nil {
 		return err
 	}
 
-	c.cache.Set(id, val, gocache.DefaultExpiration)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, exists := c.entries.Get(id); !exists && c.entries.Len() >= c.size {
+		c.metrics.evict(EvictionReasonSize)
+	}
+	c.entries.Add(id, entry{val: val, expires: c.now().Add(c.ttl)})
 	return nil
 }
 
-func getID(machineSpec clusterv1alpha1.MachineSpec) (string, error) {
+// Flush removes all cached validation results.
+func (c *CloudproviderCache) Flush() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.metrics.flush(c.entries.Len())
+	c.entries.Clear()
+}
+
+// Len returns the number of cached validation results, including expired ones.
+func (c *CloudproviderCache) Len() int {
+	return c.entries.Len()
+}
+
+func (c *CloudproviderCache) getID(ctx context.Context, machineSpec clusterv1alpha1.MachineSpec) (string, error) {
 	b, err := json.Marshal(machineSpec.ProviderSpec)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal MachineSpec: %w", err)
 	}
 
-	sum := sha256.Sum256(b)
-	var sumSlice []byte
-	for _, b := range sum {
-		sumSlice = append(sumSlice, b)
+	h := sha256.New()
+	h.Write(b)
+
+	versions, err := c.referencedResourceVersions(ctx, machineSpec)
+	if err != nil {
+		return "", err
+	}
+	for _, version := range versions {
+		h.Write([]byte(version))
 	}
-	return string(sumSlice), nil
+
+	return string(h.Sum(nil)), nil
+}
+
+// referencedResourceVersions returns the resourceVersions of all Secrets and ConfigMaps
+// referenced by the provider spec, in the form "kind/namespace/name=resourceVersion".
+func (c *CloudproviderCache) referencedResourceVersions(ctx context.Context, machineSpec clusterv1alpha1.MachineSpec) ([]string, error) {
+	if c.client == nil || machineSpec.ProviderSpec.Value == nil || len(machineSpec.ProviderSpec.Value.Raw) == 0 {
+		return nil, nil
+	}
+
+	var providerSpec interface{}
+	if err := json.Unmarshal(machineSpec.ProviderSpec.Value.Raw, &providerSpec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal providerSpec: %w", err)
+	}
+
+	refs := map[objectReference]struct{}{}
+	collectReferences(providerSpec, refs)
+
+	var versions []string
+	for ref := range refs {
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(ref.kind))
+		if err := c.client.Get(ctx, types.NamespacedName{Namespace: ref.namespace, Name: ref.name}, obj); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get %s %s/%s: %w", ref.kind, ref.namespace, ref.name, err)
+		}
+		versions = append(versions, fmt.Sprintf("%s/%s/%s=%s", ref.kind, ref.namespace, ref.name, obj.ResourceVersion))
+	}
+	sort.Strings(versions)
+
+	return versions, nil
 }
@@ -17,16 +17,23 @@ limitations under the License.
 package cache
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestCloudproviderCache(t *testing.T) {
+	ctx := context.Background()
 	cache := New()
 
 	m1 := clusterv1alpha1.MachineSpec{}
@@ -34,10 +41,10 @@ func TestCloudproviderCache(t *testing.T) {
 	m1.Name = "hans"
 
 	// Test SET and GET
-	if err := cache.Set(m1, nil); err != nil {
+	if err := cache.Set(ctx, m1, nil); err != nil {
 		t.Fatalf("Error setting cache value for m1: %v", err)
 	}
-	val, exists, err := cache.Get(m1)
+	val, exists, err := cache.Get(ctx, m1)
 	if err != nil {
 		t.Fatalf("Error when getting m1 from cache: %v", err)
 	}
@@ -50,7 +57,7 @@ func TestCloudproviderCache(t *testing.T) {
 
 	// Test metadata gets ignored by cache
 	m1.Name = "wurst"
-	val, exists, err = cache.Get(m1)
+	val, exists, err = cache.Get(ctx, m1)
 	if err != nil {
 		t.Fatalf("Error getting m1 from cache after changing name: %v", err)
 	}
@@ -63,7 +70,7 @@ func TestCloudproviderCache(t *testing.T) {
 
 	// Test taints get ignored by cache
 	m1.Taints = []corev1.Taint{{Key: "hello", Value: "world"}}
-	val, exists, err = cache.Get(m1)
+	val, exists, err = cache.Get(ctx, m1)
 	if err != nil {
 		t.Fatalf("Error getting m1 from cache after adding taint: %v", err)
 	}
@@ -76,7 +83,7 @@ func TestCloudproviderCache(t *testing.T) {
 
 	// Test versions field gets ignored by cache
 	m1.Versions.Kubelet = "1.13.0"
-	val, exists, err = cache.Get(m1)
+	val, exists, err = cache.Get(ctx, m1)
 	if err != nil {
 		t.Fatalf("Error getting m1 from cache after adding kubelet version: %v", err)
 	}
@@ -90,7 +97,7 @@ func TestCloudproviderCache(t *testing.T) {
 	// Test ProviderSpec does not get ignored by cache
 	m2 := clusterv1alpha1.MachineSpec{}
 	m2.ProviderSpec.Value = &runtime.RawExtension{Raw: []byte(`{"key":"m2"}`)}
-	val, exists, err = cache.Get(m2)
+	val, exists, err = cache.Get(ctx, m2)
 	if err != nil {
 		t.Fatalf("Error getting m2 from cache: %v", err)
 	}
@@ -105,10 +112,10 @@ func TestCloudproviderCache(t *testing.T) {
 	m3 := clusterv1alpha1.MachineSpec{}
 	m3.ProviderSpec.Value = &runtime.RawExtension{Raw: []byte(`{"key":"m3"}`)}
 	errMsg := "Thou shall not pass"
-	if err := cache.Set(m3, errors.New(errMsg)); err != nil {
+	if err := cache.Set(ctx, m3, errors.New(errMsg)); err != nil {
 		t.Fatalf("Error setting cache value for m3: %v", err)
 	}
-	val, exists, err = cache.Get(m3)
+	val, exists, err = cache.Get(ctx, m3)
 	if err != nil {
 		t.Fatalf("Error getting m3 from cache: %v", err)
 	}
@@ -119,3 +126,105 @@ func TestCloudproviderCache(t *testing.T) {
 		t.Errorf("Expected val for m3 to be %s but was %v", errMsg, val)
 	}
 }
+
+func specWithKey(key string) clusterv1alpha1.MachineSpec {
+	spec := clusterv1alpha1.MachineSpec{}
+	spec.ProviderSpec.Value = &runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"key":%q}`, key))}
+	return spec
+}
+
+func TestCloudproviderCacheSize(t *testing.T) {
+	ctx := context.Background()
+	metrics := NewMetrics()
+	cache := NewWithOptions(Options{Size: 2, Metrics: metrics})
+
+	for _, key := range []string{"m1", "m2", "m3"} {
+		if err := cache.Set(ctx, specWithKey(key), nil); err != nil {
+			t.Fatalf("Error setting cache value for %s: %v", key, err)
+		}
+	}
+
+	if cache.Len() != 2 {
+		t.Errorf("Expected cache to hold 2 values but it holds %d", cache.Len())
+	}
+	if _, exists, _ := cache.Get(ctx, specWithKey("m1")); exists {
+		t.Error("Expected least recently used m1 to be evicted")
+	}
+	if _, exists, _ := cache.Get(ctx, specWithKey("m3")); !exists {
+		t.Error("Expected m3 to exist")
+	}
+}
+
+func TestCloudproviderCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := NewWithOptions(Options{TTL: time.Minute})
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	if err := cache.Set(ctx, specWithKey("m1"), nil); err != nil {
+		t.Fatalf("Error setting cache value for m1: %v", err)
+	}
+	if _, exists, _ := cache.Get(ctx, specWithKey("m1")); !exists {
+		t.Error("Expected m1 to exist before its TTL passed")
+	}
+
+	now = now.Add(time.Minute)
+	if _, exists, _ := cache.Get(ctx, specWithKey("m1")); exists {
+		t.Error("Expected m1 to not exist after its TTL passed")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected expired m1 to be removed but cache holds %d values", cache.Len())
+	}
+}
+
+func TestCloudproviderCacheReferencedObjects(t *testing.T) {
+	ctx := context.Background()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: metav1.NamespaceSystem},
+		Data:       map[string][]byte{"token": []byte("old")},
+	}
+	client := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(secret).
+		Build()
+	cache := NewWithOptions(Options{Client: client})
+
+	spec := clusterv1alpha1.MachineSpec{}
+	spec.ProviderSpec.Value = &runtime.RawExtension{Raw: []byte(`{"cloudProviderSpec":{"token":{"secretKeyRef":{"namespace":"kube-system","name":"credentials","key":"token"}}}}`)}
+
+	if err := cache.Set(ctx, spec, errors.New("invalid token")); err != nil {
+		t.Fatalf("Error setting cache value: %v", err)
+	}
+	if _, exists, _ := cache.Get(ctx, spec); !exists {
+		t.Error("Expected val to exist before the secret changed")
+	}
+
+	secret.Data["token"] = []byte("new")
+	if err := client.Update(ctx, secret); err != nil {
+		t.Fatalf("Error updating secret: %v", err)
+	}
+
+	val, exists, err := cache.Get(ctx, spec)
+	if err != nil {
+		t.Fatalf("Error getting val after the secret changed: %v", err)
+	}
+	if exists {
+		t.Errorf("Expected val to not exist after the secret changed but got %v", val)
+	}
+}
+
+func TestCloudproviderCacheFlush(t *testing.T) {
+	ctx := context.Background()
+	cache := New()
+
+	if err := cache.Set(ctx, specWithKey("m1"), nil); err != nil {
+		t.Fatalf("Error setting cache value for m1: %v", err)
+	}
+	cache.Flush()
+
+	if _, exists, _ := cache.Get(ctx, specWithKey("m1")); exists {
+		t.Error("Expected m1 to not exist after flushing the cache")
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// FlushOnConfigMapChange flushes the cache whenever the given ConfigMap is created or changed,
+// until the context is done. As every replica of the webhook watches the ConfigMap, this
+// flushes the caches of all replicas, e.g. after permissions or quotas at the cloud provider
+// were changed:
+//
+//	kubectl -n <namespace> annotate configmap <name> flushed-at="$(date +%s)" --overwrite
+func (c *CloudproviderCache) FlushOnConfigMapChange(ctx context.Context, log *zap.SugaredLogger, client kubernetes.Interface, namespace, name string) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}),
+	)
+
+	flush := func(obj interface{}) {
+		configMap, ok := obj.(*corev1.ConfigMap)
+		if !ok || configMap.Name != name {
+			return
+		}
+		log.Infow("Flushing validation cache", "configmap", fmt.Sprintf("%s/%s", namespace, name), "resourceVersion", configMap.ResourceVersion)
+		c.Flush()
+	}
+
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: flush,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// Relisting the ConfigMap after the watch expired does not change it.
+			oldConfigMap, oldOK := oldObj.(*corev1.ConfigMap)
+			newConfigMap, newOK := newObj.(*corev1.ConfigMap)
+			if oldOK && newOK && oldConfigMap.ResourceVersion != newConfigMap.ResourceVersion {
+				flush(newConfigMap)
+			}
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to watch ConfigMap %s/%s: %w", namespace, name, err)
+	}
+
+	factory.Start(ctx.Done())
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFlushOnConfigMapChange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := New()
+	client := kubefake.NewClientset()
+	if err := cache.FlushOnConfigMapChange(ctx, zap.NewNop().Sugar(), client, "kube-system", "flush"); err != nil {
+		t.Fatalf("failed to watch ConfigMap: %v", err)
+	}
+
+	waitForFlush := func(action string) {
+		t.Helper()
+		err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+			_, exists, err := cache.Get(ctx, specWithKey("m1"))
+			return !exists, err
+		})
+		if err != nil {
+			t.Fatalf("expected cache to be flushed after the ConfigMap was %s: %v", action, err)
+		}
+	}
+
+	if err := cache.Set(ctx, specWithKey("m1"), nil); err != nil {
+		t.Fatalf("Error setting cache value for m1: %v", err)
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "flush", ResourceVersion: "1"}}
+	if _, err := client.CoreV1().ConfigMaps("kube-system").Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create ConfigMap: %v", err)
+	}
+	waitForFlush("created")
+
+	if err := cache.Set(ctx, specWithKey("m1"), nil); err != nil {
+		t.Fatalf("Error setting cache value for m1: %v", err)
+	}
+	configMap.Annotations = map[string]string{"flushed-at": "now"}
+	configMap.ResourceVersion = "2"
+	if _, err := client.CoreV1().ConfigMaps("kube-system").Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update ConfigMap: %v", err)
+	}
+	waitForFlush("updated")
+}
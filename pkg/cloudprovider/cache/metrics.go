@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsPrefix = "machine_controller_validation_cache_"
+
+const (
+	// EvictionReasonSize is used for results evicted because the cache was full.
+	EvictionReasonSize = "size"
+	// EvictionReasonExpired is used for results evicted because their TTL passed.
+	EvictionReasonExpired = "expired"
+	// EvictionReasonFlush is used for results evicted by flushing the cache.
+	EvictionReasonFlush = "flush"
+)
+
+// Metrics is a struct of all metrics used by the validation cache.
+type Metrics struct {
+	Hits      prometheus.Counter
+	Misses    prometheus.Counter
+	Evictions *prometheus.CounterVec
+}
+
+// NewMetrics creates new Metrics for the validation cache.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		Hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: metricsPrefix + "hits_total",
+			Help: "The total number of validations answered from the cache",
+		}),
+		Misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: metricsPrefix + "misses_total",
+			Help: "The total number of validations not found in the cache",
+		}),
+		Evictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metricsPrefix + "evictions_total",
+			Help: "The total number of validation results removed from the cache",
+		}, []string{"reason"}),
+	}
+}
+
+// MustRegister registers all metrics with the given registerer.
+func (m *Metrics) MustRegister(registerer prometheus.Registerer) {
+	registerer.MustRegister(
+		m.Hits,
+		m.Misses,
+		m.Evictions,
+	)
+}
+
+func (m *Metrics) hit() {
+	if m != nil {
+		m.Hits.Inc()
+	}
+}
+
+func (m *Metrics) miss() {
+	if m != nil {
+		m.Misses.Inc()
+	}
+}
+
+func (m *Metrics) evict(reason string) {
+	if m != nil {
+		m.Evictions.WithLabelValues(reason).Inc()
+	}
+}
+
+func (m *Metrics) flush(entries int) {
+	if m != nil {
+		m.Evictions.WithLabelValues(EvictionReasonFlush).Add(float64(entries))
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+// referenceKinds maps the fields used by providerconfig.ConfigVarString and its
+// siblings to the kind of the object they reference.
+var referenceKinds = map[string]string{
+	"secretKeyRef":    "Secret",
+	"configMapKeyRef": "ConfigMap",
+}
+
+type objectReference struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// collectReferences walks the unmarshalled provider spec and adds all Secrets and
+// ConfigMaps referenced by it to refs.
+func collectReferences(val interface{}, refs map[objectReference]struct{}) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for field, fieldVal := range v {
+			if kind, ok := referenceKinds[field]; ok {
+				if ref, ok := fieldVal.(map[string]interface{}); ok {
+					namespace, _ := ref["namespace"].(string)
+					name, _ := ref["name"].(string)
+					if name != "" {
+						refs[objectReference{kind: kind, namespace: namespace, name: name}] = struct{}{}
+					}
+					continue
+				}
+			}
+			collectReferences(fieldVal, refs)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectReferences(item, refs)
+		}
+	}
+}
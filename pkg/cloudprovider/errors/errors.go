@@ -19,6 +19,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"k8c.io/machine-controller/sdk/apis/cluster/common"
 )
@@ -26,6 +27,9 @@ import (
 var (
 	// ErrInstanceNotFound tells that the requested instance was not found on the cloud provider.
 	ErrInstanceNotFound = errors.New("instance not found")
+
+	// ErrNotSupported tells that the cloud provider does not support the requested operation.
+	ErrNotSupported = errors.New("operation not supported by cloud provider")
 )
 
 func IsNotFound(err error) bool {
@@ -50,3 +54,24 @@ func IsTerminalError(err error) (bool, common.MachineStatusError, string) {
 	}
 	return true, tError.Reason, tError.Message
 }
+
+// RateLimitedError tells that a call to the cloud provider was not made, because the
+// request budget of the account was used up. The call should be retried after RetryAfter.
+type RateLimitedError struct {
+	Operation  string
+	RetryAfter time.Duration
+}
+
+func (re RateLimitedError) Error() string {
+	return fmt.Sprintf("%s call to cloud provider is rate limited, retry after %v", re.Operation, re.RetryAfter)
+}
+
+// IsRateLimitedError returns the time after which a rate limited call should be retried, if
+// the given error is a RateLimitedError.
+func IsRateLimitedError(err error) (bool, time.Duration) {
+	var rlError RateLimitedError
+	if !errors.As(err, &rlError) {
+		return false, 0
+	}
+	return true, rlError.RetryAfter
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/pkg/tracing"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type instrumentedWrapper struct {
+	OptionalInterfacesForwarder
+	providerName providerconfig.CloudProvider
+	metrics      *Metrics
+}
+
+// NewInstrumentedCloudProvider returns a wrapped cloudprovider, which records the duration
+// and the errors of all calls to the actual provider. Calls which get a context are traced.
+func NewInstrumentedCloudProvider(actualProvider cloudprovidertypes.Provider, providerName providerconfig.CloudProvider, metrics *Metrics) cloudprovidertypes.Provider {
+	return &instrumentedWrapper{OptionalInterfacesForwarder: NewOptionalInterfacesForwarder(actualProvider), providerName: providerName, metrics: metrics}
+}
+
+// failed returns whether the call failed with err. A missing instance or an unsupported
+// operation are expected results rather than failures of the call.
+func failed(err error) bool {
+	return err != nil && !cloudprovidererrors.IsNotFound(err) && !errors.Is(err, cloudprovidererrors.ErrNotSupported)
+}
+
+// startSpan starts a span for a call of the operation.
+func (w *instrumentedWrapper) startSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "cloudprovider."+operation, trace.WithAttributes(attribute.String("provider", string(w.providerName))))
+}
+
+// finish ends the span of a call of the operation and observes the call.
+func (w *instrumentedWrapper) finish(span trace.Span, operation string, start time.Time, err error) {
+	w.observe(operation, start, err)
+	if !failed(err) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
+
+// observe records a call of the operation which started at start and returned err.
+func (w *instrumentedWrapper) observe(operation string, start time.Time, err error) {
+	w.metrics.CallDuration.WithLabelValues(string(w.providerName), operation).Observe(time.Since(start).Seconds())
+
+	if !failed(err) {
+		return
+	}
+	errorType := "transient"
+	if terminal, _, _ := cloudprovidererrors.IsTerminalError(err); terminal {
+		errorType = "terminal"
+	}
+	w.metrics.CallErrors.WithLabelValues(string(w.providerName), operation, errorType).Inc()
+}
+
+// AddDefaults calls the underlying cloudproviders AddDefaults.
+func (w *instrumentedWrapper) AddDefaults(log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (result clusterv1alpha1.MachineSpec, err error) {
+	defer func(start time.Time) { w.observe("add_defaults", start, err) }(time.Now())
+	return w.actualProvider.AddDefaults(log, spec)
+}
+
+// Validate calls the underlying cloudproviders Validate.
+func (w *instrumentedWrapper) Validate(ctx context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (err error) {
+	ctx, span := w.startSpan(ctx, "validate")
+	defer func(start time.Time) { w.finish(span, "validate", start, err) }(time.Now())
+	return w.actualProvider.Validate(ctx, log, spec)
+}
+
+// Get calls the underlying cloudproviders Get.
+func (w *instrumentedWrapper) Get(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData) (result instance.Instance, err error) {
+	ctx, span := w.startSpan(ctx, "get")
+	defer func(start time.Time) { w.finish(span, "get", start, err) }(time.Now())
+	return w.actualProvider.Get(ctx, log, machine, data)
+}
+
+// Create calls the underlying cloudproviders Create.
+func (w *instrumentedWrapper) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, userdata string) (result instance.Instance, err error) {
+	ctx, span := w.startSpan(ctx, "create")
+	defer func(start time.Time) { w.finish(span, "create", start, err) }(time.Now())
+	return w.actualProvider.Create(ctx, log, machine, data, userdata)
+}
+
+// Cleanup calls the underlying cloudproviders Cleanup.
+func (w *instrumentedWrapper) Cleanup(ctx context.Context, log *zap.SugaredLogger, m *clusterv1alpha1.Machine, mcd *cloudprovidertypes.ProviderData) (deleted bool, err error) {
+	ctx, span := w.startSpan(ctx, "cleanup")
+	defer func(start time.Time) { w.finish(span, "cleanup", start, err) }(time.Now())
+	return w.actualProvider.Cleanup(ctx, log, m, mcd)
+}
+
+// MigrateUID calls the underlying cloudproviders MigrateUID.
+func (w *instrumentedWrapper) MigrateUID(ctx context.Context, log *zap.SugaredLogger, m *clusterv1alpha1.Machine, newUID types.UID) (err error) {
+	ctx, span := w.startSpan(ctx, "migrate_uid")
+	defer func(start time.Time) { w.finish(span, "migrate_uid", start, err) }(time.Now())
+	return w.actualProvider.MigrateUID(ctx, log, m, newUID)
+}
+
+// MachineMetricsLabels calls the underlying cloudproviders MachineMetricsLabels.
+func (w *instrumentedWrapper) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (labels map[string]string, err error) {
+	defer func(start time.Time) { w.observe("machine_metrics_labels", start, err) }(time.Now())
+	return w.actualProvider.MachineMetricsLabels(machine)
+}
+
+// FallbackCandidates calls the underlying cloudproviders FallbackCandidates, if it
+// implements cloudprovidertypes.FallbackProvider.
+func (w *instrumentedWrapper) FallbackCandidates(spec clusterv1alpha1.MachineSpec) (candidates []cloudprovidertypes.FallbackCandidate, err error) {
+	fallbackProvider, ok := w.actualProvider.(cloudprovidertypes.FallbackProvider)
+	if !ok {
+		return nil, nil
+	}
+	defer func(start time.Time) { w.observe("fallback_candidates", start, err) }(time.Now())
+	return fallbackProvider.FallbackCandidates(spec)
+}
+
+// ListInstances calls the underlying cloudproviders ListInstances, if it implements
+// cloudprovidertypes.InstanceLister.
+func (w *instrumentedWrapper) ListInstances(ctx context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec, clusterID string) (instances []cloudprovidertypes.TaggedInstance, err error) {
+	lister, ok := w.actualProvider.(cloudprovidertypes.InstanceLister)
+	if !ok {
+		return nil, cloudprovidererrors.ErrNotSupported
+	}
+	ctx, span := w.startSpan(ctx, "list_instances")
+	defer func(start time.Time) { w.finish(span, "list_instances", start, err) }(time.Now())
+	return lister.ListInstances(ctx, log, spec, clusterID)
+}
+
+// MachineAttributes calls the underlying cloudproviders MachineAttributes, if it
+// implements cloudprovidertypes.AttributesProvider.
+func (w *instrumentedWrapper) MachineAttributes(spec clusterv1alpha1.MachineSpec) (attributes cloudprovidertypes.MachineAttributes, err error) {
+	attributesProvider, ok := w.actualProvider.(cloudprovidertypes.AttributesProvider)
+	if !ok {
+		return cloudprovidertypes.MachineAttributes{}, cloudprovidererrors.ErrNotSupported
+	}
+	defer func(start time.Time) { w.observe("machine_attributes", start, err) }(time.Now())
+	return attributesProvider.MachineAttributes(spec)
+}
+
+// Plan calls the underlying cloudproviders Plan, if it implements cloudprovidertypes.Planner.
+func (w *instrumentedWrapper) Plan(ctx context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (plan *cloudprovidertypes.Plan, err error) {
+	planner, ok := w.actualProvider.(cloudprovidertypes.Planner)
+	if !ok {
+		return nil, cloudprovidererrors.ErrNotSupported
+	}
+	ctx, span := w.startSpan(ctx, "plan")
+	defer func(start time.Time) { w.finish(span, "plan", start, err) }(time.Now())
+	return planner.Plan(ctx, log, spec)
+}
+
+// SetMetricsForMachines calls the underlying cloudproviders SetMetricsForMachines.
+func (w *instrumentedWrapper) SetMetricsForMachines(machines clusterv1alpha1.MachineList) (err error) {
+	defer func(start time.Time) { w.observe("set_metrics_for_machines", start, err) }(time.Now())
+	return w.actualProvider.SetMetricsForMachines(machines)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig"
+)
+
+// failingProvider returns the given errors from Get, Create and Cleanup.
+type failingProvider struct {
+	cloudprovidertypes.Provider
+	getErr, createErr, cleanupErr error
+}
+
+func (p *failingProvider) Get(_ context.Context, _ *zap.SugaredLogger, _ *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData) (instance.Instance, error) {
+	return nil, p.getErr
+}
+
+func (p *failingProvider) Create(_ context.Context, _ *zap.SugaredLogger, _ *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData, _ string) (instance.Instance, error) {
+	return nil, p.createErr
+}
+
+func (p *failingProvider) Cleanup(_ context.Context, _ *zap.SugaredLogger, _ *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData) (bool, error) {
+	return true, p.cleanupErr
+}
+
+func TestInstrumentedCloudProvider(t *testing.T) {
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	machine := &clusterv1alpha1.Machine{}
+
+	metrics := NewMetrics()
+	prov := NewInstrumentedCloudProvider(&failingProvider{
+		getErr:     cloudprovidererrors.ErrInstanceNotFound,
+		createErr:  cloudprovidererrors.TerminalError{Reason: common.InvalidConfigurationMachineError, Message: "invalid"},
+		cleanupErr: errors.New("connection refused"),
+	}, "fake", metrics)
+
+	if _, err := prov.Get(ctx, log, machine, nil); !cloudprovidererrors.IsNotFound(err) {
+		t.Fatalf("expected the error of the provider to be returned, got %v", err)
+	}
+	if _, err := prov.Create(ctx, log, machine, nil, ""); err == nil {
+		t.Fatal("expected the error of the provider to be returned")
+	}
+	for range 2 {
+		if _, err := prov.Cleanup(ctx, log, machine, nil); err == nil {
+			t.Fatal("expected the error of the provider to be returned")
+		}
+	}
+	if _, err := prov.(cloudprovidertypes.Planner).Plan(ctx, log, machine.Spec); !errors.Is(err, cloudprovidererrors.ErrNotSupported) {
+		t.Fatalf("expected the plan of a provider without planner to be unsupported, got %v", err)
+	}
+
+	if count := testutil.CollectAndCount(metrics.CallDuration); count != 3 {
+		t.Errorf("expected durations of 3 operations, got %d", count)
+	}
+	if count := testutil.CollectAndCount(metrics.CallErrors); count != 2 {
+		t.Errorf("expected errors of 2 operations, got %d", count)
+	}
+
+	expectedErrors := []struct {
+		operation, errorType string
+		count                float64
+	}{
+		{operation: "get", errorType: "transient", count: 0},
+		{operation: "create", errorType: "terminal", count: 1},
+		{operation: "cleanup", errorType: "transient", count: 2},
+	}
+	for _, expected := range expectedErrors {
+		if count := testutil.ToFloat64(metrics.CallErrors.WithLabelValues("fake", expected.operation, expected.errorType)); count != expected.count {
+			t.Errorf("expected %v %s errors of %s, got %v", expected.count, expected.errorType, expected.operation, count)
+		}
+	}
+}
+
+func TestForProviderRecordsCalls(t *testing.T) {
+	metrics := NewMetrics()
+	SetMetrics(metrics)
+	defer SetMetrics(nil)
+
+	prov, err := ForProvider(providerconfig.CloudProviderFake, nil)
+	if err != nil {
+		t.Fatalf("failed to get provider: %v", err)
+	}
+	if _, err := prov.AddDefaults(zap.NewNop().Sugar(), clusterv1alpha1.MachineSpec{}); err != nil {
+		t.Fatalf("failed to add defaults: %v", err)
+	}
+
+	if count := testutil.CollectAndCount(metrics.CallDuration, metricsPrefix+"call_duration_seconds"); count != 1 {
+		t.Errorf("expected the call of the provider to be recorded, got durations of %d operations", count)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsPrefix = "machine_controller_cloud_provider_"
+
+// Metrics is a struct of all metrics recorded for the calls to the cloud providers.
+type Metrics struct {
+	CallDuration *prometheus.HistogramVec
+	CallErrors   *prometheus.CounterVec
+}
+
+// NewMetrics creates new Metrics for the calls to the cloud providers.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		CallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    metricsPrefix + "call_duration_seconds",
+			Help:    "Histogram of the duration of cloud provider calls",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"provider", "operation"}),
+		CallErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metricsPrefix + "call_errors_total",
+			Help: "The total number of failed cloud provider calls, by whether the error is terminal or transient",
+		}, []string{"provider", "operation", "type"}),
+	}
+}
+
+// MustRegister registers all metrics with the given registerer.
+func (m *Metrics) MustRegister(registerer prometheus.Registerer) {
+	registerer.MustRegister(
+		m.CallDuration,
+		m.CallErrors,
+	)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+)
+
+// OptionalInterfacesForwarder forwards the calls of the optional interfaces of the cloud
+// providers to the actual provider, if it implements them. The wrappers embed it, so that
+// they implement all optional interfaces and only override the calls they change.
+type OptionalInterfacesForwarder struct {
+	actualProvider cloudprovidertypes.Provider
+}
+
+// NewOptionalInterfacesForwarder returns a forwarder to the given provider.
+func NewOptionalInterfacesForwarder(actualProvider cloudprovidertypes.Provider) OptionalInterfacesForwarder {
+	return OptionalInterfacesForwarder{actualProvider: actualProvider}
+}
+
+var (
+	_ cloudprovidertypes.FallbackProvider   = OptionalInterfacesForwarder{}
+	_ cloudprovidertypes.InstanceLister     = OptionalInterfacesForwarder{}
+	_ cloudprovidertypes.AccountIdentifier  = OptionalInterfacesForwarder{}
+	_ cloudprovidertypes.AttributesProvider = OptionalInterfacesForwarder{}
+	_ cloudprovidertypes.Planner            = OptionalInterfacesForwarder{}
+)
+
+// FallbackCandidates calls the underlying cloudproviders FallbackCandidates, if it
+// implements cloudprovidertypes.FallbackProvider.
+func (f OptionalInterfacesForwarder) FallbackCandidates(spec clusterv1alpha1.MachineSpec) ([]cloudprovidertypes.FallbackCandidate, error) {
+	if fallbackProvider, ok := f.actualProvider.(cloudprovidertypes.FallbackProvider); ok {
+		return fallbackProvider.FallbackCandidates(spec)
+	}
+	return nil, nil
+}
+
+// ListInstances calls the underlying cloudproviders ListInstances, if it implements
+// cloudprovidertypes.InstanceLister.
+func (f OptionalInterfacesForwarder) ListInstances(ctx context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec, clusterID string) ([]cloudprovidertypes.TaggedInstance, error) {
+	if lister, ok := f.actualProvider.(cloudprovidertypes.InstanceLister); ok {
+		return lister.ListInstances(ctx, log, spec, clusterID)
+	}
+	return nil, cloudprovidererrors.ErrNotSupported
+}
+
+// AccountID calls the underlying cloudproviders AccountID, if it implements
+// cloudprovidertypes.AccountIdentifier.
+func (f OptionalInterfacesForwarder) AccountID(spec clusterv1alpha1.MachineSpec) (string, error) {
+	if identifier, ok := f.actualProvider.(cloudprovidertypes.AccountIdentifier); ok {
+		return identifier.AccountID(spec)
+	}
+	return "", nil
+}
+
+// MachineAttributes calls the underlying cloudproviders MachineAttributes, if it
+// implements cloudprovidertypes.AttributesProvider.
+func (f OptionalInterfacesForwarder) MachineAttributes(spec clusterv1alpha1.MachineSpec) (cloudprovidertypes.MachineAttributes, error) {
+	if attributesProvider, ok := f.actualProvider.(cloudprovidertypes.AttributesProvider); ok {
+		return attributesProvider.MachineAttributes(spec)
+	}
+	return cloudprovidertypes.MachineAttributes{}, cloudprovidererrors.ErrNotSupported
+}
+
+// Plan calls the underlying cloudproviders Plan, if it implements cloudprovidertypes.Planner.
+func (f OptionalInterfacesForwarder) Plan(ctx context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (*cloudprovidertypes.Plan, error) {
+	if planner, ok := f.actualProvider.(cloudprovidertypes.Planner); ok {
+		return planner.Plan(ctx, log, spec)
+	}
+	return nil, cloudprovidererrors.ErrNotSupported
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin exposes the cloudprovidertypes.Provider interface as a
+// versioned gRPC service, so that cloud providers can run out-of-process,
+// e.g. as a sidecar of the machine-controller or as a remote endpoint.
+//
+// Messages are encoded as JSON using the "json" gRPC content-subtype, which
+// allows plugins to be written in any language that has a gRPC implementation
+// without having to share generated protobuf code.
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ServiceName is the fully qualified name of the gRPC service. The version
+	// is part of the name so that incompatible changes can be served side by side.
+	ServiceName = "machinecontroller.cloudprovider.v1.Provider"
+
+	// CodecName is the gRPC content-subtype used by clients and servers.
+	CodecName = "json"
+
+	methodAddDefaults           = "AddDefaults"
+	methodValidate              = "Validate"
+	methodGet                   = "Get"
+	methodCreate                = "Create"
+	methodCleanup               = "Cleanup"
+	methodMigrateUID            = "MigrateUID"
+	methodMachineMetricsLabels  = "MachineMetricsLabels"
+	methodSetMetricsForMachines = "SetMetricsForMachines"
+)
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec implements encoding.Codec using encoding/json.
+type codec struct{}
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (codec) Name() string {
+	return CodecName
+}
+
+func fullMethod(method string) string {
+	return fmt.Sprintf("/%s/%s", ServiceName, method)
+}
+
+// Error is the wire representation of an error returned by a provider.
+// Transport errors are reported through gRPC status codes instead.
+type Error struct {
+	Message string `json:"message"`
+	// NotFound is set when the provider returned cloudprovidererrors.ErrInstanceNotFound.
+	NotFound bool `json:"notFound,omitempty"`
+	// TerminalReason is set when the provider returned a cloudprovidererrors.TerminalError.
+	TerminalReason common.MachineStatusError `json:"terminalReason,omitempty"`
+	// TerminalMessage is the message of the cloudprovidererrors.TerminalError.
+	TerminalMessage string `json:"terminalMessage,omitempty"`
+}
+
+// errorToWire converts err into its wire representation, returning nil if err is nil.
+func errorToWire(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	wireErr := &Error{
+		Message:  err.Error(),
+		NotFound: errors.Is(err, cloudprovidererrors.ErrInstanceNotFound),
+	}
+	if ok, reason, message := cloudprovidererrors.IsTerminalError(err); ok {
+		wireErr.TerminalReason = reason
+		wireErr.TerminalMessage = message
+	}
+
+	return wireErr
+}
+
+// notFoundError is an instance not found error returned by a plugin. It keeps the message
+// of the plugin, but is still matched by cloudprovidererrors.IsNotFound.
+type notFoundError struct {
+	message string
+}
+
+func (e notFoundError) Error() string {
+	return e.message
+}
+
+func (e notFoundError) Is(target error) bool {
+	return target == cloudprovidererrors.ErrInstanceNotFound
+}
+
+// errorFromWire converts the wire representation back into an error that can be
+// checked using cloudprovidererrors.IsNotFound and cloudprovidererrors.IsTerminalError.
+func errorFromWire(wireErr *Error) error {
+	if wireErr == nil {
+		return nil
+	}
+	if wireErr.NotFound {
+		if wireErr.Message == "" {
+			return cloudprovidererrors.ErrInstanceNotFound
+		}
+		return notFoundError{message: wireErr.Message}
+	}
+	if wireErr.TerminalReason != "" {
+		return cloudprovidererrors.TerminalError{
+			Reason:  wireErr.TerminalReason,
+			Message: wireErr.TerminalMessage,
+		}
+	}
+	return errors.New(wireErr.Message)
+}
+
+// Instance is the wire representation of instance.Instance.
+type Instance struct {
+	Name       string                            `json:"name"`
+	ID         string                            `json:"id"`
+	ProviderID string                            `json:"providerID"`
+	Addresses  map[string]corev1.NodeAddressType `json:"addresses,omitempty"`
+	Status     instance.Status                   `json:"status"`
+}
+
+func instanceToWire(i instance.Instance) *Instance {
+	if i == nil {
+		return nil
+	}
+	return &Instance{
+		Name:       i.Name(),
+		ID:         i.ID(),
+		ProviderID: i.ProviderID(),
+		Addresses:  i.Addresses(),
+		Status:     i.Status(),
+	}
+}
+
+// remoteInstance implements instance.Instance for instances returned by a plugin.
+type remoteInstance struct {
+	wire *Instance
+}
+
+func (i remoteInstance) Name() string {
+	return i.wire.Name
+}
+
+func (i remoteInstance) ID() string {
+	return i.wire.ID
+}
+
+func (i remoteInstance) ProviderID() string {
+	return i.wire.ProviderID
+}
+
+func (i remoteInstance) Addresses() map[string]corev1.NodeAddressType {
+	return i.wire.Addresses
+}
+
+func (i remoteInstance) Status() instance.Status {
+	return i.wire.Status
+}
+
+type AddDefaultsRequest struct {
+	Spec clusterv1alpha1.MachineSpec `json:"spec"`
+}
+
+type AddDefaultsResponse struct {
+	Spec  clusterv1alpha1.MachineSpec `json:"spec"`
+	Error *Error                      `json:"error,omitempty"`
+}
+
+type ValidateRequest struct {
+	Spec clusterv1alpha1.MachineSpec `json:"spec"`
+}
+
+type ValidateResponse struct {
+	Error *Error `json:"error,omitempty"`
+}
+
+type GetRequest struct {
+	Machine   *clusterv1alpha1.Machine `json:"machine"`
+	ClusterID string                   `json:"clusterID,omitempty"`
+}
+
+type GetResponse struct {
+	Instance *Instance `json:"instance,omitempty"`
+	// MachinePatch is a JSON merge patch of the changes the provider made to the machine,
+	// which are applied by the machine-controller. This is the equivalent of ProviderData.Update.
+	MachinePatch json.RawMessage `json:"machinePatch,omitempty"`
+	Error        *Error          `json:"error,omitempty"`
+}
+
+type CreateRequest struct {
+	Machine   *clusterv1alpha1.Machine `json:"machine"`
+	Userdata  string                   `json:"userdata"`
+	ClusterID string                   `json:"clusterID,omitempty"`
+}
+
+type CreateResponse struct {
+	Instance     *Instance       `json:"instance,omitempty"`
+	MachinePatch json.RawMessage `json:"machinePatch,omitempty"`
+	Error        *Error          `json:"error,omitempty"`
+}
+
+type CleanupRequest struct {
+	Machine   *clusterv1alpha1.Machine `json:"machine"`
+	ClusterID string                   `json:"clusterID,omitempty"`
+}
+
+type CleanupResponse struct {
+	// Done is true once all resources of the machine have been deleted.
+	Done         bool            `json:"done"`
+	MachinePatch json.RawMessage `json:"machinePatch,omitempty"`
+	Error        *Error          `json:"error,omitempty"`
+}
+
+type MigrateUIDRequest struct {
+	Machine *clusterv1alpha1.Machine `json:"machine"`
+	NewUID  types.UID                `json:"newUID"`
+}
+
+type MigrateUIDResponse struct {
+	Error *Error `json:"error,omitempty"`
+}
+
+type MachineMetricsLabelsRequest struct {
+	Machine *clusterv1alpha1.Machine `json:"machine"`
+}
+
+type MachineMetricsLabelsResponse struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Error  *Error            `json:"error,omitempty"`
+}
+
+type SetMetricsForMachinesRequest struct {
+	Machines clusterv1alpha1.MachineList `json:"machines"`
+}
+
+type SetMetricsForMachinesResponse struct {
+	Error *Error `json:"error,omitempty"`
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// defaultCallTimeout is used for calls that do not get a context passed
+// by the cloudprovidertypes.Provider interface.
+const defaultCallTimeout = 30 * time.Second
+
+var (
+	connectionsLock sync.Mutex
+	// connections caches one client connection per endpoint, as gRPC
+	// connections are meant to be long-lived and shared.
+	connections = map[string]*grpc.ClientConn{}
+)
+
+type provider struct {
+	conn grpc.ClientConnInterface
+}
+
+// New returns a cloudprovidertypes.Provider that forwards all calls to the
+// plugin listening on the given endpoint. The endpoint uses the gRPC target
+// syntax, e.g. "unix:///var/run/plugin.sock" or "dns:///plugin.example.com:9090".
+// Connections to endpoints other than unix sockets are secured as configured
+// by the transport options.
+func New(endpoint string, transport TransportOptions) (cloudprovidertypes.Provider, error) {
+	connectionsLock.Lock()
+	defer connectionsLock.Unlock()
+
+	conn, exists := connections[endpoint]
+	if !exists {
+		creds, err := transport.transportCredentials(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure transport of plugin endpoint %q: %w", endpoint, err)
+		}
+		conn, err = grpc.NewClient(endpoint,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithDefaultCallOptions(grpc.CallContentSubtype(CodecName)),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for plugin endpoint %q: %w", endpoint, err)
+		}
+		connections[endpoint] = conn
+	}
+
+	return NewForConnection(conn), nil
+}
+
+// NewForConnection returns a cloudprovidertypes.Provider using an existing
+// connection. The connection must use the CodecName content-subtype.
+func NewForConnection(conn grpc.ClientConnInterface) cloudprovidertypes.Provider {
+	return &provider{conn: conn}
+}
+
+func (p *provider) invoke(ctx context.Context, method string, req, resp interface{}) error {
+	if err := p.conn.Invoke(ctx, fullMethod(method), req, resp); err != nil {
+		return fmt.Errorf("failed to call plugin method %s: %w", method, err)
+	}
+	return nil
+}
+
+func (p *provider) AddDefaults(_ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (clusterv1alpha1.MachineSpec, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCallTimeout)
+	defer cancel()
+
+	resp := &AddDefaultsResponse{}
+	if err := p.invoke(ctx, methodAddDefaults, &AddDefaultsRequest{Spec: spec}, resp); err != nil {
+		return spec, err
+	}
+	if resp.Error != nil {
+		return spec, errorFromWire(resp.Error)
+	}
+	return resp.Spec, nil
+}
+
+func (p *provider) Validate(ctx context.Context, _ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) error {
+	resp := &ValidateResponse{}
+	if err := p.invoke(ctx, methodValidate, &ValidateRequest{Spec: spec}, resp); err != nil {
+		return err
+	}
+	return errorFromWire(resp.Error)
+}
+
+// applyMachinePatch persists the changes a plugin made to the machine using ProviderData.Update.
+func applyMachinePatch(machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, patch json.RawMessage) error {
+	if len(patch) == 0 {
+		return nil
+	}
+	if data == nil || data.Update == nil {
+		return fmt.Errorf("plugin changed the machine, but no machine updater was provided")
+	}
+
+	var patchErr error
+	err := data.Update(machine, func(m *clusterv1alpha1.Machine) {
+		current, err := json.Marshal(m)
+		if err != nil {
+			patchErr = fmt.Errorf("failed to marshal machine: %w", err)
+			return
+		}
+		patched, err := jsonpatch.MergePatch(current, patch)
+		if err != nil {
+			patchErr = fmt.Errorf("failed to apply machine patch of plugin: %w", err)
+			return
+		}
+		updated := &clusterv1alpha1.Machine{}
+		if err := json.Unmarshal(patched, updated); err != nil {
+			patchErr = fmt.Errorf("failed to unmarshal patched machine: %w", err)
+			return
+		}
+		*m = *updated
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update machine: %w", err)
+	}
+	return patchErr
+}
+
+func clusterID(data *cloudprovidertypes.ProviderData) string {
+	if data == nil {
+		return ""
+	}
+	return data.ClusterID
+}
+
+func (p *provider) Get(ctx context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData) (instance.Instance, error) {
+	resp := &GetResponse{}
+	if err := p.invoke(ctx, methodGet, &GetRequest{Machine: machine, ClusterID: clusterID(data)}, resp); err != nil {
+		return nil, err
+	}
+	if err := applyMachinePatch(machine, data, resp.MachinePatch); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, errorFromWire(resp.Error)
+	}
+	if resp.Instance == nil {
+		return nil, fmt.Errorf("plugin returned neither an instance nor an error")
+	}
+	return remoteInstance{wire: resp.Instance}, nil
+}
+
+func (p *provider) Create(ctx context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
+	resp := &CreateResponse{}
+	if err := p.invoke(ctx, methodCreate, &CreateRequest{Machine: machine, Userdata: userdata, ClusterID: clusterID(data)}, resp); err != nil {
+		return nil, err
+	}
+	if err := applyMachinePatch(machine, data, resp.MachinePatch); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, errorFromWire(resp.Error)
+	}
+	if resp.Instance == nil {
+		return nil, fmt.Errorf("plugin returned neither an instance nor an error")
+	}
+	return remoteInstance{wire: resp.Instance}, nil
+}
+
+func (p *provider) Cleanup(ctx context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData) (bool, error) {
+	resp := &CleanupResponse{}
+	if err := p.invoke(ctx, methodCleanup, &CleanupRequest{Machine: machine, ClusterID: clusterID(data)}, resp); err != nil {
+		return false, err
+	}
+	if err := applyMachinePatch(machine, data, resp.MachinePatch); err != nil {
+		return false, err
+	}
+	if resp.Error != nil {
+		return false, errorFromWire(resp.Error)
+	}
+	return resp.Done, nil
+}
+
+func (p *provider) MigrateUID(ctx context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, newUID types.UID) error {
+	resp := &MigrateUIDResponse{}
+	if err := p.invoke(ctx, methodMigrateUID, &MigrateUIDRequest{Machine: machine, NewUID: newUID}, resp); err != nil {
+		return err
+	}
+	return errorFromWire(resp.Error)
+}
+
+func (p *provider) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCallTimeout)
+	defer cancel()
+
+	resp := &MachineMetricsLabelsResponse{}
+	if err := p.invoke(ctx, methodMachineMetricsLabels, &MachineMetricsLabelsRequest{Machine: machine}, resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, errorFromWire(resp.Error)
+	}
+	if resp.Labels == nil {
+		resp.Labels = map[string]string{}
+	}
+	return resp.Labels, nil
+}
+
+func (p *provider) SetMetricsForMachines(machines clusterv1alpha1.MachineList) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCallTimeout)
+	defer cancel()
+
+	resp := &SetMetricsForMachinesResponse{}
+	if err := p.invoke(ctx, methodSetMetricsForMachines, &SetMetricsForMachinesRequest{Machines: machines}, resp); err != nil {
+		return err
+	}
+	return errorFromWire(resp.Error)
+}
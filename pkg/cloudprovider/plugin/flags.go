@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+)
+
+// EndpointsFlag is a flag.Value collecting "<cloud-provider>=<endpoint>" pairs.
+// The flag can be given multiple times.
+type EndpointsFlag map[providerconfig.CloudProvider]string
+
+func (f EndpointsFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for provider, endpoint := range f {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", provider, endpoint))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f EndpointsFlag) Set(value string) error {
+	provider, endpoint, found := strings.Cut(value, "=")
+	if !found || provider == "" || endpoint == "" {
+		return fmt.Errorf("invalid cloud provider plugin %q, expected <cloud-provider>=<endpoint>", value)
+	}
+	if _, exists := f[providerconfig.CloudProvider(provider)]; exists {
+		return fmt.Errorf("cloud provider plugin %q specified more than once", provider)
+	}
+	f[providerconfig.CloudProvider(provider)] = endpoint
+	return nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/fake"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newTestProvider(t *testing.T, server ProviderServer) cloudprovidertypes.Provider {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	RegisterProviderServer(srv, server)
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(CodecName)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return NewForConnection(conn)
+}
+
+func fakeMachineSpec(passValidation bool) clusterv1alpha1.MachineSpec {
+	spec := clusterv1alpha1.MachineSpec{}
+	spec.ProviderSpec.Value = &runtime.RawExtension{
+		Raw: []byte(fmt.Sprintf(`{"cloudProvider":"fake","cloudProviderSpec":{"passValidation":%t}}`, passValidation)),
+	}
+	return spec
+}
+
+func TestFakeProviderRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	prov := newTestProvider(t, NewServer(log, fake.New(nil), nil))
+
+	if err := prov.Validate(ctx, log, fakeMachineSpec(true)); err != nil {
+		t.Errorf("expected validation to pass, got: %v", err)
+	}
+	if err := prov.Validate(ctx, log, fakeMachineSpec(false)); err == nil {
+		t.Error("expected validation to fail")
+	}
+
+	machine := &clusterv1alpha1.Machine{Spec: fakeMachineSpec(true)}
+	inst, err := prov.Create(ctx, log, machine, nil, "#cloud-config")
+	if err != nil {
+		t.Fatalf("failed to create instance: %v", err)
+	}
+	if inst.Status() != instance.StatusUnknown {
+		t.Errorf("expected instance status %q, got %q", instance.StatusUnknown, inst.Status())
+	}
+
+	done, err := prov.Cleanup(ctx, log, machine, nil)
+	if err != nil {
+		t.Fatalf("failed to cleanup instance: %v", err)
+	}
+	if !done {
+		t.Error("expected cleanup to be done")
+	}
+
+	labels, err := prov.MachineMetricsLabels(machine)
+	if err != nil {
+		t.Fatalf("failed to get metrics labels: %v", err)
+	}
+	if labels == nil {
+		t.Error("expected non-nil metrics labels")
+	}
+}
+
+// erroringServer returns a fixed error from Get and Create.
+type erroringServer struct {
+	*Server
+	err error
+}
+
+func (s *erroringServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
+	return &GetResponse{Error: errorToWire(s.err)}, nil
+}
+
+func (s *erroringServer) Create(context.Context, *CreateRequest) (*CreateResponse, error) {
+	return &CreateResponse{Error: errorToWire(s.err)}, nil
+}
+
+func TestErrorsAreSerialized(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            error
+		expectNotFound bool
+		expectTerminal common.MachineStatusError
+	}{
+		{
+			name:           "instance not found",
+			err:            fmt.Errorf("lookup failed: %w", cloudprovidererrors.ErrInstanceNotFound),
+			expectNotFound: true,
+		},
+		{
+			name: "terminal error",
+			err: cloudprovidererrors.TerminalError{
+				Reason:  common.InsufficientResourcesMachineError,
+				Message: "no capacity",
+			},
+			expectTerminal: common.InsufficientResourcesMachineError,
+		},
+		{
+			name: "transient error",
+			err:  errors.New("connection reset"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			log := zap.NewNop().Sugar()
+			prov := newTestProvider(t, &erroringServer{Server: NewServer(log, fake.New(nil), nil), err: tc.err})
+
+			_, err := prov.Get(ctx, log, &clusterv1alpha1.Machine{}, nil)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if cloudprovidererrors.IsNotFound(err) != tc.expectNotFound {
+				t.Errorf("expected IsNotFound to be %t, got error: %v", tc.expectNotFound, err)
+			}
+			if tc.expectNotFound && err.Error() != tc.err.Error() {
+				t.Errorf("expected error message %q, got %q", tc.err.Error(), err.Error())
+			}
+
+			_, err = prov.Create(ctx, log, &clusterv1alpha1.Machine{}, nil, "")
+			isTerminal, reason, _ := cloudprovidererrors.IsTerminalError(err)
+			if isTerminal != (tc.expectTerminal != "") || reason != tc.expectTerminal {
+				t.Errorf("expected terminal reason %q, got error: %v", tc.expectTerminal, err)
+			}
+		})
+	}
+}
+
+// updatingProvider sets an annotation using ProviderData.Update when creating an instance.
+type updatingProvider struct {
+	cloudprovidertypes.Provider
+}
+
+func (p *updatingProvider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
+	if err := data.Update(machine, func(m *clusterv1alpha1.Machine) {
+		if m.Annotations == nil {
+			m.Annotations = map[string]string{}
+		}
+		m.Annotations["instance-id"] = data.ClusterID + "-1"
+	}); err != nil {
+		return nil, err
+	}
+	if machine.Annotations["instance-id"] == "" {
+		return nil, errors.New("update was not applied to the passed machine")
+	}
+	return p.Provider.Create(ctx, log, machine, data, userdata)
+}
+
+func TestMachineUpdatesAreForwarded(t *testing.T) {
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	prov := newTestProvider(t, NewServer(log, &updatingProvider{Provider: fake.New(nil)}, nil))
+
+	machine := &clusterv1alpha1.Machine{Spec: fakeMachineSpec(true)}
+	machine.Name = "machine"
+	machine.Labels = map[string]string{"keep": "me"}
+
+	updates := 0
+	data := &cloudprovidertypes.ProviderData{
+		ClusterID: "cluster",
+		Update: func(m *clusterv1alpha1.Machine, modifiers ...cloudprovidertypes.MachineModifier) error {
+			updates++
+			for _, modify := range modifiers {
+				modify(m)
+			}
+			return nil
+		},
+	}
+
+	if _, err := prov.Create(ctx, log, machine, data, ""); err != nil {
+		t.Fatalf("failed to create instance: %v", err)
+	}
+	if updates != 1 {
+		t.Errorf("expected one machine update, got %d", updates)
+	}
+	if machine.Annotations["instance-id"] != "cluster-1" {
+		t.Errorf("expected the annotation of the plugin to be applied, got annotations %v", machine.Annotations)
+	}
+	if machine.Name != "machine" || machine.Labels["keep"] != "me" {
+		t.Errorf("expected unchanged fields to be kept, got machine %v", machine.ObjectMeta)
+	}
+
+	// The fake provider does not change the machine, so no update must be made.
+	if _, err := prov.Cleanup(ctx, log, machine, data); err != nil {
+		t.Fatalf("failed to cleanup instance: %v", err)
+	}
+	if updates != 1 {
+		t.Errorf("expected no machine update on cleanup, got %d updates", updates)
+	}
+
+	// Providers can only update machines if the machine-controller passes an updater.
+	if _, err := prov.Create(ctx, log, &clusterv1alpha1.Machine{Spec: fakeMachineSpec(true)}, nil, ""); err == nil {
+		t.Error("expected an error if the machine cannot be updated")
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ProviderServer is the server API of the provider service.
+type ProviderServer interface {
+	AddDefaults(context.Context, *AddDefaultsRequest) (*AddDefaultsResponse, error)
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	Get(context.Context, *GetRequest) (*GetResponse, error)
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	Cleanup(context.Context, *CleanupRequest) (*CleanupResponse, error)
+	MigrateUID(context.Context, *MigrateUIDRequest) (*MigrateUIDResponse, error)
+	MachineMetricsLabels(context.Context, *MachineMetricsLabelsRequest) (*MachineMetricsLabelsResponse, error)
+	SetMetricsForMachines(context.Context, *SetMetricsForMachinesRequest) (*SetMetricsForMachinesResponse, error)
+}
+
+// RegisterProviderServer registers srv on the given gRPC server.
+func RegisterProviderServer(registrar grpc.ServiceRegistrar, srv ProviderServer) {
+	registrar.RegisterService(&serviceDesc, srv)
+}
+
+// Server serves a cloudprovidertypes.Provider over gRPC. It is meant to be
+// used by plugin authors that implement their provider in Go.
+type Server struct {
+	log      *zap.SugaredLogger
+	provider cloudprovidertypes.Provider
+	client   ctrlruntimeclient.Client
+}
+
+// NewServer returns a Server for the given provider.
+//
+// Changes the provider makes to a machine using ProviderData.Update are sent back
+// to the machine-controller, which persists them. The machine-controller does not
+// share its Kubernetes client with plugins, so the passed client is used as
+// ProviderData.Client instead; it can be nil for providers that do not need it.
+func NewServer(log *zap.SugaredLogger, provider cloudprovidertypes.Provider, client ctrlruntimeclient.Client) *Server {
+	return &Server{
+		log:      log,
+		provider: provider,
+		client:   client,
+	}
+}
+
+// machineRecorder records the changes a provider makes to a machine using
+// ProviderData.Update during a single call.
+type machineRecorder struct {
+	original *clusterv1alpha1.Machine
+	updated  *clusterv1alpha1.Machine
+}
+
+func newMachineRecorder(machine *clusterv1alpha1.Machine) *machineRecorder {
+	return &machineRecorder{
+		original: machine.DeepCopy(),
+		updated:  machine.DeepCopy(),
+	}
+}
+
+func (r *machineRecorder) update(machine *clusterv1alpha1.Machine, modifiers ...cloudprovidertypes.MachineModifier) error {
+	for _, modify := range modifiers {
+		modify(r.updated)
+	}
+	r.updated.DeepCopyInto(machine)
+	return nil
+}
+
+// patch returns a JSON merge patch of the recorded changes, or nil if there are none.
+func (r *machineRecorder) patch() (json.RawMessage, error) {
+	original, err := json.Marshal(r.original)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal machine: %w", err)
+	}
+	updated, err := json.Marshal(r.updated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal machine: %w", err)
+	}
+	patch, err := jsonpatch.CreateMergePatch(original, updated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create machine patch: %w", err)
+	}
+	if string(patch) == "{}" {
+		return nil, nil
+	}
+	return patch, nil
+}
+
+func (s *Server) providerData(ctx context.Context, clusterID string, recorder *machineRecorder) *cloudprovidertypes.ProviderData {
+	return &cloudprovidertypes.ProviderData{
+		Ctx:       ctx,
+		Update:    recorder.update,
+		Client:    s.client,
+		ClusterID: clusterID,
+	}
+}
+
+// machinePatch returns the recorded changes, joining a failure to compute them with the
+// error of the call, as the machine-controller must not silently lose them.
+func machinePatch(recorder *machineRecorder, err error) (json.RawMessage, *Error) {
+	patch, patchErr := recorder.patch()
+	if patchErr != nil {
+		if err == nil {
+			return nil, errorToWire(patchErr)
+		}
+		return nil, errorToWire(fmt.Errorf("%w (%w)", err, patchErr))
+	}
+	return patch, errorToWire(err)
+}
+
+func (s *Server) AddDefaults(_ context.Context, req *AddDefaultsRequest) (*AddDefaultsResponse, error) {
+	spec, err := s.provider.AddDefaults(s.log, req.Spec)
+	return &AddDefaultsResponse{Spec: spec, Error: errorToWire(err)}, nil
+}
+
+func (s *Server) Validate(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error) {
+	return &ValidateResponse{Error: errorToWire(s.provider.Validate(ctx, s.log, req.Spec))}, nil
+}
+
+func (s *Server) Get(ctx context.Context, req *GetRequest) (*GetResponse, error) {
+	recorder := newMachineRecorder(req.Machine)
+	i, err := s.provider.Get(ctx, s.log, req.Machine, s.providerData(ctx, req.ClusterID, recorder))
+	patch, wireErr := machinePatch(recorder, err)
+	if wireErr != nil {
+		return &GetResponse{MachinePatch: patch, Error: wireErr}, nil
+	}
+	return &GetResponse{Instance: instanceToWire(i), MachinePatch: patch}, nil
+}
+
+func (s *Server) Create(ctx context.Context, req *CreateRequest) (*CreateResponse, error) {
+	recorder := newMachineRecorder(req.Machine)
+	i, err := s.provider.Create(ctx, s.log, req.Machine, s.providerData(ctx, req.ClusterID, recorder), req.Userdata)
+	patch, wireErr := machinePatch(recorder, err)
+	if wireErr != nil {
+		return &CreateResponse{MachinePatch: patch, Error: wireErr}, nil
+	}
+	return &CreateResponse{Instance: instanceToWire(i), MachinePatch: patch}, nil
+}
+
+func (s *Server) Cleanup(ctx context.Context, req *CleanupRequest) (*CleanupResponse, error) {
+	recorder := newMachineRecorder(req.Machine)
+	done, err := s.provider.Cleanup(ctx, s.log, req.Machine, s.providerData(ctx, req.ClusterID, recorder))
+	patch, wireErr := machinePatch(recorder, err)
+	return &CleanupResponse{Done: done && wireErr == nil, MachinePatch: patch, Error: wireErr}, nil
+}
+
+func (s *Server) MigrateUID(ctx context.Context, req *MigrateUIDRequest) (*MigrateUIDResponse, error) {
+	return &MigrateUIDResponse{Error: errorToWire(s.provider.MigrateUID(ctx, s.log, req.Machine, req.NewUID))}, nil
+}
+
+func (s *Server) MachineMetricsLabels(_ context.Context, req *MachineMetricsLabelsRequest) (*MachineMetricsLabelsResponse, error) {
+	labels, err := s.provider.MachineMetricsLabels(req.Machine)
+	return &MachineMetricsLabelsResponse{Labels: labels, Error: errorToWire(err)}, nil
+}
+
+func (s *Server) SetMetricsForMachines(_ context.Context, req *SetMetricsForMachinesRequest) (*SetMetricsForMachinesResponse, error) {
+	return &SetMetricsForMachinesResponse{Error: errorToWire(s.provider.SetMetricsForMachines(req.Machines))}, nil
+}
+
+// unaryHandler adapts a typed ProviderServer method to a grpc.MethodHandler.
+func unaryHandler[Req any, Resp any](method string, call func(ProviderServer, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := new(Req)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return call(srv.(ProviderServer), ctx, req)
+			}
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: fullMethod(method),
+			}
+			return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(ProviderServer), ctx, req.(*Req))
+			})
+		},
+	}
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*ProviderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryHandler(methodAddDefaults, ProviderServer.AddDefaults),
+		unaryHandler(methodValidate, ProviderServer.Validate),
+		unaryHandler(methodGet, ProviderServer.Get),
+		unaryHandler(methodCreate, ProviderServer.Create),
+		unaryHandler(methodCleanup, ProviderServer.Cleanup),
+		unaryHandler(methodMigrateUID, ProviderServer.MigrateUID),
+		unaryHandler(methodMachineMetricsLabels, ProviderServer.MachineMetricsLabels),
+		unaryHandler(methodSetMetricsForMachines, ProviderServer.SetMetricsForMachines),
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "cloudprovider/v1",
+}
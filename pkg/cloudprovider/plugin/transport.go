@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TransportOptions configure the transport security of the connections to the plugins.
+// Connections to unix sockets are never encrypted, all other connections use TLS unless
+// Insecure is set, as the calls carry userdata with bootstrap tokens and cloud credentials.
+type TransportOptions struct {
+	// CAFile is the CA bundle used to verify the plugin's serving certificate. If empty,
+	// the system CAs are used.
+	CAFile string
+	// CertFile and KeyFile are the client certificate and key presented to the plugin
+	// for mutual TLS. Both or neither must be set.
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the name used to verify the plugin's serving certificate.
+	ServerName string
+	// Insecure disables TLS for connections to endpoints other than unix sockets.
+	Insecure bool
+}
+
+// AddFlags adds the flags of the transport options to the given flagset.
+func (o *TransportOptions) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.CAFile, "plugin-ca-file", "", "CA bundle used to verify the serving certificates of cloud provider plugins not listening on a unix socket. Defaults to the system CAs")
+	fs.StringVar(&o.CertFile, "plugin-cert-file", "", "Client certificate presented to cloud provider plugins for mutual TLS")
+	fs.StringVar(&o.KeyFile, "plugin-key-file", "", "Key of the client certificate presented to cloud provider plugins for mutual TLS")
+	fs.StringVar(&o.ServerName, "plugin-server-name", "", "Name used to verify the serving certificates of cloud provider plugins. Defaults to the host of the endpoint")
+	fs.BoolVar(&o.Insecure, "plugin-insecure", false, "Connect to cloud provider plugins not listening on a unix socket without TLS. Userdata and credentials are then sent unencrypted")
+}
+
+// isUnixSocket returns true if the gRPC target is a unix socket, which is only reachable
+// from the same host.
+func isUnixSocket(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "unix:") || strings.HasPrefix(endpoint, "unix-abstract:")
+}
+
+// transportCredentials returns the transport credentials for connections to the given endpoint.
+func (o TransportOptions) transportCredentials(endpoint string) (credentials.TransportCredentials, error) {
+	if isUnixSocket(endpoint) || o.Insecure {
+		return insecure.NewCredentials(), nil
+	}
+
+	config, err := o.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// tlsConfig returns the TLS configuration for connections to plugins.
+func (o TransportOptions) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: o.ServerName,
+	}
+
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plugin CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("plugin CA file %q does not contain any certificate", o.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, errors.New("the plugin client certificate and key must be given together")
+	}
+	if o.CertFile != "" {
+		if _, err := o.clientCertificate(nil); err != nil {
+			return nil, err
+		}
+		// The certificate is loaded on every handshake, so rotated certificates are used
+		// without restarting.
+		config.GetClientCertificate = o.clientCertificate
+	}
+
+	return config, nil
+}
+
+// clientCertificate loads the client certificate presented to plugins.
+func (o TransportOptions) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load plugin client certificate: %w", err)
+	}
+	return &cert, nil
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/fake"
+)
+
+func TestTransportCredentials(t *testing.T) {
+	testCases := []struct {
+		name             string
+		endpoint         string
+		transport        TransportOptions
+		expectedProtocol string
+		expectErr        bool
+	}{
+		{
+			name:             "unix socket",
+			endpoint:         "unix:///var/run/plugin.sock",
+			expectedProtocol: "insecure",
+		},
+		{
+			name:             "remote endpoint",
+			endpoint:         "dns:///plugin.example.com:9090",
+			expectedProtocol: "tls",
+		},
+		{
+			name:             "remote endpoint without TLS",
+			endpoint:         "dns:///plugin.example.com:9090",
+			transport:        TransportOptions{Insecure: true},
+			expectedProtocol: "insecure",
+		},
+		{
+			name:      "client certificate without key",
+			endpoint:  "dns:///plugin.example.com:9090",
+			transport: TransportOptions{CertFile: "client.crt"},
+			expectErr: true,
+		},
+		{
+			name:      "missing CA file",
+			endpoint:  "dns:///plugin.example.com:9090",
+			transport: TransportOptions{CAFile: filepath.Join(t.TempDir(), "ca.crt")},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			creds, err := tc.transport.transportCredentials(tc.endpoint)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error: %t, got %v", tc.expectErr, err)
+			}
+			if tc.expectErr {
+				return
+			}
+			if protocol := creds.Info().SecurityProtocol; protocol != tc.expectedProtocol {
+				t.Errorf("expected security protocol %q, got %q", tc.expectedProtocol, protocol)
+			}
+		})
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newTestCertificate(t, nil, nil, "ca")
+	server, serverKey := newTestCertificate(t, ca, caKey, "plugin")
+	client, clientKey := newTestCertificate(t, ca, caKey, "machine-controller")
+
+	caFile := writeTestPEM(t, dir, "ca.crt", "CERTIFICATE", ca.Raw)
+	certFile := writeTestPEM(t, dir, "client.crt", "CERTIFICATE", client.Raw)
+	keyFile := writeTestPEM(t, dir, "client.key", "EC PRIVATE KEY", marshalTestKey(t, clientKey))
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{server.Raw}, PrivateKey: serverKey}},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})))
+	RegisterProviderServer(srv, NewServer(zap.NewNop().Sugar(), fake.New(nil), nil))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	t.Cleanup(srv.Stop)
+
+	prov, err := New(listener.Addr().String(), TransportOptions{
+		CAFile:     caFile,
+		CertFile:   certFile,
+		KeyFile:    keyFile,
+		ServerName: "plugin",
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := prov.Validate(ctx, zap.NewNop().Sugar(), fakeMachineSpec(true)); err != nil {
+		t.Fatalf("expected call over mutual TLS to succeed, got %v", err)
+	}
+}
+
+// newTestCertificate returns a certificate for the given name, which is signed by the given CA
+// or is a CA itself if no CA is given.
+func newTestCertificate(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, name string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	parent, parentKey := template, key
+	if ca == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parent, parentKey = ca, caKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return cert, key
+}
+
+func marshalTestKey(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return der
+}
+
+func writeTestPEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
@@ -18,8 +18,10 @@ package cloudprovider
 
 import (
 	"errors"
+	"fmt"
 
 	cloudprovidercache "k8c.io/machine-controller/pkg/cloudprovider/cache"
+	"k8c.io/machine-controller/pkg/cloudprovider/plugin"
 	"k8c.io/machine-controller/pkg/cloudprovider/provider/alibaba"
 	"k8c.io/machine-controller/pkg/cloudprovider/provider/anexia"
 	"k8c.io/machine-controller/pkg/cloudprovider/provider/aws"
@@ -38,6 +40,7 @@ import (
 	"k8c.io/machine-controller/pkg/cloudprovider/provider/opennebula"
 	"k8c.io/machine-controller/pkg/cloudprovider/provider/openstack"
 	"k8c.io/machine-controller/pkg/cloudprovider/provider/scaleway"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/simulator"
 	vcd "k8c.io/machine-controller/pkg/cloudprovider/provider/vmwareclouddirector"
 	"k8c.io/machine-controller/pkg/cloudprovider/provider/vsphere"
 	"k8c.io/machine-controller/pkg/cloudprovider/provider/vultr"
@@ -46,8 +49,6 @@ import (
 )
 
 var (
-	cache = cloudprovidercache.New()
-
 	// ErrProviderNotFound tells that the requested cloud provider was not found.
 	ErrProviderNotFound = errors.New("cloudprovider not found")
 
@@ -86,6 +87,9 @@ var (
 		providerconfig.CloudProviderFake: func(cvr providerconfig.ConfigVarResolver) cloudprovidertypes.Provider {
 			return fake.New(cvr)
 		},
+		providerconfig.CloudProviderSimulator: func(cvr providerconfig.ConfigVarResolver) cloudprovidertypes.Provider {
+			return simulator.New(cvr)
+		},
 		providerconfig.CloudProviderEdge: func(cvr providerconfig.ConfigVarResolver) cloudprovidertypes.Provider {
 			return edge.New(cvr)
 		},
@@ -129,15 +133,76 @@ var (
 			return opennebula.New(cvr)
 		},
 	}
+
+	// plugins holds the gRPC endpoints of out-of-process cloud provider plugins, see RegisterPlugin.
+	plugins = map[providerconfig.CloudProvider]registeredPlugin{}
+
+	// providerMetrics records the calls of all providers, see SetMetrics. If nil, calls are not instrumented.
+	providerMetrics *Metrics
 )
 
+type registeredPlugin struct {
+	endpoint  string
+	transport plugin.TransportOptions
+}
+
+// RegisterPlugin makes ForProvider resolve the given cloud provider to the
+// out-of-process plugin listening on endpoint, which is connected to using the
+// given transport options. Built-in providers cannot be replaced by plugins.
+// This must be called before any controller is started.
+func RegisterPlugin(p providerconfig.CloudProvider, endpoint string, transport plugin.TransportOptions) error {
+	if _, found := providers[p]; found {
+		return fmt.Errorf("cloud provider %q is built-in and cannot be served by a plugin", p)
+	}
+	if _, found := communityProviders[p]; found {
+		return fmt.Errorf("cloud provider %q is built-in and cannot be served by a plugin", p)
+	}
+	// The connection is only established on the first call, but invalid transport
+	// options are reported right away.
+	if _, err := plugin.New(endpoint, transport); err != nil {
+		return err
+	}
+	plugins[p] = registeredPlugin{endpoint: endpoint, transport: transport}
+	return nil
+}
+
+// SetMetrics makes ForProvider return providers, which record the duration and the errors of
+// their calls in metrics and trace them. This must be called before any controller is started.
+func SetMetrics(metrics *Metrics) {
+	providerMetrics = metrics
+}
+
 // ForProvider returns a CloudProvider actuator for the requested provider.
+// Its validation results are not cached, see ForProviderWithCache.
 func ForProvider(p providerconfig.CloudProvider, cvr providerconfig.ConfigVarResolver) (cloudprovidertypes.Provider, error) {
-	if p, found := providers[p]; found {
-		return NewValidationCacheWrappingCloudProvider(p(cvr)), nil
+	return ForProviderWithCache(p, cvr, nil)
+}
+
+// ForProviderWithCache returns a CloudProvider actuator for the requested provider,
+// which caches its validation results in the given cache.
+func ForProviderWithCache(p providerconfig.CloudProvider, cvr providerconfig.ConfigVarResolver, cache *cloudprovidercache.CloudproviderCache) (cloudprovidertypes.Provider, error) {
+	if newProvider, found := providers[p]; found {
+		return NewValidationCacheWrappingCloudProvider(instrument(newProvider(cvr), p), cache), nil
 	}
-	if p, found := communityProviders[p]; found {
-		return NewValidationCacheWrappingCloudProvider(p(cvr)), nil
+	if newProvider, found := communityProviders[p]; found {
+		return NewValidationCacheWrappingCloudProvider(instrument(newProvider(cvr), p), cache), nil
+	}
+	if registered, found := plugins[p]; found {
+		prov, err := plugin.New(registered.endpoint, registered.transport)
+		if err != nil {
+			return nil, err
+		}
+		return NewValidationCacheWrappingCloudProvider(instrument(prov, p), cache), nil
 	}
 	return nil, ErrProviderNotFound
 }
+
+// instrument wraps the provider, so that its calls are recorded in the metrics set by
+// SetMetrics. Validations served from the cache are not recorded, as they are no calls to
+// the provider.
+func instrument(prov cloudprovidertypes.Provider, name providerconfig.CloudProvider) cloudprovidertypes.Provider {
+	if providerMetrics == nil {
+		return prov
+	}
+	return NewInstrumentedCloudProvider(prov, name, providerMetrics)
+}
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -41,6 +42,7 @@ import (
 	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
 	"k8c.io/machine-controller/pkg/cloudprovider/instance"
 	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/pkg/cloudprovider/workloadidentity"
 	"k8c.io/machine-controller/sdk/apis/cluster/common"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	awstypes "k8c.io/machine-controller/sdk/cloudprovider/aws"
@@ -88,6 +90,7 @@ func New(configVarResolver providerconfig.ConfigVarResolver) cloudprovidertypes.
 const (
 	nameTag       = "Name"
 	machineUIDTag = "Machine-UID"
+	clusterIDTag  = "Machine-Controller-Cluster-ID"
 
 	maxRetries = 100
 )
@@ -198,6 +201,7 @@ type Config struct {
 
 	AssumeRoleARN        string
 	AssumeRoleExternalID string
+	WebIdentityRoleARN   string
 }
 
 type amiFilter struct {
@@ -464,14 +468,28 @@ func (p *provider) getConfig(provSpec clusterv1alpha1.ProviderSpec) (*Config, *p
 		return nil, nil, nil, err
 	}
 	c.AssumeRoleExternalID = assumeRoleExternalID
+	c.WebIdentityRoleARN, err = p.configVarResolver.GetStringValueOrEnv(rawConfig.WebIdentityRoleARN, "AWS_WEB_IDENTITY_ROLE_ARN")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get the value of \"webIdentityRoleARN\" field, error = %w", err)
+	}
 
 	return &c, pconfig, rawConfig, err
 }
 
-func getAwsConfig(ctx context.Context, id, secret, token, region, assumeRoleARN, assumeRoleExternalID string) (aws.Config, error) {
+func getAwsConfig(ctx context.Context, id, secret, token, region, webIdentityRoleARN, assumeRoleARN, assumeRoleExternalID string) (aws.Config, error) {
+	var credentialsProvider aws.CredentialsProvider = awscredentials.NewStaticCredentialsProvider(id, secret, token)
+	if webIdentityRoleARN != "" {
+		credentialsProvider = aws.NewCredentialsCache(&webIdentityCredentialsProvider{
+			exchanger: &workloadidentity.AWSExchanger{
+				RoleARN: webIdentityRoleARN,
+				Region:  region,
+			},
+		})
+	}
+
 	cfg, err := awsconfig.LoadDefaultConfig(ctx,
 		awsconfig.WithRegion(region),
-		awsconfig.WithCredentialsProvider(awscredentials.NewStaticCredentialsProvider(id, secret, token)),
+		awsconfig.WithCredentialsProvider(credentialsProvider),
 		awsconfig.WithRetryMaxAttempts(maxRetries),
 	)
 
@@ -493,8 +511,8 @@ func getAwsConfig(ctx context.Context, id, secret, token, region, assumeRoleARN,
 	return cfg, nil
 }
 
-func getEC2client(ctx context.Context, id, secret, region, assumeRoleArn, assumeRoleExternalID string) (*ec2.Client, error) {
-	cfg, err := getAwsConfig(ctx, id, secret, "", region, assumeRoleArn, assumeRoleExternalID)
+func getEC2client(ctx context.Context, id, secret, region, webIdentityRoleARN, assumeRoleArn, assumeRoleExternalID string) (*ec2.Client, error) {
+	cfg, err := getAwsConfig(ctx, id, secret, "", region, webIdentityRoleARN, assumeRoleArn, assumeRoleExternalID)
 	if err != nil {
 		return nil, awsErrorToTerminalError(err, "failed to get aws configuration")
 	}
@@ -523,6 +541,66 @@ func (p *provider) AddDefaults(_ *zap.SugaredLogger, spec clusterv1alpha1.Machin
 	return spec, err
 }
 
+// FallbackCandidates returns a machine spec for each configured fallback, which
+// are used in order if AWS reports insufficient capacity.
+func (p *provider) FallbackCandidates(spec clusterv1alpha1.MachineSpec) ([]cloudprovidertypes.FallbackCandidate, error) {
+	pconfig, err := providerconfig.GetConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, err
+	}
+	rawConfig, err := awstypes.GetConfig(*pconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []cloudprovidertypes.FallbackCandidate
+	for i, fallback := range rawConfig.Fallbacks {
+		candidateConfig := *rawConfig
+		candidateConfig.Fallbacks = nil
+
+		var changes []string
+		if fallback.InstanceType != (providerconfig.ConfigVarString{}) {
+			instanceType, err := p.configVarResolver.GetStringValue(fallback.InstanceType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the value of \"instanceType\" of fallback %d: %w", i, err)
+			}
+			candidateConfig.InstanceType = fallback.InstanceType
+			changes = append(changes, "instanceType="+instanceType)
+		}
+		if fallback.AvailabilityZone != (providerconfig.ConfigVarString{}) {
+			availabilityZone, err := p.configVarResolver.GetStringValue(fallback.AvailabilityZone)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the value of \"availabilityZone\" of fallback %d: %w", i, err)
+			}
+			candidateConfig.AvailabilityZone = fallback.AvailabilityZone
+			changes = append(changes, "availabilityZone="+availabilityZone)
+		}
+		if fallback.SubnetID != (providerconfig.ConfigVarString{}) {
+			subnetID, err := p.configVarResolver.GetStringValue(fallback.SubnetID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the value of \"subnetId\" of fallback %d: %w", i, err)
+			}
+			candidateConfig.SubnetID = fallback.SubnetID
+			changes = append(changes, "subnetId="+subnetID)
+		}
+		if len(changes) == 0 {
+			return nil, fmt.Errorf("fallback %d does not set any of instanceType, availabilityZone or subnetId", i)
+		}
+
+		candidateSpec := spec
+		candidateSpec.ProviderSpec.Value, err = setProviderSpec(candidateConfig, spec.ProviderSpec)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, cloudprovidertypes.FallbackCandidate{
+			Name: strings.Join(changes, ","),
+			Spec: candidateSpec,
+		})
+	}
+
+	return candidates, nil
+}
+
 func (p *provider) Validate(ctx context.Context, _ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) error {
 	config, pc, _, err := p.getConfig(spec.ProviderSpec)
 	if err != nil {
@@ -547,7 +625,11 @@ func (p *provider) Validate(ctx context.Context, _ *zap.SugaredLogger, spec clus
 		return fmt.Errorf("diskSize must be specified and > 0")
 	}
 
-	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.AssumeRoleARN, config.AssumeRoleExternalID)
+	if _, err := p.FallbackCandidates(spec); err != nil {
+		return fmt.Errorf("invalid fallbacks: %w", err)
+	}
+
+	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.WebIdentityRoleARN, config.AssumeRoleARN, config.AssumeRoleExternalID)
 	if err != nil {
 		return fmt.Errorf("failed to create ec2 client: %w", err)
 	}
@@ -653,7 +735,7 @@ func areVpcDNSHostnamesEnabled(ctx context.Context, client *ec2.Client, id strin
 	return *out.EnableDnsHostnames.Value, nil
 }
 
-func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
+func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
 	config, pc, _, err := p.getConfig(machine.Spec.ProviderSpec)
 	if err != nil {
 		return nil, cloudprovidererrors.TerminalError{
@@ -662,7 +744,7 @@ func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 		}
 	}
 
-	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.AssumeRoleARN, config.AssumeRoleExternalID)
+	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.WebIdentityRoleARN, config.AssumeRoleARN, config.AssumeRoleExternalID)
 	if err != nil {
 		return nil, err
 	}
@@ -672,24 +754,9 @@ func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 		return nil, err
 	}
 
-	amiID := config.AMI
-	if amiID == "" {
-		// read the instance type to know which cpu architecture is needed in the AMI
-		cpuArchitecture, err := getCPUArchitecture(ctx, ec2Client, config.InstanceType)
-
-		if err != nil {
-			return nil, cloudprovidererrors.TerminalError{
-				Reason:  common.InvalidConfigurationMachineError,
-				Message: fmt.Sprintf("Failed to find instance type %s in region %s: %v", config.InstanceType, config.Region, err),
-			}
-		}
-
-		if amiID, err = getDefaultAMIID(ctx, log, ec2Client, pc.OperatingSystem, config.Region, cpuArchitecture); err != nil {
-			return nil, cloudprovidererrors.TerminalError{
-				Reason:  common.InvalidConfigurationMachineError,
-				Message: fmt.Sprintf("Failed to get AMI-ID for operating system %s in region %s: %v", pc.OperatingSystem, config.Region, err),
-			}
-		}
+	amiID, err := resolveAMI(ctx, log, ec2Client, config, pc.OperatingSystem)
+	if err != nil {
+		return nil, err
 	}
 
 	if pc.OperatingSystem != providerconfig.OperatingSystemFlatcar {
@@ -710,6 +777,12 @@ func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 			Value: aws.String(string(machine.UID)),
 		},
 	}
+	if data != nil && data.ClusterID != "" {
+		tags = append(tags, ec2types.Tag{
+			Key:   aws.String(clusterIDTag),
+			Value: aws.String(data.ClusterID),
+		})
+	}
 
 	for k, v := range config.Tags {
 		tags = append(tags, ec2types.Tag{
@@ -808,6 +881,32 @@ func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 	return &awsInstance{instance: &runOut.Instances[0]}, nil
 }
 
+// resolveAMI returns the configured AMI, or the default AMI of the operating system for the
+// cpu architecture of the instance type.
+func resolveAMI(ctx context.Context, log *zap.SugaredLogger, ec2Client *ec2.Client, config *Config, os providerconfig.OperatingSystem) (string, error) {
+	if config.AMI != "" {
+		return config.AMI, nil
+	}
+
+	// read the instance type to know which cpu architecture is needed in the AMI
+	cpuArchitecture, err := getCPUArchitecture(ctx, ec2Client, config.InstanceType)
+	if err != nil {
+		return "", cloudprovidererrors.TerminalError{
+			Reason:  common.InvalidConfigurationMachineError,
+			Message: fmt.Sprintf("Failed to find instance type %s in region %s: %v", config.InstanceType, config.Region, err),
+		}
+	}
+
+	amiID, err := getDefaultAMIID(ctx, log, ec2Client, os, config.Region, cpuArchitecture)
+	if err != nil {
+		return "", cloudprovidererrors.TerminalError{
+			Reason:  common.InvalidConfigurationMachineError,
+			Message: fmt.Sprintf("Failed to get AMI-ID for operating system %s in region %s: %v", os, config.Region, err),
+		}
+	}
+	return amiID, nil
+}
+
 func (p *provider) Cleanup(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData) (bool, error) {
 	ec2instance, err := p.get(ctx, machine)
 	if err != nil {
@@ -827,7 +926,7 @@ func (p *provider) Cleanup(ctx context.Context, log *zap.SugaredLogger, machine
 		}
 	}
 
-	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.AssumeRoleARN, config.AssumeRoleExternalID)
+	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.WebIdentityRoleARN, config.AssumeRoleARN, config.AssumeRoleExternalID)
 	if err != nil {
 		return false, err
 	}
@@ -874,7 +973,7 @@ func (p *provider) get(ctx context.Context, machine *clusterv1alpha1.Machine) (*
 		}
 	}
 
-	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.AssumeRoleARN, config.AssumeRoleExternalID)
+	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.WebIdentityRoleARN, config.AssumeRoleARN, config.AssumeRoleExternalID)
 	if err != nil {
 		return nil, err
 	}
@@ -908,6 +1007,150 @@ func (p *provider) get(ctx context.Context, machine *clusterv1alpha1.Machine) (*
 	return nil, cloudprovidererrors.ErrInstanceNotFound
 }
 
+// AccountID returns the access key ID or web identity role, the role and the region used by the
+// spec, as API rate limits apply per account and region.
+func (p *provider) AccountID(spec clusterv1alpha1.MachineSpec) (string, error) {
+	config, _, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse config: %w", err)
+	}
+	identity := config.AccessKeyID
+	if config.WebIdentityRoleARN != "" {
+		identity = config.WebIdentityRoleARN
+	}
+	return fmt.Sprintf("%s/%s/%s", identity, config.AssumeRoleARN, config.Region), nil
+}
+
+// ListInstances returns all instances in the configured region that are tagged with
+// a machine UID and the cluster ID and carry the configured tags.
+func (p *provider) ListInstances(ctx context.Context, _ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec, clusterID string) ([]cloudprovidertypes.TaggedInstance, error) {
+	config, _, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, cloudprovidererrors.TerminalError{
+			Reason:  common.InvalidConfigurationMachineError,
+			Message: fmt.Sprintf("Failed to parse MachineSpec, due to %v", err),
+		}
+	}
+
+	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.WebIdentityRoleARN, config.AssumeRoleARN, config.AssumeRoleExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []ec2types.Filter{
+		{
+			Name:   aws.String("tag-key"),
+			Values: []string{machineUIDTag},
+		},
+		{
+			Name:   aws.String("tag:" + clusterIDTag),
+			Values: []string{clusterID},
+		},
+	}
+	for k, v := range config.Tags {
+		filters = append(filters, ec2types.Filter{
+			Name:   aws.String("tag:" + k),
+			Values: []string{v},
+		})
+	}
+
+	var instances []cloudprovidertypes.TaggedInstance
+	paginator := ec2.NewDescribeInstancesPaginator(ec2Client, &ec2.DescribeInstancesInput{Filters: filters})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, awsErrorToTerminalError(err, "failed to list instances from aws")
+		}
+
+		for _, reservation := range page.Reservations {
+			for i := range reservation.Instances {
+				ec2instance := reservation.Instances[i]
+				if ec2instance.State == nil || ec2instance.State.Name == ec2types.InstanceStateNameTerminated {
+					continue
+				}
+
+				instances = append(instances, cloudprovidertypes.TaggedInstance{
+					Instance:   &awsInstance{instance: &ec2instance},
+					MachineUID: types.UID(getTagValue(machineUIDTag, ec2instance.Tags)),
+				})
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// MachineAttributes returns the instance type, AMI, region, availability zone and tags of the spec.
+func (p *provider) MachineAttributes(spec clusterv1alpha1.MachineSpec) (cloudprovidertypes.MachineAttributes, error) {
+	config, _, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return cloudprovidertypes.MachineAttributes{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	attributes := cloudprovidertypes.MachineAttributes{
+		InstanceType: string(config.InstanceType),
+		Image:        config.AMI,
+		Region:       config.Region,
+		Tags:         config.Tags,
+	}
+	if config.AvailabilityZone != "" {
+		attributes.Zones = []string{config.AvailabilityZone}
+	}
+	return attributes, nil
+}
+
+// Plan returns the instance Create would launch for the spec. It only describes the instance
+// type and images to resolve the default AMI.
+func (p *provider) Plan(ctx context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (*cloudprovidertypes.Plan, error) {
+	config, pc, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.WebIdentityRoleARN, config.AssumeRoleARN, config.AssumeRoleExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	amiID, err := resolveAMI(ctx, log, ec2Client, config, pc.OperatingSystem)
+	if err != nil {
+		return nil, err
+	}
+
+	rootDevicePath, err := getDefaultRootDevicePath(pc.OperatingSystem)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &cloudprovidertypes.Plan{
+		Image:          amiID,
+		InstanceType:   string(config.InstanceType),
+		Region:         config.Region,
+		Zone:           config.AvailabilityZone,
+		Network:        config.VpcID,
+		Subnet:         config.SubnetID,
+		SecurityGroups: config.SecurityGroupIDs,
+		Disks: []cloudprovidertypes.PlannedDisk{{
+			Name:   rootDevicePath,
+			Type:   string(config.DiskType),
+			SizeGB: int64(config.DiskSize),
+		}},
+		Tags: config.Tags,
+		Details: map[string]string{
+			"instanceProfile":    config.InstanceProfile,
+			"assignPublicIP":     strconv.FormatBool(config.AssignPublicIP == nil || *config.AssignPublicIP),
+			"ebsVolumeEncrypted": strconv.FormatBool(config.EBSVolumeEncrypted),
+			"spotInstance":       strconv.FormatBool(config.IsSpotInstance != nil && *config.IsSpotInstance),
+		},
+	}
+	if config.DiskIops != nil {
+		plan.Details["diskIops"] = strconv.Itoa(int(*config.DiskIops))
+	}
+	if config.SpotMaxPrice != nil && *config.SpotMaxPrice != "" {
+		plan.Details["spotMaxPrice"] = *config.SpotMaxPrice
+	}
+	return plan, nil
+}
+
 func (p *provider) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (map[string]string, error) {
 	labels := make(map[string]string)
 
@@ -939,7 +1182,7 @@ func (p *provider) MigrateUID(ctx context.Context, _ *zap.SugaredLogger, machine
 		}
 	}
 
-	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.AssumeRoleARN, config.AssumeRoleExternalID)
+	ec2Client, err := getEC2client(ctx, config.AccessKeyID, config.SecretAccessKey, config.Region, config.WebIdentityRoleARN, config.AssumeRoleARN, config.AssumeRoleExternalID)
 	if err != nil {
 		return fmt.Errorf("failed to get EC2 client: %w", err)
 	}
@@ -1047,6 +1290,11 @@ func awsErrorToTerminalError(err error, msg string) error {
 				Reason:  common.InsufficientResourcesMachineError,
 				Message: "You've reached the AWS quota for number of instances of this type",
 			}
+		case "InsufficientInstanceCapacity":
+			return cloudprovidererrors.TerminalError{
+				Reason:  common.InsufficientResourcesMachineError,
+				Message: fmt.Sprintf("AWS has no capacity for the requested instance type in the availability zone: %s", aerr.ErrorMessage()),
+			}
 		case "AuthFailure":
 			// authorization primitives come from MachineSpec
 			// thus we are setting InvalidConfigurationMachineError
@@ -1100,6 +1348,7 @@ func (p *provider) SetMetricsForMachines(machines clusterv1alpha1.MachineList) e
 		accessKeyID          string
 		secretAccessKey      string
 		region               string
+		webIdentityRoleARN   string
 		assumeRoleARN        string
 		assumeRoleExternalID string
 	}
@@ -1114,10 +1363,11 @@ func (p *provider) SetMetricsForMachines(machines clusterv1alpha1.MachineList) e
 		}
 
 		// Very simple and very stupid
-		machineEc2Credentials[fmt.Sprintf("%s/%s/%s/%s/%s", config.AccessKeyID, config.SecretAccessKey, config.Region, config.AssumeRoleARN, config.AssumeRoleExternalID)] = ec2Credentials{
+		machineEc2Credentials[fmt.Sprintf("%s/%s/%s/%s/%s/%s", config.AccessKeyID, config.SecretAccessKey, config.Region, config.WebIdentityRoleARN, config.AssumeRoleARN, config.AssumeRoleExternalID)] = ec2Credentials{
 			accessKeyID:          config.AccessKeyID,
 			secretAccessKey:      config.SecretAccessKey,
 			region:               config.Region,
+			webIdentityRoleARN:   config.WebIdentityRoleARN,
 			assumeRoleARN:        config.AssumeRoleARN,
 			assumeRoleExternalID: config.AssumeRoleExternalID,
 		}
@@ -1125,7 +1375,7 @@ func (p *provider) SetMetricsForMachines(machines clusterv1alpha1.MachineList) e
 
 	allReservations := []ec2types.Reservation{}
 	for _, cred := range machineEc2Credentials {
-		ec2Client, err := getEC2client(ctx, cred.accessKeyID, cred.secretAccessKey, cred.region, cred.assumeRoleARN, cred.assumeRoleExternalID)
+		ec2Client, err := getEC2client(ctx, cred.accessKeyID, cred.secretAccessKey, cred.region, cred.webIdentityRoleARN, cred.assumeRoleARN, cred.assumeRoleExternalID)
 		if err != nil {
 			machineErrors = append(machineErrors, fmt.Errorf("failed to get EC2 client: %w", err))
 			continue
@@ -0,0 +1,49 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/workloadidentity"
+)
+
+// webIdentityCredentialsProvider provides the credentials of an IAM role, which is assumed
+// with the service account token of the machine-controller.
+type webIdentityCredentialsProvider struct {
+	exchanger workloadidentity.Exchanger
+}
+
+var _ aws.CredentialsProvider = &webIdentityCredentialsProvider{}
+
+func (p *webIdentityCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	creds, err := workloadidentity.GetCredentials(ctx, p.exchanger)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+
+	return aws.Credentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Source:          "WebIdentity",
+		CanExpire:       true,
+		Expires:         creds.Expiry,
+	}, nil
+}
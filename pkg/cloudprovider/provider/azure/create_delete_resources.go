@@ -22,7 +22,6 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/compute/mgmt/compute"
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/network/mgmt/network"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/Azure/go-autorest/autorest/to"
 	"go.uber.org/zap"
 
@@ -365,7 +364,7 @@ func createOrUpdateNetworkInterface(ctx context.Context, log *zap.SugaredLogger,
 	ifSpec.EnableAcceleratedNetworking = enableAcceleratedNetworking
 
 	if config.SecurityGroupName != "" {
-		authorizer, err := auth.NewClientCredentialsConfig(config.ClientID, config.ClientSecret, config.TenantID).Authorizer()
+		authorizer, err := getAuthorizer(config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create authorizer for security groups: %w", err)
 		}
@@ -21,13 +21,30 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/compute/mgmt/compute"
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/network/mgmt/network"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/workloadidentity"
 )
 
+// getAuthorizer returns an authorizer using the client secret, or the service account
+// token of the machine-controller if workload identity is used.
+func getAuthorizer(c *config) (autorest.Authorizer, error) {
+	if c.UseWorkloadIdentity {
+		return &workloadIdentityAuthorizer{
+			exchanger: &workloadidentity.AzureExchanger{
+				TenantID: c.TenantID,
+				ClientID: c.ClientID,
+			},
+		}, nil
+	}
+	return auth.NewClientCredentialsConfig(c.ClientID, c.ClientSecret, c.TenantID).Authorizer()
+}
+
 func getIPClient(c *config) (*network.PublicIPAddressesClient, error) {
 	var err error
 	ipClient := network.NewPublicIPAddressesClient(c.SubscriptionID)
-	ipClient.Authorizer, err = auth.NewClientCredentialsConfig(c.ClientID, c.ClientSecret, c.TenantID).Authorizer()
+	ipClient.Authorizer, err = getAuthorizer(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorizer: %w", err)
 	}
@@ -38,7 +55,7 @@ func getIPClient(c *config) (*network.PublicIPAddressesClient, error) {
 func getIPConfigClient(c *config) (*network.InterfaceIPConfigurationsClient, error) {
 	var err error
 	ipConfigClient := network.NewInterfaceIPConfigurationsClient(c.SubscriptionID)
-	ipConfigClient.Authorizer, err = auth.NewClientCredentialsConfig(c.ClientID, c.ClientSecret, c.TenantID).Authorizer()
+	ipConfigClient.Authorizer, err = getAuthorizer(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorizer: %w", err)
 	}
@@ -49,7 +66,7 @@ func getIPConfigClient(c *config) (*network.InterfaceIPConfigurationsClient, err
 func getSubnetsClient(c *config) (*network.SubnetsClient, error) {
 	var err error
 	subnetClient := network.NewSubnetsClient(c.SubscriptionID)
-	subnetClient.Authorizer, err = auth.NewClientCredentialsConfig(c.ClientID, c.ClientSecret, c.TenantID).Authorizer()
+	subnetClient.Authorizer, err = getAuthorizer(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorizer: %w", err)
 	}
@@ -60,7 +77,7 @@ func getSubnetsClient(c *config) (*network.SubnetsClient, error) {
 func getVirtualNetworksClient(c *config) (*network.VirtualNetworksClient, error) {
 	var err error
 	virtualNetworksClient := network.NewVirtualNetworksClient(c.SubscriptionID)
-	virtualNetworksClient.Authorizer, err = auth.NewClientCredentialsConfig(c.ClientID, c.ClientSecret, c.TenantID).Authorizer()
+	virtualNetworksClient.Authorizer, err = getAuthorizer(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorizer: %w", err)
 	}
@@ -70,7 +87,7 @@ func getVirtualNetworksClient(c *config) (*network.VirtualNetworksClient, error)
 func getVMClient(c *config) (*compute.VirtualMachinesClient, error) {
 	var err error
 	vmClient := compute.NewVirtualMachinesClient(c.SubscriptionID)
-	vmClient.Authorizer, err = auth.NewClientCredentialsConfig(c.ClientID, c.ClientSecret, c.TenantID).Authorizer()
+	vmClient.Authorizer, err = getAuthorizer(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorizer: %w", err)
 	}
@@ -81,7 +98,7 @@ func getVMClient(c *config) (*compute.VirtualMachinesClient, error) {
 func getSKUClient(c *config) (*compute.ResourceSkusClient, error) {
 	var err error
 	skuClient := compute.NewResourceSkusClient(c.SubscriptionID)
-	skuClient.Authorizer, err = auth.NewClientCredentialsConfig(c.ClientID, c.ClientSecret, c.TenantID).Authorizer()
+	skuClient.Authorizer, err = getAuthorizer(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorizer: %w", err)
 	}
@@ -92,7 +109,7 @@ func getSKUClient(c *config) (*compute.ResourceSkusClient, error) {
 func getInterfacesClient(c *config) (*network.InterfacesClient, error) {
 	var err error
 	ifClient := network.NewInterfacesClient(c.SubscriptionID)
-	ifClient.Authorizer, err = auth.NewClientCredentialsConfig(c.ClientID, c.ClientSecret, c.TenantID).Authorizer()
+	ifClient.Authorizer, err = getAuthorizer(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorizer: %w", err)
 	}
@@ -103,7 +120,7 @@ func getInterfacesClient(c *config) (*network.InterfacesClient, error) {
 func getDisksClient(c *config) (*compute.DisksClient, error) {
 	var err error
 	disksClient := compute.NewDisksClient(c.SubscriptionID)
-	disksClient.Authorizer, err = auth.NewClientCredentialsConfig(c.ClientID, c.ClientSecret, c.TenantID).Authorizer()
+	disksClient.Authorizer, err = getAuthorizer(c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authorizer: %w", err)
 	}
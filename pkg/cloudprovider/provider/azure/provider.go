@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -30,6 +31,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/compute/mgmt/compute"
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/network/mgmt/network"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	gocache "github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
@@ -46,6 +48,7 @@ import (
 	"k8c.io/machine-controller/sdk/providerconfig"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 )
@@ -74,6 +77,8 @@ const (
 	envClientSecret   = "AZURE_CLIENT_SECRET"
 	envTenantID       = "AZURE_TENANT_ID"
 	envSubscriptionID = "AZURE_SUBSCRIPTION_ID"
+
+	envUseWorkloadIdentity = "AZURE_USE_WORKLOAD_IDENTITY"
 )
 
 type provider struct {
@@ -85,6 +90,9 @@ type config struct {
 	TenantID       string
 	ClientID       string
 	ClientSecret   string
+	// UseWorkloadIdentity replaces the client secret with the service account token
+	// of the machine-controller.
+	UseWorkloadIdentity bool
 
 	Location              string
 	ResourceGroup         string
@@ -264,6 +272,11 @@ func (p *provider) getConfig(provSpec clusterv1alpha1.ProviderSpec) (*config, *p
 		return nil, nil, fmt.Errorf("failed to get the value of \"clientSecret\" field, error = %w", err)
 	}
 
+	c.UseWorkloadIdentity, err = p.configVarResolver.GetBoolValueOrEnv(rawCfg.UseWorkloadIdentity, envUseWorkloadIdentity)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the value of \"useWorkloadIdentity\" field, error = %w", err)
+	}
+
 	c.ResourceGroup, err = p.configVarResolver.GetStringValue(rawCfg.ResourceGroup)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get the value of \"resourceGroup\" field, error = %w", err)
@@ -589,6 +602,58 @@ func (p *provider) AddDefaults(log *zap.SugaredLogger, spec clusterv1alpha1.Mach
 	return spec, nil
 }
 
+// FallbackCandidates returns a machine spec for each configured fallback, which
+// are used in order if Azure reports insufficient capacity.
+func (p *provider) FallbackCandidates(spec clusterv1alpha1.MachineSpec) ([]cloudprovidertypes.FallbackCandidate, error) {
+	rawConfig, pconfig, err := newCloudProviderSpec(spec.ProviderSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse provider spec: %w", err)
+	}
+
+	var candidates []cloudprovidertypes.FallbackCandidate
+	for i, fallback := range rawConfig.Fallbacks {
+		candidateConfig := *rawConfig
+		candidateConfig.Fallbacks = nil
+
+		var changes []string
+		if fallback.VMSize != (providerconfig.ConfigVarString{}) {
+			vmSize, err := p.configVarResolver.GetStringValue(fallback.VMSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the value of \"vmSize\" of fallback %d: %w", i, err)
+			}
+			candidateConfig.VMSize = fallback.VMSize
+			changes = append(changes, "vmSize="+vmSize)
+		}
+		if len(fallback.Zones) > 0 {
+			candidateConfig.Zones = fallback.Zones
+			changes = append(changes, "zones="+strings.Join(fallback.Zones, "/"))
+		}
+		if len(changes) == 0 {
+			return nil, fmt.Errorf("fallback %d does not set any of vmSize or zones", i)
+		}
+
+		rawCandidateConfig, err := json.Marshal(candidateConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal fallback config: %w", err)
+		}
+		candidatePConfig := *pconfig
+		candidatePConfig.CloudProviderSpec.Raw = rawCandidateConfig
+
+		candidate := cloudprovidertypes.FallbackCandidate{
+			Name: strings.Join(changes, ","),
+			Spec: spec,
+		}
+		rawCandidatePConfig, err := json.Marshal(candidatePConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal provider config: %w", err)
+		}
+		candidate.Spec.ProviderSpec.Value = &runtime.RawExtension{Raw: rawCandidatePConfig}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
 func getStorageProfile(config *config, providerCfg *providerconfig.Config) (*compute.StorageProfile, error) {
 	osRef, err := getOSImageReference(config, providerCfg.OperatingSystem)
 	if err != nil {
@@ -786,11 +851,17 @@ func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 
 	future, err := vmClient.CreateOrUpdate(ctx, config.ResourceGroup, machine.Name, vmSpec)
 	if err != nil {
+		if isCapacityError(err) {
+			return nil, insufficientCapacityError(ctx, log, config, machine, err)
+		}
 		return nil, fmt.Errorf("trying to create a VM: %w", err)
 	}
 
 	err = future.WaitForCompletionRef(ctx, vmClient.Client)
 	if err != nil {
+		if isCapacityError(err) {
+			return nil, insufficientCapacityError(ctx, log, config, machine, err)
+		}
 		return nil, fmt.Errorf("waiting for operation returned: %w", err)
 	}
 
@@ -818,6 +889,61 @@ func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 	return &azureVM{vm: &vm, ipAddresses: ipAddresses, status: status}, nil
 }
 
+// capacityErrorCodes are the error codes Azure returns if there is no capacity
+// for the requested VM size in the location or zones.
+var capacityErrorCodes = []string{
+	"SkuNotAvailable",
+	"AllocationFailed",
+	"ZonalAllocationFailed",
+	"OverconstrainedAllocationRequest",
+	"OverconstrainedZonalAllocationRequest",
+}
+
+// isCapacityError returns whether the error of a request or of an asynchronous operation
+// reports that Azure has no capacity for the VM. The code is either the one of the service
+// error or the one of its details.
+func isCapacityError(err error) bool {
+	serviceErr := getServiceError(err)
+	if serviceErr == nil {
+		return false
+	}
+	if slices.Contains(capacityErrorCodes, serviceErr.Code) {
+		return true
+	}
+	for _, detail := range serviceErr.Details {
+		if code, ok := detail["code"].(string); ok && slices.Contains(capacityErrorCodes, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// getServiceError returns the service error of a failed request, which autorest wraps in a
+// DetailedError, or of a failed asynchronous operation.
+func getServiceError(err error) *autorestazure.ServiceError {
+	var requestErr *autorestazure.RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.ServiceError
+	}
+	var serviceErr *autorestazure.ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr
+	}
+	return nil
+}
+
+// insufficientCapacityError deletes the VM that failed to be allocated, as its
+// zones cannot be changed when retrying with a fallback, and returns a terminal error.
+func insufficientCapacityError(ctx context.Context, log *zap.SugaredLogger, config *config, machine *clusterv1alpha1.Machine, err error) error {
+	if deleteErr := deleteVMsByMachineUID(ctx, config, machine.UID); deleteErr != nil {
+		log.Warnw("Failed to delete VM that could not be allocated", zap.Error(deleteErr))
+	}
+	return cloudprovidererrors.TerminalError{
+		Reason:  common.InsufficientResourcesMachineError,
+		Message: fmt.Sprintf("Azure has no capacity for the requested VM size: %v", err),
+	}
+}
+
 func (p *provider) Cleanup(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData) (bool, error) {
 	config, _, err := p.getConfig(machine.Spec.ProviderSpec)
 	if err != nil {
@@ -1103,7 +1229,7 @@ func (p *provider) Validate(ctx context.Context, log *zap.SugaredLogger, spec cl
 		return errors.New("clientID is missing")
 	}
 
-	if c.ClientSecret == "" {
+	if c.ClientSecret == "" && !c.UseWorkloadIdentity {
 		return errors.New("clientSecret is missing")
 	}
 
@@ -1123,6 +1249,10 @@ func (p *provider) Validate(ctx context.Context, log *zap.SugaredLogger, spec cl
 		return errors.New("subnetName is missing")
 	}
 
+	if _, err := p.FallbackCandidates(spec); err != nil {
+		return fmt.Errorf("invalid fallbacks: %w", err)
+	}
+
 	switch f := providerConfig.Network.GetIPFamily(); f {
 	case net.IPFamilyUnspecified, net.IPFamilyIPv4:
 		//noop
@@ -1283,6 +1413,21 @@ func (p *provider) MigrateUID(ctx context.Context, log *zap.SugaredLogger, machi
 	return nil
 }
 
+// MachineAttributes returns the VM size, image ID, location, zones and tags of the spec.
+func (p *provider) MachineAttributes(spec clusterv1alpha1.MachineSpec) (cloudprovidertypes.MachineAttributes, error) {
+	config, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return cloudprovidertypes.MachineAttributes{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return cloudprovidertypes.MachineAttributes{
+		InstanceType: config.VMSize,
+		Image:        config.ImageID,
+		Region:       config.Location,
+		Zones:        config.Zones,
+		Tags:         config.Tags,
+	}, nil
+}
+
 func (p *provider) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (map[string]string, error) {
 	labels := make(map[string]string)
 
@@ -18,9 +18,13 @@ package azure
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/latest/compute/mgmt/compute"
+	"github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
 
@@ -485,3 +489,50 @@ func TestBuildSecurityProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestIsCapacityError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name: "request error",
+			err: autorest.NewErrorWithError(&autorestazure.RequestError{
+				ServiceError: &autorestazure.ServiceError{Code: "SkuNotAvailable"},
+			}, "compute.VirtualMachinesClient", "CreateOrUpdate", nil, "Failure sending request"),
+			expected: true,
+		},
+		{
+			name:     "operation error",
+			err:      fmt.Errorf("waiting for operation returned: %w", &autorestazure.ServiceError{Code: "ZonalAllocationFailed"}),
+			expected: true,
+		},
+		{
+			name: "operation error with capacity error in details",
+			err: &autorestazure.ServiceError{
+				Code:    "Conflict",
+				Details: []map[string]interface{}{{"code": "AllocationFailed"}},
+			},
+			expected: true,
+		},
+		{
+			name:     "other service error",
+			err:      &autorestazure.ServiceError{Code: "InvalidParameter", Message: "AllocationFailed is not a valid name"},
+			expected: false,
+		},
+		{
+			name:     "error without service error",
+			err:      errors.New("SkuNotAvailable"),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if capacityErr := isCapacityError(test.err); capacityErr != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, capacityErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/workloadidentity"
+)
+
+// workloadIdentityAuthorizer authorizes requests with an access token, for which the
+// service account token of the machine-controller is exchanged.
+type workloadIdentityAuthorizer struct {
+	exchanger workloadidentity.Exchanger
+}
+
+var _ autorest.Authorizer = &workloadIdentityAuthorizer{}
+
+func (a *workloadIdentityAuthorizer) WithAuthorization() autorest.PrepareDecorator {
+	return func(p autorest.Preparer) autorest.Preparer {
+		return autorest.PreparerFunc(func(r *http.Request) (*http.Request, error) {
+			r, err := p.Prepare(r)
+			if err != nil {
+				return r, err
+			}
+
+			creds, err := workloadidentity.GetCredentials(r.Context(), a.exchanger)
+			if err != nil {
+				return r, fmt.Errorf("failed to get access token: %w", err)
+			}
+			return autorest.Prepare(r, autorest.WithBearerAuthorization(creds.AccessToken))
+		})
+	}
+}
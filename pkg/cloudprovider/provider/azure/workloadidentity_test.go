@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/workloadidentity"
+	"k8c.io/machine-controller/pkg/cloudprovider/workloadidentity/oidctest"
+)
+
+func TestWorkloadIdentityAuthorizer(t *testing.T) {
+	const subject = "system:serviceaccount:kube-system:machine-controller"
+
+	server, err := oidctest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	defer server.Close()
+	server.Trust(subject)
+
+	token, err := server.Issue(subject, oidctest.AzureAudience, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte(token), 0600); err != nil {
+		t.Fatalf("failed to write token: %v", err)
+	}
+	workloadidentity.SetTokenFile(tokenFile)
+	defer workloadidentity.SetTokenFile("")
+
+	authorizer, err := getAuthorizer(&config{TenantID: "tenant", ClientID: "client", UseWorkloadIdentity: true})
+	if err != nil {
+		t.Fatalf("failed to get authorizer: %v", err)
+	}
+	wiAuthorizer, ok := authorizer.(*workloadIdentityAuthorizer)
+	if !ok {
+		t.Fatalf("expected workload identity authorizer, got %T", authorizer)
+	}
+	// Send the token request to the stub instead of Entra ID.
+	wiAuthorizer.exchanger = &workloadidentity.AzureExchanger{
+		TenantID:      "tenant",
+		ClientID:      "client",
+		AuthorityHost: server.AzureAuthorityHost(),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req, err = autorest.Prepare(req, wiAuthorizer.WithAuthorization())
+	if err != nil {
+		t.Fatalf("failed to authorize request: %v", err)
+	}
+	if header := req.Header.Get("Authorization"); !strings.HasPrefix(header, "Bearer azure-token-") {
+		t.Errorf("expected bearer token of the stub, got %q", header)
+	}
+}
@@ -23,14 +23,17 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // PluginDriver manages the communications between the machine controller cloud provider and the bare metal env.
 type PluginDriver interface {
-	GetServer(context.Context) (Server, error)
+	// GetServer returns the server of the machine with the given UID.
+	GetServer(context.Context, types.UID) (Server, error)
 	Validate(runtime.RawExtension) error
 	ProvisionServer(context.Context, *zap.SugaredLogger, metav1.ObjectMeta, runtime.RawExtension, string) (Server, error)
-	DeprovisionServer(context.Context) error
+	// DeprovisionServer deprovisions the server of the machine with the given UID.
+	DeprovisionServer(context.Context, types.UID) error
 }
 
 // Server represents the server/instance which exists in the bare metal env.
@@ -41,3 +44,33 @@ type Server interface {
 	GetMACAddress() string
 	GetStatus() string
 }
+
+// WorkflowReporter is implemented by drivers, which provision servers with a workflow whose progress
+// is reported on the machine.
+type WorkflowReporter interface {
+	// GetWorkflowStatus returns the status of the latest workflow of the server of the machine with the
+	// given UID, or nil if it has none.
+	GetWorkflowStatus(context.Context, types.UID) (*WorkflowStatus, error)
+}
+
+// WorkflowState is the state of a workflow.
+type WorkflowState string
+
+const (
+	WorkflowStatePending   WorkflowState = "Pending"
+	WorkflowStateRunning   WorkflowState = "Running"
+	WorkflowStateSucceeded WorkflowState = "Succeeded"
+	WorkflowStateFailed    WorkflowState = "Failed"
+)
+
+// WorkflowStatus is the progress of a workflow.
+type WorkflowStatus struct {
+	Name  string
+	State WorkflowState
+	// Action is the action which is running or failed.
+	Action string
+	// CompletedActions is the number of actions which succeeded.
+	CompletedActions int
+	TotalActions     int
+	Message          string
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 30 * time.Second
+
+const (
+	// ResetOn powers a system on.
+	ResetOn = "On"
+	// ResetForceOff powers a system off immediately.
+	ResetForceOff = "ForceOff"
+	// ResetForceRestart restarts a system immediately.
+	ResetForceRestart = "ForceRestart"
+
+	// PowerStateOn is the power state of a system which is powered on.
+	PowerStateOn = "On"
+	// PowerStateOff is the power state of a system which is powered off.
+	PowerStateOff = "Off"
+
+	// BootTargetPxe boots a system from the network.
+	BootTargetPxe = "Pxe"
+	// BootTargetCd boots a system from the virtual CD.
+	BootTargetCd = "Cd"
+	// BootOnce applies the boot source override to the next boot only.
+	BootOnce = "Once"
+)
+
+// RequestError is returned for requests which were not successful.
+type RequestError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("redfish request failed with status code %d: %s", e.StatusCode, e.Message)
+}
+
+// Link references a Redfish resource.
+type Link struct {
+	ODataID string `json:"@odata.id"`
+}
+
+type collection struct {
+	Members []Link `json:"Members"`
+}
+
+type action struct {
+	Target string `json:"target"`
+}
+
+// Boot contains the boot source override of a system.
+type Boot struct {
+	BootSourceOverrideEnabled string `json:"BootSourceOverrideEnabled,omitempty"`
+	BootSourceOverrideTarget  string `json:"BootSourceOverrideTarget,omitempty"`
+}
+
+// ComputerSystem is a server managed by the BMC.
+type ComputerSystem struct {
+	ODataID string `json:"@odata.id"`
+	// ETag identifies the version of the system that was read. It is cleared when the system
+	// is modified, as the BMC assigns a new one.
+	ETag               string `json:"@odata.etag"`
+	ID                 string `json:"Id"`
+	Name               string `json:"Name"`
+	UUID               string `json:"UUID"`
+	AssetTag           string `json:"AssetTag"`
+	PowerState         string `json:"PowerState"`
+	Boot               Boot   `json:"Boot"`
+	EthernetInterfaces Link   `json:"EthernetInterfaces"`
+	Links              struct {
+		ManagedBy []Link `json:"ManagedBy"`
+	} `json:"Links"`
+	Actions struct {
+		Reset action `json:"#ComputerSystem.Reset"`
+	} `json:"Actions"`
+}
+
+// EthernetInterface is a NIC of a system.
+type EthernetInterface struct {
+	ID            string `json:"Id"`
+	MACAddress    string `json:"MACAddress"`
+	IPv4Addresses []struct {
+		Address string `json:"Address"`
+	} `json:"IPv4Addresses"`
+}
+
+type manager struct {
+	VirtualMedia Link `json:"VirtualMedia"`
+}
+
+// VirtualMedia is a virtual device of the BMC, into which images can be inserted.
+type VirtualMedia struct {
+	ODataID    string   `json:"@odata.id"`
+	ID         string   `json:"Id"`
+	MediaTypes []string `json:"MediaTypes"`
+	Image      string   `json:"Image"`
+	Inserted   bool     `json:"Inserted"`
+	Actions    struct {
+		InsertMedia action `json:"#VirtualMedia.InsertMedia"`
+		EjectMedia  action `json:"#VirtualMedia.EjectMedia"`
+	} `json:"Actions"`
+}
+
+// Client talks to the Redfish service of a BMC.
+type Client struct {
+	endpoint   string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the Redfish service at the endpoint, which authenticates with basic auth.
+func NewClient(endpoint, username, password string, insecureSkipTLSVerify bool) *Client {
+	return &Client{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: insecureSkipTLSVerify,
+				},
+				TLSHandshakeTimeout: defaultTimeout,
+			},
+			Timeout: defaultTimeout,
+		},
+	}
+}
+
+// GetSystem returns the system with the ID. If the ID is empty, the BMC must manage exactly one system.
+func (c *Client) GetSystem(ctx context.Context, id string) (*ComputerSystem, error) {
+	if id != "" {
+		system, err := c.getSystem(ctx, "/redfish/v1/Systems/"+id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get system %s: %w", id, err)
+		}
+		return system, nil
+	}
+
+	systems := &collection{}
+	if err := c.do(ctx, http.MethodGet, "/redfish/v1/Systems", nil, systems); err != nil {
+		return nil, fmt.Errorf("failed to list systems: %w", err)
+	}
+	if len(systems.Members) != 1 {
+		return nil, fmt.Errorf("BMC manages %d systems, the system ID must be specified", len(systems.Members))
+	}
+
+	system, err := c.getSystem(ctx, systems.Members[0].ODataID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system %s: %w", systems.Members[0].ODataID, err)
+	}
+	return system, nil
+}
+
+func (c *Client) getSystem(ctx context.Context, path string) (*ComputerSystem, error) {
+	system := &ComputerSystem{}
+	header, err := c.doWithHeader(ctx, http.MethodGet, path, nil, nil, system)
+	if err != nil {
+		return nil, err
+	}
+	// Some BMCs only return the ETag as header.
+	if etag := header.Get("ETag"); etag != "" {
+		system.ETag = etag
+	}
+	return system, nil
+}
+
+// SetAssetTag sets the asset tag of the system. If the ETag of the system is known, the asset tag
+// is only set if the system was not modified since it was read, otherwise the request fails with
+// the status code 412.
+func (c *Client) SetAssetTag(ctx context.Context, system *ComputerSystem, assetTag string) error {
+	var header http.Header
+	if system.ETag != "" {
+		header = http.Header{"If-Match": []string{system.ETag}}
+	}
+	patch := map[string]string{"AssetTag": assetTag}
+	if _, err := c.doWithHeader(ctx, http.MethodPatch, system.ODataID, header, patch, nil); err != nil {
+		return fmt.Errorf("failed to set asset tag of system %s: %w", system.ID, err)
+	}
+	system.AssetTag = assetTag
+	system.ETag = ""
+	return nil
+}
+
+// SetBootOnce makes the system boot from the target on its next boot.
+func (c *Client) SetBootOnce(ctx context.Context, system *ComputerSystem, target string) error {
+	boot := Boot{
+		BootSourceOverrideEnabled: BootOnce,
+		BootSourceOverrideTarget:  target,
+	}
+	if err := c.do(ctx, http.MethodPatch, system.ODataID, map[string]Boot{"Boot": boot}, nil); err != nil {
+		return fmt.Errorf("failed to set boot source override of system %s to %s: %w", system.ID, target, err)
+	}
+	system.Boot = boot
+	system.ETag = ""
+	return nil
+}
+
+// Reset changes the power state of the system according to the reset type.
+func (c *Client) Reset(ctx context.Context, system *ComputerSystem, resetType string) error {
+	target := system.Actions.Reset.Target
+	if target == "" {
+		target = system.ODataID + "/Actions/ComputerSystem.Reset"
+	}
+	if err := c.do(ctx, http.MethodPost, target, map[string]string{"ResetType": resetType}, nil); err != nil {
+		return fmt.Errorf("failed to reset system %s with %s: %w", system.ID, resetType, err)
+	}
+	system.ETag = ""
+	return nil
+}
+
+// ListEthernetInterfaces returns the NICs of the system.
+func (c *Client) ListEthernetInterfaces(ctx context.Context, system *ComputerSystem) ([]EthernetInterface, error) {
+	if system.EthernetInterfaces.ODataID == "" {
+		return nil, nil
+	}
+
+	nics := &collection{}
+	if err := c.do(ctx, http.MethodGet, system.EthernetInterfaces.ODataID, nil, nics); err != nil {
+		return nil, fmt.Errorf("failed to list ethernet interfaces of system %s: %w", system.ID, err)
+	}
+
+	result := make([]EthernetInterface, 0, len(nics.Members))
+	for _, member := range nics.Members {
+		nic := EthernetInterface{}
+		if err := c.do(ctx, http.MethodGet, member.ODataID, nil, &nic); err != nil {
+			return nil, fmt.Errorf("failed to get ethernet interface %s: %w", member.ODataID, err)
+		}
+		result = append(result, nic)
+	}
+	return result, nil
+}
+
+// IsCD returns true if CDs or DVDs can be inserted into the virtual media.
+func (m *VirtualMedia) IsCD() bool {
+	return slices.Contains(m.MediaTypes, "CD") || slices.Contains(m.MediaTypes, "DVD")
+}
+
+// ListVirtualMedia returns the virtual media of the manager of the system, which support CDs, DVDs
+// or USB sticks. Virtual CDs and DVDs are returned first.
+func (c *Client) ListVirtualMedia(ctx context.Context, system *ComputerSystem) ([]*VirtualMedia, error) {
+	if len(system.Links.ManagedBy) == 0 {
+		return nil, fmt.Errorf("system %s is not managed by any manager", system.ID)
+	}
+
+	mgr := &manager{}
+	if err := c.do(ctx, http.MethodGet, system.Links.ManagedBy[0].ODataID, nil, mgr); err != nil {
+		return nil, fmt.Errorf("failed to get manager of system %s: %w", system.ID, err)
+	}
+	if mgr.VirtualMedia.ODataID == "" {
+		return nil, fmt.Errorf("manager of system %s does not support virtual media", system.ID)
+	}
+
+	media := &collection{}
+	if err := c.do(ctx, http.MethodGet, mgr.VirtualMedia.ODataID, nil, media); err != nil {
+		return nil, fmt.Errorf("failed to list virtual media of system %s: %w", system.ID, err)
+	}
+
+	var cds, usbSticks []*VirtualMedia
+	for _, member := range media.Members {
+		vm := &VirtualMedia{}
+		if err := c.do(ctx, http.MethodGet, member.ODataID, nil, vm); err != nil {
+			return nil, fmt.Errorf("failed to get virtual media %s: %w", member.ODataID, err)
+		}
+		switch {
+		case vm.IsCD():
+			cds = append(cds, vm)
+		case slices.Contains(vm.MediaTypes, "USBStick"):
+			usbSticks = append(usbSticks, vm)
+		}
+	}
+
+	return append(cds, usbSticks...), nil
+}
+
+// InsertMedia inserts the image at the URL into the virtual media.
+func (c *Client) InsertMedia(ctx context.Context, media *VirtualMedia, image string) error {
+	target := media.Actions.InsertMedia.Target
+	if target == "" {
+		target = media.ODataID + "/Actions/VirtualMedia.InsertMedia"
+	}
+	body := map[string]interface{}{
+		"Image":          image,
+		"Inserted":       true,
+		"WriteProtected": true,
+	}
+	if err := c.do(ctx, http.MethodPost, target, body, nil); err != nil {
+		return fmt.Errorf("failed to insert %s into virtual media %s: %w", image, media.ID, err)
+	}
+	media.Image = image
+	media.Inserted = true
+	return nil
+}
+
+// EjectMedia ejects the image from the virtual media.
+func (c *Client) EjectMedia(ctx context.Context, media *VirtualMedia) error {
+	target := media.Actions.EjectMedia.Target
+	if target == "" {
+		target = media.ODataID + "/Actions/VirtualMedia.EjectMedia"
+	}
+	if err := c.do(ctx, http.MethodPost, target, map[string]interface{}{}, nil); err != nil {
+		return fmt.Errorf("failed to eject virtual media %s: %w", media.ID, err)
+	}
+	media.Image = ""
+	media.Inserted = false
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	_, err := c.doWithHeader(ctx, method, path, nil, body, out)
+	return err
+}
+
+// doWithHeader sends a request with the additional header and returns the header of the response.
+func (c *Client) doWithHeader(ctx context.Context, method, path string, header http.Header, body, out interface{}) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &RequestError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+
+	if out == nil {
+		return resp.Header, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return resp.Header, nil
+}
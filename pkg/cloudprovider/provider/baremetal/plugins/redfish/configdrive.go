@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// configDrives holds the config drives of the servers provisioned by this machine-controller, so
+// they can be served to the BMCs. They are kept in memory only, so a BMC must read the config
+// drive before the machine-controller restarts.
+var configDrives = &configDriveStore{images: map[types.UID][]byte{}}
+
+type configDriveStore struct {
+	lock    sync.RWMutex
+	baseURL string
+	images  map[types.UID][]byte
+}
+
+// SetConfigDriveURL sets the URL under which the BMCs reach the config drive server. If it is
+// empty, servers cannot be provisioned, as their userdata cannot be passed to them.
+func SetConfigDriveURL(url string) {
+	configDrives.lock.Lock()
+	defer configDrives.lock.Unlock()
+
+	configDrives.baseURL = strings.TrimSuffix(url, "/")
+}
+
+// AddConfigDriveServer adds a server to the manager, which serves the config drives of the
+// provisioned servers at the address. The URL set by SetConfigDriveURL must reach it.
+func AddConfigDriveServer(mgr manager.Manager, log *zap.SugaredLogger, address string) error {
+	return mgr.Add(&configDriveServer{
+		log: log.Named("redfish-config-drive-server"),
+		server: &http.Server{
+			Addr:              address,
+			Handler:           configDrives,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	})
+}
+
+type configDriveServer struct {
+	log    *zap.SugaredLogger
+	server *http.Server
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Only the leader provisions
+// servers and thus holds their config drives.
+func (s *configDriveServer) NeedLeaderElection() bool {
+	return true
+}
+
+// Start serves the config drives until the context is done.
+// Start is part of manager.Runnable.
+func (s *configDriveServer) Start(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		if err := s.server.Shutdown(context.Background()); err != nil {
+			s.log.Errorw("Failed to shut down config drive server", zap.Error(err))
+		}
+	}()
+
+	s.log.Infow("Serving config drives", "address", s.server.Addr)
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve config drives: %w", err)
+	}
+	return nil
+}
+
+// add stores the config drive of the machine and returns the URL under which it is served.
+func (s *configDriveStore) add(uid types.UID, image []byte) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.baseURL == "" {
+		return "", errors.New("no URL is configured for the config drive server, which passes the userdata to the servers")
+	}
+	s.images[uid] = image
+	return fmt.Sprintf("%s/%s.iso", s.baseURL, uid), nil
+}
+
+func (s *configDriveStore) remove(uid types.UID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.images, uid)
+}
+
+// ServeHTTP serves the config drive of a machine at /<machine-uid>.iso.
+func (s *configDriveStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	uid, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/"), ".iso")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.lock.RLock()
+	image, ok := s.images[types.UID(uid)]
+	s.lock.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, uid+".iso", time.Time{}, bytes.NewReader(image))
+}
+
+// buildConfigDrive returns an ISO, which passes the userdata to cloud-init by its NoCloud datasource.
+func buildConfigDrive(ctx context.Context, meta metav1.ObjectMeta, userdata string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "redfish-config-drive")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory for the config drive: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path, err := util.GenerateUserdataISO(ctx, dir, userdata, meta.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate config drive: %w", err)
+	}
+
+	image, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config drive: %w", err)
+	}
+	return image, nil
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"text/template"
+
+	"go.uber.org/zap"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins/redfish/client"
+	redfishtypes "k8c.io/machine-controller/sdk/cloudprovider/baremetal/plugins/redfish"
+	providerconfigtypes "k8c.io/machine-controller/sdk/providerconfig"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// driver manages a single server through the Redfish service of its BMC. The server is claimed
+// by a machine by setting the asset tag of the system to the UID of the machine. The userdata is
+// passed to the server by a config drive, which is inserted as virtual media.
+type driver struct {
+	client     *client.Client
+	systemID   string
+	bootSource redfishtypes.BootSource
+	isoURL     *template.Template
+
+	buildConfigDrive func(ctx context.Context, meta metav1.ObjectMeta, userdata string) ([]byte, error)
+}
+
+// isoURLData is passed to the ISO URL template.
+type isoURLData struct {
+	MachineName string
+	MachineUID  types.UID
+}
+
+// NewRedfishDriver returns a new Redfish driver for the BMC of the config.
+func NewRedfishDriver(config redfishtypes.Config) (plugins.PluginDriver, error) {
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
+	d := &driver{
+		client:     client.NewClient(config.Endpoint, config.Username, config.Password, config.InsecureSkipTLSVerify),
+		systemID:   config.SystemID,
+		bootSource: config.BootSource,
+
+		buildConfigDrive: buildConfigDrive,
+	}
+
+	if config.BootSource == redfishtypes.BootSourceVirtualMedia {
+		// The template was parsed by validateConfig already.
+		d.isoURL = template.Must(template.New("isoUrl").Parse(config.ISOURL))
+	}
+
+	return d, nil
+}
+
+func (d *driver) GetServer(ctx context.Context, uid types.UID) (plugins.Server, error) {
+	system, err := d.client.GetSystem(ctx, d.systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if uid == "" || system.AssetTag != string(uid) {
+		return nil, cloudprovidererrors.ErrInstanceNotFound
+	}
+
+	return d.server(ctx, system)
+}
+
+func (d *driver) ProvisionServer(ctx context.Context, log *zap.SugaredLogger, meta metav1.ObjectMeta, _ runtime.RawExtension, userdata string) (plugins.Server, error) {
+	system, err := d.client.GetSystem(ctx, d.systemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if system.AssetTag != "" && system.AssetTag != string(meta.UID) {
+		return nil, fmt.Errorf("system %s is already claimed by the machine with the UID %s", system.ID, system.AssetTag)
+	}
+
+	configDrive, err := d.buildConfigDrive(ctx, meta, userdata)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claim the system first, so it's released by DeprovisionServer if anything below fails
+	// and the machine is deleted. The claim fails if the system was modified since it was read,
+	// e.g. because another machine claimed it concurrently.
+	if err := d.client.SetAssetTag(ctx, system, string(meta.UID)); err != nil {
+		return nil, err
+	}
+
+	if err := d.boot(ctx, log, meta, system, configDrive); err != nil {
+		configDrives.remove(meta.UID)
+		// Release the claim, so the system can be provisioned from scratch by the next attempt.
+		if releaseErr := d.client.SetAssetTag(ctx, system, ""); releaseErr != nil {
+			return nil, kerrors.NewAggregate([]error{err, releaseErr})
+		}
+		return nil, err
+	}
+
+	return d.server(ctx, system)
+}
+
+// boot inserts the config drive, sets the one-time boot source of the system and powers it on.
+func (d *driver) boot(ctx context.Context, log *zap.SugaredLogger, meta metav1.ObjectMeta, system *client.ComputerSystem, configDrive []byte) error {
+	media, err := d.client.ListVirtualMedia(ctx, system)
+	if err != nil {
+		return err
+	}
+
+	var images []string
+	target := client.BootTargetPxe
+	if d.bootSource == redfishtypes.BootSourceVirtualMedia {
+		if len(media) == 0 || !media[0].IsCD() {
+			return fmt.Errorf("manager of system %s has no virtual CD", system.ID)
+		}
+		image, err := d.renderISOURL(meta)
+		if err != nil {
+			return err
+		}
+		images = append(images, image)
+		target = client.BootTargetCd
+	}
+
+	configDriveURL, err := configDrives.add(meta.UID, configDrive)
+	if err != nil {
+		return err
+	}
+	images = append(images, configDriveURL)
+
+	if len(media) < len(images) {
+		return fmt.Errorf("manager of system %s has %d virtual CDs or USB sticks, but %d are required for the boot ISO and the config drive", system.ID, len(media), len(images))
+	}
+	for i, image := range images {
+		if err := d.insertMedia(ctx, media[i], image); err != nil {
+			return err
+		}
+	}
+
+	if err := d.client.SetBootOnce(ctx, system, target); err != nil {
+		return err
+	}
+
+	resetType := client.ResetOn
+	if system.PowerState == client.PowerStateOn {
+		resetType = client.ResetForceRestart
+	}
+	if err := d.client.Reset(ctx, system, resetType); err != nil {
+		return err
+	}
+	system.PowerState = client.PowerStateOn
+
+	log.Infow("Booting system", "system", system.ID, "bootTarget", target)
+	return nil
+}
+
+// insertMedia replaces the image inserted into the virtual media.
+func (d *driver) insertMedia(ctx context.Context, media *client.VirtualMedia, image string) error {
+	if media.Inserted {
+		if err := d.client.EjectMedia(ctx, media); err != nil {
+			return err
+		}
+	}
+	return d.client.InsertMedia(ctx, media, image)
+}
+
+func (d *driver) renderISOURL(meta metav1.ObjectMeta) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := d.isoURL.Execute(buf, isoURLData{MachineName: meta.Name, MachineUID: meta.UID}); err != nil {
+		return "", fmt.Errorf("failed to render isoUrl: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (d *driver) DeprovisionServer(ctx context.Context, uid types.UID) error {
+	system, err := d.client.GetSystem(ctx, d.systemID)
+	if err != nil {
+		return err
+	}
+
+	if uid == "" || system.AssetTag != string(uid) {
+		// The machine did not claim the system or it was released already.
+		return nil
+	}
+
+	if system.PowerState != client.PowerStateOff {
+		if err := d.client.Reset(ctx, system, client.ResetForceOff); err != nil {
+			return err
+		}
+	}
+
+	media, err := d.client.ListVirtualMedia(ctx, system)
+	if err != nil {
+		return err
+	}
+	for _, m := range media {
+		if m.Inserted {
+			if err := d.client.EjectMedia(ctx, m); err != nil {
+				return err
+			}
+		}
+	}
+	configDrives.remove(uid)
+
+	// Reset the asset tag of the system. This releases the claim of the machine.
+	return d.client.SetAssetTag(ctx, system, "")
+}
+
+func (d *driver) Validate(spec runtime.RawExtension) error {
+	redfishSpec := &redfishtypes.RedfishPluginSpec{}
+	if err := json.Unmarshal(spec.Raw, redfishSpec); err != nil {
+		return fmt.Errorf("failed to unmarshal redfish driver spec: %w", err)
+	}
+
+	switch redfishSpec.BootSource {
+	case "", redfishtypes.BootSourcePXE, redfishtypes.BootSourceVirtualMedia:
+		return nil
+	default:
+		return fmt.Errorf("unsupported bootSource %q, expected %q or %q", redfishSpec.BootSource, redfishtypes.BootSourcePXE, redfishtypes.BootSourceVirtualMedia)
+	}
+}
+
+func validateConfig(config redfishtypes.Config) error {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %w", err)
+	}
+	if endpoint.Scheme != "https" && endpoint.Scheme != "http" || endpoint.Host == "" {
+		return fmt.Errorf("invalid endpoint %q, expected an http or https URL", config.Endpoint)
+	}
+
+	switch config.BootSource {
+	case redfishtypes.BootSourcePXE:
+	case redfishtypes.BootSourceVirtualMedia:
+		if config.ISOURL == "" {
+			return errors.New("isoUrl must be specified if the bootSource is VirtualMedia")
+		}
+		if _, err := template.New("isoUrl").Parse(config.ISOURL); err != nil {
+			return fmt.Errorf("invalid isoUrl: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported bootSource %q, expected %q or %q", config.BootSource, redfishtypes.BootSourcePXE, redfishtypes.BootSourceVirtualMedia)
+	}
+
+	return nil
+}
+
+func (d *driver) server(ctx context.Context, system *client.ComputerSystem) (plugins.Server, error) {
+	nics, err := d.client.ListEthernetInterfaces(ctx, system)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &server{
+		name:   system.Name,
+		id:     system.AssetTag,
+		status: system.PowerState,
+	}
+	if s.name == "" {
+		s.name = system.ID
+	}
+	for _, nic := range nics {
+		if s.macAddress == "" {
+			s.macAddress = nic.MACAddress
+		}
+		if s.ipAddress == "" && len(nic.IPv4Addresses) > 0 {
+			s.ipAddress = nic.IPv4Addresses[0].Address
+		}
+	}
+
+	return s, nil
+}
+
+func GetConfig(driverConfig redfishtypes.RedfishPluginSpec, valueFromStringOrEnvVar func(configVar providerconfigtypes.ConfigVarString, envVarName string) (string, error)) (*redfishtypes.Config, error) {
+	config := redfishtypes.Config{
+		SystemID:              driverConfig.SystemID,
+		InsecureSkipTLSVerify: driverConfig.InsecureSkipTLSVerify,
+		BootSource:            driverConfig.BootSource,
+	}
+	if config.BootSource == "" {
+		config.BootSource = redfishtypes.BootSourcePXE
+	}
+
+	var err error
+	config.Endpoint, err = valueFromStringOrEnvVar(driverConfig.Endpoint, "REDFISH_ENDPOINT")
+	if err != nil {
+		return nil, fmt.Errorf(`failed to get value of "endpoint" field: %w`, err)
+	}
+
+	config.Username, err = valueFromStringOrEnvVar(driverConfig.Auth.Username, "REDFISH_USERNAME")
+	if err != nil {
+		return nil, fmt.Errorf(`failed to get value of "username" field: %w`, err)
+	}
+
+	config.Password, err = valueFromStringOrEnvVar(driverConfig.Auth.Password, "REDFISH_PASSWORD")
+	if err != nil {
+		return nil, fmt.Errorf(`failed to get value of "password" field: %w`, err)
+	}
+
+	config.ISOURL, err = valueFromStringOrEnvVar(driverConfig.ISOURL, "REDFISH_ISO_URL")
+	if err != nil {
+		return nil, fmt.Errorf(`failed to get value of "isoUrl" field: %w`, err)
+	}
+
+	return &config, nil
+}
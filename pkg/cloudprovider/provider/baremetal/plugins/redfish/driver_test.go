@@ -0,0 +1,337 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins/redfish/client"
+	redfishtypes "k8c.io/machine-controller/sdk/cloudprovider/baremetal/plugins/redfish"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const configDriveURL = "http://controller.example.com/machine-a-uid.iso"
+
+func newDriver(t *testing.T, endpoint string, bootSource redfishtypes.BootSource) *driver {
+	d, err := NewRedfishDriver(redfishtypes.Config{
+		Endpoint:   endpoint,
+		Username:   mockUsername,
+		Password:   mockPassword,
+		BootSource: bootSource,
+		ISOURL:     "http://images.example.com/{{ .MachineName }}.iso",
+	})
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+
+	SetConfigDriveURL("http://controller.example.com/")
+	t.Cleanup(func() { SetConfigDriveURL("") })
+
+	drv := d.(*driver)
+	// Building ISOs requires genisoimage or mkisofs, so the userdata is served as is.
+	drv.buildConfigDrive = func(_ context.Context, _ metav1.ObjectMeta, userdata string) ([]byte, error) {
+		return []byte(userdata), nil
+	}
+	return drv
+}
+
+// getConfigDrive returns the config drive served for the machine, or nil if none is served.
+func getConfigDrive(t *testing.T, uid string) []byte {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	configDrives.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/"+uid+".iso", nil))
+	if recorder.Code == http.StatusNotFound {
+		return nil
+	}
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("failed to get config drive of %s: status code %d", uid, recorder.Code)
+	}
+	return recorder.Body.Bytes()
+}
+
+func TestProvisionServer(t *testing.T) {
+	testCases := []struct {
+		name          string
+		bootSource    redfishtypes.BootSource
+		powerState    string
+		expectedBoot  string
+		expectedMedia map[string]string
+		expectedReset string
+	}{
+		{
+			name:          "pxe boot of a powered off server",
+			bootSource:    redfishtypes.BootSourcePXE,
+			powerState:    "Off",
+			expectedBoot:  "Pxe",
+			expectedMedia: map[string]string{"Floppy": "", "Cd": configDriveURL, "Usb": ""},
+			expectedReset: "On",
+		},
+		{
+			name:          "virtual media boot of a powered on server",
+			bootSource:    redfishtypes.BootSourceVirtualMedia,
+			powerState:    "On",
+			expectedBoot:  "Cd",
+			expectedMedia: map[string]string{"Floppy": "", "Cd": "http://images.example.com/machine-a.iso", "Usb": configDriveURL},
+			expectedReset: "ForceRestart",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			bmc, server := newMockBMC(t)
+			bmc.locked(func() {
+				bmc.powerState = tc.powerState
+				// A stale image must be replaced.
+				bmc.media["Cd"] = "http://images.example.com/stale.iso"
+			})
+			d := newDriver(t, server.URL, tc.bootSource)
+
+			if _, err := d.GetServer(ctx, "machine-a-uid"); !errors.Is(err, cloudprovidererrors.ErrInstanceNotFound) {
+				t.Fatalf("expected unclaimed server to be not found, got %v", err)
+			}
+
+			meta := metav1.ObjectMeta{Name: "machine-a", UID: "machine-a-uid"}
+			provisioned, err := d.ProvisionServer(ctx, zap.NewNop().Sugar(), meta, runtime.RawExtension{}, "#cloud-config")
+			if err != nil {
+				t.Fatalf("failed to provision server: %v", err)
+			}
+
+			bmc.locked(func() {
+				if bmc.assetTag != "machine-a-uid" {
+					t.Errorf("expected server to be claimed by machine-a-uid, got %q", bmc.assetTag)
+				}
+				if bmc.boot["BootSourceOverrideTarget"] != tc.expectedBoot || bmc.boot["BootSourceOverrideEnabled"] != "Once" {
+					t.Errorf("expected one-time boot from %s, got %v", tc.expectedBoot, bmc.boot)
+				}
+				for id, expected := range tc.expectedMedia {
+					if bmc.media[id] != expected {
+						t.Errorf("expected %q in the virtual media %s, got %q", expected, id, bmc.media[id])
+					}
+				}
+				if !slices.Equal(bmc.resets, []string{tc.expectedReset}) {
+					t.Errorf("expected reset %s, got %v", tc.expectedReset, bmc.resets)
+				}
+			})
+
+			if configDrive := getConfigDrive(t, "machine-a-uid"); string(configDrive) != "#cloud-config" {
+				t.Errorf("expected config drive with the userdata to be served, got %q", configDrive)
+			}
+
+			found, err := d.GetServer(ctx, "machine-a-uid")
+			if err != nil {
+				t.Fatalf("failed to get server: %v", err)
+			}
+			for _, s := range []plugins.Server{provisioned, found} {
+				if s.GetID() != "machine-a-uid" || s.GetName() != "server-1" || s.GetStatus() != "On" {
+					t.Errorf("unexpected server %s with ID %s in power state %s", s.GetName(), s.GetID(), s.GetStatus())
+				}
+				if s.GetIPAddress() != "10.0.0.20" || s.GetMACAddress() != "52:54:00:12:34:56" {
+					t.Errorf("unexpected addresses of server: %s, %s", s.GetIPAddress(), s.GetMACAddress())
+				}
+			}
+		})
+	}
+}
+
+func TestProvisionServerClaimedByOtherMachine(t *testing.T) {
+	bmc, server := newMockBMC(t)
+	bmc.locked(func() { bmc.assetTag = "machine-b-uid" })
+	d := newDriver(t, server.URL, redfishtypes.BootSourcePXE)
+
+	meta := metav1.ObjectMeta{Name: "machine-a", UID: "machine-a-uid"}
+	if _, err := d.ProvisionServer(context.Background(), zap.NewNop().Sugar(), meta, runtime.RawExtension{}, ""); err == nil {
+		t.Fatal("expected provisioning of a server claimed by another machine to fail")
+	}
+	bmc.locked(func() {
+		if bmc.assetTag != "machine-b-uid" || len(bmc.resets) > 0 {
+			t.Errorf("expected server of machine-b-uid to be untouched, got asset tag %q and resets %v", bmc.assetTag, bmc.resets)
+		}
+	})
+}
+
+func TestProvisionServerReleasesClaimOnFailure(t *testing.T) {
+	testCases := []struct {
+		name  string
+		setup func(bmc *mockBMC)
+	}{
+		{
+			name:  "failed reset",
+			setup: func(bmc *mockBMC) { bmc.locked(func() { bmc.failResets = true }) },
+		},
+		{
+			name:  "no config drive URL",
+			setup: func(*mockBMC) { SetConfigDriveURL("") },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bmc, server := newMockBMC(t)
+			d := newDriver(t, server.URL, redfishtypes.BootSourcePXE)
+			tc.setup(bmc)
+
+			meta := metav1.ObjectMeta{Name: "machine-a", UID: "machine-a-uid"}
+			if _, err := d.ProvisionServer(context.Background(), zap.NewNop().Sugar(), meta, runtime.RawExtension{}, "#cloud-config"); err == nil {
+				t.Fatal("expected provisioning to fail")
+			}
+			bmc.locked(func() {
+				if bmc.assetTag != "" {
+					t.Errorf("expected claim to be released, got asset tag %q", bmc.assetTag)
+				}
+			})
+			if configDrive := getConfigDrive(t, "machine-a-uid"); configDrive != nil {
+				t.Errorf("expected config drive to be removed, got %q", configDrive)
+			}
+		})
+	}
+}
+
+func TestClaimOfModifiedSystemFails(t *testing.T) {
+	ctx := context.Background()
+	bmc, server := newMockBMC(t)
+	d := newDriver(t, server.URL, redfishtypes.BootSourcePXE)
+
+	system, err := d.client.GetSystem(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to get system: %v", err)
+	}
+	// Another machine claims the system in the meantime.
+	bmc.locked(func() {
+		bmc.assetTag = "machine-b-uid"
+		bmc.version++
+	})
+
+	var requestErr *client.RequestError
+	if err := d.client.SetAssetTag(ctx, system, "machine-a-uid"); !errors.As(err, &requestErr) || requestErr.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("expected claim of modified system to fail with status code 412, got %v", err)
+	}
+	bmc.locked(func() {
+		if bmc.assetTag != "machine-b-uid" {
+			t.Errorf("expected system to stay claimed by machine-b-uid, got asset tag %q", bmc.assetTag)
+		}
+	})
+}
+
+func TestDeprovisionServer(t *testing.T) {
+	ctx := context.Background()
+	bmc, server := newMockBMC(t)
+	d := newDriver(t, server.URL, redfishtypes.BootSourceVirtualMedia)
+
+	meta := metav1.ObjectMeta{Name: "machine-a", UID: "machine-a-uid"}
+	if _, err := d.ProvisionServer(ctx, zap.NewNop().Sugar(), meta, runtime.RawExtension{}, ""); err != nil {
+		t.Fatalf("failed to provision server: %v", err)
+	}
+
+	// Another machine must not release the server.
+	if err := d.DeprovisionServer(ctx, "machine-b-uid"); err != nil {
+		t.Fatalf("failed to deprovision server of other machine: %v", err)
+	}
+	bmc.locked(func() {
+		if bmc.assetTag != "machine-a-uid" || bmc.powerState != "On" {
+			t.Errorf("expected server to be untouched, got asset tag %q in power state %s", bmc.assetTag, bmc.powerState)
+		}
+	})
+
+	// Deprovisioning must be idempotent.
+	for range 2 {
+		if err := d.DeprovisionServer(ctx, "machine-a-uid"); err != nil {
+			t.Fatalf("failed to deprovision server: %v", err)
+		}
+	}
+	bmc.locked(func() {
+		if bmc.assetTag != "" || bmc.powerState != "Off" || bmc.media["Cd"] != "" || bmc.media["Usb"] != "" {
+			t.Errorf("expected server to be released, powered off and the media to be ejected, got asset tag %q, power state %s and media %v", bmc.assetTag, bmc.powerState, bmc.media)
+		}
+		if !slices.Equal(bmc.resets, []string{"On", "ForceOff"}) {
+			t.Errorf("expected server to be powered off once, got resets %v", bmc.resets)
+		}
+	})
+	if configDrive := getConfigDrive(t, "machine-a-uid"); configDrive != nil {
+		t.Errorf("expected config drive to be removed, got %q", configDrive)
+	}
+}
+
+func TestGetServerRequiresCredentials(t *testing.T) {
+	_, server := newMockBMC(t)
+	d, err := NewRedfishDriver(redfishtypes.Config{Endpoint: server.URL, Username: mockUsername, Password: "wrong", BootSource: redfishtypes.BootSourcePXE})
+	if err != nil {
+		t.Fatalf("failed to create driver: %v", err)
+	}
+
+	if _, err := d.GetServer(context.Background(), "machine-a-uid"); err == nil || errors.Is(err, cloudprovidererrors.ErrInstanceNotFound) {
+		t.Errorf("expected request with invalid credentials to fail, got %v", err)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config redfishtypes.Config
+		valid  bool
+	}{
+		{
+			name:   "pxe boot",
+			config: redfishtypes.Config{Endpoint: "https://10.0.0.10", BootSource: redfishtypes.BootSourcePXE},
+			valid:  true,
+		},
+		{
+			name:   "virtual media boot",
+			config: redfishtypes.Config{Endpoint: "https://10.0.0.10", BootSource: redfishtypes.BootSourceVirtualMedia, ISOURL: "http://images.example.com/{{ .MachineUID }}.iso"},
+			valid:  true,
+		},
+		{
+			name:   "missing endpoint",
+			config: redfishtypes.Config{BootSource: redfishtypes.BootSourcePXE},
+		},
+		{
+			name:   "endpoint without scheme",
+			config: redfishtypes.Config{Endpoint: "10.0.0.10", BootSource: redfishtypes.BootSourcePXE},
+		},
+		{
+			name:   "virtual media boot without ISO",
+			config: redfishtypes.Config{Endpoint: "https://10.0.0.10", BootSource: redfishtypes.BootSourceVirtualMedia},
+		},
+		{
+			name:   "invalid ISO URL template",
+			config: redfishtypes.Config{Endpoint: "https://10.0.0.10", BootSource: redfishtypes.BootSourceVirtualMedia, ISOURL: "http://images.example.com/{{ .MachineName"},
+		},
+		{
+			name:   "unsupported boot source",
+			config: redfishtypes.Config{Endpoint: "https://10.0.0.10", BootSource: "Usb"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := validateConfig(tc.config); (err == nil) != tc.valid {
+				t.Errorf("expected valid to be %t, got error %v", tc.valid, err)
+			}
+		})
+	}
+}
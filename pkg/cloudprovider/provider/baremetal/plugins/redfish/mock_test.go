@@ -0,0 +1,256 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const (
+	mockUsername = "admin"
+	mockPassword = "secret"
+)
+
+// mockBMC is a Redfish service managing a single system with one NIC and a virtual floppy, CD
+// and USB stick.
+type mockBMC struct {
+	lock sync.Mutex
+
+	// version is increased on every modification of the system and used as its ETag.
+	version    int
+	assetTag   string
+	powerState string
+	boot       map[string]string
+	// media maps the IDs of the virtual media to the inserted images.
+	media map[string]string
+	// resets records the reset types of all reset requests.
+	resets []string
+	// failResets makes all reset requests fail.
+	failResets bool
+}
+
+func newMockBMC(t *testing.T) (*mockBMC, *httptest.Server) {
+	bmc := &mockBMC{
+		powerState: "Off",
+		boot:       map[string]string{},
+		media:      map[string]string{"Floppy": "", "Cd": "", "Usb": ""},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /redfish/v1/Systems", func(w http.ResponseWriter, _ *http.Request) {
+		bmc.writeJSON(w, map[string]interface{}{"Members": []interface{}{link("/redfish/v1/Systems/1")}})
+	})
+	mux.HandleFunc("GET /redfish/v1/Systems/1", bmc.getSystem)
+	mux.HandleFunc("PATCH /redfish/v1/Systems/1", bmc.patchSystem)
+	mux.HandleFunc("POST /redfish/v1/Systems/1/Actions/ComputerSystem.Reset", bmc.reset)
+	mux.HandleFunc("GET /redfish/v1/Systems/1/EthernetInterfaces", func(w http.ResponseWriter, _ *http.Request) {
+		bmc.writeJSON(w, map[string]interface{}{"Members": []interface{}{link("/redfish/v1/Systems/1/EthernetInterfaces/1")}})
+	})
+	mux.HandleFunc("GET /redfish/v1/Systems/1/EthernetInterfaces/1", func(w http.ResponseWriter, _ *http.Request) {
+		bmc.writeJSON(w, map[string]interface{}{
+			"Id":            "1",
+			"MACAddress":    "52:54:00:12:34:56",
+			"IPv4Addresses": []interface{}{map[string]string{"Address": "10.0.0.20"}},
+		})
+	})
+	mux.HandleFunc("GET /redfish/v1/Managers/1", func(w http.ResponseWriter, _ *http.Request) {
+		bmc.writeJSON(w, map[string]interface{}{"VirtualMedia": link("/redfish/v1/Managers/1/VirtualMedia")})
+	})
+	mux.HandleFunc("GET /redfish/v1/Managers/1/VirtualMedia", func(w http.ResponseWriter, _ *http.Request) {
+		bmc.writeJSON(w, map[string]interface{}{"Members": []interface{}{
+			link("/redfish/v1/Managers/1/VirtualMedia/Floppy"),
+			link("/redfish/v1/Managers/1/VirtualMedia/Cd"),
+			link("/redfish/v1/Managers/1/VirtualMedia/Usb"),
+		}})
+	})
+	mux.HandleFunc("GET /redfish/v1/Managers/1/VirtualMedia/{id}", bmc.getVirtualMedia)
+	mux.HandleFunc("POST /redfish/v1/Managers/1/VirtualMedia/{id}/Actions/VirtualMedia.InsertMedia", bmc.insertMedia)
+	mux.HandleFunc("POST /redfish/v1/Managers/1/VirtualMedia/{id}/Actions/VirtualMedia.EjectMedia", bmc.ejectMedia)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != mockUsername || password != mockPassword {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return bmc, server
+}
+
+// locked runs the function while holding the lock of the BMC, so tests can access its state.
+func (b *mockBMC) locked(f func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	f()
+}
+
+func (b *mockBMC) etag() string {
+	return fmt.Sprintf(`W/"%d"`, b.version)
+}
+
+func link(id string) map[string]string {
+	return map[string]string{"@odata.id": id}
+}
+
+func (b *mockBMC) writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (b *mockBMC) getSystem(w http.ResponseWriter, _ *http.Request) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.writeJSON(w, map[string]interface{}{
+		"@odata.id":          "/redfish/v1/Systems/1",
+		"@odata.etag":        b.etag(),
+		"Id":                 "1",
+		"Name":               "server-1",
+		"AssetTag":           b.assetTag,
+		"PowerState":         b.powerState,
+		"Boot":               b.boot,
+		"EthernetInterfaces": link("/redfish/v1/Systems/1/EthernetInterfaces"),
+		"Links": map[string]interface{}{
+			"ManagedBy": []interface{}{link("/redfish/v1/Managers/1")},
+		},
+		"Actions": map[string]interface{}{
+			"#ComputerSystem.Reset": map[string]string{"target": "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"},
+		},
+	})
+}
+
+func (b *mockBMC) patchSystem(w http.ResponseWriter, r *http.Request) {
+	patch := struct {
+		AssetTag *string           `json:"AssetTag"`
+		Boot     map[string]string `json:"Boot"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && ifMatch != b.etag() {
+		http.Error(w, "system was modified", http.StatusPreconditionFailed)
+		return
+	}
+
+	b.version++
+	if patch.AssetTag != nil {
+		b.assetTag = *patch.AssetTag
+	}
+	for key, value := range patch.Boot {
+		b.boot[key] = value
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (b *mockBMC) reset(w http.ResponseWriter, r *http.Request) {
+	body := struct {
+		ResetType string `json:"ResetType"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.failResets {
+		http.Error(w, "reset failed", http.StatusInternalServerError)
+		return
+	}
+
+	b.version++
+	b.resets = append(b.resets, body.ResetType)
+	switch body.ResetType {
+	case "On", "ForceRestart":
+		b.powerState = "On"
+	case "ForceOff":
+		b.powerState = "Off"
+	default:
+		http.Error(w, "unsupported reset type", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (b *mockBMC) getVirtualMedia(w http.ResponseWriter, r *http.Request) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	id := r.PathValue("id")
+	image, ok := b.media[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	mediaType := "CD"
+	switch id {
+	case "Floppy":
+		mediaType = "Floppy"
+	case "Usb":
+		mediaType = "USBStick"
+	}
+	b.writeJSON(w, map[string]interface{}{
+		"@odata.id":  "/redfish/v1/Managers/1/VirtualMedia/" + id,
+		"Id":         id,
+		"MediaTypes": []string{mediaType},
+		"Image":      image,
+		"Inserted":   image != "",
+	})
+}
+
+func (b *mockBMC) insertMedia(w http.ResponseWriter, r *http.Request) {
+	body := struct {
+		Image string `json:"Image"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	id := r.PathValue("id")
+	if b.media[id] != "" {
+		http.Error(w, "media is inserted already", http.StatusConflict)
+		return
+	}
+	b.media[id] = body.Image
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (b *mockBMC) ejectMedia(w http.ResponseWriter, r *http.Request) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.media[r.PathValue("id")] = ""
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins"
+)
+
+// server is a system managed by a BMC, which was claimed by a machine.
+type server struct {
+	name       string
+	id         string
+	ipAddress  string
+	macAddress string
+	status     string
+}
+
+var _ plugins.Server = &server{}
+
+func (s *server) GetName() string {
+	return s.name
+}
+
+// GetID returns the UID of the machine which claimed the system.
+func (s *server) GetID() string {
+	return s.id
+}
+
+func (s *server) GetIPAddress() string {
+	return s.ipAddress
+}
+
+func (s *server) GetMACAddress() string {
+	return s.macAddress
+}
+
+// GetStatus returns the power state of the system.
+func (s *server) GetStatus() string {
+	return s.status
+}
@@ -25,6 +25,7 @@ import (
 	"k8c.io/machine-controller/pkg/cloudprovider/errors"
 	tbtypes "k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins/tinkerbell/types"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -52,7 +53,22 @@ func (h *HardwareClient) GetHardware(ctx context.Context, hardwareRef types.Name
 	return hardware, nil
 }
 
-// SetHardwareID sets the ID of a specified Hardware object.
+// ListHardware lists the hardware objects in the namespace matching the selector. An empty namespace
+// lists the hardware objects in all namespaces.
+func (h *HardwareClient) ListHardware(ctx context.Context, namespace string, selector labels.Selector) ([]tinkv1alpha1.Hardware, error) {
+	var hardwares tinkv1alpha1.HardwareList
+	if err := h.TinkerbellClient.List(ctx, &hardwares, &ctrlruntimeclient.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: selector,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list hardware: %w", err)
+	}
+
+	return hardwares.Items, nil
+}
+
+// SetHardwareID sets the ID of a specified Hardware object. The update fails with a conflict if the
+// hardware object was modified since it was read, so setting the ID claims the hardware atomically.
 func (h *HardwareClient) SetHardwareID(ctx context.Context, hardware *tinkv1alpha1.Hardware, newID string) error {
 	if hardware.Spec.Metadata == nil {
 		hardware.Spec.Metadata = &tinkv1alpha1.HardwareMetadata{}
@@ -77,7 +93,13 @@ func (h *HardwareClient) SetHardwareID(ctx context.Context, hardware *tinkv1alph
 	return nil
 }
 
+// GetHardwareWithID returns the hardware object claimed by the machine with the given UID.
 func (h *HardwareClient) GetHardwareWithID(ctx context.Context, uid string) (*tinkv1alpha1.Hardware, error) {
+	// Free hardware objects have an empty ID.
+	if uid == "" {
+		return nil, errors.ErrInstanceNotFound
+	}
+
 	// List all hardware in the cluster
 	var hardwares tinkv1alpha1.HardwareList
 	if err := h.TinkerbellClient.List(ctx, &hardwares); err != nil {
@@ -86,7 +108,7 @@ func (h *HardwareClient) GetHardwareWithID(ctx context.Context, uid string) (*ti
 
 	// Find the Hardware with the given ID
 	for _, hw := range hardwares.Items {
-		if hw.Spec.Metadata.Instance.ID == uid {
+		if hw.Spec.Metadata != nil && hw.Spec.Metadata.Instance != nil && hw.Spec.Metadata.Instance.ID == uid {
 			return &hw, nil
 		}
 	}
@@ -18,12 +18,14 @@ package client
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 
 	tinkv1alpha1 "github.com/tinkerbell/tink/api/v1alpha1"
 	"gopkg.in/yaml.v3"
 
 	"k8c.io/machine-controller/pkg/mirror"
+	tinktypes "k8c.io/machine-controller/sdk/cloudprovider/baremetal/plugins/tinkerbell"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -57,13 +59,21 @@ type Template struct {
 }
 
 const (
-	fsType                      = "ext4"
+	ext4FSType                  = "ext4"
+	xfsFSType                   = "xfs"
 	defaultInterpreter          = "/bin/sh -c"
 	hardwareDisk1               = "{{ index .Hardware.Disks 0 }}"
 	hardwareName                = "{{.hardware_name}}"
 	ProvisionWorkerNodeTemplate = "provision-worker-node"
 	PartitionNumber             = "{{.partition_number}}"
 	OSImageURL                  = "{{.os_image}}"
+
+	// flatcarRootPartitionNumber is the ROOT partition of Flatcar images.
+	flatcarRootPartitionNumber = "9"
+	// flatcarOEMPartitionNumber is the OEM partition of Flatcar images, from which Ignition reads its config.
+	flatcarOEMPartitionNumber = "6"
+	// rhelRootPartitionNumber is the root partition of RHEL and Rocky Linux cloud images.
+	rhelRootPartitionNumber = "4"
 )
 
 // TemplateClient handles interactions with the Tinkerbell Templates in the Tinkerbell cluster.
@@ -93,20 +103,93 @@ func (t *TemplateClient) Delete(ctx context.Context, namespacedName types.Namesp
 	return nil
 }
 
-// CreateTemplate creates a Tinkerbell Template in the Kubernetes cluster.
-func (t *TemplateClient) CreateTemplate(ctx context.Context, namespace string) error {
+// WorkflowTemplate is a Tinkerbell template, which provisions the hardware of a workflow.
+type WorkflowTemplate struct {
+	Name string
+	Data string
+	// DefaultPartitionNumber is the root partition of the image, unless the hardware specifies it
+	// with the PartitionNumberAnnotation.
+	DefaultPartitionNumber string
+}
+
+// PresetTemplate returns the built-in template of the preset.
+func PresetTemplate(preset tinktypes.TemplatePreset) (*WorkflowTemplate, error) {
+	template := &WorkflowTemplate{}
+
+	var actions []Action
+	switch preset {
+	case tinktypes.TemplatePresetUbuntu:
+		// The Ubuntu template keeps its original name, which is referenced by existing workflows.
+		template.Name = ProvisionWorkerNodeTemplate
+		template.DefaultPartitionNumber = DefaultPartitionNumber
+		actions = []Action{
+			createWipeDiskAction(),
+			createStreamImageAction("stream-ubuntu-image", hardwareDisk1, OSImageURL),
+			createGrowPartitionAction(hardwareDisk1, ext4FSType, "resize2fs '{{ formatPartition ( index .Hardware.Disks 0 ) (.partition_number | int) }}'"),
+			createNetworkConfigAction(),
+			configureCloudInitAction(ext4FSType),
+			decodeCloudInitFile(hardwareName, ext4FSType),
+			createRebootAction(),
+		}
+	case tinktypes.TemplatePresetFlatcar:
+		// Flatcar grows its root partition and configures the network with DHCP on the first boot.
+		template.Name = fmt.Sprintf("%s-%s", ProvisionWorkerNodeTemplate, preset)
+		template.DefaultPartitionNumber = flatcarRootPartitionNumber
+		actions = []Action{
+			createWipeDiskAction(),
+			createStreamImageAction("stream-flatcar-image", hardwareDisk1, OSImageURL),
+			createWriteIgnitionAction(),
+			createRebootAction(),
+		}
+	case tinktypes.TemplatePresetRHEL:
+		template.Name = fmt.Sprintf("%s-%s", ProvisionWorkerNodeTemplate, preset)
+		template.DefaultPartitionNumber = rhelRootPartitionNumber
+		actions = []Action{
+			createWipeDiskAction(),
+			createStreamImageAction("stream-rhel-image", hardwareDisk1, OSImageURL),
+			// The partition is mounted as root directory of the chroot.
+			createGrowPartitionAction(hardwareDisk1, xfsFSType, "xfs_growfs /"),
+			createNetworkManagerConfigAction(),
+			configureCloudInitAction(xfsFSType),
+			decodeCloudInitFile(hardwareName, xfsFSType),
+			createRebootAction(),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported template preset %q", preset)
+	}
+
+	data, err := marshalTemplate(string(preset), actions)
+	if err != nil {
+		return nil, err
+	}
+	template.Data = data
+
+	return template, nil
+}
+
+// CustomTemplate returns a workflow template with the data. Its name is derived from the data, so
+// changing the data creates a new template instead of changing the template of existing workflows.
+func CustomTemplate(data string) *WorkflowTemplate {
+	hash := sha256.Sum256([]byte(data))
+	return &WorkflowTemplate{
+		Name:                   fmt.Sprintf("%s-custom-%x", ProvisionWorkerNodeTemplate, hash[:5]),
+		Data:                   data,
+		DefaultPartitionNumber: DefaultPartitionNumber,
+	}
+}
+
+// CreateTemplate creates the Tinkerbell Template in the Kubernetes cluster or updates its data,
+// e.g. if a preset changed.
+func (t *TemplateClient) CreateTemplate(ctx context.Context, namespace string, workflowTemplate *WorkflowTemplate) error {
 	template := &tinkv1alpha1.Template{}
 	if err := t.tinkclient.Get(ctx, types.NamespacedName{
-		Name:      ProvisionWorkerNodeTemplate,
+		Name:      workflowTemplate.Name,
 		Namespace: namespace,
 	}, template); err != nil {
 		if apierrors.IsNotFound(err) {
-			data, err := getTemplate(OSImageURL)
-			if err != nil {
-				return err
-			}
+			data := workflowTemplate.Data
 
-			template.Name = ProvisionWorkerNodeTemplate
+			template.Name = workflowTemplate.Name
 			template.Namespace = namespace
 			template.Spec = tinkv1alpha1.TemplateSpec{
 				Data: &data, // templateData is a string containing the YAML definition.
@@ -120,23 +203,23 @@ func (t *TemplateClient) CreateTemplate(ctx context.Context, namespace string) e
 			return nil
 		}
 
-		return fmt.Errorf("failed to get template %s: %w", ProvisionWorkerNodeTemplate, err)
+		return fmt.Errorf("failed to get template %s: %w", workflowTemplate.Name, err)
 	}
 
-	return nil
-}
+	if template.Spec.Data != nil && *template.Spec.Data == workflowTemplate.Data {
+		return nil
+	}
 
-func getTemplate(osImageURL string) (string, error) {
-	actions := []Action{
-		createWipeDiskAction(),
-		createStreamUbuntuImageAction(hardwareDisk1, osImageURL),
-		createGrowPartitionAction(hardwareDisk1),
-		createNetworkConfigAction(),
-		configureCloudInitAction(),
-		decodeCloudInitFile(hardwareName),
-		createRebootAction(),
+	data := workflowTemplate.Data
+	template.Spec.Data = &data
+	if err := t.tinkclient.Update(ctx, template); err != nil {
+		return fmt.Errorf("failed to update template %s: %w", workflowTemplate.Name, err)
 	}
 
+	return nil
+}
+
+func marshalTemplate(name string, actions []Action) (string, error) {
 	task := Task{
 		Name:       "os-installation",
 		WorkerAddr: "{{.device_1}}",
@@ -145,7 +228,7 @@ func getTemplate(osImageURL string) (string, error) {
 	}
 
 	template := Template{
-		Name:          "ubuntu",
+		Name:          name,
 		Version:       "0.1",
 		GlobalTimeout: 1800,
 		Tasks:         []Task{task},
@@ -180,9 +263,9 @@ echo "All partitions on ${disks} have been wiped."
 	}
 }
 
-func createStreamUbuntuImageAction(destDisk, osImageURL string) Action {
+func createStreamImageAction(name, destDisk, osImageURL string) Action {
 	return Action{
-		Name:    "stream-ubuntu-image",
+		Name:    name,
 		Image:   mirror.Image("tinkerbell/actions/image2disk"),
 		Timeout: 600,
 		Environment: map[string]string{
@@ -193,7 +276,8 @@ func createStreamUbuntuImageAction(destDisk, osImageURL string) Action {
 	}
 }
 
-func createGrowPartitionAction(destDisk string) Action {
+// createGrowPartitionAction grows the root partition and runs the command to grow its file system.
+func createGrowPartitionAction(destDisk, fsType, growFSCommand string) Action {
 	return Action{
 		Name:    "grow-partition",
 		Image:   mirror.Image("tinkerbell/actions/cexec"),
@@ -203,7 +287,7 @@ func createGrowPartitionAction(destDisk string) Action {
 			"FS_TYPE":             fsType,
 			"CHROOT":              "y",
 			"DEFAULT_INTERPRETER": defaultInterpreter,
-			"CMD_LINE":            fmt.Sprintf("growpart %s %s && resize2fs '{{ formatPartition ( index .Hardware.Disks 0 ) (.partition_number | int) }}'", destDisk, PartitionNumber),
+			"CMD_LINE":            fmt.Sprintf("growpart %s %s && %s", destDisk, PartitionNumber, growFSCommand),
 		},
 	}
 }
@@ -230,7 +314,7 @@ network:
 		Timeout: 90,
 		Environment: map[string]string{
 			"DEST_DISK": "{{ formatPartition ( index .Hardware.Disks 0 ) (.partition_number | int) }}",
-			"FS_TYPE":   fsType,
+			"FS_TYPE":   ext4FSType,
 			"DEST_PATH": "/etc/netplan/config.yaml",
 			"CONTENTS":  netplanConfig,
 			"UID":       "0",
@@ -241,7 +325,60 @@ network:
 	}
 }
 
-func configureCloudInitAction() Action {
+// createNetworkManagerConfigAction writes the static network config of the hardware as NetworkManager
+// connection, which is used by RHEL and Rocky Linux.
+func createNetworkManagerConfigAction() Action {
+	connection := `[connection]
+id={{.interface_name}}
+type=ethernet
+interface-name={{.interface_name}}
+
+[ipv4]
+method=manual
+address1={{.cidr}},{{.default_route}}
+dns={{.ns}};
+`
+	return Action{
+		Name:    "add-networkmanager-config",
+		Image:   mirror.Image("tinkerbell/actions/writefile"),
+		Timeout: 90,
+		Environment: map[string]string{
+			"DEST_DISK": "{{ formatPartition ( index .Hardware.Disks 0 ) (.partition_number | int) }}",
+			"FS_TYPE":   xfsFSType,
+			"DEST_PATH": "/etc/NetworkManager/system-connections/{{.interface_name}}.nmconnection",
+			"CONTENTS":  connection,
+			"UID":       "0",
+			"GID":       "0",
+			"MODE":      "0600",
+			"DIRMODE":   "0755",
+		},
+	}
+}
+
+// createWriteIgnitionAction writes the userdata to the OEM partition of Flatcar, from which it is
+// read on the first boot: as Ignition config if it's JSON, otherwise as cloud-config.
+func createWriteIgnitionAction() Action {
+	script := fmt.Sprintf(`set -e
+mkdir -p /mnt/oem
+mount "{{ formatPartition ( index .Hardware.Disks 0 ) %s }}" /mnt/oem
+echo '{{.cloud_init_script}}' | base64 -d > /tmp/userdata
+if [ "$(head -c 1 /tmp/userdata)" = "{" ]; then
+  cp /tmp/userdata /mnt/oem/config.ign
+else
+  cp /tmp/userdata /mnt/oem/cloud-config.yml
+fi
+umount /mnt/oem
+`, flatcarOEMPartitionNumber)
+
+	return Action{
+		Name:    "write-ignition-config",
+		Image:   mirror.Image("alpine"),
+		Timeout: 90,
+		Command: []string{"/bin/sh", "-c", script},
+	}
+}
+
+func configureCloudInitAction(fsType string) Action {
 	commands := `mkdir -p /var/lib/cloud/seed/nocloud && chmod 755 /var/lib/cloud/seed/nocloud
 echo 'datasource_list: [ NoCloud ]' > /etc/cloud/cloud.cfg.d/01_ds-identify.cfg
 echo '{{.cloud_init_script}}' > /tmp/{{.hardware_name}}-bootstrap-config
@@ -263,7 +400,7 @@ echo 'local-hostname: {{.hardware_name}}' >> /var/lib/cloud/seed/nocloud/meta-da
 	}
 }
 
-func decodeCloudInitFile(hardwareName string) Action {
+func decodeCloudInitFile(hardwareName, fsType string) Action {
 	return Action{
 		Name:    "decode-cloud-init-file",
 		Image:   mirror.Image("tinkerbell/actions/cexec"),
@@ -17,10 +17,14 @@ limitations under the License.
 package client
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
+	"gopkg.in/yaml.v3"
+
 	"k8c.io/machine-controller/pkg/mirror"
+	tinktypes "k8c.io/machine-controller/sdk/cloudprovider/baremetal/plugins/tinkerbell"
 )
 
 // tinkerbellImageKeys lists every manifest key Tinkerbell's template.go
@@ -44,3 +48,71 @@ func TestTinkerbellImagesResolve(t *testing.T) {
 		}
 	}
 }
+
+func TestPresetTemplates(t *testing.T) {
+	testCases := []struct {
+		preset          tinktypes.TemplatePreset
+		expectedName    string
+		expectedActions []string
+	}{
+		{
+			preset:          tinktypes.TemplatePresetUbuntu,
+			expectedName:    ProvisionWorkerNodeTemplate,
+			expectedActions: []string{"wipe-disk", "stream-ubuntu-image", "grow-partition", "add-netplan-config", "configure-cloud-init", "decode-cloud-init-file", "reboot-action"},
+		},
+		{
+			preset:          tinktypes.TemplatePresetFlatcar,
+			expectedName:    "provision-worker-node-flatcar",
+			expectedActions: []string{"wipe-disk", "stream-flatcar-image", "write-ignition-config", "reboot-action"},
+		},
+		{
+			preset:          tinktypes.TemplatePresetRHEL,
+			expectedName:    "provision-worker-node-rhel",
+			expectedActions: []string{"wipe-disk", "stream-rhel-image", "grow-partition", "add-networkmanager-config", "configure-cloud-init", "decode-cloud-init-file", "reboot-action"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.preset), func(t *testing.T) {
+			template, err := PresetTemplate(tc.preset)
+			if err != nil {
+				t.Fatalf("failed to get template: %v", err)
+			}
+			if template.Name != tc.expectedName {
+				t.Errorf("expected template name %s, got %s", tc.expectedName, template.Name)
+			}
+
+			parsed := Template{}
+			if err := yaml.Unmarshal([]byte(template.Data), &parsed); err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			if len(parsed.Tasks) != 1 {
+				t.Fatalf("expected one task, got %d", len(parsed.Tasks))
+			}
+			var actions []string
+			for _, action := range parsed.Tasks[0].Actions {
+				actions = append(actions, action.Name)
+			}
+			if !slices.Equal(actions, tc.expectedActions) {
+				t.Errorf("expected actions %v, got %v", tc.expectedActions, actions)
+			}
+		})
+	}
+
+	if _, err := PresetTemplate("windows"); err == nil {
+		t.Error("expected unknown preset to be rejected")
+	}
+}
+
+func TestCustomTemplate(t *testing.T) {
+	template := CustomTemplate("name: custom")
+	if template.Name != CustomTemplate("name: custom").Name {
+		t.Error("expected the name of a custom template to be stable")
+	}
+	if template.Name == CustomTemplate("name: changed").Name {
+		t.Error("expected changed data to result in a new template name")
+	}
+	if !strings.HasPrefix(template.Name, ProvisionWorkerNodeTemplate+"-custom-") {
+		t.Errorf("unexpected name of custom template: %s", template.Name)
+	}
+}
@@ -32,7 +32,8 @@ import (
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// DefaultPartitionNumber defines the default value for the "partition_number" field.
+// DefaultPartitionNumber defines the default value for the "partition_number" field of the Ubuntu preset
+// and custom templates.
 const DefaultPartitionNumber = "3"
 
 // PartitionNumberAnnotation is used to specify the main partition number of the disk device.
@@ -51,7 +52,7 @@ func NewWorkflowClient(k8sClient ctrlruntimeclient.Client) *WorkflowClient {
 }
 
 // CreateWorkflow creates a new Tinkerbell Workflow resource in the cluster.
-func (w *WorkflowClient) CreateWorkflow(ctx context.Context, userData, templateRef, osImageURL string, hardware tink.Hardware) error {
+func (w *WorkflowClient) CreateWorkflow(ctx context.Context, userData string, template *WorkflowTemplate, osImageURL string, hardware tink.Hardware) error {
 	// Construct the Workflow object
 	ifaceConfig := hardware.Spec.Interfaces[0].DHCP
 	dnsNameservers := "1.1.1.1"
@@ -60,7 +61,12 @@ func (w *WorkflowClient) CreateWorkflow(ctx context.Context, userData, templateR
 		dnsNameservers = ns
 	}
 
-	workflowName := fmt.Sprintf("%s-%s-%s", hardware.Name, templateRef, time.Now().Format("20060102150405"))
+	var disk string
+	if len(hardware.Spec.Disks) > 0 {
+		disk = hardware.Spec.Disks[0].Device
+	}
+
+	workflowName := fmt.Sprintf("%s-%s-%s", hardware.Name, template.Name, time.Now().Format("20060102150405"))
 	workflow := &tinkv1alpha1.Workflow{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      workflowName,
@@ -70,7 +76,7 @@ func (w *WorkflowClient) CreateWorkflow(ctx context.Context, userData, templateR
 			},
 		},
 		Spec: tinkv1alpha1.WorkflowSpec{
-			TemplateRef: templateRef,
+			TemplateRef: template.Name,
 			HardwareRef: hardware.GetName(),
 			HardwareMap: map[string]string{
 				"device_1":          hardware.GetMACAddress(),
@@ -80,7 +86,8 @@ func (w *WorkflowClient) CreateWorkflow(ctx context.Context, userData, templateR
 				"cidr":              convertNetmaskToCIDR(ifaceConfig.IP),
 				"ns":                dnsNameservers,
 				"default_route":     ifaceConfig.IP.Gateway,
-				"partition_number":  w.getPartitionNumber(hardware),
+				"disk":              disk,
+				"partition_number":  w.getPartitionNumber(hardware, template.DefaultPartitionNumber),
 				"os_image":          osImageURL,
 			},
 		},
@@ -134,10 +141,34 @@ func (w *WorkflowClient) CleanupWorkflows(ctx context.Context, hardwareName, nam
 	return nil
 }
 
-func (w *WorkflowClient) getPartitionNumber(hardware tink.Hardware) string {
+// GetLatestWorkflow returns the most recently created workflow of the hardware, or nil if it has none.
+func (w *WorkflowClient) GetLatestWorkflow(ctx context.Context, hardwareName, namespace string) (*tinkv1alpha1.Workflow, error) {
+	workflows := &tinkv1alpha1.WorkflowList{}
+	if err := w.tinkclient.List(ctx, workflows, &ctrlruntimeclient.ListOptions{
+		Namespace: namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{
+			tink.HardwareRefLabel: hardwareName,
+		}),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to fetch workflows: %w", err)
+	}
+
+	var latest *tinkv1alpha1.Workflow
+	for i := range workflows.Items {
+		workflow := &workflows.Items[i]
+		// The names of workflows end with their creation time, which breaks ties.
+		if latest == nil || latest.CreationTimestamp.Before(&workflow.CreationTimestamp) ||
+			latest.CreationTimestamp.Equal(&workflow.CreationTimestamp) && latest.Name < workflow.Name {
+			latest = workflow
+		}
+	}
+	return latest, nil
+}
+
+func (w *WorkflowClient) getPartitionNumber(hardware tink.Hardware, defaultPartitionNumber string) string {
 	partitionNumber, exists := hardware.Annotations[PartitionNumberAnnotation]
 	if !exists {
-		partitionNumber = DefaultPartitionNumber // Use the default value
+		partitionNumber = defaultPartitionNumber // Use the default value
 	}
 	return partitionNumber
 }
@@ -19,7 +19,10 @@ package tinkerbell
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/aws/smithy-go/ptr"
 	tinkv1alpha1 "github.com/tinkerbell/tink/api/v1alpha1"
@@ -32,7 +35,10 @@ import (
 	tinktypes "k8c.io/machine-controller/sdk/cloudprovider/baremetal/plugins/tinkerbell"
 	providerconfigtypes "k8c.io/machine-controller/sdk/providerconfig"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/clientcmd"
@@ -45,6 +51,13 @@ type driver struct {
 	OSImageURL  string
 
 	HardwareRef types.NamespacedName
+	// HardwarePool is set if the hardware is selected from a pool instead of by HardwareRef.
+	HardwarePool *hardwarePool
+
+	// Template is the template of the workflows. The preset for the operating system is used if it is nil.
+	Template *tinktypes.TemplateSpec
+	// DefaultPreset is the template preset for the operating system of the machine.
+	DefaultPreset tinktypes.TemplatePreset
 
 	TinkClient     ctrlruntimeclient.Client
 	HardwareClient client.HardwareClient
@@ -52,6 +65,12 @@ type driver struct {
 	TemplateClient client.TemplateClient
 }
 
+// hardwarePool is a set of hardware objects of which each machine claims a free one.
+type hardwarePool struct {
+	namespace string
+	selector  labels.Selector
+}
+
 func init() {
 	// Ensure the Tinkerbell API types are registered with the global scheme.
 	if err := tinkv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
@@ -60,7 +79,11 @@ func init() {
 }
 
 // NewTinkerbellDriver returns a new TinkerBell driver with a configured tinkserver address and a client timeout.
-func NewTinkerbellDriver(tinkConfig tinktypes.Config, tinkSpec *tinktypes.TinkerbellPluginSpec) (plugins.PluginDriver, error) {
+func NewTinkerbellDriver(tinkConfig tinktypes.Config, tinkSpec *tinktypes.TinkerbellPluginSpec, operatingSystem providerconfigtypes.OperatingSystem) (plugins.PluginDriver, error) {
+	if err := validateSpec(tinkSpec); err != nil {
+		return nil, err
+	}
+
 	tinkClient, err := ctrlruntimeclient.New(tinkConfig.RestConfig, ctrlruntimeclient.Options{Scheme: scheme.Scheme})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create k8s client: %w", err)
@@ -79,13 +102,28 @@ func NewTinkerbellDriver(tinkConfig tinktypes.Config, tinkSpec *tinktypes.Tinker
 		WorkflowClient: *wkClient,
 		TemplateClient: *tmplClient,
 		OSImageURL:     tinkSpec.OSImageURL.Value,
+		Template:       tinkSpec.Template,
+		DefaultPreset:  defaultPreset(operatingSystem),
+	}
+
+	if tinkSpec.HardwareSelector != nil {
+		selector := labels.Everything()
+		if tinkSpec.HardwareSelector.LabelSelector != nil {
+			if selector, err = metav1.LabelSelectorAsSelector(tinkSpec.HardwareSelector.LabelSelector); err != nil {
+				return nil, fmt.Errorf("invalid hardwareSelector.labelSelector: %w", err)
+			}
+		}
+		d.HardwarePool = &hardwarePool{
+			namespace: tinkSpec.HardwareSelector.Namespace,
+			selector:  selector,
+		}
 	}
 
 	return &d, nil
 }
 
-func (d *driver) GetServer(ctx context.Context) (plugins.Server, error) {
-	targetHardware, err := d.HardwareClient.GetHardware(ctx, d.HardwareRef)
+func (d *driver) GetServer(ctx context.Context, uid types.UID) (plugins.Server, error) {
+	targetHardware, err := d.getHardware(ctx, uid)
 	if err != nil {
 		return nil, err
 	}
@@ -98,58 +136,289 @@ func (d *driver) GetServer(ctx context.Context) (plugins.Server, error) {
 	return &server, nil
 }
 
-func (d *driver) ProvisionServer(ctx context.Context, _ *zap.SugaredLogger, meta metav1.ObjectMeta, _ runtime.RawExtension, userdata string) (plugins.Server, error) {
+func (d *driver) ProvisionServer(ctx context.Context, log *zap.SugaredLogger, meta metav1.ObjectMeta, _ runtime.RawExtension, userdata string) (plugins.Server, error) {
+	if d.HardwarePool != nil {
+		return d.provisionPoolServer(ctx, log, meta, userdata)
+	}
+
 	// Get the hardware object from tinkerbell
 	hardware, err := d.HardwareClient.GetHardware(ctx, d.HardwareRef)
 	if err != nil {
 		return nil, err
 	}
 
-	var allowProvision bool
-	for _, iface := range hardware.Spec.Interfaces {
-		if iface.Netboot != nil && iface.Netboot.AllowPXE != nil && iface.Netboot.AllowPXE == ptr.Bool(false) {
-			continue
+	if !allowsProvisioning(hardware) {
+		return nil, fmt.Errorf("server %s is not allowed to be provisioned; either hardware allowPXE or allowWorkflow is set to false", hardware.Name)
+	}
+
+	server := tinkerbelltypes.Hardware{Hardware: hardware}
+	if err := d.createWorkflow(ctx, server, userdata); err != nil {
+		return nil, err
+	}
+
+	// Set the HardwareID with machine UID. The hardware object is claimed by the machine.
+	if err = d.HardwareClient.SetHardwareID(ctx, hardware, string(meta.UID)); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+// provisionPoolServer claims a free hardware object of the pool and provisions it. The claim is
+// released if the provisioning fails, so the hardware object can be claimed again.
+func (d *driver) provisionPoolServer(ctx context.Context, log *zap.SugaredLogger, meta metav1.ObjectMeta, userdata string) (plugins.Server, error) {
+	hardware, err := d.claimHardware(ctx, log, string(meta.UID))
+	if err != nil {
+		return nil, err
+	}
+
+	server := tinkerbelltypes.Hardware{Hardware: hardware}
+	if err := d.createWorkflow(ctx, server, userdata); err != nil {
+		if releaseErr := d.HardwareClient.SetHardwareID(ctx, hardware, ""); releaseErr != nil {
+			log.Errorw("Failed to release hardware", "hardware", ctrlruntimeclient.ObjectKeyFromObject(hardware), zap.Error(releaseErr))
 		}
+		return nil, err
+	}
 
-		if iface.Netboot != nil && iface.Netboot.AllowWorkflow != nil && iface.Netboot.AllowWorkflow == ptr.Bool(false) {
+	return &server, nil
+}
+
+// claimHardware claims a free hardware object of the pool for the machine with the given UID. The claim
+// is atomic, as setting the hardware ID fails with a conflict if another machine claimed the hardware
+// object in the meantime. In this case the next free hardware object is tried.
+func (d *driver) claimHardware(ctx context.Context, log *zap.SugaredLogger, uid string) (*tinkv1alpha1.Hardware, error) {
+	// A previous attempt might have claimed a hardware object already.
+	hardware, err := d.HardwareClient.GetHardwareWithID(ctx, uid)
+	if err == nil {
+		return hardware, nil
+	}
+	if !errors.Is(err, cloudprovidererrors.ErrInstanceNotFound) {
+		return nil, err
+	}
+
+	candidates, err := d.HardwareClient.ListHardware(ctx, d.HardwarePool.namespace, d.HardwarePool.selector)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Namespace != candidates[j].Namespace {
+			return candidates[i].Namespace < candidates[j].Namespace
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	for i := range candidates {
+		hardware := &candidates[i]
+		if !isFree(hardware) || !allowsProvisioning(hardware) {
 			continue
 		}
 
-		allowProvision = true
+		if err := d.HardwareClient.SetHardwareID(ctx, hardware, uid); err != nil {
+			if apierrors.IsConflict(err) {
+				log.Debugw("Hardware was claimed concurrently, trying the next one", "hardware", ctrlruntimeclient.ObjectKeyFromObject(hardware))
+				continue
+			}
+			return nil, err
+		}
+
+		log.Infow("Claimed hardware", "hardware", ctrlruntimeclient.ObjectKeyFromObject(hardware))
+		return hardware, nil
 	}
 
-	if !allowProvision {
-		return nil, fmt.Errorf("server %s is not allowed to be provisioned; either hardware allowPXE or allowWorkflow is set to false", hardware.Name)
+	return nil, errors.New("no free hardware found matching the hardware selector")
+}
+
+func (d *driver) createWorkflow(ctx context.Context, server tinkerbelltypes.Hardware, userdata string) error {
+	template, err := d.workflowTemplate(ctx, server.Namespace)
+	if err != nil {
+		return err
 	}
 
 	// Create template if it doesn't exist
-	err = d.TemplateClient.CreateTemplate(ctx, d.HardwareRef.Namespace)
-	if err != nil {
-		return nil, err
+	if err := d.TemplateClient.CreateTemplate(ctx, server.Namespace, template); err != nil {
+		return err
 	}
 
 	// Create Workflow to match the template and server
-	server := tinkerbelltypes.Hardware{Hardware: hardware}
-	if err = d.WorkflowClient.CreateWorkflow(ctx, userdata, client.ProvisionWorkerNodeTemplate, d.OSImageURL, server); err != nil {
+	return d.WorkflowClient.CreateWorkflow(ctx, userdata, template, d.OSImageURL, server)
+}
+
+// workflowTemplate returns the template of the workflows for hardware in the namespace.
+func (d *driver) workflowTemplate(ctx context.Context, namespace string) (*client.WorkflowTemplate, error) {
+	spec := d.Template
+	switch {
+	case spec == nil || spec.Preset == "" && spec.Data == "" && spec.ConfigMapRef == nil:
+		return client.PresetTemplate(d.DefaultPreset)
+	case spec.Preset != "":
+		return client.PresetTemplate(spec.Preset)
+	case spec.Data != "":
+		return client.CustomTemplate(spec.Data), nil
+	}
+
+	ref := spec.ConfigMapRef
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := d.TinkClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get template ConfigMap %s/%s: %w", namespace, ref.Name, err)
+	}
+	data, ok := configMap.Data[ref.Key]
+	if !ok || data == "" {
+		return nil, fmt.Errorf("template ConfigMap %s/%s has no key %q", namespace, ref.Name, ref.Key)
+	}
+	return client.CustomTemplate(data), nil
+}
+
+// defaultPreset returns the template preset for the operating system.
+func defaultPreset(operatingSystem providerconfigtypes.OperatingSystem) tinktypes.TemplatePreset {
+	switch operatingSystem {
+	case providerconfigtypes.OperatingSystemFlatcar:
+		return tinktypes.TemplatePresetFlatcar
+	case providerconfigtypes.OperatingSystemRHEL, providerconfigtypes.OperatingSystemRockyLinux:
+		return tinktypes.TemplatePresetRHEL
+	default:
+		return tinktypes.TemplatePresetUbuntu
+	}
+}
+
+// GetWorkflowStatus returns the status of the latest workflow of the hardware claimed by the machine.
+func (d *driver) GetWorkflowStatus(ctx context.Context, uid types.UID) (*plugins.WorkflowStatus, error) {
+	hardware, err := d.getHardware(ctx, uid)
+	if err != nil {
 		return nil, err
 	}
 
-	// Set the HardwareID with machine UID. The hardware object is claimed by the machine.
-	if err = d.HardwareClient.SetHardwareID(ctx, hardware, string(meta.UID)); err != nil {
+	workflow, err := d.WorkflowClient.GetLatestWorkflow(ctx, hardware.Name, hardware.Namespace)
+	if err != nil || workflow == nil {
 		return nil, err
 	}
 
-	return &server, nil
+	return workflowStatus(workflow), nil
 }
 
-func (d *driver) Validate(_ runtime.RawExtension) error {
+func workflowStatus(workflow *tinkv1alpha1.Workflow) *plugins.WorkflowStatus {
+	status := &plugins.WorkflowStatus{Name: workflow.Name}
+
+	switch workflow.Status.State {
+	case tinkv1alpha1.WorkflowStateSuccess:
+		status.State = plugins.WorkflowStateSucceeded
+	case tinkv1alpha1.WorkflowStateFailed, tinkv1alpha1.WorkflowStateTimeout:
+		status.State = plugins.WorkflowStateFailed
+	case tinkv1alpha1.WorkflowStateRunning:
+		status.State = plugins.WorkflowStateRunning
+	default:
+		status.State = plugins.WorkflowStatePending
+	}
+
+	for _, task := range workflow.Status.Tasks {
+		for _, action := range task.Actions {
+			status.TotalActions++
+			switch action.Status {
+			case tinkv1alpha1.WorkflowStateSuccess:
+				status.CompletedActions++
+			case tinkv1alpha1.WorkflowStateRunning, tinkv1alpha1.WorkflowStateFailed, tinkv1alpha1.WorkflowStateTimeout:
+				status.Action = action.Name
+				status.Message = action.Message
+			}
+		}
+	}
+	if status.State == plugins.WorkflowStateFailed && workflow.Status.State == tinkv1alpha1.WorkflowStateTimeout && status.Message == "" {
+		status.Message = "workflow timed out"
+	}
+
+	return status
+}
+
+// getHardware returns the hardware object of the machine with the given UID.
+func (d *driver) getHardware(ctx context.Context, uid types.UID) (*tinkv1alpha1.Hardware, error) {
+	if d.HardwarePool != nil {
+		return d.HardwareClient.GetHardwareWithID(ctx, string(uid))
+	}
+	return d.HardwareClient.GetHardware(ctx, d.HardwareRef)
+}
+
+func allowsProvisioning(hardware *tinkv1alpha1.Hardware) bool {
+	var allowProvision bool
+	for _, iface := range hardware.Spec.Interfaces {
+		if iface.Netboot != nil && iface.Netboot.AllowPXE != nil && iface.Netboot.AllowPXE == ptr.Bool(false) {
+			continue
+		}
+
+		if iface.Netboot != nil && iface.Netboot.AllowWorkflow != nil && iface.Netboot.AllowWorkflow == ptr.Bool(false) {
+			continue
+		}
+
+		allowProvision = true
+	}
+
+	return allowProvision
+}
+
+// isFree returns whether the hardware object is not claimed by any machine.
+func isFree(hardware *tinkv1alpha1.Hardware) bool {
+	return hardware.Spec.Metadata == nil || hardware.Spec.Metadata.Instance == nil || hardware.Spec.Metadata.Instance.ID == ""
+}
+
+func (d *driver) Validate(spec runtime.RawExtension) error {
+	tinkSpec := &tinktypes.TinkerbellPluginSpec{}
+	if err := json.Unmarshal(spec.Raw, tinkSpec); err != nil {
+		return fmt.Errorf("failed to unmarshal tinkerbell driver spec: %w", err)
+	}
+
+	return validateSpec(tinkSpec)
+}
+
+func validateSpec(tinkSpec *tinktypes.TinkerbellPluginSpec) error {
+	hasHardwareRef := tinkSpec.HardwareRef.Name != ""
+	if hasHardwareRef == (tinkSpec.HardwareSelector != nil) {
+		return errors.New("exactly one of hardwareRef or hardwareSelector must be specified")
+	}
+
+	if template := tinkSpec.Template; template != nil {
+		specified := 0
+		for _, set := range []bool{template.Preset != "", template.Data != "", template.ConfigMapRef != nil} {
+			if set {
+				specified++
+			}
+		}
+		if specified > 1 {
+			return errors.New("at most one of template.preset, template.data or template.configMapRef must be specified")
+		}
+
+		switch template.Preset {
+		case "", tinktypes.TemplatePresetUbuntu, tinktypes.TemplatePresetFlatcar, tinktypes.TemplatePresetRHEL:
+		default:
+			return fmt.Errorf("unsupported template.preset %q, expected one of %q, %q or %q", template.Preset, tinktypes.TemplatePresetUbuntu, tinktypes.TemplatePresetFlatcar, tinktypes.TemplatePresetRHEL)
+		}
+
+		if ref := template.ConfigMapRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+			return errors.New("template.configMapRef must specify a name and a key")
+		}
+	}
+
+	if selector := tinkSpec.HardwareSelector; selector != nil {
+		if selector.Namespace == "" && selector.LabelSelector == nil {
+			return errors.New("hardwareSelector must specify a namespace or a labelSelector")
+		}
+		if selector.LabelSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(selector.LabelSelector); err != nil {
+				return fmt.Errorf("invalid hardwareSelector.labelSelector: %w", err)
+			}
+		}
+	}
+
 	return nil
 }
 
-func (d *driver) DeprovisionServer(ctx context.Context) error {
+func (d *driver) DeprovisionServer(ctx context.Context, uid types.UID) error {
 	// Get the hardware object from tinkerbell cluster
-	targetHardware, err := d.HardwareClient.GetHardware(ctx, d.HardwareRef)
+	targetHardware, err := d.getHardware(ctx, uid)
 	if err != nil {
+		if d.HardwarePool != nil && errors.Is(err, cloudprovidererrors.ErrInstanceNotFound) {
+			// The machine did not claim any hardware object or it was released already.
+			return nil
+		}
 		return err
 	}
 
@@ -157,7 +426,7 @@ func (d *driver) DeprovisionServer(ctx context.Context) error {
 		return fmt.Errorf("failed to cleanup workflows for hardware %s: %w", targetHardware.Name, err)
 	}
 
-	// Reset the hardware ID and state in the tinkerbell cluster.
+	// Reset the hardware ID and state in the tinkerbell cluster. This releases the claim of the machine.
 	if err := d.HardwareClient.SetHardwareID(ctx, targetHardware, ""); err != nil {
 		return fmt.Errorf("failed to reset hardware ID for %s: %w", targetHardware.Name, err)
 	}
@@ -0,0 +1,334 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tinkerbell
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tinkv1alpha1 "github.com/tinkerbell/tink/api/v1alpha1"
+	"go.uber.org/zap"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins/tinkerbell/client"
+	tinkerbelltypes "k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins/tinkerbell/types"
+	tinktypes "k8c.io/machine-controller/sdk/cloudprovider/baremetal/plugins/tinkerbell"
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubectl/pkg/scheme"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newHardware(name string, poolLabels map[string]string, id string) *tinkv1alpha1.Hardware {
+	hardware := &tinkv1alpha1.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "tinkerbell", Labels: poolLabels},
+		Spec: tinkv1alpha1.HardwareSpec{
+			Interfaces: []tinkv1alpha1.Interface{{
+				DHCP: &tinkv1alpha1.DHCP{MAC: "00:00:5e:00:53:01", IP: &tinkv1alpha1.IP{Address: "192.0.2.10"}},
+			}},
+		},
+	}
+	if id != "" {
+		hardware.Spec.Metadata = &tinkv1alpha1.HardwareMetadata{Instance: &tinkv1alpha1.MetadataInstance{ID: id}}
+	}
+	return hardware
+}
+
+func newPoolDriver(tinkClient ctrlruntimeclient.Client) *driver {
+	return &driver{
+		HardwarePool: &hardwarePool{
+			namespace: "tinkerbell",
+			selector:  labels.SelectorFromSet(labels.Set{"pool": "workers"}),
+		},
+		TinkClient:     tinkClient,
+		HardwareClient: *client.NewHardwareClient(tinkClient),
+		WorkflowClient: *client.NewWorkflowClient(tinkClient),
+		TemplateClient: *client.NewTemplateClient(tinkClient),
+	}
+}
+
+func TestClaimHardware(t *testing.T) {
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	pool := map[string]string{"pool": "workers"}
+
+	tinkClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			newHardware("hw-a", pool, "claimed-by-other-machine"),
+			newHardware("hw-b", pool, ""),
+			newHardware("hw-c", pool, ""),
+			newHardware("hw-other-pool", map[string]string{"pool": "storage"}, ""),
+		).
+		Build()
+	d := newPoolDriver(tinkClient)
+
+	hardware, err := d.claimHardware(ctx, log, "machine-1")
+	if err != nil {
+		t.Fatalf("failed to claim hardware: %v", err)
+	}
+	if hardware.Name != "hw-b" {
+		t.Errorf("expected machine-1 to claim the first free hardware hw-b, got %s", hardware.Name)
+	}
+
+	// Claiming again returns the hardware claimed before.
+	hardware, err = d.claimHardware(ctx, log, "machine-1")
+	if err != nil || hardware.Name != "hw-b" {
+		t.Errorf("expected machine-1 to keep its claim on hw-b, got %v (error: %v)", hardware, err)
+	}
+
+	hardware, err = d.claimHardware(ctx, log, "machine-2")
+	if err != nil || hardware.Name != "hw-c" {
+		t.Errorf("expected machine-2 to claim hw-c, got %v (error: %v)", hardware, err)
+	}
+
+	if _, err := d.claimHardware(ctx, log, "machine-3"); err == nil {
+		t.Error("expected claim to fail as the pool is exhausted")
+	}
+
+	// Deprovisioning releases the claim, so the hardware can be claimed again.
+	if err := d.DeprovisionServer(ctx, "machine-1"); err != nil {
+		t.Fatalf("failed to deprovision server: %v", err)
+	}
+	if _, err := d.GetServer(ctx, "machine-1"); !errors.Is(err, cloudprovidererrors.ErrInstanceNotFound) {
+		t.Errorf("expected server of machine-1 to be gone, got %v", err)
+	}
+	if err := d.DeprovisionServer(ctx, "machine-1"); err != nil {
+		t.Errorf("expected deprovisioning a released server to succeed, got %v", err)
+	}
+
+	hardware, err = d.claimHardware(ctx, log, "machine-3")
+	if err != nil || hardware.Name != "hw-b" {
+		t.Errorf("expected machine-3 to claim the released hw-b, got %v (error: %v)", hardware, err)
+	}
+}
+
+func TestClaimHardwareConflict(t *testing.T) {
+	pool := map[string]string{"pool": "workers"}
+
+	// Another machine-controller claims hw-a between listing and updating it.
+	tinkClient := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(newHardware("hw-a", pool, ""), newHardware("hw-b", pool, "")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c ctrlruntimeclient.WithWatch, obj ctrlruntimeclient.Object, opts ...ctrlruntimeclient.UpdateOption) error {
+				if obj.GetName() == "hw-a" {
+					return apierrors.NewConflict(schema.GroupResource{Group: "tinkerbell.org", Resource: "hardware"}, obj.GetName(), errors.New("the object has been modified"))
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	hardware, err := newPoolDriver(tinkClient).claimHardware(context.Background(), zap.NewNop().Sugar(), "machine-1")
+	if err != nil {
+		t.Fatalf("failed to claim hardware: %v", err)
+	}
+	if hardware.Name != "hw-b" {
+		t.Errorf("expected machine-1 to claim hw-b after the conflict on hw-a, got %s", hardware.Name)
+	}
+}
+
+func TestProvisionServerWithTemplate(t *testing.T) {
+	customTemplate := "version: \"0.1\"\nname: custom\n"
+
+	testCases := []struct {
+		name                    string
+		operatingSystem         providerconfig.OperatingSystem
+		template                *tinktypes.TemplateSpec
+		expectedTemplate        string
+		expectedPartitionNumber string
+	}{
+		{
+			name:                    "default preset of ubuntu",
+			operatingSystem:         providerconfig.OperatingSystemUbuntu,
+			expectedTemplate:        client.ProvisionWorkerNodeTemplate,
+			expectedPartitionNumber: "3",
+		},
+		{
+			name:                    "default preset of rocky linux",
+			operatingSystem:         providerconfig.OperatingSystemRockyLinux,
+			expectedTemplate:        "provision-worker-node-rhel",
+			expectedPartitionNumber: "4",
+		},
+		{
+			name:                    "preset overriding the default of the operating system",
+			operatingSystem:         providerconfig.OperatingSystemUbuntu,
+			template:                &tinktypes.TemplateSpec{Preset: tinktypes.TemplatePresetFlatcar},
+			expectedTemplate:        "provision-worker-node-flatcar",
+			expectedPartitionNumber: "9",
+		},
+		{
+			name:                    "custom template",
+			operatingSystem:         providerconfig.OperatingSystemFlatcar,
+			template:                &tinktypes.TemplateSpec{Data: customTemplate},
+			expectedTemplate:        client.CustomTemplate(customTemplate).Name,
+			expectedPartitionNumber: "3",
+		},
+		{
+			name:                    "custom template of a ConfigMap",
+			operatingSystem:         providerconfig.OperatingSystemUbuntu,
+			template:                &tinktypes.TemplateSpec{ConfigMapRef: &tinktypes.ConfigMapKeyRef{Name: "templates", Key: "worker"}},
+			expectedTemplate:        client.CustomTemplate(customTemplate).Name,
+			expectedPartitionNumber: "3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			hardware := newHardware("hw-a", nil, "")
+			hardware.Spec.Disks = []tinkv1alpha1.Disk{{Device: "/dev/sda"}}
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "templates", Namespace: "tinkerbell"},
+				Data:       map[string]string{"worker": customTemplate},
+			}
+			tinkClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(hardware, configMap).Build()
+
+			d := &driver{
+				HardwareRef:    ctrlruntimeclient.ObjectKeyFromObject(hardware),
+				Template:       tc.template,
+				DefaultPreset:  defaultPreset(tc.operatingSystem),
+				TinkClient:     tinkClient,
+				HardwareClient: *client.NewHardwareClient(tinkClient),
+				WorkflowClient: *client.NewWorkflowClient(tinkClient),
+				TemplateClient: *client.NewTemplateClient(tinkClient),
+			}
+			meta := metav1.ObjectMeta{Name: "machine-1", UID: "machine-1"}
+			if _, err := d.ProvisionServer(ctx, zap.NewNop().Sugar(), meta, runtime.RawExtension{}, "#cloud-config"); err != nil {
+				t.Fatalf("failed to provision server: %v", err)
+			}
+
+			template := &tinkv1alpha1.Template{}
+			if err := tinkClient.Get(ctx, ctrlruntimeclient.ObjectKey{Namespace: "tinkerbell", Name: tc.expectedTemplate}, template); err != nil {
+				t.Fatalf("failed to get template %s: %v", tc.expectedTemplate, err)
+			}
+
+			workflow, err := d.WorkflowClient.GetLatestWorkflow(ctx, "hw-a", "tinkerbell")
+			if err != nil || workflow == nil {
+				t.Fatalf("failed to get workflow: %v", err)
+			}
+			if workflow.Spec.TemplateRef != tc.expectedTemplate {
+				t.Errorf("expected workflow to reference template %s, got %s", tc.expectedTemplate, workflow.Spec.TemplateRef)
+			}
+			if partitionNumber := workflow.Spec.HardwareMap["partition_number"]; partitionNumber != tc.expectedPartitionNumber {
+				t.Errorf("expected partition number %s, got %s", tc.expectedPartitionNumber, partitionNumber)
+			}
+			if disk := workflow.Spec.HardwareMap["disk"]; disk != "/dev/sda" {
+				t.Errorf("expected disk /dev/sda, got %s", disk)
+			}
+		})
+	}
+}
+
+func TestWorkflowStatus(t *testing.T) {
+	newWorkflow := func(state tinkv1alpha1.WorkflowState, actions ...tinkv1alpha1.Action) *tinkv1alpha1.Workflow {
+		return &tinkv1alpha1.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: "hw-a-provision-worker-node-20260101000000"},
+			Status: tinkv1alpha1.WorkflowStatus{
+				State: state,
+				Tasks: []tinkv1alpha1.Task{{Name: "os-installation", Actions: actions}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		workflow *tinkv1alpha1.Workflow
+		expected plugins.WorkflowStatus
+	}{
+		{
+			name: "pending workflow",
+			workflow: newWorkflow("",
+				tinkv1alpha1.Action{Name: "wipe-disk"},
+			),
+			expected: plugins.WorkflowStatus{State: plugins.WorkflowStatePending, TotalActions: 1},
+		},
+		{
+			name: "running workflow",
+			workflow: newWorkflow(tinkv1alpha1.WorkflowStateRunning,
+				tinkv1alpha1.Action{Name: "wipe-disk", Status: tinkv1alpha1.WorkflowStateSuccess},
+				tinkv1alpha1.Action{Name: "stream-image", Status: tinkv1alpha1.WorkflowStateRunning},
+				tinkv1alpha1.Action{Name: "reboot-action"},
+			),
+			expected: plugins.WorkflowStatus{State: plugins.WorkflowStateRunning, Action: "stream-image", CompletedActions: 1, TotalActions: 3},
+		},
+		{
+			name: "failed workflow",
+			workflow: newWorkflow(tinkv1alpha1.WorkflowStateFailed,
+				tinkv1alpha1.Action{Name: "wipe-disk", Status: tinkv1alpha1.WorkflowStateSuccess},
+				tinkv1alpha1.Action{Name: "stream-image", Status: tinkv1alpha1.WorkflowStateFailed, Message: "image not found"},
+			),
+			expected: plugins.WorkflowStatus{State: plugins.WorkflowStateFailed, Action: "stream-image", CompletedActions: 1, TotalActions: 2, Message: "image not found"},
+		},
+		{
+			name: "timed out workflow",
+			workflow: newWorkflow(tinkv1alpha1.WorkflowStateTimeout,
+				tinkv1alpha1.Action{Name: "wipe-disk", Status: tinkv1alpha1.WorkflowStateTimeout},
+			),
+			expected: plugins.WorkflowStatus{State: plugins.WorkflowStateFailed, Action: "wipe-disk", TotalActions: 1, Message: "workflow timed out"},
+		},
+		{
+			name: "succeeded workflow",
+			workflow: newWorkflow(tinkv1alpha1.WorkflowStateSuccess,
+				tinkv1alpha1.Action{Name: "wipe-disk", Status: tinkv1alpha1.WorkflowStateSuccess},
+			),
+			expected: plugins.WorkflowStatus{State: plugins.WorkflowStateSucceeded, CompletedActions: 1, TotalActions: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expected.Name = tc.workflow.Name
+			if status := workflowStatus(tc.workflow); *status != tc.expected {
+				t.Errorf("expected status %+v, got %+v", tc.expected, *status)
+			}
+		})
+	}
+}
+
+func TestGetWorkflowStatusOfLatestWorkflow(t *testing.T) {
+	hardware := newHardware("hw-a", nil, "machine-1")
+	oldWorkflow := &tinkv1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "hw-a-provision-worker-node-20260101000000", Namespace: "tinkerbell", Labels: map[string]string{tinkerbelltypes.HardwareRefLabel: "hw-a"}},
+		Status:     tinkv1alpha1.WorkflowStatus{State: tinkv1alpha1.WorkflowStateFailed},
+	}
+	newWorkflow := oldWorkflow.DeepCopy()
+	newWorkflow.Name = "hw-a-provision-worker-node-20260102000000"
+	newWorkflow.Status.State = tinkv1alpha1.WorkflowStateRunning
+
+	tinkClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(hardware, oldWorkflow, newWorkflow).Build()
+	d := newPoolDriver(tinkClient)
+
+	status, err := d.GetWorkflowStatus(context.Background(), "machine-1")
+	if err != nil {
+		t.Fatalf("failed to get workflow status: %v", err)
+	}
+	if status.Name != newWorkflow.Name || status.State != plugins.WorkflowStateRunning {
+		t.Errorf("expected status of the latest workflow %s, got %+v", newWorkflow.Name, status)
+	}
+}
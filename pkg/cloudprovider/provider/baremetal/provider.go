@@ -27,13 +27,16 @@ import (
 	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
 	"k8c.io/machine-controller/pkg/cloudprovider/instance"
 	"k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins"
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins/redfish"
 	tink "k8c.io/machine-controller/pkg/cloudprovider/provider/baremetal/plugins/tinkerbell"
 	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
 	"k8c.io/machine-controller/pkg/cloudprovider/util"
+	controllerutil "k8c.io/machine-controller/pkg/controller/util"
 	"k8c.io/machine-controller/sdk/apis/cluster/common"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	baremetaltypes "k8c.io/machine-controller/sdk/cloudprovider/baremetal"
 	plugintypes "k8c.io/machine-controller/sdk/cloudprovider/baremetal/plugins"
+	redfishtypes "k8c.io/machine-controller/sdk/cloudprovider/baremetal/plugins/redfish"
 	tinktypes "k8c.io/machine-controller/sdk/cloudprovider/baremetal/plugins/tinkerbell"
 	"k8c.io/machine-controller/sdk/providerconfig"
 
@@ -130,10 +133,26 @@ func (p *provider) getConfig(provSpec clusterv1alpha1.ProviderSpec) (*Config, *p
 			return nil, nil, err
 		}
 
-		c.driver, err = tink.NewTinkerbellDriver(*tinkConfig, driverConfig)
+		c.driver, err = tink.NewTinkerbellDriver(*tinkConfig, driverConfig, pconfig.OperatingSystem)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create a tinkerbell driver: %w", err)
 		}
+	case plugintypes.Redfish:
+		driverConfig := &redfishtypes.RedfishPluginSpec{}
+
+		if err := json.Unmarshal(c.driverSpec.Raw, &driverConfig); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal redfish driver spec: %w", err)
+		}
+
+		redfishConfig, err := redfish.GetConfig(*driverConfig, p.configVarResolver.GetStringValueOrEnv)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		c.driver, err = redfish.NewRedfishDriver(*redfishConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create a redfish driver: %w", err)
+		}
 	default:
 		return nil, nil, fmt.Errorf("unsupported baremetal driver: %s", pconfig.CloudProvider)
 	}
@@ -163,7 +182,7 @@ func (p provider) Validate(_ context.Context, _ *zap.SugaredLogger, spec cluster
 	return nil
 }
 
-func (p provider) Get(ctx context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData) (instance.Instance, error) {
+func (p provider) Get(ctx context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData) (instance.Instance, error) {
 	c, _, err := p.getConfig(machine.Spec.ProviderSpec)
 	if err != nil {
 		return nil, cloudprovidererrors.TerminalError{
@@ -172,7 +191,7 @@ func (p provider) Get(ctx context.Context, _ *zap.SugaredLogger, machine *cluste
 		}
 	}
 
-	server, err := c.driver.GetServer(ctx)
+	server, err := c.driver.GetServer(ctx, machine.UID)
 	if err != nil {
 		if errors.Is(err, cloudprovidererrors.ErrInstanceNotFound) {
 			return nil, cloudprovidererrors.ErrInstanceNotFound
@@ -181,16 +200,61 @@ func (p provider) Get(ctx context.Context, _ *zap.SugaredLogger, machine *cluste
 		return nil, fmt.Errorf("failed to fetch server with the id %s: %w", machine.Name, err)
 	}
 
+	if reporter, ok := c.driver.(plugins.WorkflowReporter); ok && data != nil && data.Update != nil {
+		if err := reportWorkflowStatus(ctx, reporter, machine, data); err != nil {
+			return nil, err
+		}
+	}
+
 	return &bareMetalServer{
 		server: server,
 	}, nil
 }
 
+// reportWorkflowStatus reports the progress of the provisioning workflow of the server with the
+// WorkflowSucceeded condition of the machine. A failed workflow is a terminal error, as the server
+// must be provisioned from scratch.
+func reportWorkflowStatus(ctx context.Context, reporter plugins.WorkflowReporter, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData) error {
+	workflow, err := reporter.GetWorkflowStatus(ctx, machine.UID)
+	if err != nil {
+		return fmt.Errorf("failed to get status of provisioning workflow: %w", err)
+	}
+	if workflow == nil {
+		return nil
+	}
+
+	status, reason, message := corev1.ConditionFalse, common.WorkflowPendingReason, fmt.Sprintf("Workflow %s is pending", workflow.Name)
+	switch workflow.State {
+	case plugins.WorkflowStateSucceeded:
+		status, reason, message = corev1.ConditionTrue, "", ""
+	case plugins.WorkflowStateRunning:
+		reason = common.WorkflowRunningReason
+		message = fmt.Sprintf("Workflow %s is running action %s, %d of %d actions completed", workflow.Name, workflow.Action, workflow.CompletedActions, workflow.TotalActions)
+	case plugins.WorkflowStateFailed:
+		reason = common.WorkflowFailedReason
+		message = fmt.Sprintf("Workflow %s failed in action %s: %s", workflow.Name, workflow.Action, workflow.Message)
+	}
+
+	if err := data.Update(machine, func(m *clusterv1alpha1.Machine) {
+		controllerutil.SetMachineCondition(m, common.WorkflowSucceededCondition, status, reason, message)
+	}); err != nil {
+		return fmt.Errorf("failed to update %s condition: %w", common.WorkflowSucceededCondition, err)
+	}
+
+	if workflow.State == plugins.WorkflowStateFailed {
+		return cloudprovidererrors.TerminalError{
+			Reason:  common.CreateMachineError,
+			Message: message,
+		}
+	}
+	return nil
+}
+
 func (p provider) GetCloudConfig(_ clusterv1alpha1.MachineSpec) (config string, name string, err error) {
 	return "", "", nil
 }
 
-func (p provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
+func (p provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
 	c, _, err := p.getConfig(machine.Spec.ProviderSpec)
 	if err != nil {
 		return nil, cloudprovidererrors.TerminalError{
@@ -199,6 +263,14 @@ func (p provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *c
 		}
 	}
 
+	if c.driverName == plugintypes.Redfish {
+		// The Redfish driver can only boot the server, so the booted image fetches the userdata
+		// from the cloud-init-settings namespace. The secret is deleted by Cleanup.
+		if err := util.CreateMachineCloudInitSecret(ctx, userdata, machine.Name, data.Client); err != nil {
+			return nil, err
+		}
+	}
+
 	server, err := c.driver.ProvisionServer(ctx, log, machine.ObjectMeta, c.driverSpec, userdata)
 	if err != nil {
 		return nil, fmt.Errorf("failed to provision server: %w", err)
@@ -218,7 +290,7 @@ func (p provider) Cleanup(ctx context.Context, _ *zap.SugaredLogger, machine *cl
 		}
 	}
 
-	if err := c.driver.DeprovisionServer(ctx); err != nil {
+	if err := c.driver.DeprovisionServer(ctx, machine.UID); err != nil {
 		return false, fmt.Errorf("failed to de-provision server: %w", err)
 	}
 
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/digitalocean/godo"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"golang.org/x/oauth2"
 
@@ -65,6 +66,10 @@ type Config struct {
 }
 
 const (
+	// clusterIDTagPrefix is followed by the cluster ID in a tag, so that only droplets of
+	// the own cluster are listed.
+	clusterIDTagPrefix = "machine-controller-cluster-id:"
+
 	createCheckPeriod           = 10 * time.Second
 	createCheckTimeout          = 5 * time.Minute
 	createCheckFailedWaitPeriod = 10 * time.Second
@@ -269,7 +274,7 @@ func uploadRandomSSHPublicKey(ctx context.Context, service godo.KeysService) (st
 	return newDoKey.Fingerprint, nil
 }
 
-func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
+func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
 	c, pc, err := p.getConfig(machine.Spec.ProviderSpec)
 	if err != nil {
 		return nil, cloudprovidererrors.TerminalError{
@@ -298,6 +303,10 @@ func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 			Message: fmt.Sprintf("Failed to parse MachineSpec, invalid operating system specified %q: %v", pc.OperatingSystem, err),
 		}
 	}
+	tags := append(c.Tags, string(machine.UID))
+	if data != nil && data.ClusterID != "" {
+		tags = append(tags, clusterIDTagPrefix+data.ClusterID)
+	}
 	createRequest := &godo.DropletCreateRequest{
 		Image:             godo.DropletCreateImage{Slug: slug},
 		Name:              machine.Spec.Name,
@@ -309,7 +318,7 @@ func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 		Monitoring:        c.Monitoring,
 		UserData:          userdata,
 		SSHKeys:           []godo.DropletCreateSSHKey{{Fingerprint: fingerprint}},
-		Tags:              append(c.Tags, string(machine.UID)),
+		Tags:              tags,
 	}
 
 	droplet, rsp, err := client.Droplets.Create(ctx, createRequest)
@@ -431,6 +440,44 @@ func (p *provider) listDroplets(ctx context.Context, token string) ([]godo.Dropl
 	return result, nil
 }
 
+// ListInstances returns all droplets that are tagged with a machine UID and the cluster
+// ID and carry the configured tags. As the machine UID is added as a bare tag, any tag
+// that parses as a UUID and is not configured in the spec is taken as machine UID.
+func (p *provider) ListInstances(ctx context.Context, _ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec, clusterID string) ([]cloudprovidertypes.TaggedInstance, error) {
+	c, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, cloudprovidererrors.TerminalError{
+			Reason:  common.InvalidConfigurationMachineError,
+			Message: fmt.Sprintf("Failed to parse MachineSpec, due to %v", err),
+		}
+	}
+
+	droplets, err := p.listDroplets(ctx, c.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	configuredTags := sets.NewString(c.Tags...)
+	var instances []cloudprovidertypes.TaggedInstance
+	for i, droplet := range droplets {
+		dropletTags := sets.NewString(droplet.Tags...)
+		if !dropletTags.IsSuperset(configuredTags) || !dropletTags.Has(clusterIDTagPrefix+clusterID) {
+			continue
+		}
+		for _, tag := range dropletTags.Difference(configuredTags).List() {
+			if _, err := uuid.Parse(tag); err == nil {
+				instances = append(instances, cloudprovidertypes.TaggedInstance{
+					Instance:   &doInstance{droplet: &droplets[i]},
+					MachineUID: types.UID(tag),
+				})
+				break
+			}
+		}
+	}
+
+	return instances, nil
+}
+
 func (p *provider) MigrateUID(ctx context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, newUID types.UID) error {
 	c, _, err := p.getConfig(machine.Spec.ProviderSpec)
 	if err != nil {
@@ -470,6 +517,24 @@ func (p *provider) MigrateUID(ctx context.Context, _ *zap.SugaredLogger, machine
 	return nil
 }
 
+// MachineAttributes returns the size, region and tags of the spec. As droplet tags have
+// no values, all tag values are empty.
+func (p *provider) MachineAttributes(spec clusterv1alpha1.MachineSpec) (cloudprovidertypes.MachineAttributes, error) {
+	config, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return cloudprovidertypes.MachineAttributes{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	tags := make(map[string]string, len(config.Tags))
+	for _, tag := range config.Tags {
+		tags[tag] = ""
+	}
+	return cloudprovidertypes.MachineAttributes{
+		InstanceType: config.Size,
+		Region:       config.Region,
+		Tags:         tags,
+	}, nil
+}
+
 func (p *provider) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (map[string]string, error) {
 	labels := make(map[string]string)
 
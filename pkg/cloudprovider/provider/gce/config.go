@@ -27,11 +27,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"golang.org/x/oauth2"
 	googleoauth "golang.org/x/oauth2/google"
 	"google.golang.org/api/compute/v1"
 
+	"k8c.io/machine-controller/pkg/cloudprovider/workloadidentity"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	gcetypes "k8c.io/machine-controller/sdk/cloudprovider/gce"
 	"k8c.io/machine-controller/sdk/providerconfig"
@@ -114,6 +116,9 @@ type config struct {
 	minCPUPlatform               string
 	guestOSFeatures              []string
 	clientConfig                 *clientConfig
+
+	workloadIdentityPoolProvider   string
+	workloadIdentityServiceAccount string
 }
 
 type clientConfig struct {
@@ -148,9 +153,23 @@ func newConfig(resolver providerconfig.ConfigVarResolver, spec clusterv1alpha1.P
 		return nil, fmt.Errorf("failed to retrieve project id: %w", err)
 	}
 
-	err = cfg.postprocessServiceAccount()
+	cfg.workloadIdentityPoolProvider, err = resolver.GetStringValue(cpSpec.WorkloadIdentityPoolProvider)
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve workload identity pool provider: %w", err)
+	}
+
+	cfg.workloadIdentityServiceAccount, err = resolver.GetStringValue(cpSpec.WorkloadIdentityServiceAccount)
 	if err != nil {
-		return nil, fmt.Errorf("cannot prepare JWT: %w", err)
+		return nil, fmt.Errorf("cannot retrieve workload identity service account: %w", err)
+	}
+
+	if cfg.workloadIdentityPoolProvider != "" {
+		cfg.setupWorkloadIdentity()
+	} else {
+		err = cfg.postprocessServiceAccount()
+		if err != nil {
+			return nil, fmt.Errorf("cannot prepare JWT: %w", err)
+		}
 	}
 
 	cfg.zone, err = resolver.GetStringValue(cpSpec.Zone)
@@ -286,6 +305,44 @@ func (cfg *config) postprocessServiceAccount() error {
 	return nil
 }
 
+// setupWorkloadIdentity creates a token source, which exchanges the service account token
+// of the machine-controller for GCP credentials.
+func (cfg *config) setupWorkloadIdentity() {
+	cfg.clientConfig = &clientConfig{
+		ClientEmail: cfg.workloadIdentityServiceAccount,
+		TokenSource: &workloadIdentityTokenSource{
+			exchanger: &workloadidentity.GCPExchanger{
+				Audience:            cfg.workloadIdentityPoolProvider,
+				ServiceAccountEmail: cfg.workloadIdentityServiceAccount,
+			},
+		},
+	}
+}
+
+// workloadIdentityTokenTimeout bounds the retrieval of a token, as oauth2.TokenSource
+// gets no context of the request the token is needed for.
+const workloadIdentityTokenTimeout = time.Minute
+
+// workloadIdentityTokenSource returns the access tokens of workload identity federation.
+type workloadIdentityTokenSource struct {
+	exchanger workloadidentity.Exchanger
+}
+
+func (ts *workloadIdentityTokenSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), workloadIdentityTokenTimeout)
+	defer cancel()
+
+	creds, err := workloadidentity.GetCredentials(ctx, ts.exchanger)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{
+		AccessToken: creds.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      creds.Expiry,
+	}, nil
+}
+
 // machineTypeDescriptor creates the descriptor out of zone and machine type
 // for the machine type of an instance.
 func (cfg *config) machineTypeDescriptor() string {
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"cloud.google.com/go/logging"
 	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
@@ -50,10 +51,13 @@ const (
 	errOperatingSystem       = "Invalid or not supported operating system specified %q: %v"
 	errConnect               = "Failed to connect: %v"
 	errInvalidServiceAccount = "Service account is missing"
+	errInvalidProjectID      = "Project ID is missing, it is required for workload identity federation"
+	errInvalidMachineSA      = "Workload identity service account is missing, it is required unless the machine service account is disabled"
 	errInvalidZone           = "Zone is missing"
 	errInvalidMachineType    = "Machine type is missing"
 	errInvalidDiskSize       = "Disk size must be a positive number"
 	errInvalidDiskType       = "Disk type is missing or has wrong type, allowed are 'pd-standard' and 'pd-ssd'"
+	errInvalidFallbacks      = "Invalid fallbacks: %v"
 	errRetrieveInstance      = "Failed to retrieve instance: %v"
 	errGotTooManyInstances   = "Got more than 1 instance matching the machine UID label"
 	errInsertInstance        = "Failed to insert instance: %v"
@@ -82,6 +86,54 @@ func New(configVarResolver providerconfig.ConfigVarResolver) *Provider {
 	}
 }
 
+// FallbackCandidates returns a machine spec for each configured fallback, which
+// are used in order if GCE reports insufficient capacity.
+func (p *Provider) FallbackCandidates(spec clusterv1alpha1.MachineSpec) ([]cloudprovidertypes.FallbackCandidate, error) {
+	cpSpec, _, err := newCloudProviderSpec(spec.ProviderSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []cloudprovidertypes.FallbackCandidate
+	for i, fallback := range cpSpec.Fallbacks {
+		candidateSpec := *cpSpec
+		candidateSpec.Fallbacks = nil
+
+		var changes []string
+		if fallback.MachineType != (providerconfig.ConfigVarString{}) {
+			machineType, err := p.resolver.GetStringValue(fallback.MachineType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the value of \"machineType\" of fallback %d: %w", i, err)
+			}
+			candidateSpec.MachineType = fallback.MachineType
+			changes = append(changes, "machineType="+machineType)
+		}
+		if fallback.Zone != (providerconfig.ConfigVarString{}) {
+			zone, err := p.resolver.GetStringValue(fallback.Zone)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the value of \"zone\" of fallback %d: %w", i, err)
+			}
+			candidateSpec.Zone = fallback.Zone
+			changes = append(changes, "zone="+zone)
+		}
+		if len(changes) == 0 {
+			return nil, fmt.Errorf("fallback %d does not set any of machineType or zone", i)
+		}
+
+		candidate := cloudprovidertypes.FallbackCandidate{
+			Name: strings.Join(changes, ","),
+			Spec: spec,
+		}
+		candidate.Spec.ProviderSpec.Value, err = candidateSpec.UpdateProviderSpec(spec.ProviderSpec)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
 // AddDefaults reads the MachineSpec and applies defaults for provider specific fields.
 func (p *Provider) AddDefaults(_ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (clusterv1alpha1.MachineSpec, error) {
 	// Read cloud provider spec.
@@ -108,7 +160,14 @@ func (p *Provider) Validate(_ context.Context, _ *zap.SugaredLogger, spec cluste
 		return newError(common.InvalidConfigurationMachineError, errMachineSpec, err)
 	}
 	// Check configured values.
-	if cfg.serviceAccount == "" {
+	if cfg.workloadIdentityPoolProvider != "" {
+		if cfg.projectID == "" {
+			return newError(common.InvalidConfigurationMachineError, errInvalidProjectID)
+		}
+		if cfg.workloadIdentityServiceAccount == "" && !cfg.disableMachineServiceAccount {
+			return newError(common.InvalidConfigurationMachineError, errInvalidMachineSA)
+		}
+	} else if cfg.serviceAccount == "" {
 		return newError(common.InvalidConfigurationMachineError, errInvalidServiceAccount)
 	}
 	if cfg.zone == "" {
@@ -128,6 +187,9 @@ func (p *Provider) Validate(_ context.Context, _ *zap.SugaredLogger, spec cluste
 	if cfg.machineType == "" {
 		return newError(common.InvalidConfigurationMachineError, errInvalidMachineType)
 	}
+	if _, err := p.FallbackCandidates(spec); err != nil {
+		return newError(common.InvalidConfigurationMachineError, errInvalidFallbacks, err)
+	}
 	if cfg.diskSize < 1 {
 		return newError(common.InvalidConfigurationMachineError, errInvalidDiskSize)
 	}
@@ -264,10 +326,16 @@ func (p *Provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 	}
 
 	op, err := svc.Instances.Insert(cfg.projectID, cfg.zone, inst).Do()
+	if isResourcePoolExhausted(err) {
+		return nil, newError(common.InsufficientResourcesMachineError, errInsertInstance, err)
+	}
 	if err != nil {
 		return nil, newError(common.InvalidConfigurationMachineError, errInsertInstance, err)
 	}
 	err = svc.waitZoneOperation(ctx, cfg, op.Name)
+	if isResourcePoolExhausted(err) {
+		return nil, newError(common.InsufficientResourcesMachineError, errInsertInstance, err)
+	}
 	if err != nil {
 		return nil, newError(common.InvalidConfigurationMachineError, errInsertInstance, err)
 	}
@@ -306,6 +374,27 @@ func (p *Provider) Cleanup(ctx context.Context, _ *zap.SugaredLogger, machine *c
 	return false, nil
 }
 
+// MachineAttributes returns the machine type, custom image, region, zone and labels of the spec.
+func (p *Provider) MachineAttributes(spec clusterv1alpha1.MachineSpec) (cloudprovidertypes.MachineAttributes, error) {
+	cfg, err := newConfig(p.resolver, spec.ProviderSpec)
+	if err != nil {
+		return cloudprovidertypes.MachineAttributes{}, newError(common.InvalidConfigurationMachineError, errMachineSpec, err)
+	}
+	attributes := cloudprovidertypes.MachineAttributes{
+		InstanceType: cfg.machineType,
+		Image:        cfg.customImage,
+		Tags:         cfg.labels,
+	}
+	if cfg.zone != "" {
+		attributes.Zones = []string{cfg.zone}
+		// Zones are named after their region, e.g. "europe-west3-a".
+		if i := strings.LastIndex(cfg.zone, "-"); i > 0 {
+			attributes.Region = cfg.zone[:i]
+		}
+	}
+	return attributes, nil
+}
+
 // MachineMetricsLabels returns labels used for the  Prometheus metrics about created machines.
 func (p *Provider) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (map[string]string, error) {
 	// Read configuration.
@@ -373,6 +462,31 @@ func (p *Provider) SetMetricsForMachines(_ clusterv1alpha1.MachineList) error {
 	return nil
 }
 
+// isResourcePoolExhausted returns true if the given error reports that the zone
+// has no capacity for the requested machine type. GCE reports this either as error
+// of the insert operation or already as error of the insert request.
+func isResourcePoolExhausted(err error) bool {
+	var opErr *operationError
+	if errors.As(err, &opErr) {
+		return isResourcePoolExhaustedCode(opErr.Code)
+	}
+
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		for _, item := range gerr.Errors {
+			if isResourcePoolExhaustedCode(item.Reason) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isResourcePoolExhaustedCode(code string) bool {
+	// Also matches ZONE_RESOURCE_POOL_EXHAUSTED_WITH_DETAILS.
+	return strings.HasPrefix(code, "ZONE_RESOURCE_POOL_EXHAUSTED")
+}
+
 // newError creates a terminal error matching to the provider interface.
 func newError(reason common.MachineStatusError, msg string, args ...interface{}) error {
 	return cloudprovidererrors.TerminalError{
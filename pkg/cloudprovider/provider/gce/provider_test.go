@@ -20,11 +20,14 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
 
 	"go.uber.org/zap"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
 
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	"k8c.io/machine-controller/sdk/providerconfig/configvar"
@@ -89,6 +92,8 @@ func testServiceAccount() string {
 `))
 }
 
+const testPoolProvider = "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/pool/providers/kubernetes"
+
 type testMap map[string]interface{}
 
 // with patches value of m at keypath with val e.g. keypath=x.y val=z then m[x][y] = z.
@@ -166,6 +171,49 @@ func TestValidate(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"with workload identity",
+			clusterv1alpha1.MachineSpec{
+				ProviderSpec: clusterv1alpha1.ProviderSpec{
+					Value: &runtime.RawExtension{
+						Raw: rawBytes(testMap(testProviderSpec()).
+							with("cloudProviderSpec.workloadIdentityPoolProvider", testPoolProvider).
+							with("cloudProviderSpec.workloadIdentityServiceAccount", "machine-controller@test-dev.iam.gserviceaccount.com").
+							with("cloudProviderSpec.projectID", "test-dev"),
+						),
+					},
+				},
+			},
+			false,
+		},
+		{
+			"with workload identity but without project ID",
+			clusterv1alpha1.MachineSpec{
+				ProviderSpec: clusterv1alpha1.ProviderSpec{
+					Value: &runtime.RawExtension{
+						Raw: rawBytes(testMap(testProviderSpec()).
+							with("cloudProviderSpec.workloadIdentityPoolProvider", testPoolProvider).
+							with("cloudProviderSpec.workloadIdentityServiceAccount", "machine-controller@test-dev.iam.gserviceaccount.com"),
+						),
+					},
+				},
+			},
+			true,
+		},
+		{
+			"with workload identity but without machine service account",
+			clusterv1alpha1.MachineSpec{
+				ProviderSpec: clusterv1alpha1.ProviderSpec{
+					Value: &runtime.RawExtension{
+						Raw: rawBytes(testMap(testProviderSpec()).
+							with("cloudProviderSpec.workloadIdentityPoolProvider", testPoolProvider).
+							with("cloudProviderSpec.projectID", "test-dev"),
+						),
+					},
+				},
+			},
+			true,
+		},
 	}
 
 	for _, test := range tests {
@@ -177,3 +225,44 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestIsResourcePoolExhausted(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "operation error",
+			err:      fmt.Errorf("wrapped: %w", &operationError{&compute.OperationErrorErrors{Code: "ZONE_RESOURCE_POOL_EXHAUSTED_WITH_DETAILS"}}),
+			expected: true,
+		},
+		{
+			name:     "other operation error",
+			err:      &operationError{&compute.OperationErrorErrors{Code: "QUOTA_EXCEEDED"}},
+			expected: false,
+		},
+		{
+			name:     "request error",
+			err:      &googleapi.Error{Code: 503, Errors: []googleapi.ErrorItem{{Reason: "ZONE_RESOURCE_POOL_EXHAUSTED"}}},
+			expected: true,
+		},
+		{
+			name:     "other request error",
+			err:      &googleapi.Error{Code: 400, Errors: []googleapi.ErrorItem{{Reason: "invalid"}}},
+			expected: false,
+		},
+		{
+			name:     "no error",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if exhausted := isResourcePoolExhausted(test.err); exhausted != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, exhausted)
+			}
+		})
+	}
+}
@@ -145,6 +145,15 @@ func (svc *service) waitZoneOperation(ctx context.Context, cfg *config, opName s
 	})
 }
 
+// operationError is returned if a GCE operation failed.
+type operationError struct {
+	*compute.OperationErrorErrors
+}
+
+func (e *operationError) Error() string {
+	return fmt.Sprintf("GCE operation failed: %v", *e.OperationErrorErrors)
+}
+
 // waitOperation waits for a GCE operation to be completed or timed out.
 func (svc *service) waitOperation(ctx context.Context, refreshOperation func() (*compute.Operation, error)) error {
 	var op *compute.Operation
@@ -159,7 +168,7 @@ func (svc *service) waitOperation(ctx context.Context, refreshOperation func() (
 		if op.Status == statusDone {
 			if op.Error != nil {
 				// Operation failed.
-				return false, fmt.Errorf("GCE operation failed: %v", *op.Error.Errors[0])
+				return false, &operationError{OperationErrorErrors: op.Error.Errors[0]}
 			}
 			return true, nil
 		}
@@ -18,6 +18,8 @@ package hetzner
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -46,6 +48,7 @@ import (
 
 const (
 	machineUIDLabelKey = "machine-uid"
+	clusterIDLabelKey  = "machine-controller-cluster-id"
 )
 
 type provider struct {
@@ -178,7 +181,7 @@ func (p *provider) getServerPlacementGroup(ctx context.Context, client *hcloud.C
 	}
 	pgLabels := map[string]string{}
 	for k, v := range c.Labels {
-		if k != machineUIDLabelKey {
+		if k != machineUIDLabelKey && k != clusterIDLabelKey {
 			pgLabels[k] = v
 		}
 	}
@@ -259,7 +262,7 @@ func (p *provider) Validate(ctx context.Context, _ *zap.SugaredLogger, spec clus
 	return nil
 }
 
-func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
+func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
 	c, pc, _, err := p.getConfig(machine.Spec.ProviderSpec)
 	if err != nil {
 		return nil, cloudprovidererrors.TerminalError{
@@ -286,6 +289,9 @@ func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 	}
 
 	c.Labels[machineUIDLabelKey] = string(machine.UID)
+	if data != nil && data.ClusterID != "" {
+		c.Labels[clusterIDLabelKey] = data.ClusterID
+	}
 
 	serverCreateOpts := hcloud.ServerCreateOpts{
 		Name:     machine.Spec.Name,
@@ -559,6 +565,53 @@ func (p *provider) Get(ctx context.Context, _ *zap.SugaredLogger, machine *clust
 	return nil, cloudprovidererrors.ErrInstanceNotFound
 }
 
+// AccountID returns a hash of the token used by the spec, as API rate limits apply per
+// project and every token belongs to exactly one project.
+func (p *provider) AccountID(spec clusterv1alpha1.MachineSpec) (string, error) {
+	c, _, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse config: %w", err)
+	}
+	sum := sha256.Sum256([]byte(c.Token))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// ListInstances returns all servers that are labeled with a machine UID and the cluster ID
+// and carry the configured labels.
+func (p *provider) ListInstances(ctx context.Context, _ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec, clusterID string) ([]cloudprovidertypes.TaggedInstance, error) {
+	c, _, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, cloudprovidererrors.TerminalError{
+			Reason:  common.InvalidConfigurationMachineError,
+			Message: fmt.Sprintf("Failed to parse MachineSpec, due to %v", err),
+		}
+	}
+
+	client := getClient(c.Token)
+
+	selector := []string{machineUIDLabelKey, clusterIDLabelKey + "==" + clusterID}
+	for k, v := range c.Labels {
+		selector = append(selector, k+"=="+v)
+	}
+
+	servers, err := client.Server.AllWithOpts(ctx, hcloud.ServerListOpts{ListOpts: hcloud.ListOpts{
+		LabelSelector: strings.Join(selector, ","),
+	}})
+	if err != nil {
+		return nil, hzErrorToTerminalError(err, "failed to list servers")
+	}
+
+	instances := make([]cloudprovidertypes.TaggedInstance, 0, len(servers))
+	for _, server := range servers {
+		instances = append(instances, cloudprovidertypes.TaggedInstance{
+			Instance:   &hetznerServer{server: server},
+			MachineUID: types.UID(server.Labels[machineUIDLabelKey]),
+		})
+	}
+
+	return instances, nil
+}
+
 func (p *provider) MigrateUID(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, newUID types.UID) error {
 	c, _, _, err := p.getConfig(machine.Spec.ProviderSpec)
 	if err != nil {
@@ -596,6 +649,117 @@ func (p *provider) MigrateUID(ctx context.Context, log *zap.SugaredLogger, machi
 	return nil
 }
 
+// MachineAttributes returns the server type, image, location, datacenter and labels of the spec.
+func (p *provider) MachineAttributes(spec clusterv1alpha1.MachineSpec) (cloudprovidertypes.MachineAttributes, error) {
+	config, _, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return cloudprovidertypes.MachineAttributes{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	attributes := cloudprovidertypes.MachineAttributes{
+		InstanceType: config.ServerType,
+		Image:        config.Image,
+		Region:       config.Location,
+		Tags:         config.Labels,
+	}
+	if config.Datacenter != "" {
+		attributes.Zones = []string{config.Datacenter}
+	}
+	return attributes, nil
+}
+
+// Plan returns the server Create would launch for the spec. It only looks up the server type,
+// image, networks and firewalls.
+func (p *provider) Plan(ctx context.Context, _ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (*cloudprovidertypes.Plan, error) {
+	c, pc, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	client := getClient(c.Token)
+
+	if c.Image == "" {
+		if c.Image, err = getNameForOS(pc.OperatingSystem); err != nil {
+			return nil, fmt.Errorf("invalid operating system specified %q: %w", pc.OperatingSystem, err)
+		}
+	}
+	if c.Location == "" && c.Datacenter != "" {
+		if c.Location, err = datacenterToLocation(c.Datacenter); err != nil {
+			return nil, err
+		}
+	}
+
+	serverType, _, err := client.ServerType.Get(ctx, c.ServerType)
+	if err != nil {
+		return nil, hzErrorToTerminalError(err, "failed to get server type")
+	}
+	if serverType == nil {
+		return nil, fmt.Errorf("server type %q does not exist", c.ServerType)
+	}
+
+	image, _, err := client.Image.GetForArchitecture(ctx, c.Image, serverType.Architecture)
+	if err != nil {
+		return nil, hzErrorToTerminalError(err, "failed to get image")
+	}
+	if image == nil {
+		return nil, fmt.Errorf("image %q does not exist", c.Image)
+	}
+
+	var networks []string
+	for _, network := range c.Networks {
+		n, _, err := client.Network.Get(ctx, network)
+		if err != nil {
+			return nil, hzErrorToTerminalError(err, "failed to get network")
+		}
+		if n == nil {
+			return nil, fmt.Errorf("network %q does not exist", network)
+		}
+		networks = append(networks, n.Name)
+	}
+
+	var firewalls []string
+	for _, firewall := range c.Firewalls {
+		f, _, err := client.Firewall.Get(ctx, firewall)
+		if err != nil {
+			return nil, hzErrorToTerminalError(err, "failed to get firewall")
+		}
+		if f == nil {
+			return nil, fmt.Errorf("firewall %q does not exist", firewall)
+		}
+		firewalls = append(firewalls, f.Name)
+	}
+
+	plan := &cloudprovidertypes.Plan{
+		Image:          image.Name,
+		InstanceType:   serverType.Name,
+		Region:         c.Location,
+		SecurityGroups: firewalls,
+		Disks: []cloudprovidertypes.PlannedDisk{{
+			Name:   "root",
+			Type:   string(serverType.StorageType),
+			SizeGB: int64(serverType.Disk),
+		}},
+		Tags: c.Labels,
+		Details: map[string]string{
+			"imageID":      strconv.FormatInt(image.ID, 10),
+			"architecture": string(serverType.Architecture),
+			"cores":        strconv.Itoa(serverType.Cores),
+			"memoryGB":     strconv.FormatFloat(float64(serverType.Memory), 'f', -1, 32),
+			"assignIPv4":   strconv.FormatBool(c.AssignIPv4),
+			"assignIPv6":   strconv.FormatBool(c.AssignIPv6),
+		},
+	}
+	if len(networks) > 0 {
+		plan.Network = networks[0]
+	}
+	if len(networks) > 1 {
+		plan.Details["additionalNetworks"] = strings.Join(networks[1:], ",")
+	}
+	if c.PlacementGroupPrefix != "" {
+		plan.Details["placementGroupPrefix"] = c.PlacementGroupPrefix
+	}
+	return plan, nil
+}
+
 func (p *provider) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (map[string]string, error) {
 	labels := make(map[string]string)
 
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -118,6 +119,7 @@ type Config struct {
 
 const (
 	machineUIDMetaKey = "machine-uid"
+	clusterIDMetaKey  = "machine-controller-cluster-id"
 	securityGroupName = "kubernetes-v1"
 	ovhAuthURL        = "auth.cloud.ovh.net"
 )
@@ -610,8 +612,10 @@ func (p *provider) Validate(_ context.Context, _ *zap.SugaredLogger, spec cluste
 	}
 
 	// validate reserved tags.
-	if _, ok := c.Tags[machineUIDMetaKey]; ok {
-		return fmt.Errorf("the tag with the given name =%s is reserved, choose a different one", machineUIDMetaKey)
+	for _, reserved := range []string{machineUIDMetaKey, clusterIDMetaKey} {
+		if _, ok := c.Tags[reserved]; ok {
+			return fmt.Errorf("the tag with the given name =%s is reserved, choose a different one", reserved)
+		}
 	}
 
 	return nil
@@ -698,6 +702,9 @@ func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *
 	// we check against reserved tags in Validation method.
 	allTags := cfg.Tags
 	allTags[machineUIDMetaKey] = string(machine.UID)
+	if data != nil && data.ClusterID != "" {
+		allTags[clusterIDMetaKey] = data.ClusterID
+	}
 
 	serverOpts := osservers.CreateOpts{
 		Name:             machine.Spec.Name,
@@ -927,6 +934,61 @@ func (p *provider) Get(_ context.Context, log *zap.SugaredLogger, machine *clust
 	return nil, cloudprovidererrors.ErrInstanceNotFound
 }
 
+// ListInstances returns all servers that have the machine UID, the cluster ID and all
+// configured tags set in their metadata.
+func (p *provider) ListInstances(_ context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec, clusterID string) ([]cloudprovidertypes.TaggedInstance, error) {
+	c, _, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, cloudprovidererrors.TerminalError{
+			Reason:  common.InvalidConfigurationMachineError,
+			Message: fmt.Sprintf("Failed to parse MachineSpec, due to %v", err),
+		}
+	}
+
+	client, err := p.clientGetter(c)
+	if err != nil {
+		return nil, osErrorToTerminalError(log, err, "failed to get a openstack client")
+	}
+
+	computeClient, err := getNewComputeV2(client, c)
+	if err != nil {
+		return nil, osErrorToTerminalError(log, err, "failed to get compute client")
+	}
+
+	var instances []cloudprovidertypes.TaggedInstance
+	err = osservers.List(computeClient, osservers.ListOpts{}).EachPage(func(page pagination.Page) (bool, error) {
+		var servers []serverWithExt
+		if err := osservers.ExtractServersInto(page, &servers); err != nil {
+			return false, osErrorToTerminalError(log, err, "failed to extract instance info")
+		}
+		for i, s := range servers {
+			if !hasMetadata(s.Metadata, c.Tags) || s.Metadata[machineUIDMetaKey] == "" || s.Metadata[clusterIDMetaKey] != clusterID {
+				continue
+			}
+			instances = append(instances, cloudprovidertypes.TaggedInstance{
+				Instance:   &osInstance{server: &servers[i]},
+				MachineUID: types.UID(s.Metadata[machineUIDMetaKey]),
+			})
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, osErrorToTerminalError(log, err, "failed to list instances")
+	}
+
+	return instances, nil
+}
+
+// hasMetadata returns true if all given tags are set in the metadata.
+func hasMetadata(metadata, tags map[string]string) bool {
+	for k, v := range tags {
+		if value, ok := metadata[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
 func openStackInstanceErrorMessage(s serverWithExt) string {
 	faultMsg := s.Fault.Message
 	if faultMsg == "" {
@@ -986,6 +1048,120 @@ func (p *provider) MigrateUID(_ context.Context, log *zap.SugaredLogger, machine
 	return nil
 }
 
+// MachineAttributes returns the flavor, image, region, availability zone and tags of the spec.
+func (p *provider) MachineAttributes(spec clusterv1alpha1.MachineSpec) (cloudprovidertypes.MachineAttributes, error) {
+	config, _, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return cloudprovidertypes.MachineAttributes{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	attributes := cloudprovidertypes.MachineAttributes{
+		InstanceType: config.Flavor,
+		Image:        config.Image,
+		Region:       config.Region,
+		Tags:         config.Tags,
+	}
+	if config.AvailabilityZone != "" {
+		attributes.Zones = []string{config.AvailabilityZone}
+	}
+	return attributes, nil
+}
+
+// Plan returns the server Create would launch for the spec. It only looks up the flavor, image
+// and networks.
+func (p *provider) Plan(_ context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (*cloudprovidertypes.Plan, error) {
+	cfg, _, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	client, err := p.clientGetter(cfg)
+	if err != nil {
+		return nil, osErrorToTerminalError(log, err, "failed to get a openstack client")
+	}
+
+	computeClient, err := getNewComputeV2(client, cfg)
+	if err != nil {
+		return nil, osErrorToTerminalError(log, err, "failed to get a openstack client")
+	}
+
+	flavor, err := getFlavor(computeClient, cfg)
+	if err != nil {
+		return nil, osErrorToTerminalError(log, err, fmt.Sprintf("failed to get flavor %s", cfg.Flavor))
+	}
+
+	imageClient, err := goopenstack.NewImageServiceV2(client, gophercloud.EndpointOpts{Region: cfg.Region})
+	if err != nil {
+		return nil, osErrorToTerminalError(log, err, "failed to get a image client")
+	}
+
+	image, err := getImageByName(imageClient, cfg)
+	if err != nil {
+		return nil, osErrorToTerminalError(log, err, fmt.Sprintf("failed to get image %s", cfg.Image))
+	}
+
+	netClient, err := goopenstack.NewNetworkV2(client, gophercloud.EndpointOpts{Region: cfg.Region})
+	if err != nil {
+		return nil, err
+	}
+
+	networkNames, err := p.resolveNetworks(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve networks: %w", err)
+	}
+	networkIDs := make([]string, 0, len(networkNames))
+	for _, networkName := range networkNames {
+		network, err := getNetwork(netClient, networkName)
+		if err != nil {
+			return nil, osErrorToTerminalError(log, err, fmt.Sprintf("failed to get network %s", networkName))
+		}
+		networkIDs = append(networkIDs, network.ID)
+	}
+
+	// Create creates the default security group if none is configured.
+	securityGroups := cfg.SecurityGroups
+	if len(securityGroups) == 0 {
+		securityGroups = []string{securityGroupName}
+	}
+
+	// Without a root disk size the server boots from an ephemeral disk of the flavor's size.
+	rootDisk := cloudprovidertypes.PlannedDisk{Name: "root", SizeGB: int64(flavor.Disk)}
+	if cfg.RootDiskSizeGB != nil {
+		rootDisk = cloudprovidertypes.PlannedDisk{Name: "root", Type: cfg.RootDiskVolumeType, SizeGB: int64(*cfg.RootDiskSizeGB)}
+	}
+
+	plan := &cloudprovidertypes.Plan{
+		Image:          image.ID,
+		InstanceType:   flavor.Name,
+		Region:         cfg.Region,
+		Zone:           cfg.AvailabilityZone,
+		Network:        networkIDs[0],
+		Subnet:         cfg.Subnet,
+		SecurityGroups: securityGroups,
+		Disks:          []cloudprovidertypes.PlannedDisk{rootDisk},
+		Tags:           cfg.Tags,
+		Details: map[string]string{
+			"imageName":   image.Name,
+			"flavorID":    flavor.ID,
+			"vcpus":       strconv.Itoa(flavor.VCPUs),
+			"ramMB":       strconv.Itoa(flavor.RAM),
+			"configDrive": strconv.FormatBool(cfg.ConfigDrive),
+		},
+	}
+	if len(networkIDs) > 1 {
+		plan.Details["additionalNetworks"] = strings.Join(networkIDs[1:], ",")
+	}
+	if cfg.FloatingIPPool != "" {
+		plan.Details["floatingIPPool"] = cfg.FloatingIPPool
+	}
+	if cfg.ServerGroup != "" {
+		plan.Details["serverGroup"] = cfg.ServerGroup
+	}
+	if cfg.DisablePortSecurity {
+		plan.Details["disablePortSecurity"] = "true"
+	}
+	return plan, nil
+}
+
 func (p *provider) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (map[string]string, error) {
 	labels := make(map[string]string)
 
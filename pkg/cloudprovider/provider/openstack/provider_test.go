@@ -725,6 +725,71 @@ func TestResolveNetworks(t *testing.T) {
 	}
 }
 
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		specConf openstackProviderSpecConf
+		wantDisk cloudprovidertypes.PlannedDisk
+		wantNets string
+	}{
+		{
+			name:     "Ephemeral root disk",
+			specConf: openstackProviderSpecConf{},
+			wantDisk: cloudprovidertypes.PlannedDisk{Name: "root", SizeGB: 1},
+		},
+		{
+			name:     "Root volume and multiple networks",
+			specConf: openstackProviderSpecConf{RootDiskSizeGB: ptr.To(int32(10)), RootDiskVolumeType: "ssd", Networks: []string{"public", "private"}},
+			wantDisk: cloudprovidertypes.PlannedDisk{Name: "root", Type: "ssd", SizeGB: 10},
+			wantNets: "1df1458e-bd0c-423d-b201-2e5f56c94714",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th.SetupHTTP()
+			defer th.TeardownHTTP()
+			// Registers the read-only handlers, Plan must not create a server.
+			ExpectServerCreated(t, expectedServerRequest)
+			p := &provider{
+				configVarResolver: configvar.NewResolver(context.Background(), fakectrlruntimeclient.NewClientBuilder().Build()),
+				clientGetter: func(*Config) (*gophercloud.ProviderClient, error) {
+					pc := client.ServiceClient()
+					pc.EndpointLocator = func(_ gophercloud.EndpointOpts) (string, error) {
+						return pc.Endpoint, nil
+					}
+					return pc.ProviderClient, nil
+				},
+			}
+			tt.specConf.IdentityEndpointURL = th.Endpoint()
+			m := cloudprovidertesting.Creator{
+				Name:               "test",
+				Namespace:          "openstack",
+				ProviderSpecGetter: tt.specConf.rawProviderSpec,
+			}.CreateMachine(t)
+
+			plan, err := p.Plan(context.Background(), zap.NewNop().Sugar(), m.Spec)
+			if err != nil {
+				t.Fatalf("failed to plan server: %v", err)
+			}
+			if plan.Image != "1bea47ed-f6a9-463b-b423-14b9cca9ad27" || plan.InstanceType != "m1.tiny" {
+				t.Errorf("unexpected image %q or flavor %q", plan.Image, plan.InstanceType)
+			}
+			if plan.Network != "d32019d3-bc6e-4319-9c1d-6722fc136a22" {
+				t.Errorf("expected the public network, got %q", plan.Network)
+			}
+			if plan.Details["additionalNetworks"] != tt.wantNets {
+				t.Errorf("expected additional networks %q, got %q", tt.wantNets, plan.Details["additionalNetworks"])
+			}
+			if len(plan.Disks) != 1 || plan.Disks[0] != tt.wantDisk {
+				t.Errorf("expected disks %+v, got %+v", []cloudprovidertypes.PlannedDisk{tt.wantDisk}, plan.Disks)
+			}
+			if len(plan.SecurityGroups) != 1 || plan.SecurityGroups[0] != "kubernetes-xyz" {
+				t.Errorf("unexpected security groups %v", plan.SecurityGroups)
+			}
+		})
+	}
+}
+
 type ServerResponse struct {
 	Server servers.Server `json:"server"`
 }
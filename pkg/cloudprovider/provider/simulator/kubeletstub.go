@@ -0,0 +1,238 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	simulatortypes "k8c.io/machine-controller/sdk/cloudprovider/simulator"
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// nodeLeaseDurationSeconds is the duration of the node leases, which is the default of the kubelet.
+const nodeLeaseDurationSeconds = 40
+
+// kubeletStub registers a Ready Node for every running simulated instance and renews its lease,
+// like the kubelet of a real instance would, so that the machines of the simulator join the cluster.
+type kubeletStub struct {
+	client   ctrlruntimeclient.Client
+	log      *zap.SugaredLogger
+	provider *provider
+	interval time.Duration
+}
+
+// AddKubeletStub adds a kubelet stub, which registers the Nodes of the simulated instances and
+// renews their leases every interval, to the manager. The interval must be shorter than the node
+// monitor grace period of the kube-controller-manager, otherwise the Nodes become NotReady between
+// the renewals. The status of the Nodes is not updated and they are not deleted by the stub, the
+// latter is done by the machine-controller when the machine is deleted.
+func AddKubeletStub(mgr manager.Manager, log *zap.SugaredLogger, interval time.Duration) error {
+	return mgr.Add(&kubeletStub{
+		client:   mgr.GetClient(),
+		log:      log.Named("simulator-kubelet-stub"),
+		provider: newProvider(),
+		interval: interval,
+	})
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (k *kubeletStub) NeedLeaderElection() bool {
+	return true
+}
+
+// Start registers the Nodes and renews their leases every interval until the context is done.
+// Start is part of manager.Runnable.
+func (k *kubeletStub) Start(ctx context.Context) error {
+	k.log.Infow("Starting simulator kubelet stub", "interval", k.interval)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := k.sync(ctx); err != nil {
+			k.log.Errorw("Failed to register nodes of simulated instances", zap.Error(err))
+		}
+	}, k.interval)
+
+	return nil
+}
+
+func (k *kubeletStub) sync(ctx context.Context) error {
+	machines := &clusterv1alpha1.MachineList{}
+	if err := k.client.List(ctx, machines); err != nil {
+		return fmt.Errorf("failed to list machines: %w", err)
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := k.client.List(ctx, nodes); err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	registered := make(map[string]*corev1.Node, len(nodes.Items))
+	for i := range nodes.Items {
+		registered[nodes.Items[i].Spec.ProviderID] = &nodes.Items[i]
+	}
+
+	// The instances are loaded once per store instead of once per machine, as usually all
+	// machines share the same store.
+	stores := map[simulatortypes.StateConfigMapRef]map[types.UID]*instanceState{}
+	now := k.provider.now()
+
+	for i := range machines.Items {
+		machine := &machines.Items[i]
+		if machine.DeletionTimestamp != nil {
+			continue
+		}
+
+		c, pconfig, err := getConfig(machine.Spec.ProviderSpec)
+		if err != nil || pconfig.CloudProvider != providerconfig.CloudProviderSimulator {
+			continue
+		}
+
+		var storeRef simulatortypes.StateConfigMapRef
+		if c.StateConfigMap != nil {
+			storeRef = *c.StateConfigMap
+		}
+		instances, loaded := stores[storeRef]
+		if !loaded {
+			if instances, err = k.loadInstances(ctx, c); err != nil {
+				k.log.Errorw("Failed to load simulated instances", "machine", ctrlruntimeclient.ObjectKeyFromObject(machine), zap.Error(err))
+				continue
+			}
+			stores[storeRef] = instances
+		}
+
+		state, exists := instances[machine.UID]
+		if !exists || state.status(now) != instance.StatusRunning {
+			continue
+		}
+		inst := newSimulatedInstance(state, now)
+
+		node, exists := registered[inst.ProviderID()]
+		if !exists {
+			node = newNode(inst, now)
+			if err := k.client.Create(ctx, node); err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					k.log.Infow("Node of simulated instance already exists", "node", inst.Name())
+					continue
+				}
+				return fmt.Errorf("failed to create node %s: %w", inst.Name(), err)
+			}
+			k.log.Debugw("Registered node of simulated instance", "node", inst.Name())
+		}
+
+		if err := k.renewLease(ctx, node, now); err != nil {
+			return fmt.Errorf("failed to renew lease of node %s: %w", node.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (k *kubeletStub) loadInstances(ctx context.Context, c *Config) (map[types.UID]*instanceState, error) {
+	s, err := k.provider.store(c)
+	if err != nil {
+		return nil, err
+	}
+	return s.load(ctx)
+}
+
+// renewLease renews the lease of the node, which the kubelet uses as its heartbeat. The lease
+// is patched without reading it first, so the leases of all nodes do not need to be cached.
+func (k *kubeletStub) renewLease(ctx context.Context, node *corev1.Node, now time.Time) error {
+	renewTime := metav1.NewMicroTime(now)
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceNodeLease,
+			Name:      node.Name,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: corev1.SchemeGroupVersion.String(),
+				Kind:       "Node",
+				Name:       node.Name,
+				UID:        node.UID,
+			}},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(node.Name),
+			LeaseDurationSeconds: ptr.To[int32](nodeLeaseDurationSeconds),
+			RenewTime:            &renewTime,
+		},
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"renewTime": renewTime},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal patch: %w", err)
+	}
+	err = k.client.Patch(ctx, lease.DeepCopy(), ctrlruntimeclient.RawPatch(types.MergePatchType, patch))
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err := k.client.Create(ctx, lease); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// newNode returns the Node the kubelet of the instance would register.
+func newNode(inst instance.Instance, now time.Time) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: inst.Name(),
+			Labels: map[string]string{
+				corev1.LabelHostname: inst.Name(),
+			},
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: inst.ProviderID(),
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{
+				Type:               corev1.NodeReady,
+				Status:             corev1.ConditionTrue,
+				Reason:             "KubeletReady",
+				Message:            "simulator kubelet stub is posting ready status",
+				LastHeartbeatTime:  metav1.NewTime(now),
+				LastTransitionTime: metav1.NewTime(now),
+			}},
+		},
+	}
+
+	for address, addressType := range inst.Addresses() {
+		node.Status.Addresses = append(node.Status.Addresses, corev1.NodeAddress{Type: addressType, Address: address})
+	}
+	sort.Slice(node.Status.Addresses, func(i, j int) bool {
+		return node.Status.Addresses[i].Address < node.Status.Addresses[j].Address
+	})
+
+	return node
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// TestKubeletStubSyncWithAPIServer runs the kubelet stub against a real API server, which
+// validates the Nodes and Leases and sets their UIDs. It requires the envtest binaries, whose
+// directory is passed in KUBEBUILDER_ASSETS.
+func TestKubeletStubSyncWithAPIServer(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "..", "..", "examples", "machine-controller.yaml")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("failed to start test environment: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("failed to stop test environment: %v", err)
+		}
+	})
+
+	client, err := ctrlruntimeclient.New(cfg, ctrlruntimeclient.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	clock := &fakeClock{now: time.Now().UTC().Truncate(time.Second)}
+
+	// The API server creates the system namespaces in the background.
+	for _, namespace := range []string{metav1.NamespaceSystem, corev1.NamespaceNodeLease} {
+		if err := client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}); err != nil && !apierrors.IsAlreadyExists(err) {
+			t.Fatalf("failed to create namespace %s: %v", namespace, err)
+		}
+	}
+
+	// The instances are kept in a ConfigMap, so that the store is read from the API server.
+	machine := newMachine("node-1", "", `{"cidr":"10.1.0.0/16","stateConfigMap":{"namespace":"kube-system","name":"simulator-state"}}`)
+	if err := client.Create(ctx, machine); err != nil {
+		t.Fatalf("failed to create machine: %v", err)
+	}
+	p := newTestProvider(clock, client)
+	if _, err := p.Create(ctx, log, machine, nil, ""); err != nil {
+		t.Fatalf("failed to create instance: %v", err)
+	}
+
+	stub := &kubeletStub{client: client, log: log, provider: p}
+	for range 2 {
+		if err := stub.sync(ctx); err != nil {
+			t.Fatalf("failed to sync: %v", err)
+		}
+		clock.now = clock.now.Add(10 * time.Second)
+	}
+
+	node := &corev1.Node{}
+	if err := client.Get(ctx, types.NamespacedName{Name: "node-1"}, node); err != nil {
+		t.Fatalf("failed to get node: %v", err)
+	}
+	inst, err := p.Get(ctx, log, machine, nil)
+	if err != nil {
+		t.Fatalf("failed to get instance: %v", err)
+	}
+	if node.Spec.ProviderID != inst.ProviderID() {
+		t.Errorf("expected node with provider ID %q, got %q", inst.ProviderID(), node.Spec.ProviderID)
+	}
+
+	lease := &coordinationv1.Lease{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: corev1.NamespaceNodeLease, Name: node.Name}, lease); err != nil {
+		t.Fatalf("failed to get node lease: %v", err)
+	}
+	expectedRenewTime := clock.now.Add(-10 * time.Second)
+	if lease.Spec.RenewTime == nil || !lease.Spec.RenewTime.Time.Equal(expectedRenewTime) {
+		t.Errorf("expected lease to be renewed at %v, got %v", expectedRenewTime, lease.Spec.RenewTime)
+	}
+	if len(lease.OwnerReferences) != 1 || lease.OwnerReferences[0].UID != node.UID {
+		t.Errorf("expected lease to be owned by node %s, got owner references %v", node.UID, lease.OwnerReferences)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKubeletStubSync(t *testing.T) {
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)}
+
+	running := newMachine("running", "running-uid", `{"cidr":"10.1.0.0/16"}`)
+	creating := newMachine("creating", "creating-uid", `{"createLatency":"1h"}`)
+	other := newMachine("other", "other-uid", `{}`)
+	other.Spec.ProviderSpec.Value.Raw = []byte(`{"cloudProvider":"fake","cloudProviderSpec":{}}`)
+
+	client := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(running, creating, other).
+		Build()
+	p := newTestProvider(clock, client)
+	if _, err := p.Create(ctx, log, running, nil, ""); err != nil {
+		t.Fatalf("failed to create instance: %v", err)
+	}
+	if _, err := p.Create(ctx, log, creating, nil, ""); err != nil {
+		t.Fatalf("failed to create instance: %v", err)
+	}
+
+	stub := &kubeletStub{client: client, log: log, provider: p}
+	// Syncing twice must not fail on the already registered node and must renew its lease.
+	for range 2 {
+		if err := stub.sync(ctx); err != nil {
+			t.Fatalf("failed to sync: %v", err)
+		}
+		clock.now = clock.now.Add(10 * time.Second)
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := client.List(ctx, nodes); err != nil {
+		t.Fatalf("failed to list nodes: %v", err)
+	}
+	if len(nodes.Items) != 1 {
+		t.Fatalf("expected a node for the running instance only, got %d nodes", len(nodes.Items))
+	}
+
+	node := nodes.Items[0]
+	inst, err := p.Get(ctx, log, running, nil)
+	if err != nil {
+		t.Fatalf("failed to get instance: %v", err)
+	}
+	if node.Name != "running" || node.Spec.ProviderID != inst.ProviderID() {
+		t.Errorf("expected node running with provider ID %q, got node %s with provider ID %q", inst.ProviderID(), node.Name, node.Spec.ProviderID)
+	}
+	expectedAddresses := []corev1.NodeAddress{
+		{Type: corev1.NodeInternalIP, Address: "10.1.0.1"},
+		{Type: corev1.NodeHostName, Address: "running"},
+	}
+	if len(node.Status.Addresses) != len(expectedAddresses) || node.Status.Addresses[0] != expectedAddresses[0] || node.Status.Addresses[1] != expectedAddresses[1] {
+		t.Errorf("expected addresses %v, got %v", expectedAddresses, node.Status.Addresses)
+	}
+	if len(node.Status.Conditions) != 1 || node.Status.Conditions[0].Type != corev1.NodeReady || node.Status.Conditions[0].Status != corev1.ConditionTrue {
+		t.Errorf("expected node to be ready, got conditions %v", node.Status.Conditions)
+	}
+
+	lease := &coordinationv1.Lease{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: corev1.NamespaceNodeLease, Name: node.Name}, lease); err != nil {
+		t.Fatalf("failed to get node lease: %v", err)
+	}
+	expectedRenewTime := clock.now.Add(-10 * time.Second)
+	if lease.Spec.RenewTime == nil || !lease.Spec.RenewTime.Time.Equal(expectedRenewTime) {
+		t.Errorf("expected lease to be renewed at %v, got %v", expectedRenewTime, lease.Spec.RenewTime)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != node.Name {
+		t.Errorf("expected lease to be held by %s, got %v", node.Name, lease.Spec.HolderIdentity)
+	}
+}
@@ -0,0 +1,518 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	simulatortypes "k8c.io/machine-controller/sdk/cloudprovider/simulator"
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+const (
+	defaultCIDR = "10.0.0.0/8"
+
+	providerIDPrefix = "simulator://"
+)
+
+type provider struct {
+	// now returns the current time, which determines the status of the instances.
+	now func() time.Time
+	// random returns a number in [0, 1), which decides whether a fault is injected.
+	random func() float64
+	// memory keeps the instances of machines without a state ConfigMap.
+	memory store
+	// stateClient returns the client used to read and write the state ConfigMaps.
+	stateClient func() (ctrlruntimeclient.Client, error)
+}
+
+// memory keeps the instances of all machines without a state ConfigMap. It is shared by all
+// providers, as a new provider is created for every call.
+var memory = &memoryStore{instances: map[types.UID]*instanceState{}}
+
+// stateClient is shared by all providers for the same reason.
+var stateClient = sync.OnceValues(func() (ctrlruntimeclient.Client, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
+	}
+	return ctrlruntimeclient.New(cfg, ctrlruntimeclient.Options{})
+})
+
+// New returns a simulator provider, which keeps simulated instances in memory or in a ConfigMap
+// instead of creating real ones. It is meant for scale and chaos testing.
+func New(_ providerconfig.ConfigVarResolver) cloudprovidertypes.Provider {
+	return newProvider()
+}
+
+func newProvider() *provider {
+	return &provider{
+		now:         time.Now,
+		random:      rand.Float64,
+		memory:      memory,
+		stateClient: stateClient,
+	}
+}
+
+type Config struct {
+	CreateLatency  time.Duration
+	DeleteLatency  time.Duration
+	CIDR           netip.Prefix
+	StateConfigMap *simulatortypes.StateConfigMapRef
+	Faults         simulatortypes.Faults
+}
+
+// APIError is an error response of the simulated cloud provider API.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e APIError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+func getConfig(provSpec clusterv1alpha1.ProviderSpec) (*Config, *providerconfig.Config, error) {
+	pconfig, err := providerconfig.GetConfig(provSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawConfig, err := simulatortypes.GetConfig(*pconfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := Config{
+		StateConfigMap: rawConfig.StateConfigMap,
+		Faults:         rawConfig.Faults,
+	}
+
+	if rawConfig.CreateLatency != "" {
+		c.CreateLatency, err = time.ParseDuration(rawConfig.CreateLatency)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid createLatency %q: %w", rawConfig.CreateLatency, err)
+		}
+	}
+
+	if rawConfig.DeleteLatency != "" {
+		c.DeleteLatency, err = time.ParseDuration(rawConfig.DeleteLatency)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid deleteLatency %q: %w", rawConfig.DeleteLatency, err)
+		}
+	}
+
+	cidr := rawConfig.CIDR
+	if cidr == "" {
+		cidr = defaultCIDR
+	}
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid cidr %q: %w", cidr, err)
+	}
+	c.CIDR = prefix.Masked()
+
+	return &c, pconfig, nil
+}
+
+func invalidConfigurationError(err error) error {
+	return cloudprovidererrors.TerminalError{
+		Reason:  common.InvalidConfigurationMachineError,
+		Message: fmt.Sprintf("Failed to parse MachineSpec, due to %v", err),
+	}
+}
+
+// store returns the store keeping the instances of the config.
+func (p *provider) store(c *Config) (store, error) {
+	if c.StateConfigMap == nil {
+		return p.memory, nil
+	}
+
+	client, err := p.stateClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for state ConfigMap: %w", err)
+	}
+	return &configMapStore{
+		client: client,
+		key:    types.NamespacedName{Namespace: c.StateConfigMap.Namespace, Name: c.StateConfigMap.Name},
+	}, nil
+}
+
+// injectFault returns true with the given probability.
+func (p *provider) injectFault(ratio float64) bool {
+	return ratio > 0 && p.random() < ratio
+}
+
+// rateLimited returns a 429 response if a rate limit fault is injected.
+func (p *provider) rateLimited(c *Config, operation string) error {
+	if p.injectFault(c.Faults.RateLimited) {
+		return APIError{
+			StatusCode: http.StatusTooManyRequests,
+			Message:    fmt.Sprintf("%s request was throttled", operation),
+		}
+	}
+	return nil
+}
+
+func (p *provider) AddDefaults(_ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (clusterv1alpha1.MachineSpec, error) {
+	return spec, nil
+}
+
+func (p *provider) Validate(_ context.Context, _ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) error {
+	c, _, err := getConfig(spec.ProviderSpec)
+	if err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if c.CreateLatency < 0 {
+		return errors.New("createLatency must not be negative")
+	}
+	if c.DeleteLatency < 0 {
+		return errors.New("deleteLatency must not be negative")
+	}
+
+	if c.StateConfigMap != nil && (c.StateConfigMap.Namespace == "" || c.StateConfigMap.Name == "") {
+		return errors.New("stateConfigMap requires a namespace and a name")
+	}
+
+	for name, ratio := range map[string]float64{
+		"quotaExceeded":       c.Faults.QuotaExceeded,
+		"rateLimited":         c.Faults.RateLimited,
+		"terminal":            c.Faults.Terminal,
+		"silentCreateFailure": c.Faults.SilentCreateFailure,
+	} {
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("faults.%s must be between 0 and 1, got %v", name, ratio)
+		}
+	}
+
+	return nil
+}
+
+func (p *provider) Get(ctx context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData) (instance.Instance, error) {
+	c, _, err := getConfig(machine.Spec.ProviderSpec)
+	if err != nil {
+		return nil, invalidConfigurationError(err)
+	}
+
+	if err := p.rateLimited(c, "get"); err != nil {
+		return nil, err
+	}
+
+	return p.get(ctx, c, machine.UID)
+}
+
+// get returns the instance of the machine without injecting faults.
+func (p *provider) get(ctx context.Context, c *Config, machineUID types.UID) (instance.Instance, error) {
+	s, err := p.store(c)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.now()
+	state, ok := instances[machineUID]
+	if !ok || state.status(now) == instance.StatusDeleted {
+		return nil, cloudprovidererrors.ErrInstanceNotFound
+	}
+	return newSimulatedInstance(state, now), nil
+}
+
+// Create stores a new instance for the machine, which is creating for the configured latency
+// before it is running.
+func (p *provider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, _ string) (instance.Instance, error) {
+	c, _, err := getConfig(machine.Spec.ProviderSpec)
+	if err != nil {
+		return nil, invalidConfigurationError(err)
+	}
+
+	if err := p.rateLimited(c, "create"); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.injectFault(c.Faults.QuotaExceeded):
+		return nil, cloudprovidererrors.TerminalError{
+			Reason:  common.InsufficientResourcesMachineError,
+			Message: "Quota exceeded for instances",
+		}
+	case p.injectFault(c.Faults.Terminal):
+		return nil, cloudprovidererrors.TerminalError{
+			Reason:  common.CreateMachineError,
+			Message: "Simulated failure to create instance",
+		}
+	}
+
+	var clusterID string
+	if data != nil {
+		clusterID = data.ClusterID
+	}
+
+	now := p.now()
+	if p.injectFault(c.Faults.SilentCreateFailure) {
+		log.Debug("Silently failing to create instance as requested")
+		return newSimulatedInstance(&instanceState{
+			ID:         uuid.NewString(),
+			Name:       machine.Spec.Name,
+			MachineUID: machine.UID,
+			RunningAt:  now.Add(c.CreateLatency),
+		}, now), nil
+	}
+
+	s, err := p.store(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *instanceState
+	if err := s.update(ctx, func(instances map[types.UID]*instanceState) error {
+		if existing, ok := instances[machine.UID]; ok && existing.status(now) != instance.StatusDeleted {
+			created = existing
+			return nil
+		}
+
+		address, err := allocateAddress(c.CIDR, instances)
+		if err != nil {
+			return err
+		}
+
+		created = &instanceState{
+			ID:         uuid.NewString(),
+			Name:       machine.Spec.Name,
+			MachineUID: machine.UID,
+			ClusterID:  clusterID,
+			Address:    address.String(),
+			RunningAt:  now.Add(c.CreateLatency),
+		}
+		instances[machine.UID] = created
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create instance: %w", err)
+	}
+
+	return newSimulatedInstance(created, now), nil
+}
+
+// Cleanup starts the deletion of the instance of the machine, which is deleting for the
+// configured latency before it is gone.
+func (p *provider) Cleanup(ctx context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData) (bool, error) {
+	c, _, err := getConfig(machine.Spec.ProviderSpec)
+	if err != nil {
+		return false, invalidConfigurationError(err)
+	}
+
+	if err := p.rateLimited(c, "delete"); err != nil {
+		return false, err
+	}
+
+	s, err := p.store(c)
+	if err != nil {
+		return false, err
+	}
+
+	var deleted bool
+	if err := s.update(ctx, func(instances map[types.UID]*instanceState) error {
+		state, ok := instances[machine.UID]
+		if !ok {
+			deleted = true
+			return nil
+		}
+
+		now := p.now()
+		if state.DeletedAt == nil {
+			deletedAt := now.Add(c.DeleteLatency)
+			state.DeletedAt = &deletedAt
+		}
+
+		deleted = state.status(now) == instance.StatusDeleted
+		if deleted {
+			delete(instances, machine.UID)
+		}
+		return nil
+	}); err != nil {
+		return false, fmt.Errorf("failed to delete instance: %w", err)
+	}
+
+	return deleted, nil
+}
+
+func (p *provider) MigrateUID(ctx context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, newUID types.UID) error {
+	c, _, err := getConfig(machine.Spec.ProviderSpec)
+	if err != nil {
+		return invalidConfigurationError(err)
+	}
+
+	s, err := p.store(c)
+	if err != nil {
+		return err
+	}
+
+	return s.update(ctx, func(instances map[types.UID]*instanceState) error {
+		state, ok := instances[machine.UID]
+		if !ok {
+			return nil
+		}
+		delete(instances, machine.UID)
+		state.MachineUID = newUID
+		instances[newUID] = state
+		return nil
+	})
+}
+
+// ListInstances returns all instances of the cluster kept in the store of the spec.
+func (p *provider) ListInstances(ctx context.Context, _ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec, clusterID string) ([]cloudprovidertypes.TaggedInstance, error) {
+	c, _, err := getConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, invalidConfigurationError(err)
+	}
+
+	s, err := p.store(c)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.now()
+	result := make([]cloudprovidertypes.TaggedInstance, 0, len(instances))
+	for _, state := range instances {
+		if state.ClusterID != clusterID || state.status(now) == instance.StatusDeleted {
+			continue
+		}
+		result = append(result, cloudprovidertypes.TaggedInstance{
+			Instance:   newSimulatedInstance(state, now),
+			MachineUID: state.MachineUID,
+		})
+	}
+
+	return result, nil
+}
+
+func (p *provider) MachineMetricsLabels(_ *clusterv1alpha1.Machine) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (p *provider) SetMetricsForMachines(_ clusterv1alpha1.MachineList) error {
+	return nil
+}
+
+// allocateAddress returns the first address of the CIDR after the network address that is
+// not used by any instance.
+func allocateAddress(cidr netip.Prefix, instances map[types.UID]*instanceState) (netip.Addr, error) {
+	used := make(map[string]bool, len(instances))
+	for _, state := range instances {
+		used[state.Address] = true
+	}
+
+	for address := cidr.Addr().Next(); cidr.Contains(address); address = address.Next() {
+		if !used[address.String()] {
+			return address, nil
+		}
+	}
+	return netip.Addr{}, fmt.Errorf("no free address left in %s", cidr)
+}
+
+// instanceState is a simulated instance as it is kept in the store.
+type instanceState struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	MachineUID types.UID `json:"machineUID"`
+	ClusterID  string    `json:"clusterID,omitempty"`
+	Address    string    `json:"address,omitempty"`
+	// RunningAt is the time the instance finishes creating.
+	RunningAt time.Time `json:"runningAt"`
+	// DeletedAt is the time the instance is gone. It is set once the instance is deleted.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+func (s *instanceState) status(now time.Time) instance.Status {
+	switch {
+	case s.DeletedAt != nil && !now.Before(*s.DeletedAt):
+		return instance.StatusDeleted
+	case s.DeletedAt != nil:
+		return instance.StatusDeleting
+	case now.Before(s.RunningAt):
+		return instance.StatusCreating
+	default:
+		return instance.StatusRunning
+	}
+}
+
+// simulatedInstance is a simulated instance at a point in time.
+type simulatedInstance struct {
+	state  instanceState
+	status instance.Status
+}
+
+func newSimulatedInstance(state *instanceState, now time.Time) *simulatedInstance {
+	return &simulatedInstance{state: *state, status: state.status(now)}
+}
+
+func (i *simulatedInstance) Name() string {
+	return i.state.Name
+}
+
+func (i *simulatedInstance) ID() string {
+	return i.state.ID
+}
+
+func (i *simulatedInstance) ProviderID() string {
+	return providerIDPrefix + i.state.ID
+}
+
+// Addresses returns the addresses of the instance, which are only reported once it finished
+// creating.
+func (i *simulatedInstance) Addresses() map[string]corev1.NodeAddressType {
+	if i.status == instance.StatusCreating || i.state.Address == "" {
+		return nil
+	}
+	return map[string]corev1.NodeAddressType{
+		i.state.Address: corev1.NodeInternalIP,
+		i.state.Name:    corev1.NodeHostName,
+	}
+}
+
+func (i *simulatedInstance) Status() instance.Status {
+	return i.status
+}
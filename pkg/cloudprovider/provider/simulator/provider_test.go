@@ -0,0 +1,310 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	if err := clusterv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		panic(fmt.Sprintf("failed to add clusterv1alpha1 api to scheme: %v", err))
+	}
+}
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestProvider(clock *fakeClock, client ctrlruntimeclient.Client) *provider {
+	return &provider{
+		now:    clock.Now,
+		random: func() float64 { return 0.5 },
+		memory: &memoryStore{instances: map[types.UID]*instanceState{}},
+		stateClient: func() (ctrlruntimeclient.Client, error) {
+			return client, nil
+		},
+	}
+}
+
+func newMachine(name string, uid types.UID, cloudProviderSpec string) *clusterv1alpha1.Machine {
+	return &clusterv1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+			UID:       uid,
+		},
+		Spec: clusterv1alpha1.MachineSpec{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			ProviderSpec: clusterv1alpha1.ProviderSpec{
+				Value: &runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"cloudProvider":"simulator","cloudProviderSpec":%s}`, cloudProviderSpec))},
+			},
+		},
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	tests := []struct {
+		name              string
+		cloudProviderSpec string
+	}{
+		{
+			name:              "instances kept in memory",
+			cloudProviderSpec: `{"createLatency":"1m","deleteLatency":"30s","cidr":"192.168.0.0/24"}`,
+		},
+		{
+			name:              "instances kept in a ConfigMap",
+			cloudProviderSpec: `{"createLatency":"1m","deleteLatency":"30s","cidr":"192.168.0.0/24","stateConfigMap":{"namespace":"kube-system","name":"simulator"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			log := zap.NewNop().Sugar()
+			clock := &fakeClock{now: time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)}
+			client := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			p := newTestProvider(clock, client)
+
+			data := &cloudprovidertypes.ProviderData{ClusterID: "cluster-id"}
+			first := newMachine("first", "first-uid", test.cloudProviderSpec)
+			second := newMachine("second", "second-uid", test.cloudProviderSpec)
+
+			if err := p.Validate(ctx, log, first.Spec); err != nil {
+				t.Fatalf("failed to validate spec: %v", err)
+			}
+
+			if _, err := p.Get(ctx, log, first, nil); !errors.Is(err, cloudprovidererrors.ErrInstanceNotFound) {
+				t.Fatalf("expected instance not to be found before it is created, got %v", err)
+			}
+
+			inst, err := p.Create(ctx, log, first, data, "")
+			if err != nil {
+				t.Fatalf("failed to create instance: %v", err)
+			}
+			if inst.Status() != instance.StatusCreating || len(inst.Addresses()) != 0 {
+				t.Errorf("expected a creating instance without addresses, got status %s with addresses %v", inst.Status(), inst.Addresses())
+			}
+			if _, err := p.Create(ctx, log, second, data, ""); err != nil {
+				t.Fatalf("failed to create instance: %v", err)
+			}
+
+			clock.now = clock.now.Add(time.Minute)
+
+			inst, err = p.Get(ctx, log, first, nil)
+			if err != nil {
+				t.Fatalf("failed to get instance: %v", err)
+			}
+			if inst.Status() != instance.StatusRunning {
+				t.Errorf("expected instance to be running after the create latency, got %s", inst.Status())
+			}
+			if inst.Addresses()["192.168.0.1"] != corev1.NodeInternalIP {
+				t.Errorf("expected first instance to get the first address, got %v", inst.Addresses())
+			}
+			if inst.ProviderID() != "simulator://"+inst.ID() {
+				t.Errorf("unexpected provider ID %q", inst.ProviderID())
+			}
+
+			inst, err = p.Get(ctx, log, second, nil)
+			if err != nil {
+				t.Fatalf("failed to get instance: %v", err)
+			}
+			if inst.Addresses()["192.168.0.2"] != corev1.NodeInternalIP {
+				t.Errorf("expected second instance to get the second address, got %v", inst.Addresses())
+			}
+
+			if err := p.MigrateUID(ctx, log, second, "migrated-uid"); err != nil {
+				t.Fatalf("failed to migrate UID: %v", err)
+			}
+			instances, err := p.ListInstances(ctx, log, first.Spec, "other-cluster-id")
+			if err != nil {
+				t.Fatalf("failed to list instances: %v", err)
+			}
+			if len(instances) != 0 {
+				t.Errorf("expected no instances of another cluster, got %d", len(instances))
+			}
+			instances, err = p.ListInstances(ctx, log, first.Spec, "cluster-id")
+			if err != nil {
+				t.Fatalf("failed to list instances: %v", err)
+			}
+			uids := map[types.UID]bool{}
+			for _, i := range instances {
+				uids[i.MachineUID] = true
+			}
+			if len(uids) != 2 || !uids["first-uid"] || !uids["migrated-uid"] {
+				t.Errorf("expected instances of machines first-uid and migrated-uid, got %v", uids)
+			}
+
+			deleted, err := p.Cleanup(ctx, log, first, nil)
+			if err != nil {
+				t.Fatalf("failed to delete instance: %v", err)
+			}
+			if deleted {
+				t.Error("expected instance not to be gone before the delete latency")
+			}
+			inst, err = p.Get(ctx, log, first, nil)
+			if err != nil {
+				t.Fatalf("failed to get instance: %v", err)
+			}
+			if inst.Status() != instance.StatusDeleting {
+				t.Errorf("expected instance to be deleting, got %s", inst.Status())
+			}
+
+			clock.now = clock.now.Add(30 * time.Second)
+
+			if _, err := p.Get(ctx, log, first, nil); !errors.Is(err, cloudprovidererrors.ErrInstanceNotFound) {
+				t.Errorf("expected instance not to be found after the delete latency, got %v", err)
+			}
+			deleted, err = p.Cleanup(ctx, log, first, nil)
+			if err != nil {
+				t.Fatalf("failed to delete instance: %v", err)
+			}
+			if !deleted {
+				t.Error("expected instance to be gone after the delete latency")
+			}
+
+			// The address of the deleted instance is free again.
+			third := newMachine("third", "third-uid", test.cloudProviderSpec)
+			if _, err := p.Create(ctx, log, third, data, ""); err != nil {
+				t.Fatalf("failed to create instance: %v", err)
+			}
+			clock.now = clock.now.Add(time.Minute)
+			inst, err = p.Get(ctx, log, third, nil)
+			if err != nil {
+				t.Fatalf("failed to get instance: %v", err)
+			}
+			if inst.Addresses()["192.168.0.1"] != corev1.NodeInternalIP {
+				t.Errorf("expected third instance to reuse the first address, got %v", inst.Addresses())
+			}
+		})
+	}
+}
+
+func TestFaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		faults      string
+		expectError func(error) bool
+		expectGone  bool
+	}{
+		{
+			name:   "quota exceeded",
+			faults: `{"quotaExceeded":1}`,
+			expectError: func(err error) bool {
+				terminal, reason, _ := cloudprovidererrors.IsTerminalError(err)
+				return terminal && reason == common.InsufficientResourcesMachineError
+			},
+		},
+		{
+			name:   "rate limited",
+			faults: `{"rateLimited":1}`,
+			expectError: func(err error) bool {
+				var apiErr APIError
+				return errors.As(err, &apiErr) && apiErr.StatusCode == 429
+			},
+		},
+		{
+			name:   "terminal",
+			faults: `{"terminal":1}`,
+			expectError: func(err error) bool {
+				terminal, reason, _ := cloudprovidererrors.IsTerminalError(err)
+				return terminal && reason == common.CreateMachineError
+			},
+		},
+		{
+			name:       "silent create failure",
+			faults:     `{"silentCreateFailure":1}`,
+			expectGone: true,
+		},
+		{
+			name:   "faults below the ratio are not injected",
+			faults: `{"quotaExceeded":0.4,"rateLimited":0.4,"terminal":0.4,"silentCreateFailure":0.4}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			log := zap.NewNop().Sugar()
+			p := newTestProvider(&fakeClock{now: time.Now()}, nil)
+			machine := newMachine("machine", "machine-uid", fmt.Sprintf(`{"faults":%s}`, test.faults))
+
+			if err := p.Validate(ctx, log, machine.Spec); err != nil {
+				t.Fatalf("failed to validate spec: %v", err)
+			}
+
+			_, err := p.Create(ctx, log, machine, nil, "")
+			switch {
+			case test.expectError != nil && !test.expectError(err):
+				t.Fatalf("unexpected error: %v", err)
+			case test.expectError == nil && err != nil:
+				t.Fatalf("failed to create instance: %v", err)
+			}
+
+			// The fault of a failed create is not on the instance, so it is only checked
+			// when the create succeeded.
+			if err != nil {
+				return
+			}
+			_, err = p.Get(ctx, log, machine, nil)
+			if gone := errors.Is(err, cloudprovidererrors.ErrInstanceNotFound); gone != test.expectGone {
+				t.Errorf("expected instance to be gone: %t, got error %v", test.expectGone, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, spec := range []string{
+		`{"createLatency":"soon"}`,
+		`{"deleteLatency":"-1s"}`,
+		`{"cidr":"10.0.0.0"}`,
+		`{"stateConfigMap":{"name":"simulator"}}`,
+		`{"faults":{"terminal":2}}`,
+		`{"unknown":true}`,
+	} {
+		p := newTestProvider(&fakeClock{now: time.Now()}, nil)
+		if err := p.Validate(context.Background(), zap.NewNop().Sugar(), newMachine("machine", "machine-uid", spec).Spec); err == nil {
+			t.Errorf("expected spec %s to be rejected", spec)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// store keeps the simulated instances by the UID of their machine.
+type store interface {
+	// load returns all instances.
+	load(ctx context.Context) (map[types.UID]*instanceState, error)
+	// update calls modify with all instances and persists its changes, unless it returns an
+	// error. modify may be called more than once.
+	update(ctx context.Context, modify func(instances map[types.UID]*instanceState) error) error
+}
+
+// memoryStore keeps the instances in memory, so they are lost when the machine-controller restarts.
+type memoryStore struct {
+	lock      sync.Mutex
+	instances map[types.UID]*instanceState
+}
+
+func (s *memoryStore) load(_ context.Context) (map[types.UID]*instanceState, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return copyInstances(s.instances), nil
+}
+
+func (s *memoryStore) update(_ context.Context, modify func(instances map[types.UID]*instanceState) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	instances := copyInstances(s.instances)
+	if err := modify(instances); err != nil {
+		return err
+	}
+	s.instances = instances
+	return nil
+}
+
+func copyInstances(instances map[types.UID]*instanceState) map[types.UID]*instanceState {
+	copied := make(map[types.UID]*instanceState, len(instances))
+	for uid, state := range instances {
+		c := *state
+		copied[uid] = &c
+	}
+	return copied
+}
+
+// configMapStore keeps the instances in a ConfigMap, with one key per machine UID. The size of
+// a ConfigMap is limited to 1MiB, which is enough for several thousand instances.
+type configMapStore struct {
+	client ctrlruntimeclient.Client
+	key    types.NamespacedName
+}
+
+func (s *configMapStore) load(ctx context.Context) (map[types.UID]*instanceState, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := s.client.Get(ctx, s.key, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return map[types.UID]*instanceState{}, nil
+		}
+		return nil, fmt.Errorf("failed to get state ConfigMap %s: %w", s.key, err)
+	}
+	return decodeInstances(configMap)
+}
+
+func (s *configMapStore) update(ctx context.Context, modify func(instances map[types.UID]*instanceState) error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap := &corev1.ConfigMap{}
+		err := s.client.Get(ctx, s.key, configMap)
+		notFound := apierrors.IsNotFound(err)
+		if err != nil && !notFound {
+			return fmt.Errorf("failed to get state ConfigMap %s: %w", s.key, err)
+		}
+
+		instances, err := decodeInstances(configMap)
+		if err != nil {
+			return err
+		}
+		if err := modify(instances); err != nil {
+			return err
+		}
+		if configMap.Data, err = encodeInstances(instances); err != nil {
+			return err
+		}
+
+		if !notFound {
+			if err := s.client.Update(ctx, configMap); err != nil {
+				return fmt.Errorf("failed to update state ConfigMap %s: %w", s.key, err)
+			}
+			return nil
+		}
+
+		configMap.Namespace = s.key.Namespace
+		configMap.Name = s.key.Name
+		if err := s.client.Create(ctx, configMap); err != nil {
+			// The ConfigMap was created concurrently, so retry with its content.
+			if apierrors.IsAlreadyExists(err) {
+				return apierrors.NewConflict(corev1.Resource("configmaps"), s.key.Name, err)
+			}
+			return fmt.Errorf("failed to create state ConfigMap %s: %w", s.key, err)
+		}
+		return nil
+	})
+}
+
+func decodeInstances(configMap *corev1.ConfigMap) (map[types.UID]*instanceState, error) {
+	instances := make(map[types.UID]*instanceState, len(configMap.Data))
+	for uid, value := range configMap.Data {
+		state := &instanceState{}
+		if err := json.Unmarshal([]byte(value), state); err != nil {
+			return nil, fmt.Errorf("failed to decode instance of machine %s: %w", uid, err)
+		}
+		instances[types.UID(uid)] = state
+	}
+	return instances, nil
+}
+
+func encodeInstances(instances map[types.UID]*instanceState) (map[string]string, error) {
+	data := make(map[string]string, len(instances))
+	for uid, state := range instances {
+		value, err := json.Marshal(state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode instance of machine %s: %w", uid, err)
+		}
+		data[string(uid)] = string(value)
+	}
+	return data, nil
+}
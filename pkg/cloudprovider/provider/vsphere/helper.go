@@ -17,14 +17,9 @@ limitations under the License.
 package vsphere
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
-	"errors"
 	"fmt"
-	"os"
-	"os/exec"
-	"text/template"
 
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vapi/tags"
@@ -32,14 +27,10 @@ import (
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
 	"go.uber.org/zap"
-
-	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 )
 
 const (
-	localTempDir     = "/tmp"
-	metaDataTemplate = `instance-id: {{ .InstanceID}}
-local-hostname: {{ .Hostname }}`
+	localTempDir = "/tmp"
 
 	gigaByte = (1024 * 1024 * 1024)
 )
@@ -313,68 +304,6 @@ func uploadAndAttachISO(ctx context.Context, log *zap.SugaredLogger, session *Se
 	return vmRef.EditDevice(ctx, devices.InsertIso(cdrom, iso))
 }
 
-func generateLocalUserdataISO(ctx context.Context, userdata, name string) (string, error) {
-	// We must create a directory, because the iso-generation commands
-	// take a directory as input
-	userdataDir, err := os.MkdirTemp(localTempDir, name)
-	if err != nil {
-		return "", fmt.Errorf("failed to create local temp directory for userdata at %s: %w", userdataDir, err)
-	}
-	defer func() {
-		if err := os.RemoveAll(userdataDir); err != nil {
-			utilruntime.HandleError(fmt.Errorf("error cleaning up local userdata tempdir %s: %w", userdataDir, err))
-		}
-	}()
-
-	userdataFilePath := fmt.Sprintf("%s/user-data", userdataDir)
-	metadataFilePath := fmt.Sprintf("%s/meta-data", userdataDir)
-	isoFilePath := fmt.Sprintf("%s/%s.iso", localTempDir, name)
-
-	metadataTmpl, err := template.New("metadata").Parse(metaDataTemplate)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse metadata template: %w", err)
-	}
-	metadata := &bytes.Buffer{}
-	templateContext := struct {
-		InstanceID string
-		Hostname   string
-	}{
-		InstanceID: name,
-		Hostname:   name,
-	}
-	if err = metadataTmpl.Execute(metadata, templateContext); err != nil {
-		return "", fmt.Errorf("failed to render metadata: %w", err)
-	}
-
-	if err := os.WriteFile(userdataFilePath, []byte(userdata), 0644); err != nil {
-		return "", fmt.Errorf("failed to locally write userdata file to %s: %w", userdataFilePath, err)
-	}
-
-	if err := os.WriteFile(metadataFilePath, metadata.Bytes(), 0644); err != nil {
-		return "", fmt.Errorf("failed to locally write metadata file to %s: %w", userdataFilePath, err)
-	}
-
-	var command string
-	var args []string
-
-	if _, err := exec.LookPath("genisoimage"); err == nil {
-		command = "genisoimage"
-		args = []string{"-o", isoFilePath, "-volid", "cidata", "-joliet", "-rock", userdataDir}
-	} else if _, err := exec.LookPath("mkisofs"); err == nil {
-		command = "mkisofs"
-		args = []string{"-o", isoFilePath, "-V", "cidata", "-J", "-R", userdataDir}
-	} else {
-		return "", errors.New("system is missing genisoimage or mkisofs, can't generate userdata iso without it")
-	}
-
-	cmd := exec.CommandContext(ctx, command, args...)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return "", fmt.Errorf("error executing command `%s %s`: output: `%s`, error: `%w`", command, args, string(output), err)
-	}
-
-	return isoFilePath, nil
-}
-
 func removeFloppyDevice(ctx context.Context, virtualMachine *object.VirtualMachine) error {
 	vmDevices, err := virtualMachine.Device(ctx)
 	if err != nil {
@@ -34,6 +34,7 @@ import (
 	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
 	"k8c.io/machine-controller/pkg/cloudprovider/instance"
 	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/pkg/cloudprovider/util"
 	"k8c.io/machine-controller/sdk/apis/cluster/common"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	vspheretypes "k8c.io/machine-controller/sdk/cloudprovider/vsphere"
@@ -397,7 +398,7 @@ func (p *provider) create(ctx context.Context, log *zap.SugaredLogger, machine *
 	}
 
 	if pc.OperatingSystem != providerconfig.OperatingSystemFlatcar {
-		localUserdataIsoFilePath, err := generateLocalUserdataISO(ctx, userdata, machine.Spec.Name)
+		localUserdataIsoFilePath, err := util.GenerateUserdataISO(ctx, localTempDir, userdata, machine.Spec.Name)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate local userdadata iso: %w", err)
 		}
@@ -602,6 +603,117 @@ func (p *provider) MigrateUID(_ context.Context, _ *zap.SugaredLogger, _ *cluste
 	return nil
 }
 
+// MachineAttributes returns the size, template and datacenter of the spec. The size has
+// the format "<cpus>-cpus-<memory>-mb", as in the machine metrics.
+func (p *provider) MachineAttributes(spec clusterv1alpha1.MachineSpec) (cloudprovidertypes.MachineAttributes, error) {
+	config, _, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return cloudprovidertypes.MachineAttributes{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return cloudprovidertypes.MachineAttributes{
+		InstanceType: fmt.Sprintf("%d-cpus-%d-mb", config.CPUs, config.MemoryMB),
+		Image:        config.TemplateVMName,
+		Region:       config.Datacenter,
+	}, nil
+}
+
+// Plan returns the VM Create would clone for the spec. It only looks up the template VM to
+// report its disk.
+func (p *provider) Plan(ctx context.Context, _ *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (*cloudprovidertypes.Plan, error) {
+	config, pc, _, err := p.getConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	session, err := NewSession(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vCenter session: %w", err)
+	}
+	defer session.Logout(ctx)
+
+	templateVM, err := session.Finder.VirtualMachine(ctx, config.TemplateVMName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template vm %q: %w", config.TemplateVMName, err)
+	}
+	disks, err := getDisksFromVM(ctx, templateVM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disks from VM: %w", err)
+	}
+	if diskLen := len(disks); diskLen != 1 {
+		return nil, fmt.Errorf("expected vm to have exactly one disk, had %d", diskLen)
+	}
+
+	// The cloned disk keeps the size of the template, unless it is resized.
+	diskSizeGB := (disks[0].CapacityInBytes + gigaByte - 1) / gigaByte
+	if config.DiskSizeGB != nil {
+		diskSizeGB = *config.DiskSizeGB
+	}
+
+	plan := &cloudprovidertypes.Plan{
+		Image:        templateVM.InventoryPath,
+		InstanceType: fmt.Sprintf("%d-cpus-%d-mb", config.CPUs, config.MemoryMB),
+		Region:       config.Datacenter,
+		Disks: []cloudprovidertypes.PlannedDisk{{
+			Name:   "root",
+			SizeGB: diskSizeGB,
+		}},
+		Details: map[string]string{
+			"folder": config.Folder,
+		},
+	}
+
+	// Without networks the VM keeps the networks of the template.
+	networks := config.Networks
+	if config.VMNetName != "" {
+		networks = []string{config.VMNetName}
+	}
+	if len(networks) > 0 {
+		plan.Network = networks[0]
+	}
+	if len(networks) > 1 {
+		plan.Details["additionalNetworks"] = strings.Join(networks[1:], ",")
+	}
+
+	if len(config.Tags) > 0 {
+		plan.Tags = map[string]string{}
+		for _, tag := range config.Tags {
+			name := tag.Name
+			if name == "" {
+				name = tag.ID
+			}
+			plan.Tags[name] = tag.CategoryID
+		}
+	}
+
+	if config.Datastore != "" {
+		plan.Details["datastore"] = config.Datastore
+	}
+	if config.DatastoreCluster != "" {
+		plan.Details["datastoreCluster"] = config.DatastoreCluster
+	}
+	if config.Cluster != "" {
+		plan.Details["cluster"] = config.Cluster
+	}
+	if config.ResourcePool != "" {
+		plan.Details["resourcePool"] = config.ResourcePool
+	}
+	if config.VMGroup != "" {
+		plan.Details["vmGroup"] = config.VMGroup
+	}
+	if config.VMAntiAffinity {
+		plan.Details["vmAntiAffinity"] = "true"
+	}
+
+	// Flatcar reads its userdata from the guestinfo of the VM, all other operating
+	// systems from an ISO uploaded next to it.
+	plan.Details["userdata"] = "iso"
+	if pc.OperatingSystem == providerconfig.OperatingSystemFlatcar {
+		plan.Details["userdata"] = "guestinfo"
+	}
+
+	return plan, nil
+}
+
 func (p *provider) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (map[string]string, error) {
 	labels := make(map[string]string)
 
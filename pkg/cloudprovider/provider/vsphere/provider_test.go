@@ -189,3 +189,48 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestPlan(t *testing.T) {
+	model := simulator.VPX()
+	model.Cluster++
+
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := model.Service.NewServer()
+	defer s.Close()
+
+	password, _ := simulator.DefaultLogin.Password()
+	conf := vsphereProviderSpecConf{
+		Datastore: ptr.To("LocalDS_0"),
+		User:      simulator.DefaultLogin.Username(),
+		Password:  password,
+		URL:       strings.TrimSuffix(s.URL.String(), "/sdk"),
+	}
+	p := &provider{
+		configVarResolver: configvar.NewResolver(context.Background(), fakectrlruntimeclient.NewClientBuilder().Build()),
+	}
+	m := cloudprovidertesting.Creator{Name: "test", Namespace: "vsphere", ProviderSpecGetter: conf.rawProviderSpec}.CreateMachine(t)
+
+	plan, err := p.Plan(context.Background(), zap.NewNop().Sugar(), m.Spec)
+	if err != nil {
+		t.Fatalf("failed to plan vm: %v", err)
+	}
+	if plan.InstanceType != "1-cpus-2000-mb" || plan.Region != "DC0" {
+		t.Errorf("unexpected size %q or datacenter %q", plan.InstanceType, plan.Region)
+	}
+	if !strings.HasSuffix(plan.Image, "/DC0_H0_VM0") {
+		t.Errorf("expected the inventory path of the template, got %q", plan.Image)
+	}
+	if len(plan.Disks) != 1 || plan.Disks[0].SizeGB < 1 {
+		t.Errorf("expected the disk of the template, got %+v", plan.Disks)
+	}
+	if plan.Details["datastore"] != "LocalDS_0" || plan.Details["cluster"] != "DC0_C0" {
+		t.Errorf("unexpected placement %v", plan.Details)
+	}
+	if plan.Details["userdata"] != "guestinfo" {
+		t.Errorf("expected flatcar to read its userdata from guestinfo, got %q", plan.Details["userdata"])
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+)
+
+var operations = []Operation{OperationGet, OperationCreate, OperationCleanup}
+
+// BudgetsFlag is a flag.Value collecting "<cloud-provider>.<operation>=<qps>:<burst>" budgets.
+// The operation can be omitted to set the budget of all operations. The flag can be given
+// multiple times.
+type BudgetsFlag Budgets
+
+func (f BudgetsFlag) String() string {
+	var budgets []string
+	for provider, operationBudgets := range f {
+		for operation, budget := range operationBudgets {
+			budgets = append(budgets, fmt.Sprintf("%s.%s=%s:%d", provider, operation, strconv.FormatFloat(budget.QPS, 'f', -1, 64), budget.Burst))
+		}
+	}
+	sort.Strings(budgets)
+	return strings.Join(budgets, ",")
+}
+
+func (f BudgetsFlag) Set(value string) error {
+	key, rawBudget, found := strings.Cut(value, "=")
+	rawQPS, rawBurst, foundBurst := strings.Cut(rawBudget, ":")
+	if !found || !foundBurst || key == "" {
+		return fmt.Errorf("invalid rate limit %q, expected <cloud-provider>[.<operation>]=<qps>:<burst>", value)
+	}
+
+	qps, err := strconv.ParseFloat(rawQPS, 64)
+	if err != nil || qps <= 0 {
+		return fmt.Errorf("invalid rate limit %q, qps must be a positive number", value)
+	}
+	burst, err := strconv.Atoi(rawBurst)
+	if err != nil || burst < 1 {
+		return fmt.Errorf("invalid rate limit %q, burst must be at least 1", value)
+	}
+
+	provider, rawOperation, _ := strings.Cut(key, ".")
+	selectedOperations := operations
+	if rawOperation != "" {
+		if !isOperation(Operation(rawOperation)) {
+			return fmt.Errorf("invalid rate limit %q, operation must be one of %v", value, operations)
+		}
+		selectedOperations = []Operation{Operation(rawOperation)}
+	}
+
+	operationBudgets, ok := f[providerconfig.CloudProvider(provider)]
+	if !ok {
+		operationBudgets = map[Operation]Budget{}
+		f[providerconfig.CloudProvider(provider)] = operationBudgets
+	}
+	for _, operation := range selectedOperations {
+		operationBudgets[operation] = Budget{QPS: qps, Burst: burst}
+	}
+	return nil
+}
+
+func isOperation(operation Operation) bool {
+	for _, o := range operations {
+		if o == operation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	"k8s.io/utils/lru"
+)
+
+// Operation is a cloud provider call that is rate limited.
+type Operation string
+
+const (
+	OperationGet     Operation = "get"
+	OperationCreate  Operation = "create"
+	OperationCleanup Operation = "cleanup"
+)
+
+// Budget is a token bucket, which refills QPS tokens per second up to Burst tokens.
+// Burst must be at least 1 to allow any call.
+type Budget struct {
+	QPS   float64
+	Burst int
+}
+
+// Budgets are the budgets per cloud provider and operation. Operations without a
+// budget are not limited.
+type Budgets map[providerconfig.CloudProvider]map[Operation]Budget
+
+const (
+	// accountTTL is the time the account of a spec is cached, so that credentials
+	// rotated in referenced Secrets are picked up eventually.
+	accountTTL = 5 * time.Minute
+	// accountCacheSize is the maximum number of cached accounts.
+	accountCacheSize = 1024
+)
+
+type accountEntry struct {
+	account string
+	expires time.Time
+}
+
+type bucketKey struct {
+	provider  providerconfig.CloudProvider
+	account   string
+	operation Operation
+}
+
+// Limiter keeps a token bucket per cloud provider, account and operation.
+type Limiter struct {
+	lock    sync.Mutex
+	budgets Budgets
+	buckets map[bucketKey]*rate.Limiter
+	// accounts caches the accounts by provider spec hash, as identifying them
+	// requires resolving the credentials of the spec.
+	accounts *lru.Cache
+	metrics  *Metrics
+	now      func() time.Time
+}
+
+// New returns a Limiter for the given budgets. metrics may be nil.
+func New(budgets Budgets, metrics *Metrics) *Limiter {
+	return &Limiter{
+		budgets:  budgets,
+		buckets:  map[bucketKey]*rate.Limiter{},
+		accounts: lru.New(accountCacheSize),
+		metrics:  metrics,
+		now:      time.Now,
+	}
+}
+
+// Limits returns whether calls of the operation of the provider are limited.
+func (l *Limiter) Limits(provider providerconfig.CloudProvider, operation Operation) bool {
+	_, ok := l.budgets[provider][operation]
+	return ok
+}
+
+// Account returns the cached account of the spec with the given hash. If none is cached,
+// it calls identify and caches its result, unless it fails.
+func (l *Limiter) Account(specHash string, identify func() (string, error)) (string, error) {
+	now := l.now()
+	if val, found := l.accounts.Get(specHash); found {
+		if cached := val.(accountEntry); now.Before(cached.expires) {
+			return cached.account, nil
+		}
+	}
+
+	account, err := identify()
+	if err != nil {
+		return "", err
+	}
+	l.accounts.Add(specHash, accountEntry{account: account, expires: now.Add(accountTTL)})
+	return account, nil
+}
+
+// Take takes a token for a call of the operation with the given account. If no token is
+// left, nothing is taken and the time until one becomes available is returned.
+func (l *Limiter) Take(provider providerconfig.CloudProvider, account string, operation Operation) time.Duration {
+	budget, ok := l.budgets[provider][operation]
+	if !ok {
+		return 0
+	}
+
+	l.lock.Lock()
+	key := bucketKey{provider: provider, account: account, operation: operation}
+	bucket, exists := l.buckets[key]
+	if !exists {
+		bucket = rate.NewLimiter(rate.Limit(budget.QPS), budget.Burst)
+		l.buckets[key] = bucket
+	}
+	l.lock.Unlock()
+
+	now := l.now()
+	reservation := bucket.ReserveN(now, 1)
+	if !reservation.OK() {
+		// The budget does not allow a single call, as its burst is 0.
+		return time.Duration(math.MaxInt64)
+	}
+
+	delay := reservation.DelayFrom(now)
+	if delay <= 0 {
+		return 0
+	}
+	reservation.CancelAt(now)
+
+	if l.metrics != nil {
+		l.metrics.Throttled.WithLabelValues(string(provider), string(operation)).Inc()
+		l.metrics.Delay.WithLabelValues(string(provider), string(operation)).Observe(delay.Seconds())
+	}
+
+	return delay
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+)
+
+func TestLimiterTake(t *testing.T) {
+	limiter := New(Budgets{
+		providerconfig.CloudProviderHetzner: {
+			OperationCreate: {QPS: 1, Burst: 2},
+		},
+	}, NewMetrics())
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if delay := limiter.Take(providerconfig.CloudProviderHetzner, "project-a", OperationCreate); delay != 0 {
+			t.Fatalf("expected call %d within the burst to not be delayed, got %v", i+1, delay)
+		}
+	}
+
+	if delay := limiter.Take(providerconfig.CloudProviderHetzner, "project-a", OperationCreate); delay <= 0 || delay > time.Second {
+		t.Errorf("expected call exceeding the burst to be delayed by up to 1s, got %v", delay)
+	}
+	if delay := limiter.Take(providerconfig.CloudProviderHetzner, "project-b", OperationCreate); delay != 0 {
+		t.Errorf("expected other account to have its own budget, got delay %v", delay)
+	}
+	if delay := limiter.Take(providerconfig.CloudProviderHetzner, "project-a", OperationGet); delay != 0 {
+		t.Errorf("expected operation without budget to not be limited, got delay %v", delay)
+	}
+	if delay := limiter.Take(providerconfig.CloudProviderAWS, "project-a", OperationCreate); delay != 0 {
+		t.Errorf("expected provider without budget to not be limited, got delay %v", delay)
+	}
+
+	// A throttled call does not take a token, so the next one is allowed once
+	// the bucket refilled.
+	now = now.Add(time.Second)
+	if delay := limiter.Take(providerconfig.CloudProviderHetzner, "project-a", OperationCreate); delay != 0 {
+		t.Errorf("expected call to be allowed after the bucket refilled, got delay %v", delay)
+	}
+}
+
+func TestLimiterAccount(t *testing.T) {
+	limiter := New(Budgets{}, nil)
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	calls := 0
+	identify := func() (string, error) {
+		calls++
+		return "project-a", nil
+	}
+
+	for i := 0; i < 2; i++ {
+		account, err := limiter.Account("spec-a", identify)
+		if err != nil {
+			t.Fatalf("failed to get account: %v", err)
+		}
+		if account != "project-a" {
+			t.Errorf("expected account %q, got %q", "project-a", account)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the account to be identified once, got %d calls", calls)
+	}
+
+	if _, err := limiter.Account("spec-b", func() (string, error) { return "", errors.New("invalid spec") }); err == nil {
+		t.Error("expected error of failed identification to be returned")
+	}
+	if _, err := limiter.Account("spec-b", identify); err != nil {
+		t.Fatalf("failed to get account: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected failed identification to not be cached, got %d calls", calls)
+	}
+
+	now = now.Add(accountTTL)
+	if _, err := limiter.Account("spec-a", identify); err != nil {
+		t.Fatalf("failed to get account: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected expired account to be identified again, got %d calls", calls)
+	}
+}
+
+func TestBudgetsFlag(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []string
+		expected  Budgets
+		expectErr bool
+	}{
+		{
+			name:   "all operations",
+			values: []string{"hetzner=2:5"},
+			expected: Budgets{
+				providerconfig.CloudProviderHetzner: {
+					OperationGet:     {QPS: 2, Burst: 5},
+					OperationCreate:  {QPS: 2, Burst: 5},
+					OperationCleanup: {QPS: 2, Burst: 5},
+				},
+			},
+		},
+		{
+			name:   "single operation overrides all operations",
+			values: []string{"aws=10:20", "aws.create=0.5:1"},
+			expected: Budgets{
+				providerconfig.CloudProviderAWS: {
+					OperationGet:     {QPS: 10, Burst: 20},
+					OperationCreate:  {QPS: 0.5, Burst: 1},
+					OperationCleanup: {QPS: 10, Burst: 20},
+				},
+			},
+		},
+		{
+			name:      "unknown operation",
+			values:    []string{"aws.list=1:1"},
+			expectErr: true,
+		},
+		{
+			name:      "missing burst",
+			values:    []string{"aws=1"},
+			expectErr: true,
+		},
+		{
+			name:      "zero burst",
+			values:    []string{"aws=1:0"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := BudgetsFlag{}
+			var err error
+			for _, value := range test.values {
+				if err = f.Set(value); err != nil {
+					break
+				}
+			}
+			if (err != nil) != test.expectErr {
+				t.Fatalf("expected error: %t, got %v", test.expectErr, err)
+			}
+			if test.expectErr {
+				return
+			}
+
+			for provider, operationBudgets := range test.expected {
+				for operation, budget := range operationBudgets {
+					if f[provider][operation] != budget {
+						t.Errorf("expected budget %+v for %s.%s, got %+v", budget, provider, operation, f[provider][operation])
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsPrefix = "machine_controller_cloud_provider_"
+
+// Metrics is a struct of all metrics used by the Limiter.
+type Metrics struct {
+	Throttled *prometheus.CounterVec
+	Delay     *prometheus.HistogramVec
+}
+
+// NewMetrics creates new Metrics for the Limiter.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		Throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metricsPrefix + "throttled_total",
+			Help: "The total number of cloud provider calls that were requeued because of rate limiting",
+		}, []string{"provider", "operation"}),
+		Delay: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    metricsPrefix + "rate_limit_delay_seconds",
+			Help:    "The time throttled cloud provider calls were requeued for",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 10),
+		}, []string{"provider", "operation"}),
+	}
+}
+
+// MustRegister registers all metrics with the given registerer.
+func (m *Metrics) MustRegister(registerer prometheus.Registerer) {
+	registerer.MustRegister(
+		m.Throttled,
+		m.Delay,
+	)
+}
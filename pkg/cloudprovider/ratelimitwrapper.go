@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudprovider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	"k8c.io/machine-controller/pkg/cloudprovider/ratelimit"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type rateLimitingWrapper struct {
+	OptionalInterfacesForwarder
+	providerName providerconfig.CloudProvider
+	limiter      *ratelimit.Limiter
+}
+
+// NewRateLimitingCloudProvider returns a wrapped cloudprovider, which returns a
+// cloudprovidererrors.RateLimitedError instead of calling Get, Create or Cleanup of the
+// actual provider once the budget of the machine's account is used up.
+func NewRateLimitingCloudProvider(actualProvider cloudprovidertypes.Provider, providerName providerconfig.CloudProvider, limiter *ratelimit.Limiter) cloudprovidertypes.Provider {
+	return &rateLimitingWrapper{OptionalInterfacesForwarder: NewOptionalInterfacesForwarder(actualProvider), providerName: providerName, limiter: limiter}
+}
+
+// take takes a token for the operation from the bucket of the machine's account.
+func (w *rateLimitingWrapper) take(log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, operation ratelimit.Operation) error {
+	if !w.limiter.Limits(w.providerName, operation) {
+		return nil
+	}
+
+	// Providers which cannot tell the account share one budget for all of them.
+	var account string
+	if identifier, ok := w.actualProvider.(cloudprovidertypes.AccountIdentifier); ok {
+		var err error
+		if account, err = w.accountID(identifier, machine.Spec); err != nil {
+			log.Debugw("Failed to identify account for rate limiting", zap.Error(err))
+		}
+	}
+
+	if delay := w.limiter.Take(w.providerName, account, operation); delay > 0 {
+		return cloudprovidererrors.RateLimitedError{Operation: string(operation), RetryAfter: delay}
+	}
+	return nil
+}
+
+// accountID returns the account of the spec, which the limiter caches per provider spec,
+// as identifying it requires resolving the credentials of the spec.
+func (w *rateLimitingWrapper) accountID(identifier cloudprovidertypes.AccountIdentifier, spec clusterv1alpha1.MachineSpec) (string, error) {
+	b, err := json.Marshal(spec.ProviderSpec)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal provider spec: %w", err)
+	}
+	sum := sha256.Sum256(b)
+
+	return w.limiter.Account(string(w.providerName)+"/"+hex.EncodeToString(sum[:]), func() (string, error) {
+		return identifier.AccountID(spec)
+	})
+}
+
+// AddDefaults just calls the underlying cloudproviders AddDefaults.
+func (w *rateLimitingWrapper) AddDefaults(log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (clusterv1alpha1.MachineSpec, error) {
+	return w.actualProvider.AddDefaults(log, spec)
+}
+
+// Validate just calls the underlying cloudproviders Validate.
+func (w *rateLimitingWrapper) Validate(ctx context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) error {
+	return w.actualProvider.Validate(ctx, log, spec)
+}
+
+// Get calls the underlying cloudproviders Get, if the get budget allows it.
+func (w *rateLimitingWrapper) Get(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData) (instance.Instance, error) {
+	if err := w.take(log, machine, ratelimit.OperationGet); err != nil {
+		return nil, err
+	}
+	return w.actualProvider.Get(ctx, log, machine, data)
+}
+
+// Create calls the underlying cloudproviders Create, if the create budget allows it.
+func (w *rateLimitingWrapper) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
+	if err := w.take(log, machine, ratelimit.OperationCreate); err != nil {
+		return nil, err
+	}
+	return w.actualProvider.Create(ctx, log, machine, data, userdata)
+}
+
+// Cleanup calls the underlying cloudproviders Cleanup, if the cleanup budget allows it.
+func (w *rateLimitingWrapper) Cleanup(ctx context.Context, log *zap.SugaredLogger, m *clusterv1alpha1.Machine, mcd *cloudprovidertypes.ProviderData) (bool, error) {
+	if err := w.take(log, m, ratelimit.OperationCleanup); err != nil {
+		return false, err
+	}
+	return w.actualProvider.Cleanup(ctx, log, m, mcd)
+}
+
+// MigrateUID just calls the underlying cloudproviders MigrateUID.
+func (w *rateLimitingWrapper) MigrateUID(ctx context.Context, log *zap.SugaredLogger, m *clusterv1alpha1.Machine, newUID types.UID) error {
+	return w.actualProvider.MigrateUID(ctx, log, m, newUID)
+}
+
+// MachineMetricsLabels just calls the underlying cloudproviders MachineMetricsLabels.
+func (w *rateLimitingWrapper) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (map[string]string, error) {
+	return w.actualProvider.MachineMetricsLabels(machine)
+}
+
+func (w *rateLimitingWrapper) SetMetricsForMachines(machines clusterv1alpha1.MachineList) error {
+	return w.actualProvider.SetMetricsForMachines(machines)
+}
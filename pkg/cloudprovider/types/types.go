@@ -76,6 +76,115 @@ type Provider interface {
 	SetMetricsForMachines(machines clusterv1alpha1.MachineList) error
 }
 
+// FallbackCandidate is an alternative machine spec that is used if the cloud
+// provider reports insufficient capacity for a machine.
+type FallbackCandidate struct {
+	// Name describes the candidate, e.g. the instance type and zone it uses.
+	Name string
+	// Spec is the machine spec with the candidate's fields applied.
+	Spec clusterv1alpha1.MachineSpec
+}
+
+// FallbackProvider is implemented by providers that allow configuring fallback
+// instance types or zones in the provider spec.
+type FallbackProvider interface {
+	// FallbackCandidates returns the configured fallback candidates in the order
+	// they should be tried. It must not do any api calls to the cloud provider.
+	FallbackCandidates(spec clusterv1alpha1.MachineSpec) ([]FallbackCandidate, error)
+}
+
+// TaggedInstance is a cloud instance along with the UID of the machine it was created for.
+type TaggedInstance struct {
+	instance.Instance
+	MachineUID types.UID
+}
+
+// InstanceLister is implemented by providers that tag instances with the UID of their machine
+// and the ProviderData.ClusterID at create time, so that instances whose machine no longer
+// exists can be found.
+type InstanceLister interface {
+	// ListInstances returns all instances tagged with a machine UID and the given cluster ID
+	// that are visible with the credentials and location of the given spec. Instances without
+	// the cluster ID tag must not be returned, as they might belong to another cluster sharing
+	// the same account. The cluster ID is never empty.
+	ListInstances(ctx context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec, clusterID string) ([]TaggedInstance, error)
+}
+
+// AccountIdentifier is implemented by providers that can tell which account the credentials
+// of a spec belong to, so that cloud provider calls can be rate limited per account.
+type AccountIdentifier interface {
+	// AccountID returns an identifier of the account used by the given spec. It must not
+	// contain any secret.
+	AccountID(spec clusterv1alpha1.MachineSpec) (string, error)
+}
+
+// MachineAttributes are the attributes of the instance of a machine spec that can be
+// restricted by a MachinePolicy. Empty fields are chosen by the cloud provider at runtime.
+type MachineAttributes struct {
+	// InstanceType is the instance type, flavor or size of the instance.
+	InstanceType string
+	// Image is the image, AMI or template of the instance.
+	Image string
+	// Region is the region, location or datacenter of the instance.
+	Region string
+	// Zones are the availability zones the instance may be placed in.
+	Zones []string
+	// Tags are the tags or labels of the instance.
+	Tags map[string]string
+}
+
+// AttributesProvider is implemented by providers that can report the attributes of the
+// instance of a spec, so that they can be restricted by MachinePolicies.
+type AttributesProvider interface {
+	// MachineAttributes returns the attributes of the instance of the given spec. It must
+	// not do any api calls to the cloud provider.
+	MachineAttributes(spec clusterv1alpha1.MachineSpec) (MachineAttributes, error)
+}
+
+// PlannedDisk is a disk the instance of a machine spec would be created with.
+type PlannedDisk struct {
+	// Name is the name or device path of the disk.
+	Name string `json:"name"`
+	// Type is the volume type or storage policy of the disk.
+	Type string `json:"type,omitempty"`
+	// SizeGB is the size of the disk in GB. Zero means the size of the image.
+	SizeGB int64 `json:"sizeGB,omitempty"`
+}
+
+// Plan describes the instance that would be created for a machine spec.
+type Plan struct {
+	// Image is the resolved image, AMI or template of the instance.
+	Image string `json:"image,omitempty"`
+	// InstanceType is the instance type, flavor or size of the instance.
+	InstanceType string `json:"instanceType,omitempty"`
+	// Region is the region, location or datacenter of the instance.
+	Region string `json:"region,omitempty"`
+	// Zone is the availability zone of the instance. It is empty if the cloud provider
+	// chooses it at runtime.
+	Zone string `json:"zone,omitempty"`
+	// Network is the network or VPC the instance is attached to.
+	Network string `json:"network,omitempty"`
+	// Subnet is the subnet the instance is attached to.
+	Subnet string `json:"subnet,omitempty"`
+	// SecurityGroups are the security groups or firewalls of the instance.
+	SecurityGroups []string `json:"securityGroups,omitempty"`
+	// Disks are the disks of the instance, starting with the root disk.
+	Disks []PlannedDisk `json:"disks,omitempty"`
+	// Tags are the tags or labels of the instance.
+	Tags map[string]string `json:"tags,omitempty"`
+	// Details are further provider specific settings of the instance.
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// Planner is implemented by providers that can tell which instance they would create for a
+// spec, without creating it.
+type Planner interface {
+	// Plan returns the plan for the instance of the given defaulted and validated spec. It may
+	// do read-only api calls to the cloud provider to resolve defaults like the image, but
+	// must not create or modify any resource.
+	Plan(ctx context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) (*Plan, error)
+}
+
 // MachineModifier defines a function to modify a machine.
 type MachineModifier func(*clusterv1alpha1.Machine)
 
@@ -87,6 +196,9 @@ type ProviderData struct {
 	Ctx    context.Context
 	Update MachineUpdater
 	Client ctrlruntimeclient.Client
+	// ClusterID identifies the cluster the machines belong to. Providers implementing
+	// InstanceLister tag the instances they create with it, unless it is empty.
+	ClusterID string
 }
 
 // GetMachineUpdater returns an MachineUpdater based on the passed in context and ctrlruntimeclient.Client.
@@ -69,6 +69,7 @@ func CreateMachineCloudInitSecret(ctx context.Context, userdata, machineName str
 			if err := client.Create(ctx, secret); err != nil {
 				return fmt.Errorf("failed to create secret for userdata: %w", err)
 			}
+			return nil
 		}
 
 		return fmt.Errorf("failed to fetch cloud-init secret: %w", err)
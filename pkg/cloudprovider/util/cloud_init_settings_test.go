@@ -23,6 +23,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -78,3 +79,22 @@ func TestCloudInitGeneration(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateMachineCloudInitSecret(t *testing.T) {
+	fakeClient := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+	// Creating the secret a second time must be a no-op.
+	for range 2 {
+		if err := CreateMachineCloudInitSecret(context.Background(), "#cloud-config", "machine-a", fakeClient); err != nil {
+			t.Fatalf("failed to create cloud-init secret: %v", err)
+		}
+	}
+
+	secret := &corev1.Secret{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: CloudInitNamespace, Name: "machine-a"}, secret); err != nil {
+		t.Fatalf("failed to get cloud-init secret: %v", err)
+	}
+	if userdata := string(secret.Data["cloud_init"]); userdata != "#cloud-config" {
+		t.Errorf("unexpected userdata in cloud-init secret: %q", userdata)
+	}
+}
@@ -28,6 +28,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"k8c.io/machine-controller/pkg/tracing"
 
 	"k8s.io/klog"
 )
@@ -63,6 +68,10 @@ type HTTPClientConfig struct {
 	LogPrefix string
 	// Global timeout used by the client
 	Timeout time.Duration
+	// Sensitive prevents dumps of headers and bodies at any log level, for endpoints
+	// whose requests or responses carry credentials. Only request lines and response
+	// statuses are logged.
+	Sensitive bool
 }
 
 // New return a custom HTTP client that allows for logging
@@ -77,6 +86,7 @@ func (c HTTPClientConfig) New() http.Client {
 	return http.Client{
 		Transport: &LogRoundTripper{
 			logPrefix: c.LogPrefix,
+			sensitive: c.Sensitive,
 			rt: &http.Transport{
 				TLSClientConfig: &tls.Config{
 					RootCAs: CABundle,
@@ -88,11 +98,13 @@ func (c HTTPClientConfig) New() http.Client {
 }
 
 // LogRoundTripper is used to log information about requests and responses that
-// may be useful for debugging purposes.
+// may be useful for debugging purposes. Every request is traced as client span.
 // Note that setting log level >5 results in full dumps of requests and
-// responses, including sensitive invormation (e.g. Authorization header).
+// responses, including sensitive invormation (e.g. Authorization header), unless
+// the client is configured as sensitive.
 type LogRoundTripper struct {
 	logPrefix string
+	sensitive bool
 	rt        http.RoundTripper
 }
 
@@ -102,6 +114,9 @@ func (lrt *LogRoundTripper) RoundTrip(request *http.Request) (*http.Response, er
 	// Generate unique ID to correlate requests and responses
 	id := uuid.New()
 	switch {
+	case lrt.sensitive:
+		// The query is left out, as it might carry credentials as well.
+		log = requestLine(request, request.URL.EscapedPath())
 	case bool(klog.V(6)):
 		log, err = httputil.DumpRequest(request, true)
 		if err != nil {
@@ -113,19 +128,34 @@ func (lrt *LogRoundTripper) RoundTrip(request *http.Request) (*http.Response, er
 			klog.Warningf("Error occurred while dumping request: %v", err)
 		}
 	default:
-		var b bytes.Buffer
-		fmt.Fprintf(&b, "%s %s HTTP/%d.%d", valueOrDefault(request.Method, "GET"),
-			request.URL.RequestURI(), request.ProtoMajor, request.ProtoMinor)
-		log = b.Bytes()
+		log = requestLine(request, request.URL.RequestURI())
 	}
 	klog.V(1).Infof("%s request sent [%s]: %s\n", lrt.logPrefix, id.String(), string(log))
 
-	response, err := lrt.rt.RoundTrip(request)
+	// The URL is not recorded, as its query might contain credentials.
+	method := valueOrDefault(request.Method, "GET")
+	ctx, span := tracing.Start(request.Context(), method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.ServerAddress(request.URL.Hostname()),
+			semconv.URLPath(request.URL.Path),
+		),
+	)
+	response, err := lrt.rt.RoundTrip(request.WithContext(ctx))
 	if response == nil {
+		tracing.End(span, err)
 		return nil, err
 	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(response.StatusCode))
+	if response.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, response.Status)
+	}
+	span.End()
 
 	switch {
+	case lrt.sensitive:
+		log = statusLine(response)
 	case bool(klog.V(6)):
 		log, err = httputil.DumpResponse(response, true)
 		if err != nil {
@@ -137,15 +167,28 @@ func (lrt *LogRoundTripper) RoundTrip(request *http.Request) (*http.Response, er
 			klog.Warningf("Error occurred while dumping response: %v", err)
 		}
 	default:
-		var b bytes.Buffer
-		fmt.Fprintf(&b, "HTTP/%d.%d %03d", response.ProtoMajor, response.ProtoMinor, response.StatusCode)
-		log = b.Bytes()
+		log = statusLine(response)
 	}
 	klog.V(1).Infof("%s request received [%s]: %s\n", lrt.logPrefix, id.String(), string(log))
 
 	return response, nil
 }
 
+// requestLine returns the method, the given URI and the protocol of the request.
+func requestLine(request *http.Request, uri string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s %s HTTP/%d.%d", valueOrDefault(request.Method, "GET"),
+		uri, request.ProtoMajor, request.ProtoMinor)
+	return b.Bytes()
+}
+
+// statusLine returns the protocol and status code of the response.
+func statusLine(response *http.Response) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "HTTP/%d.%d %03d", response.ProtoMajor, response.ProtoMinor, response.StatusCode)
+	return b.Bytes()
+}
+
 // Return value if nonempty, def otherwise.
 func valueOrDefault(value, def string) string {
 	if value != "" {
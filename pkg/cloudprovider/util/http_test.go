@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"k8s.io/klog"
+)
+
+func TestSensitiveClientDoesNotDumpBodies(t *testing.T) {
+	flags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(flags)
+	for name, value := range map[string]string{"v": "6", "logtostderr": "false", "alsologtostderr": "false"} {
+		if err := flags.Set(name, value); err != nil {
+			t.Fatalf("failed to set klog flag %s: %v", name, err)
+		}
+	}
+	var logs bytes.Buffer
+	klog.SetOutput(&logs)
+	t.Cleanup(func() {
+		_ = flags.Set("v", "0")
+		_ = flags.Set("logtostderr", "true")
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"cloud-credential"}`))
+	}))
+	defer server.Close()
+
+	for _, sensitive := range []bool{false, true} {
+		logs.Reset()
+
+		client := HTTPClientConfig{LogPrefix: "[Test API]", Sensitive: sensitive}.New()
+		resp, err := client.PostForm(server.URL+"/token?secret=query-credential", url.Values{"token": {"service-account-token"}})
+		if err != nil {
+			t.Fatalf("failed to post form: %v", err)
+		}
+		resp.Body.Close()
+		klog.Flush()
+
+		for _, secret := range []string{"service-account-token", "cloud-credential", "query-credential"} {
+			if logged := strings.Contains(logs.String(), secret); logged == sensitive {
+				t.Errorf("expected %q to be logged: %t, got logs %q", secret, !sensitive, logs.String())
+			}
+		}
+	}
+}
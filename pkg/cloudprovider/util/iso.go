@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"text/template"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+)
+
+const metaDataTemplate = `instance-id: {{ .InstanceID}}
+local-hostname: {{ .Hostname }}`
+
+// GenerateUserdataISO writes an ISO with the volume label cidata into the directory, which is
+// read by the NoCloud datasource of cloud-init. It contains the userdata and metadata with the
+// name as instance ID and hostname. The path of the ISO is returned.
+func GenerateUserdataISO(ctx context.Context, dir, userdata, name string) (string, error) {
+	// We must create a directory, because the iso-generation commands
+	// take a directory as input
+	userdataDir, err := os.MkdirTemp(dir, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to create local temp directory for userdata at %s: %w", userdataDir, err)
+	}
+	defer func() {
+		if err := os.RemoveAll(userdataDir); err != nil {
+			utilruntime.HandleError(fmt.Errorf("error cleaning up local userdata tempdir %s: %w", userdataDir, err))
+		}
+	}()
+
+	userdataFilePath := fmt.Sprintf("%s/user-data", userdataDir)
+	metadataFilePath := fmt.Sprintf("%s/meta-data", userdataDir)
+	isoFilePath := fmt.Sprintf("%s/%s.iso", dir, name)
+
+	metadataTmpl, err := template.New("metadata").Parse(metaDataTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse metadata template: %w", err)
+	}
+	metadata := &bytes.Buffer{}
+	templateContext := struct {
+		InstanceID string
+		Hostname   string
+	}{
+		InstanceID: name,
+		Hostname:   name,
+	}
+	if err = metadataTmpl.Execute(metadata, templateContext); err != nil {
+		return "", fmt.Errorf("failed to render metadata: %w", err)
+	}
+
+	if err := os.WriteFile(userdataFilePath, []byte(userdata), 0644); err != nil {
+		return "", fmt.Errorf("failed to locally write userdata file to %s: %w", userdataFilePath, err)
+	}
+
+	if err := os.WriteFile(metadataFilePath, metadata.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("failed to locally write metadata file to %s: %w", userdataFilePath, err)
+	}
+
+	var command string
+	var args []string
+
+	if _, err := exec.LookPath("genisoimage"); err == nil {
+		command = "genisoimage"
+		args = []string{"-o", isoFilePath, "-volid", "cidata", "-joliet", "-rock", userdataDir}
+	} else if _, err := exec.LookPath("mkisofs"); err == nil {
+		command = "mkisofs"
+		args = []string{"-o", isoFilePath, "-V", "cidata", "-J", "-R", userdataDir}
+	} else {
+		return "", errors.New("system is missing genisoimage or mkisofs, can't generate userdata iso without it")
+	}
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("error executing command `%s %s`: output: `%s`, error: `%w`", command, args, string(output), err)
+	}
+
+	return isoFilePath, nil
+}
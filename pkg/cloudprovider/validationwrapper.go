@@ -23,6 +23,7 @@ import (
 
 	"go.uber.org/zap"
 
+	cloudprovidercache "k8c.io/machine-controller/pkg/cloudprovider/cache"
 	"k8c.io/machine-controller/pkg/cloudprovider/instance"
 	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
@@ -31,12 +32,14 @@ import (
 )
 
 type cachingValidationWrapper struct {
-	actualProvider cloudprovidertypes.Provider
+	OptionalInterfacesForwarder
+	cache *cloudprovidercache.CloudproviderCache
 }
 
-// NewValidationCacheWrappingCloudProvider returns a wrapped cloudprovider.
-func NewValidationCacheWrappingCloudProvider(actualProvider cloudprovidertypes.Provider) cloudprovidertypes.Provider {
-	return &cachingValidationWrapper{actualProvider: actualProvider}
+// NewValidationCacheWrappingCloudProvider returns a wrapped cloudprovider, which caches
+// validation results in the given cache. If cache is nil, nothing is cached.
+func NewValidationCacheWrappingCloudProvider(actualProvider cloudprovidertypes.Provider, cache *cloudprovidercache.CloudproviderCache) cloudprovidertypes.Provider {
+	return &cachingValidationWrapper{OptionalInterfacesForwarder: NewOptionalInterfacesForwarder(actualProvider), cache: cache}
 }
 
 // AddDefaults just calls the underlying cloudproviders AddDefaults.
@@ -47,7 +50,11 @@ func (w *cachingValidationWrapper) AddDefaults(log *zap.SugaredLogger, spec clus
 // Validate tries to get the validation result from the cache and if not found, calls the
 // cloudproviders Validate and saves that to the cache.
 func (w *cachingValidationWrapper) Validate(ctx context.Context, log *zap.SugaredLogger, spec clusterv1alpha1.MachineSpec) error {
-	result, exists, err := cache.Get(spec)
+	if w.cache == nil {
+		return w.actualProvider.Validate(ctx, log, spec)
+	}
+
+	result, exists, err := w.cache.Get(ctx, spec)
 	if err != nil {
 		return fmt.Errorf("error getting validation result from cache: %w", err)
 	}
@@ -62,7 +69,7 @@ func (w *cachingValidationWrapper) Validate(ctx context.Context, log *zap.Sugare
 	// do not cache canceled contexts (e.g. the validation request was canceled client-side)
 	// and timeouts (assumed to be temporary)
 	if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
-		if err := cache.Set(spec, err); err != nil {
+		if err := w.cache.Set(ctx, spec, err); err != nil {
 			return fmt.Errorf("failed to set cache after validation: %w", err)
 		}
 	}
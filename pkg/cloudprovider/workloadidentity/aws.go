@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadidentity
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultAWSSessionName = "machine-controller"
+
+// AWSExchanger exchanges the service account token for the credentials of an IAM role
+// using STS AssumeRoleWithWebIdentity.
+type AWSExchanger struct {
+	RoleARN string
+	// SessionName defaults to "machine-controller".
+	SessionName string
+	// Region selects the regional STS endpoint, if empty the global endpoint is used.
+	Region string
+	// Endpoint overrides the STS endpoint.
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+var _ Exchanger = &AWSExchanger{}
+
+type assumeRoleWithWebIdentityResponse struct {
+	Result struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"Credentials"`
+	} `xml:"AssumeRoleWithWebIdentityResult"`
+}
+
+func (e *AWSExchanger) Key() string {
+	return "aws/" + e.RoleARN
+}
+
+func (e *AWSExchanger) Exchange(ctx context.Context, token string) (*Credentials, error) {
+	sessionName := e.SessionName
+	if sessionName == "" {
+		sessionName = defaultAWSSessionName
+	}
+
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {e.RoleARN},
+		"RoleSessionName":  {sessionName},
+		"WebIdentityToken": {token},
+		"DurationSeconds":  {"3600"},
+	}
+	body, err := postForm(ctx, defaultHTTPClient(e.HTTPClient), e.endpoint(), form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume role %q: %w", e.RoleARN, err)
+	}
+
+	resp := &assumeRoleWithWebIdentityResponse{}
+	if err := xml.Unmarshal(body, resp); err != nil {
+		return nil, fmt.Errorf("failed to parse STS response: %w", err)
+	}
+	creds := resp.Result.Credentials
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("STS returned no credentials for role %q", e.RoleARN)
+	}
+
+	return &Credentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Expiry:          creds.Expiration,
+	}, nil
+}
+
+func (e *AWSExchanger) endpoint() string {
+	switch {
+	case e.Endpoint != "":
+		return e.Endpoint
+	case e.Region == "":
+		return "https://sts.amazonaws.com/"
+	case strings.HasPrefix(e.Region, "cn-"):
+		return fmt.Sprintf("https://sts.%s.amazonaws.com.cn/", e.Region)
+	default:
+		return fmt.Sprintf("https://sts.%s.amazonaws.com/", e.Region)
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadidentity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAzureAuthorityHost = "https://login.microsoftonline.com"
+	defaultAzureScope         = "https://management.azure.com/.default"
+)
+
+// AzureExchanger exchanges the service account token for an access token of an app
+// registration or managed identity with a federated credential.
+type AzureExchanger struct {
+	TenantID string
+	ClientID string
+	// AuthorityHost defaults to the Azure public cloud.
+	AuthorityHost string
+	// Scope defaults to the Azure Resource Manager.
+	Scope      string
+	HTTPClient *http.Client
+}
+
+var _ Exchanger = &AzureExchanger{}
+
+type azureTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func (e *AzureExchanger) Key() string {
+	return fmt.Sprintf("azure/%s/%s/%s", e.TenantID, e.ClientID, e.scope())
+}
+
+func (e *AzureExchanger) Exchange(ctx context.Context, token string) (*Credentials, error) {
+	authorityHost := e.AuthorityHost
+	if authorityHost == "" {
+		authorityHost = defaultAzureAuthorityHost
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {e.ClientID},
+		"scope":                 {e.scope()},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {token},
+	}
+	endpoint := fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authorityHost, "/"), url.PathEscape(e.TenantID))
+	body, err := postForm(ctx, defaultHTTPClient(e.HTTPClient), endpoint, form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token for client %q: %w", e.ClientID, err)
+	}
+
+	resp := &azureTokenResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return nil, fmt.Errorf("no access token returned for client %q", e.ClientID)
+	}
+
+	return &Credentials{
+		AccessToken: resp.AccessToken,
+		Expiry:      time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func (e *AzureExchanger) scope() string {
+	if e.Scope == "" {
+		return defaultAzureScope
+	}
+	return e.Scope
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadidentity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGCPSTSEndpoint = "https://sts.googleapis.com/v1/token"
+	defaultGCPIAMEndpoint = "https://iamcredentials.googleapis.com"
+	gcpCloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// GCPExchanger exchanges the service account token for an access token using a workload
+// identity pool, optionally impersonating a GCP service account.
+type GCPExchanger struct {
+	// Audience is the full resource name of the workload identity pool provider, e.g.
+	// //iam.googleapis.com/projects/<number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>.
+	Audience string
+	// ServiceAccountEmail is impersonated with the federated token, if set.
+	ServiceAccountEmail string
+	// STSEndpoint and IAMEndpoint override the Google API endpoints.
+	STSEndpoint string
+	IAMEndpoint string
+	HTTPClient  *http.Client
+}
+
+var _ Exchanger = &GCPExchanger{}
+
+type gcpSTSResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type gcpGenerateAccessTokenResponse struct {
+	AccessToken string    `json:"accessToken"`
+	ExpireTime  time.Time `json:"expireTime"`
+}
+
+func (e *GCPExchanger) Key() string {
+	return fmt.Sprintf("gcp/%s/%s", e.Audience, e.ServiceAccountEmail)
+}
+
+func (e *GCPExchanger) Exchange(ctx context.Context, token string) (*Credentials, error) {
+	client := defaultHTTPClient(e.HTTPClient)
+
+	stsEndpoint := e.STSEndpoint
+	if stsEndpoint == "" {
+		stsEndpoint = defaultGCPSTSEndpoint
+	}
+	form := url.Values{
+		"grant_type":           {"urn:ietf:params:oauth:grant-type:token-exchange"},
+		"audience":             {e.Audience},
+		"scope":                {gcpCloudPlatformScope},
+		"requested_token_type": {"urn:ietf:params:oauth:token-type:access_token"},
+		"subject_token_type":   {"urn:ietf:params:oauth:token-type:jwt"},
+		"subject_token":        {token},
+	}
+	body, err := postForm(ctx, client, stsEndpoint, form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange token for audience %q: %w", e.Audience, err)
+	}
+
+	stsResp := &gcpSTSResponse{}
+	if err := json.Unmarshal(body, stsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse STS response: %w", err)
+	}
+	if stsResp.AccessToken == "" {
+		return nil, fmt.Errorf("no access token returned for audience %q", e.Audience)
+	}
+
+	creds := &Credentials{
+		AccessToken: stsResp.AccessToken,
+		Expiry:      time.Now().Add(time.Duration(stsResp.ExpiresIn) * time.Second),
+	}
+	if e.ServiceAccountEmail == "" {
+		return creds, nil
+	}
+
+	return e.impersonate(ctx, client, creds.AccessToken)
+}
+
+// impersonate exchanges the federated token for an access token of the service account.
+func (e *GCPExchanger) impersonate(ctx context.Context, client *http.Client, federatedToken string) (*Credentials, error) {
+	iamEndpoint := e.IAMEndpoint
+	if iamEndpoint == "" {
+		iamEndpoint = defaultGCPIAMEndpoint
+	}
+
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"scope":    []string{gcpCloudPlatformScope},
+		"lifetime": "3600s",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/-/serviceAccounts/%s:generateAccessToken", strings.TrimSuffix(iamEndpoint, "/"), url.PathEscape(e.ServiceAccountEmail))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+federatedToken)
+
+	body, err := do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate service account %q: %w", e.ServiceAccountEmail, err)
+	}
+
+	resp := &gcpGenerateAccessTokenResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, fmt.Errorf("failed to parse IAM response: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return nil, fmt.Errorf("no access token returned for service account %q", e.ServiceAccountEmail)
+	}
+
+	return &Credentials{
+		AccessToken:         resp.AccessToken,
+		ServiceAccountEmail: e.ServiceAccountEmail,
+		Expiry:              resp.ExpireTime,
+	}, nil
+}
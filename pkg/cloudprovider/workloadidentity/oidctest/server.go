@@ -0,0 +1,321 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oidctest provides a local OIDC issuer together with stubs of the AWS, Azure and
+// GCP token services, which accept the tokens of the issuer. It is used to test workload
+// identity federation without a cloud.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AWSAudience is the audience STS expects in web identity tokens.
+	AWSAudience = "sts.amazonaws.com"
+	// AzureAudience is the audience Entra ID expects in federated tokens.
+	AzureAudience = "api://AzureADTokenExchange"
+
+	keyID = "oidctest"
+)
+
+// GCPAudience returns the audience GCP expects in tokens exchanged with the workload identity
+// pool provider.
+func GCPAudience(poolProvider string) string {
+	return "https:" + poolProvider
+}
+
+type claims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	Audience  string `json:"aud"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type Server struct {
+	*httptest.Server
+
+	key *rsa.PrivateKey
+
+	lock            sync.Mutex
+	subjects        map[string]bool
+	federatedTokens map[string]bool
+	exchanges       int
+}
+
+// NewServer starts a new server, which has to be closed by the caller.
+func NewServer() (*Server, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	s := &Server{
+		key:             key,
+		subjects:        map[string]bool{},
+		federatedTokens: map[string]bool{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/keys", s.keys)
+	mux.HandleFunc("/aws/", s.aws)
+	mux.HandleFunc("/azure/", s.azure)
+	mux.HandleFunc("/gcp/sts/v1/token", s.gcpSTS)
+	mux.HandleFunc("/gcp/iam/", s.gcpIAM)
+	s.Server = httptest.NewServer(mux)
+
+	return s, nil
+}
+
+func (s *Server) AWSEndpoint() string        { return s.URL + "/aws/" }
+func (s *Server) AzureAuthorityHost() string { return s.URL + "/azure" }
+func (s *Server) GCPSTSEndpoint() string     { return s.URL + "/gcp/sts/v1/token" }
+func (s *Server) GCPIAMEndpoint() string     { return s.URL + "/gcp/iam" }
+
+// Trust makes the token services accept tokens of the subject, e.g.
+// "system:serviceaccount:kube-system:machine-controller".
+func (s *Server) Trust(subject string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.subjects[subject] = true
+}
+
+// Exchanges returns the number of successful token exchanges.
+func (s *Server) Exchanges() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.exchanges
+}
+
+// Issue returns a signed token of the issuer.
+func (s *Server) Issue(subject, audience string, validity time.Duration) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": keyID})
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	payload, err := json.Marshal(claims{
+		Issuer:    s.URL,
+		Subject:   subject,
+		Audience:  audience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(validity).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// verify checks the signature, issuer, expiry, audience and subject of the token.
+// It must be called with the lock held.
+func (s *Server) verify(token, audience string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("token is not a JWT")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&s.key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return errors.New("invalid signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("invalid payload encoding: %w", err)
+	}
+	c := claims{}
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	switch {
+	case c.Issuer != s.URL:
+		return fmt.Errorf("untrusted issuer %q", c.Issuer)
+	case time.Now().Unix() >= c.ExpiresAt:
+		return errors.New("token is expired")
+	case c.Audience != audience:
+		return fmt.Errorf("invalid audience %q, expected %q", c.Audience, audience)
+	case !s.subjects[c.Subject]:
+		return fmt.Errorf("subject %q is not trusted", c.Subject)
+	}
+	return nil
+}
+
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.URL,
+		"jwks_uri":                              s.URL + "/keys",
+		"response_types_supported":              []string{"id_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (s *Server) keys(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+type awsError struct {
+	XMLName xml.Name `xml:"ErrorResponse"`
+	Code    string   `xml:"Error>Code"`
+	Message string   `xml:"Error>Message"`
+}
+
+type awsCredentials struct {
+	XMLName         xml.Name `xml:"AssumeRoleWithWebIdentityResponse"`
+	AccessKeyID     string   `xml:"AssumeRoleWithWebIdentityResult>Credentials>AccessKeyId"`
+	SecretAccessKey string   `xml:"AssumeRoleWithWebIdentityResult>Credentials>SecretAccessKey"`
+	SessionToken    string   `xml:"AssumeRoleWithWebIdentityResult>Credentials>SessionToken"`
+	Expiration      string   `xml:"AssumeRoleWithWebIdentityResult>Credentials>Expiration"`
+}
+
+func (s *Server) aws(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if r.FormValue("Action") != "AssumeRoleWithWebIdentity" || r.FormValue("RoleArn") == "" {
+		writeXML(w, http.StatusBadRequest, awsError{Code: "InvalidAction", Message: "expected AssumeRoleWithWebIdentity with a role"})
+		return
+	}
+	if err := s.verify(r.FormValue("WebIdentityToken"), AWSAudience); err != nil {
+		writeXML(w, http.StatusBadRequest, awsError{Code: "InvalidIdentityToken", Message: err.Error()})
+		return
+	}
+
+	s.exchanges++
+	writeXML(w, http.StatusOK, awsCredentials{
+		AccessKeyID:     fmt.Sprintf("ASIAOIDCTEST%d", s.exchanges),
+		SecretAccessKey: fmt.Sprintf("secret-%d", s.exchanges),
+		SessionToken:    fmt.Sprintf("session-%d", s.exchanges),
+		Expiration:      time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+}
+
+func (s *Server) azure(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") || r.FormValue("grant_type") != "client_credentials" ||
+		r.FormValue("client_assertion_type") != "urn:ietf:params:oauth:client-assertion-type:jwt-bearer" || r.FormValue("client_id") == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if err := s.verify(r.FormValue("client_assertion"), AzureAudience); err != nil {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client", "error_description": err.Error()})
+		return
+	}
+
+	s.exchanges++
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"token_type":   "Bearer",
+		"access_token": fmt.Sprintf("azure-token-%d", s.exchanges),
+		"expires_in":   3600,
+	})
+}
+
+func (s *Server) gcpSTS(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:token-exchange" || r.FormValue("subject_token_type") != "urn:ietf:params:oauth:token-type:jwt" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if err := s.verify(r.FormValue("subject_token"), GCPAudience(r.FormValue("audience"))); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": err.Error()})
+		return
+	}
+
+	s.exchanges++
+	token := fmt.Sprintf("gcp-federated-token-%d", s.exchanges)
+	s.federatedTokens[token] = true
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":      token,
+		"issued_token_type": "urn:ietf:params:oauth:token-type:access_token",
+		"token_type":        "Bearer",
+		"expires_in":        3600,
+	})
+}
+
+func (s *Server) gcpIAM(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	email, found := strings.CutPrefix(r.URL.Path, "/gcp/iam/v1/projects/-/serviceAccounts/")
+	email, isGenerate := strings.CutSuffix(email, ":generateAccessToken")
+	if !found || !isGenerate || email == "" {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"error": map[string]string{"message": "not found"}})
+		return
+	}
+	if !s.federatedTokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")] {
+		writeJSON(w, http.StatusUnauthorized, map[string]interface{}{"error": map[string]string{"message": "invalid federated token"}})
+		return
+	}
+
+	s.exchanges++
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"accessToken": fmt.Sprintf("gcp-token-%d-%s", s.exchanges, email),
+		"expireTime":  time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeXML(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workloadidentity exchanges the projected service account token of the
+// machine-controller for short-lived cloud credentials using OIDC federation,
+// so no static cloud credentials have to be stored in the cluster.
+package workloadidentity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/util"
+)
+
+const (
+	// refreshBefore is how long before their expiry cached credentials are replaced.
+	refreshBefore = 5 * time.Minute
+
+	// exchangeTimeout bounds an exchange. An exchange is shared by all callers requesting
+	// credentials of the same identity, so it is not canceled with the context of one caller.
+	exchangeTimeout = time.Minute
+)
+
+var (
+	tokenFile string
+
+	cacheLock sync.Mutex
+	cache     = map[string]*Credentials{}
+
+	// exchanges deduplicates concurrent exchanges for the same identity.
+	exchanges singleflight.Group
+)
+
+// ErrNotConfigured is returned if a machine uses workload identity federation,
+// but the machine-controller has no service account token to exchange.
+var ErrNotConfigured = errors.New("workload identity federation is not configured, -workload-identity-token-file is not set")
+
+// SetTokenFile sets the file containing the service account token which is exchanged
+// for cloud credentials. The file is read on every exchange, so it can be a projected
+// token which is rotated by the kubelet.
+func SetTokenFile(filename string) {
+	tokenFile = filename
+}
+
+// Credentials are short-lived cloud credentials.
+type Credentials struct {
+	// AccessToken is an OAuth2 access token, used by Azure and GCP.
+	AccessToken string
+	// AccessKeyID, SecretAccessKey and SessionToken are used by AWS.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// ServiceAccountEmail is the GCP service account the credentials belong to, if any.
+	ServiceAccountEmail string
+
+	Expiry time.Time
+}
+
+// Exchanger exchanges a service account token for the credentials of a cloud identity.
+type Exchanger interface {
+	// Key identifies the cloud identity, credentials are cached per key.
+	Key() string
+	Exchange(ctx context.Context, token string) (*Credentials, error)
+}
+
+// GetCredentials returns the cached credentials of the exchanger's identity. New credentials
+// are requested if there are none or they are about to expire. Concurrent callers for the same
+// identity share one exchange, callers for other identities are not blocked by it.
+func GetCredentials(ctx context.Context, exchanger Exchanger) (*Credentials, error) {
+	if tokenFile == "" {
+		return nil, ErrNotConfigured
+	}
+
+	key := exchanger.Key()
+	if creds := cachedCredentials(key); creds != nil {
+		return creds, nil
+	}
+
+	result := exchanges.DoChan(key, func() (interface{}, error) {
+		// The credentials might have been exchanged since they were looked up.
+		if creds := cachedCredentials(key); creds != nil {
+			return creds, nil
+		}
+
+		exchangeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), exchangeTimeout)
+		defer cancel()
+		creds, err := exchange(exchangeCtx, exchanger)
+		if err != nil {
+			return nil, err
+		}
+
+		cacheLock.Lock()
+		defer cacheLock.Unlock()
+		cache[key] = creds
+		return creds, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return r.Val.(*Credentials), nil
+	}
+}
+
+// cachedCredentials returns the cached credentials of the key, or nil if there are none or
+// they are about to expire.
+func cachedCredentials(key string) *Credentials {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+
+	if creds, ok := cache[key]; ok && time.Until(creds.Expiry) > refreshBefore {
+		return creds
+	}
+	return nil
+}
+
+func exchange(ctx context.Context, exchanger Exchanger) (*Credentials, error) {
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token: %w", err)
+	}
+
+	creds, err := exchanger.Exchange(ctx, strings.TrimSpace(string(token)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange service account token: %w", err)
+	}
+	return creds, nil
+}
+
+func defaultHTTPClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	// The requests carry the service account token and the responses the cloud credentials,
+	// so they must never be dumped to the logs.
+	c := util.HTTPClientConfig{LogPrefix: "[Workload Identity API]", Sensitive: true}.New()
+	return &c
+}
+
+// postForm posts the form and returns the response body, or an error for any other status than 200.
+func postForm(ctx context.Context, client *http.Client, endpoint string, form url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return do(client, req)
+}
+
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadidentity
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/workloadidentity/oidctest"
+)
+
+const (
+	testSubject      = "system:serviceaccount:kube-system:machine-controller"
+	testPoolProvider = "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/pool/providers/kubernetes"
+)
+
+// setupTokenFile issues a token of the server and configures it as token file, the global
+// configuration is restored after the test.
+func setupTokenFile(t *testing.T, server *oidctest.Server, subject, audience string) {
+	t.Helper()
+
+	token, err := server.Issue(subject, audience, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to issue token: %v", err)
+	}
+	filename := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(filename, []byte(token), 0600); err != nil {
+		t.Fatalf("failed to write token: %v", err)
+	}
+
+	SetTokenFile(filename)
+	t.Cleanup(func() {
+		SetTokenFile("")
+		cache = map[string]*Credentials{}
+	})
+}
+
+func TestGetCredentials(t *testing.T) {
+	server, err := oidctest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	defer server.Close()
+	server.Trust(testSubject)
+
+	testCases := []struct {
+		name      string
+		audience  string
+		exchanger Exchanger
+		verify    func(t *testing.T, creds *Credentials)
+	}{
+		{
+			name:      "AWS",
+			audience:  oidctest.AWSAudience,
+			exchanger: &AWSExchanger{RoleARN: "arn:aws:iam::123456789012:role/machine-controller", Endpoint: server.AWSEndpoint()},
+			verify: func(t *testing.T, creds *Credentials) {
+				if !strings.HasPrefix(creds.AccessKeyID, "ASIA") || creds.SecretAccessKey == "" || creds.SessionToken == "" {
+					t.Errorf("expected temporary AWS credentials, got %+v", creds)
+				}
+			},
+		},
+		{
+			name:      "Azure",
+			audience:  oidctest.AzureAudience,
+			exchanger: &AzureExchanger{TenantID: "tenant", ClientID: "client", AuthorityHost: server.AzureAuthorityHost()},
+			verify: func(t *testing.T, creds *Credentials) {
+				if !strings.HasPrefix(creds.AccessToken, "azure-token-") {
+					t.Errorf("expected Azure access token, got %+v", creds)
+				}
+			},
+		},
+		{
+			name:      "GCP",
+			audience:  oidctest.GCPAudience(testPoolProvider),
+			exchanger: &GCPExchanger{Audience: testPoolProvider, STSEndpoint: server.GCPSTSEndpoint()},
+			verify: func(t *testing.T, creds *Credentials) {
+				if !strings.HasPrefix(creds.AccessToken, "gcp-federated-token-") {
+					t.Errorf("expected federated access token, got %+v", creds)
+				}
+			},
+		},
+		{
+			name:     "GCP with service account impersonation",
+			audience: oidctest.GCPAudience(testPoolProvider),
+			exchanger: &GCPExchanger{
+				Audience:            testPoolProvider,
+				ServiceAccountEmail: "machine-controller@project.iam.gserviceaccount.com",
+				STSEndpoint:         server.GCPSTSEndpoint(),
+				IAMEndpoint:         server.GCPIAMEndpoint(),
+			},
+			verify: func(t *testing.T, creds *Credentials) {
+				if !strings.HasSuffix(creds.AccessToken, "-machine-controller@project.iam.gserviceaccount.com") || creds.ServiceAccountEmail == "" {
+					t.Errorf("expected access token of the service account, got %+v", creds)
+				}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			setupTokenFile(t, server, testSubject, test.audience)
+
+			creds, err := GetCredentials(context.Background(), test.exchanger)
+			if err != nil {
+				t.Fatalf("failed to get credentials: %v", err)
+			}
+			if time.Until(creds.Expiry) < 30*time.Minute {
+				t.Errorf("expected credentials to be valid for about an hour, expiry is %v", creds.Expiry)
+			}
+			test.verify(t, creds)
+
+			exchanges := server.Exchanges()
+			cached, err := GetCredentials(context.Background(), test.exchanger)
+			if err != nil {
+				t.Fatalf("failed to get cached credentials: %v", err)
+			}
+			if cached != creds || server.Exchanges() != exchanges {
+				t.Error("expected credentials to be cached")
+			}
+		})
+	}
+}
+
+func TestGetCredentialsErrors(t *testing.T) {
+	server, err := oidctest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	defer server.Close()
+	server.Trust(testSubject)
+
+	exchanger := &AWSExchanger{RoleARN: "arn:aws:iam::123456789012:role/machine-controller", Endpoint: server.AWSEndpoint()}
+
+	if _, err := GetCredentials(context.Background(), exchanger); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("expected ErrNotConfigured without token file, got %v", err)
+	}
+
+	t.Run("untrusted subject", func(t *testing.T) {
+		setupTokenFile(t, server, "system:serviceaccount:default:attacker", oidctest.AWSAudience)
+		if _, err := GetCredentials(context.Background(), exchanger); err == nil || !strings.Contains(err.Error(), "not trusted") {
+			t.Errorf("expected untrusted subject to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		setupTokenFile(t, server, testSubject, oidctest.AzureAudience)
+		if _, err := GetCredentials(context.Background(), exchanger); err == nil || !strings.Contains(err.Error(), "invalid audience") {
+			t.Errorf("expected wrong audience to be rejected, got %v", err)
+		}
+	})
+}
+
+// blockingExchanger counts its exchanges and blocks them until release is closed.
+type blockingExchanger struct {
+	key       string
+	release   chan struct{}
+	exchanges atomic.Int32
+}
+
+func (e *blockingExchanger) Key() string {
+	return e.key
+}
+
+func (e *blockingExchanger) Exchange(ctx context.Context, _ string) (*Credentials, error) {
+	e.exchanges.Add(1)
+	select {
+	case <-e.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &Credentials{AccessToken: e.key, Expiry: time.Now().Add(time.Hour)}, nil
+}
+
+func TestGetCredentialsConcurrently(t *testing.T) {
+	server, err := oidctest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	defer server.Close()
+	setupTokenFile(t, server, testSubject, oidctest.AWSAudience)
+
+	slow := &blockingExchanger{key: "slow", release: make(chan struct{})}
+	fast := &blockingExchanger{key: "fast", release: make(chan struct{})}
+	close(fast.release)
+
+	results := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := GetCredentials(context.Background(), slow)
+			results <- err
+		}()
+	}
+
+	// The exchange of another identity is not blocked by the slow one.
+	if _, err := GetCredentials(context.Background(), fast); err != nil {
+		t.Fatalf("failed to get credentials: %v", err)
+	}
+
+	// A caller stops waiting once its context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := GetCredentials(ctx, slow); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context deadline to be exceeded, got %v", err)
+	}
+
+	close(slow.release)
+	for range 2 {
+		if err := <-results; err != nil {
+			t.Errorf("failed to get credentials: %v", err)
+		}
+	}
+	if exchanges := slow.exchanges.Load(); exchanges != 1 {
+		t.Errorf("expected concurrent callers to share one exchange, got %d exchanges", exchanges)
+	}
+}
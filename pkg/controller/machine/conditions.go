@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	controllerutil "k8c.io/machine-controller/pkg/controller/util"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// setMachineConditionTrue marks the given lifecycle condition of the machine as reached.
+func (r *Reconciler) setMachineConditionTrue(machine *clusterv1alpha1.Machine, conditionType corev1.NodeConditionType) error {
+	return r.updateMachineCondition(machine, conditionType, corev1.ConditionTrue, "", "")
+}
+
+// setMachineConditionFalse marks the given lifecycle condition of the machine as not reached.
+func (r *Reconciler) setMachineConditionFalse(machine *clusterv1alpha1.Machine, conditionType corev1.NodeConditionType, reason, message string) error {
+	return r.updateMachineCondition(machine, conditionType, corev1.ConditionFalse, reason, message)
+}
+
+func (r *Reconciler) updateMachineCondition(machine *clusterv1alpha1.Machine, conditionType corev1.NodeConditionType, status corev1.ConditionStatus, reason, message string) error {
+	if err := r.updateMachine(machine, func(m *clusterv1alpha1.Machine) {
+		setMachineCondition(m, conditionType, status, reason, message)
+	}); err != nil {
+		return fmt.Errorf("failed to update %s condition: %w", conditionType, err)
+	}
+	return nil
+}
+
+func getMachineCondition(machine *clusterv1alpha1.Machine, conditionType corev1.NodeConditionType) *corev1.NodeCondition {
+	for i := range machine.Status.Conditions {
+		if machine.Status.Conditions[i].Type == conditionType {
+			return &machine.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// setMachineCondition sets the given condition, only updating its LastTransitionTime
+// if its status changes.
+func setMachineCondition(machine *clusterv1alpha1.Machine, conditionType corev1.NodeConditionType, status corev1.ConditionStatus, reason, message string) {
+	controllerutil.SetMachineCondition(machine, conditionType, status, reason, message)
+}
@@ -23,17 +23,20 @@ import (
 	"net"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"github.com/heptiolabs/healthcheck"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"k8c.io/machine-controller/pkg/cloudprovider"
 	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
 	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	"k8c.io/machine-controller/pkg/cloudprovider/ratelimit"
 	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
 	"k8c.io/machine-controller/pkg/cloudprovider/util"
 	controllerutil "k8c.io/machine-controller/pkg/controller/util"
@@ -41,6 +44,7 @@ import (
 	"k8c.io/machine-controller/pkg/node/eviction"
 	"k8c.io/machine-controller/pkg/node/poddeletion"
 	"k8c.io/machine-controller/pkg/rhsm"
+	"k8c.io/machine-controller/pkg/tracing"
 	"k8c.io/machine-controller/sdk/apis/cluster/common"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	"k8c.io/machine-controller/sdk/bootstrap"
@@ -119,6 +123,17 @@ type Reconciler struct {
 
 	nodePortRange                     string
 	overrideBootstrapKubeletAPIServer string
+
+	// rateLimiter limits the calls to the cloud providers. If nil, calls are not limited.
+	rateLimiter *ratelimit.Limiter
+
+	// configVarSources resolve config vars referencing values outside of the cluster.
+	configVarSources *configvar.Sources
+
+	// pendingTraceContexts holds the trace contexts of the machines being reconciled, which
+	// are stored in their annotations with their next update, instead of updating them once more.
+	pendingTraceContexts     map[types.NamespacedName]string
+	pendingTraceContextsLock sync.Mutex
 }
 
 type NodeSettings struct {
@@ -152,18 +167,24 @@ type KubeconfigProvider interface {
 // this controller.
 type MetricsCollection struct {
 	Workers        prometheus.Gauge
+	ShardWorkers   *prometheus.GaugeVec
 	Errors         prometheus.Counter
 	Provisioning   prometheus.Histogram
 	Deprovisioning prometheus.Histogram
+	PhaseDuration  *prometheus.HistogramVec
+	ProviderCalls  *cloudprovider.Metrics
 }
 
 func (mc *MetricsCollection) MustRegister(registerer prometheus.Registerer) {
 	registerer.MustRegister(
 		mc.Errors,
 		mc.Workers,
+		mc.ShardWorkers,
 		mc.Provisioning,
 		mc.Deprovisioning,
+		mc.PhaseDuration,
 	)
+	mc.ProviderCalls.MustRegister(registerer)
 }
 
 func Add(
@@ -182,6 +203,9 @@ func Add(
 	nodeSettings NodeSettings,
 	nodePortRange string,
 	overrideBootstrapKubeletAPIServer string,
+	rateLimiter *ratelimit.Limiter,
+	providerWorkers ProviderWorkerCounts,
+	configVarSources *configvar.Sources,
 ) error {
 	reconciler := &Reconciler{
 		log:                              log.Named(ControllerName),
@@ -201,13 +225,13 @@ func Add(
 
 		nodePortRange:                     nodePortRange,
 		overrideBootstrapKubeletAPIServer: overrideBootstrapKubeletAPIServer,
+		rateLimiter:                       rateLimiter,
+		configVarSources:                  configVarSources,
 	}
 	utilruntime.ErrorHandlers = append(utilruntime.ErrorHandlers, func(context.Context, error, string, ...interface{}) {
 		reconciler.metrics.Errors.Add(1)
 	})
 
-	metrics.Workers.Set(float64(numWorkers))
-
 	nodePredicate := predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
 		oldNode := e.ObjectOld.(*corev1.Node)
 		newNode := e.ObjectNew.(*corev1.Node)
@@ -231,23 +255,40 @@ func Add(
 		return true
 	}}
 
-	_, err := builder.ControllerManagedBy(mgr).
-		Named(ControllerName).
-		WithOptions(controller.Options{
-			MaxConcurrentReconciles: numWorkers,
-			LogConstructor: func(*reconcile.Request) logr.Logger {
-				// we log ourselves
-				return zapr.NewLogger(zap.NewNop())
-			},
-		}).
-		For(&clusterv1alpha1.Machine{}).
-		Watches(&corev1.Node{}, enqueueRequestsForNodes(ctx, log, mgr), builder.WithPredicates(nodePredicate)).
-		Build(reconciler)
-
-	return err
+	// Each shard has its own queue and workers, so the controller-runtime workqueue and
+	// reconcile metrics are reported per shard by its controller name.
+	var totalWorkers int
+	for _, s := range shards(numWorkers, providerWorkers) {
+		shardPredicate := predicate.NewPredicateFuncs(func(obj ctrlruntimeclient.Object) bool {
+			machine, ok := obj.(*clusterv1alpha1.Machine)
+			return ok && s.containsMachine(machine)
+		})
+
+		if _, err := builder.ControllerManagedBy(mgr).
+			Named(s.name).
+			WithOptions(controller.Options{
+				MaxConcurrentReconciles: s.workers,
+				LogConstructor: func(*reconcile.Request) logr.Logger {
+					// we log ourselves
+					return zapr.NewLogger(zap.NewNop())
+				},
+			}).
+			For(&clusterv1alpha1.Machine{}, builder.WithPredicates(shardPredicate)).
+			Watches(&corev1.Node{}, enqueueRequestsForNodes(ctx, log, mgr, s), builder.WithPredicates(nodePredicate)).
+			Build(reconciler); err != nil {
+			return fmt.Errorf("failed to build controller %s: %w", s.name, err)
+		}
+
+		metrics.ShardWorkers.WithLabelValues(s.name).Set(float64(s.workers))
+		totalWorkers += s.workers
+	}
+
+	metrics.Workers.Set(float64(totalWorkers))
+
+	return nil
 }
 
-func enqueueRequestsForNodes(ctx context.Context, log *zap.SugaredLogger, mgr manager.Manager) handler.EventHandler {
+func enqueueRequestsForNodes(ctx context.Context, log *zap.SugaredLogger, mgr manager.Manager, s shard) handler.EventHandler {
 	return handler.EnqueueRequestsFromMapFunc(func(_ context.Context, node ctrlruntimeclient.Object) []reconcile.Request {
 		var result []reconcile.Request
 		machinesList := &clusterv1alpha1.MachineList{}
@@ -264,7 +305,7 @@ func enqueueRequestsForNodes(ctx context.Context, log *zap.SugaredLogger, mgr ma
 			// We get triggered by node{Add,Update}, so enqueue machines if they
 			// have no nodeRef yet to make matching happen ASAP
 			for _, machine := range machinesList.Items {
-				if machine.Status.NodeRef == nil {
+				if machine.Status.NodeRef == nil && s.containsMachine(&machine) {
 					result = append(result, reconcile.Request{
 						NamespacedName: types.NamespacedName{
 							Namespace: machine.Namespace,
@@ -278,6 +319,9 @@ func enqueueRequestsForNodes(ctx context.Context, log *zap.SugaredLogger, mgr ma
 
 		for _, machine := range machinesList.Items {
 			if string(machine.UID) == ownerUIDString {
+				if !s.containsMachine(&machine) {
+					return nil
+				}
 				log.Debugw("Processing node", "node", node.GetName(), "machine", ctrlruntimeclient.ObjectKeyFromObject(&machine))
 				return []reconcile.Request{{NamespacedName: types.NamespacedName{
 					Namespace: machine.Namespace,
@@ -313,8 +357,11 @@ func nodeIsReady(node *corev1.Node) bool {
 	return false
 }
 
-func (r *Reconciler) getNodeByNodeRef(ctx context.Context, nodeRef *corev1.ObjectReference) (*corev1.Node, error) {
-	node := &corev1.Node{}
+func (r *Reconciler) getNodeByNodeRef(ctx context.Context, nodeRef *corev1.ObjectReference) (node *corev1.Node, err error) {
+	ctx, span := tracing.Start(ctx, "GetNodeByNodeRef")
+	defer func() { tracing.End(span, err) }()
+
+	node = &corev1.Node{}
 	if err := r.client.Get(ctx, types.NamespacedName{Name: nodeRef.Name}, node); err != nil {
 		return nil, err
 	}
@@ -322,7 +369,42 @@ func (r *Reconciler) getNodeByNodeRef(ctx context.Context, nodeRef *corev1.Objec
 }
 
 func (r *Reconciler) updateMachine(m *clusterv1alpha1.Machine, modify ...cloudprovidertypes.MachineModifier) error {
-	return r.providerData.Update(m, modify...)
+	if len(modify) == 0 {
+		return nil
+	}
+
+	// The machine is fetched again by the update, so the completed phases are determined
+	// from the fetched machine before and after the modifications.
+	var (
+		unmodified *clusterv1alpha1.Machine
+		completed  []phaseDuration
+	)
+	key := types.NamespacedName{Namespace: m.Namespace, Name: m.Name}
+	traceContext := r.pendingTraceContext(key)
+	modifiers := append([]cloudprovidertypes.MachineModifier{func(m *clusterv1alpha1.Machine) {
+		unmodified = m.DeepCopy()
+	}}, modify...)
+	modifiers = append(modifiers, func(m *clusterv1alpha1.Machine) {
+		completed = completedPhases(unmodified, m)
+		// The trace context is only stored if the machine is updated anyway.
+		if traceContext != "" && m.Annotations[tracing.AnnotationTraceContext] == "" && !equality.Semantic.DeepEqual(unmodified, m) {
+			if m.Annotations == nil {
+				m.Annotations = map[string]string{}
+			}
+			m.Annotations[tracing.AnnotationTraceContext] = traceContext
+		}
+	})
+
+	if err := r.providerData.Update(m, modifiers...); err != nil {
+		return err
+	}
+	if traceContext != "" {
+		r.setPendingTraceContext(key, "")
+	}
+	for _, phase := range completed {
+		r.metrics.PhaseDuration.WithLabelValues(phase.phase).Observe(phase.duration.Seconds())
+	}
+	return nil
 }
 
 // updateMachine updates machine's ErrorMessage and ErrorReason regardless if they were set or not
@@ -354,6 +436,31 @@ func (r *Reconciler) createProviderInstance(ctx context.Context, log *zap.Sugare
 		return nil, fmt.Errorf("failed to add %q finalizer: %w", FinalizerDeleteInstance, err)
 	}
 	i, err := prov.Create(ctx, log, machine, r.providerData, userdata)
+	for isInsufficientResourcesError(err) {
+		// Retry with the next fallback candidate, if any is configured.
+		candidate, fallbackErr := nextFallbackCandidate(prov, machine)
+		if fallbackErr != nil {
+			return nil, fallbackErr
+		}
+		if candidate == nil {
+			break
+		}
+
+		log.Infow("Insufficient capacity at cloud provider, retrying with fallback", "fallback", candidate.Name, zap.Error(err))
+		r.recorder.Eventf(machine, corev1.EventTypeWarning, "InsufficientCapacity", "Insufficient capacity at cloud provider, retrying with fallback %s", candidate.Name)
+
+		var statusErr error
+		if err := r.updateMachine(machine, func(m *clusterv1alpha1.Machine) {
+			statusErr = setFallbackCandidateStatus(m, candidate)
+		}); err != nil {
+			return nil, fmt.Errorf("failed to record fallback candidate: %w", err)
+		}
+		if statusErr != nil {
+			return nil, fmt.Errorf("failed to record fallback candidate: %w", statusErr)
+		}
+
+		i, err = prov.Create(ctx, log, machine, r.providerData, userdata)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -383,8 +490,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		return reconcile.Result{}, nil
 	}
 
+	// Every reconciliation is a trace of its own, which is linked to the first trace of the
+	// machine. Its trace context is stored with the next update of the machine, if the machine
+	// has none yet.
+	ctx, span := tracing.StartLinked(ctx, machine.Annotations, "Reconcile")
+	span.SetAttributes(attribute.String("machine", request.NamespacedName.String()))
+	if machine.Annotations[tracing.AnnotationTraceContext] == "" {
+		r.setPendingTraceContext(request.NamespacedName, tracing.TraceContext(ctx))
+		defer r.setPendingTraceContext(request.NamespacedName, "")
+	}
+
 	recorderMachine := machine.DeepCopy()
 	result, err := r.reconcile(ctx, log, machine)
+	tracing.End(span, err)
+	if rateLimited, retryAfter := cloudprovidererrors.IsRateLimitedError(err); rateLimited {
+		// Being rate limited is expected, so it is neither logged as error nor recorded as event.
+		log.Debugw("Cloud provider call is rate limited, requeueing", "retryAfter", retryAfter)
+		return reconcile.Result{RequeueAfter: retryAfter}, nil
+	}
 	if err != nil {
 		// We have no guarantee that machine is non-nil after reconciliation
 		log.Errorw("Reconciling failed", zap.Error(err))
@@ -399,6 +522,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	return *result, err
 }
 
+// setPendingTraceContext sets the trace context, which is stored in the annotations of the
+// machine with its next update. An empty trace context removes the pending one.
+func (r *Reconciler) setPendingTraceContext(machine types.NamespacedName, traceContext string) {
+	r.pendingTraceContextsLock.Lock()
+	defer r.pendingTraceContextsLock.Unlock()
+
+	if traceContext == "" {
+		delete(r.pendingTraceContexts, machine)
+		return
+	}
+	if r.pendingTraceContexts == nil {
+		r.pendingTraceContexts = map[types.NamespacedName]string{}
+	}
+	r.pendingTraceContexts[machine] = traceContext
+}
+
+func (r *Reconciler) pendingTraceContext(machine types.NamespacedName) string {
+	r.pendingTraceContextsLock.Lock()
+	defer r.pendingTraceContextsLock.Unlock()
+
+	return r.pendingTraceContexts[machine]
+}
+
 func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine) (*reconcile.Result, error) {
 	// This must stay in the controller, it can not be moved into the webhook
 	// as the webhook does not get the name of machineset controller generated
@@ -412,11 +558,15 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, mach
 	if err != nil {
 		return nil, fmt.Errorf("failed to get provider config: %w", err)
 	}
-	configResolver := configvar.NewResolver(ctx, r.client)
+	configResolver := configvar.NewResolver(ctx, r.client, configvar.WithSources(r.configVarSources))
 	prov, err := cloudprovider.ForProvider(providerConfig.CloudProvider, configResolver)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cloud provider %q: %w", providerConfig.CloudProvider, err)
 	}
+	if r.rateLimiter != nil {
+		prov = cloudprovider.NewRateLimitingCloudProvider(prov, providerConfig.CloudProvider, r.rateLimiter)
+	}
+	prov = newFallbackProvider(prov)
 
 	log = log.With("provider", providerConfig.CloudProvider)
 
@@ -436,8 +586,10 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, mach
 		// In case we cannot find a node for the NodeRef we must remove the NodeRef & recreate an instance on the next sync
 		if apierrors.IsNotFound(err) {
 			log.Info("Found invalid NodeRef on machine; deleting reference...")
+			message := fmt.Sprintf("Node %s does not exist anymore", machine.Status.NodeRef.Name)
 			return nil, r.updateMachine(machine, func(m *clusterv1alpha1.Machine) {
 				m.Status.NodeRef = nil
+				setMachineCondition(m, common.NodeJoinedCondition, corev1.ConditionFalse, common.WaitingForNodeReason, message)
 			})
 		}
 		return nil, fmt.Errorf("failed to check if node for machine exists: '%w'", err)
@@ -445,6 +597,10 @@ func (r *Reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, mach
 
 	nodeLog := log.With("node", node.Name)
 
+	if err := r.setMachineConditionTrue(machine, common.NodeJoinedCondition); err != nil {
+		return nil, err
+	}
+
 	if nodeIsReady(node) {
 		// We must do this to ensure the informers in the machineSet and machineDeployment controller
 		// get triggered as soon as a ready node exists for a machine
@@ -609,6 +765,11 @@ func (r *Reconciler) deleteMachine(
 		err         error
 	)
 
+	// Give external systems the chance to act before the node is drained.
+	if proceed, err := r.waitForLifecycleHooks(log, machine, common.PreDrainDeleteHookAnnotationPrefix, common.PreDrainDeleteHookSucceededCondition); !proceed || err != nil {
+		return nil, err
+	}
+
 	if !skipEviction {
 		shouldEvict, err = r.shouldEvict(ctx, log, machine)
 		if err != nil {
@@ -620,13 +781,30 @@ func (r *Reconciler) deleteMachine(
 		return nil, err
 	}
 
-	var evictedSomething, deletedSomething bool
+	var (
+		evictedSomething, deletedSomething bool
+		drainTimeoutExceeded               bool
+		evictionResult                     eviction.Result
+	)
 	volumesFree := true
 	if shouldEvict {
-		evictedSomething, err = eviction.New(machine.Status.NodeRef.Name, r.client, r.kubeClient).Run(ctx, log)
+		drainPolicy, err := r.getDrainPolicy(ctx, machine)
+		if err != nil {
+			return nil, err
+		}
+		policy, timeoutExceeded, err := evictionPolicy(machine, drainPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid drain policy: %w", err)
+		}
+		drainTimeoutExceeded = timeoutExceeded
+
+		evictionCtx, span := tracing.Start(ctx, "Evict")
+		evictionResult, err = eviction.New(machine.Status.NodeRef.Name, r.client, r.kubeClient, policy).Run(evictionCtx, log)
+		tracing.End(span, err)
 		if err != nil {
 			return nil, fmt.Errorf("failed to evict node %s: %w", machine.Status.NodeRef.Name, err)
 		}
+		evictedSomething = evictionResult.Pending
 	}
 	if shouldCleanUpVolumes {
 		deletedSomething, volumesFree, err = poddeletion.New(machine.Status.NodeRef.Name, r.client, r.kubeClient).Run(ctx, log)
@@ -636,9 +814,34 @@ func (r *Reconciler) deleteMachine(
 	}
 
 	if evictedSomething || deletedSomething || !volumesFree {
+		if evictedSomething {
+			if err := r.reportDrainProgress(log, machine, evictionResult, drainTimeoutExceeded); err != nil {
+				return nil, err
+			}
+		} else {
+			message := fmt.Sprintf("Waiting for volumes to be detached from node %s", machine.Status.NodeRef.Name)
+			if err := r.updateMachineCondition(machine, common.DrainingCondition, corev1.ConditionTrue, common.WaitingForVolumesReason, message); err != nil {
+				return nil, err
+			}
+		}
 		return &reconcile.Result{RequeueAfter: 10 * time.Second}, nil
 	}
 
+	drainReason := common.DrainSkippedReason
+	if shouldEvict || shouldCleanUpVolumes {
+		drainReason = common.DrainCompletedReason
+	}
+	if err := r.setMachineConditionFalse(machine, common.DrainingCondition, drainReason, ""); err != nil {
+		return nil, err
+	}
+
+	// Give external systems the chance to act before the instance disappears.
+	if sets.NewString(machine.Finalizers...).Has(FinalizerDeleteInstance) {
+		if proceed, err := r.waitForLifecycleHooks(log, machine, common.PreTerminateDeleteHookAnnotationPrefix, common.PreTerminateDeleteHookSucceededCondition); !proceed || err != nil {
+			return nil, err
+		}
+	}
+
 	if result, err := r.deleteCloudProviderInstance(ctx, log, prov, machine); result != nil || err != nil {
 		return result, err
 	}
@@ -714,11 +917,21 @@ func (r *Reconciler) deleteCloudProviderInstance(ctx context.Context, log *zap.S
 
 	// Delete the instance
 	completelyGone, err := prov.Cleanup(ctx, log, machine, r.providerData)
+	if rateLimited, _ := cloudprovidererrors.IsRateLimitedError(err); rateLimited {
+		// The instance was not touched, so the conditions stay unchanged.
+		return nil, err
+	}
 	if err != nil {
+		if condErr := r.setMachineConditionFalse(machine, common.InstanceDeletedCondition, common.InstanceDeletionFailedReason, err.Error()); condErr != nil {
+			log.Errorw("Failed to update machine condition", zap.Error(condErr))
+		}
 		message := fmt.Sprintf("%v. Please manually delete %s finalizer from the machine object.", err, FinalizerDeleteInstance)
 		return nil, r.updateMachineErrorIfTerminalError(machine, common.DeleteMachineError, message, err, "failed to delete machine at cloud provider")
 	}
 	if !completelyGone {
+		if err := r.setMachineConditionFalse(machine, common.InstanceDeletedCondition, common.InstanceDeletingReason, "The instance is being deleted at the cloud provider"); err != nil {
+			return nil, err
+		}
 		// As the instance is not completely gone yet, we need to recheck in a few seconds.
 		return &reconcile.Result{RequeueAfter: deletionRetryWaitPeriod}, nil
 	}
@@ -769,6 +982,8 @@ func (r *Reconciler) deleteCloudProviderInstance(ctx context.Context, log *zap.S
 	}
 
 	return nil, r.updateMachine(machine, func(m *clusterv1alpha1.Machine) {
+		setMachineCondition(m, common.InstanceDeletedCondition, corev1.ConditionTrue, "", "")
+
 		finalizers := sets.NewString(m.Finalizers...)
 		// If a machine deployment belongs to an external cloud provider, the 'machine-delete-finalizer' must be manually
 		// removed by an administrator or an external service. This is because the machine controller lacks access to cloud
@@ -818,6 +1033,10 @@ func (r *Reconciler) ensureInstanceExistsForMachine(
 		if errors.Is(err, cloudprovidererrors.ErrInstanceNotFound) {
 			log.Debug("Validated machine spec")
 
+			if err := r.setMachineConditionFalse(machine, common.InstanceProvisionedCondition, common.InstanceNotFoundReason, "No instance exists at the cloud provider"); err != nil {
+				return nil, err
+			}
+
 			// Here we do stuff!
 			var userdata string
 			referencedMachineDeployment, machineDeploymentRevision, err := controllerutil.GetMachineDeploymentNameAndRevisionForMachine(ctx, machine, r.client)
@@ -835,18 +1054,36 @@ func (r *Reconciler) ensureInstanceExistsForMachine(
 				types.NamespacedName{Name: bootstrapSecretName, Namespace: util.CloudInitNamespace},
 				bootstrapSecret); err != nil {
 				log.Errorw("cloud-init configuration: cloud config is not ready yet", "secret", bootstrap.BootstrapCloudConfig)
+				if err := r.setMachineConditionFalse(machine, common.BootstrapReadyCondition, common.WaitingForBootstrapDataReason, fmt.Sprintf("Waiting for bootstrap secret %s/%s", util.CloudInitNamespace, bootstrapSecretName)); err != nil {
+					return nil, err
+				}
 				return &reconcile.Result{RequeueAfter: 3 * time.Second}, nil
 			}
 
 			bootstrapSecretRevision := bootstrapSecret.Annotations[bootstrap.MachineDeploymentRevision]
 			if bootstrapSecretRevision != machineDeploymentRevision {
+				message := fmt.Sprintf("Bootstrap secret %s/%s has revision %q instead of %q", util.CloudInitNamespace, bootstrapSecretName, bootstrapSecretRevision, machineDeploymentRevision)
+				if err := r.setMachineConditionFalse(machine, common.BootstrapReadyCondition, common.BootstrapDataOutdatedReason, message); err != nil {
+					return nil, err
+				}
 				return nil, fmt.Errorf("cloud-init configuration: cloud config %q is not ready yet", bootstrap.BootstrapCloudConfig)
 			}
 
+			if err := r.setMachineConditionTrue(machine, common.BootstrapReadyCondition); err != nil {
+				return nil, err
+			}
+
 			userdata = getOSMBootstrapUserdata(machine.Spec.Name, *bootstrapSecret)
 
 			// Create the instance
 			if _, err = r.createProviderInstance(ctx, log, prov, machine, userdata); err != nil {
+				if rateLimited, _ := cloudprovidererrors.IsRateLimitedError(err); rateLimited {
+					// No instance was created, so the conditions stay unchanged.
+					return nil, err
+				}
+				if condErr := r.setMachineConditionFalse(machine, common.InstanceProvisionedCondition, common.InstanceCreationFailedReason, err.Error()); condErr != nil {
+					log.Errorw("Failed to update machine condition", zap.Error(condErr))
+				}
 				message := fmt.Sprintf("%v. Failed to create a machine.", err)
 				return nil, r.updateMachineErrorIfTerminalError(machine, common.CreateMachineError, message, err, "failed to create machine at cloudprovider")
 			}
@@ -855,6 +1092,9 @@ func (r *Reconciler) ensureInstanceExistsForMachine(
 					return nil, fmt.Errorf("failed to add redhat subscription finalizer: %w", err)
 				}
 			}
+			if err := r.setMachineConditionTrue(machine, common.InstanceProvisionedCondition); err != nil {
+				return nil, err
+			}
 			r.recorder.Event(machine, corev1.EventTypeNormal, "Created", "Successfully created instance")
 			log.Info("Created machine at cloud provider")
 			// Reqeue the machine to make sure we notice if creation failed silently
@@ -863,6 +1103,9 @@ func (r *Reconciler) ensureInstanceExistsForMachine(
 
 		// case 2.2: terminal error was returned and manual interaction is required to recover
 		if ok, _, _ := cloudprovidererrors.IsTerminalError(err); ok {
+			if condErr := r.setMachineConditionFalse(machine, common.InstanceProvisionedCondition, common.InstanceGetFailedReason, err.Error()); condErr != nil {
+				log.Errorw("Failed to update machine condition", zap.Error(condErr))
+			}
 			message := fmt.Sprintf("%v. Failed to create a machine.", err)
 			return nil, r.updateMachineErrorIfTerminalError(machine, common.CreateMachineError, message, err, "failed to get instance from provider")
 		}
@@ -895,6 +1138,12 @@ func (r *Reconciler) ensureInstanceExistsForMachine(
 	// Considering that, we just retry after 15 seconds, hoping that we'll
 	// get IP addresses by then.
 	if len(addresses) == 0 {
+		if err := r.updateMachine(machine, func(m *clusterv1alpha1.Machine) {
+			setMachineCondition(m, common.InstanceProvisionedCondition, corev1.ConditionTrue, "", "")
+			setMachineCondition(m, common.AddressesAssignedCondition, corev1.ConditionFalse, common.WaitingForAddressesReason, "The cloud provider reports no addresses for the instance")
+		}); err != nil {
+			return nil, fmt.Errorf("failed to update machine conditions: %w", err)
+		}
 		return &reconcile.Result{RequeueAfter: 15 * time.Second}, nil
 	}
 
@@ -929,6 +1178,8 @@ func (r *Reconciler) ensureInstanceExistsForMachine(
 		if providerID != "" {
 			m.Spec.ProviderID = &providerID
 		}
+		setMachineCondition(m, common.InstanceProvisionedCondition, corev1.ConditionTrue, "", "")
+		setMachineCondition(m, common.AddressesAssignedCondition, corev1.ConditionTrue, "", "")
 	}); err != nil {
 		return nil, fmt.Errorf("failed to update machine after setting .status.addresses and providerID: %w", err)
 	}
@@ -954,7 +1205,14 @@ func (r *Reconciler) ensureNodeOwnerRef(ctx context.Context, log *zap.SugaredLog
 		if err := r.updateMachineStatus(machine, node); err != nil {
 			return nil, fmt.Errorf("failed to update machine status: %w", err)
 		}
+		if err := r.setMachineConditionTrue(machine, common.NodeJoinedCondition); err != nil {
+			return nil, err
+		}
 	} else {
+		if err := r.setMachineConditionFalse(machine, common.NodeJoinedCondition, common.WaitingForNodeReason, "No node was found for the instance"); err != nil {
+			return nil, err
+		}
+
 		// If the machine has an owner Ref and joinClusterTimeout is configured and reached, delete it to have it re-created by the MachineSet controller
 		// Check if the machine is a potential candidate for triggering deletion
 		if r.joinClusterTimeout != nil && ownerReferencesHasMachineSetKind(machine.OwnerReferences) {
@@ -1062,6 +1320,9 @@ func (r *Reconciler) updateMachineStatus(machine *clusterv1alpha1.Machine, node
 }
 
 func (r *Reconciler) getNode(ctx context.Context, log *zap.SugaredLogger, instance instance.Instance, provider providerconfig.CloudProvider) (node *corev1.Node, exists bool, err error) {
+	ctx, span := tracing.Start(ctx, "GetNode")
+	defer func() { tracing.End(span, err) }()
+
 	if instance == nil {
 		return nil, false, fmt.Errorf("getNode called with nil provider instance")
 	}
@@ -27,6 +27,7 @@ import (
 
 	"k8c.io/machine-controller/pkg/cloudprovider/instance"
 	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/pkg/tracing"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	providerconfigtypes "k8c.io/machine-controller/sdk/providerconfig"
 
@@ -313,6 +314,8 @@ func TestControllerDeletesMachinesOnJoinTimeout(t *testing.T) {
 				client:             client,
 				recorder:           &record.FakeRecorder{},
 				joinClusterTimeout: test.joinTimeoutConfig,
+				providerData:       &cloudprovidertypes.ProviderData{Update: cloudprovidertypes.GetMachineUpdater(ctx, client)},
+				metrics:            NewMachineControllerMetrics(),
 			}
 
 			if _, err := reconciler.ensureNodeOwnerRef(ctx, zap.NewNop().Sugar(), instance, machine, providerConfig); err != nil {
@@ -727,3 +730,44 @@ func TestControllerFindNodeByProviderID(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateMachineStoresPendingTraceContext(t *testing.T) {
+	ctx := context.Background()
+	const traceContext = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	machine := &clusterv1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: metav1.NamespaceSystem}}
+	client := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(machine).
+		Build()
+	reconciler := &Reconciler{
+		client:       client,
+		providerData: &cloudprovidertypes.ProviderData{Update: cloudprovidertypes.GetMachineUpdater(ctx, client)},
+	}
+	key := types.NamespacedName{Namespace: machine.Namespace, Name: machine.Name}
+	reconciler.setPendingTraceContext(key, traceContext)
+
+	// Updates which do not change the machine must not store the trace context.
+	if err := reconciler.updateMachine(machine, func(*clusterv1alpha1.Machine) {}); err != nil {
+		t.Fatalf("failed to update machine: %v", err)
+	}
+	if machine.Annotations[tracing.AnnotationTraceContext] != "" {
+		t.Fatalf("expected no trace context to be stored without changes, got %q", machine.Annotations[tracing.AnnotationTraceContext])
+	}
+
+	if err := reconciler.updateMachine(machine, func(m *clusterv1alpha1.Machine) {
+		m.Spec.Name = "machine"
+	}); err != nil {
+		t.Fatalf("failed to update machine: %v", err)
+	}
+	stored := &clusterv1alpha1.Machine{}
+	if err := client.Get(ctx, key, stored); err != nil {
+		t.Fatalf("failed to get machine: %v", err)
+	}
+	if stored.Annotations[tracing.AnnotationTraceContext] != traceContext {
+		t.Errorf("expected trace context %q to be stored with the update, got %q", traceContext, stored.Annotations[tracing.AnnotationTraceContext])
+	}
+	if pending := reconciler.pendingTraceContext(key); pending != "" {
+		t.Errorf("expected no pending trace context after the update, got %q", pending)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/node/eviction"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// getDrainPolicy returns the drain policy of the MachineDeployment owning the machine, or nil if the
+// machine does not belong to a MachineDeployment.
+func (r *Reconciler) getDrainPolicy(ctx context.Context, machine *clusterv1alpha1.Machine) (*clusterv1alpha1.MachineDrainPolicy, error) {
+	msRef := metav1.GetControllerOf(machine)
+	if msRef == nil || msRef.Kind != "MachineSet" {
+		return nil, nil
+	}
+	ms := &clusterv1alpha1.MachineSet{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: machine.Namespace, Name: msRef.Name}, ms); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get MachineSet %s: %w", msRef.Name, err)
+	}
+
+	mdRef := metav1.GetControllerOf(ms)
+	if mdRef == nil || mdRef.Kind != "MachineDeployment" {
+		return nil, nil
+	}
+	md := &clusterv1alpha1.MachineDeployment{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: machine.Namespace, Name: mdRef.Name}, md); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get MachineDeployment %s: %w", mdRef.Name, err)
+	}
+
+	return md.Spec.DrainPolicy, nil
+}
+
+// evictionPolicy returns the eviction policy for the machine and whether the drain timeout of its
+// drain policy was exceeded, in which case the remaining pods are deleted. The timeout is measured
+// from the start of the drain, so that time spent waiting for lifecycle hooks does not count.
+func evictionPolicy(machine *clusterv1alpha1.Machine, drainPolicy *clusterv1alpha1.MachineDrainPolicy) (eviction.Policy, bool, error) {
+	policy, err := eviction.NewPolicy(drainPolicy)
+	if err != nil {
+		return policy, false, err
+	}
+	if drainPolicy != nil && drainPolicy.Timeout != nil {
+		if condition := getMachineCondition(machine, common.DrainingCondition); condition != nil && condition.Status == corev1.ConditionTrue {
+			policy.Force = time.Since(condition.LastTransitionTime.Time) > drainPolicy.Timeout.Duration
+		}
+	}
+	return policy, policy.Force, nil
+}
+
+// reportDrainProgress sets the Draining condition of the machine for a drain that is still in progress.
+// Pods and PodDisruptionBudgets blocking the drain are reported in an event whenever they change.
+func (r *Reconciler) reportDrainProgress(log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, result eviction.Result, timeoutExceeded bool) error {
+	nodeName := machine.Status.NodeRef.Name
+	reason, message := common.EvictingPodsReason, fmt.Sprintf("Evicting pods from node %s", nodeName)
+
+	switch {
+	case timeoutExceeded:
+		reason, message = common.DrainTimeoutExceededReason, fmt.Sprintf("Deleting the remaining pods from node %s as the drain timeout was exceeded", nodeName)
+	case len(result.Blocked) > 0:
+		blocked := make([]string, 0, len(result.Blocked))
+		for _, pod := range result.Blocked {
+			blocked = append(blocked, pod.String())
+		}
+		reason, message = common.DrainBlockedReason, fmt.Sprintf("Drain of node %s is blocked by pods %s", nodeName, strings.Join(blocked, ", "))
+	}
+
+	if reason != common.EvictingPodsReason {
+		if condition := getMachineCondition(machine, common.DrainingCondition); condition == nil || condition.Reason != reason || condition.Message != message {
+			log.Infow("Drain of node is not progressing", "node", nodeName, "reason", reason, "blocked", result.Blocked)
+			r.recorder.Event(machine, corev1.EventTypeWarning, reason, message)
+		}
+	}
+
+	return r.updateMachineCondition(machine, common.DrainingCondition, corev1.ConditionTrue, reason, message)
+}
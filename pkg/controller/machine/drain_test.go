@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEvictionPolicyTimeout(t *testing.T) {
+	threeHoursAgo := metav1.NewTime(time.Now().Add(-3 * time.Hour))
+	oneMinuteAgo := metav1.NewTime(time.Now().Add(-time.Minute))
+	drainPolicy := &clusterv1alpha1.MachineDrainPolicy{Timeout: &metav1.Duration{Duration: time.Hour}}
+
+	tests := []struct {
+		name     string
+		draining *corev1.NodeCondition
+		expected bool
+	}{
+		{
+			name:     "drain not started",
+			expected: false,
+		},
+		{
+			name:     "drain started within the timeout",
+			draining: &corev1.NodeCondition{Type: common.DrainingCondition, Status: corev1.ConditionTrue, LastTransitionTime: oneMinuteAgo},
+			expected: false,
+		},
+		{
+			name:     "drain started before the timeout",
+			draining: &corev1.NodeCondition{Type: common.DrainingCondition, Status: corev1.ConditionTrue, LastTransitionTime: threeHoursAgo},
+			expected: true,
+		},
+		{
+			name:     "drain completed",
+			draining: &corev1.NodeCondition{Type: common.DrainingCondition, Status: corev1.ConditionFalse, LastTransitionTime: threeHoursAgo},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The machine was deleted long ago, e.g. while waiting for lifecycle hooks, which
+			// must not count into the drain timeout.
+			machine := &clusterv1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &threeHoursAgo}}
+			if test.draining != nil {
+				machine.Status.Conditions = []corev1.NodeCondition{*test.draining}
+			}
+
+			policy, timeoutExceeded, err := evictionPolicy(machine, drainPolicy)
+			if err != nil {
+				t.Fatalf("failed to get eviction policy: %v", err)
+			}
+			if timeoutExceeded != test.expected || policy.Force != test.expected {
+				t.Errorf("expected timeout exceeded to be %t, got %t (force %t)", test.expected, timeoutExceeded, policy.Force)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/cloudprovider"
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// providerStatusFallbackCandidateKey is the key in Machine.Status.ProviderStatus
+// which records the fallback candidate a machine was created with.
+const providerStatusFallbackCandidateKey = "fallbackCandidate"
+
+// fallbackCandidateStatus records the fallback candidate a machine was created with.
+type fallbackCandidateStatus struct {
+	// Index is the position of the candidate in the configured fallbacks, starting at 1.
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+}
+
+// getFallbackCandidateStatus returns the fallback candidate recorded for the machine,
+// or nil if the machine uses its own spec.
+func getFallbackCandidateStatus(machine *clusterv1alpha1.Machine) (*fallbackCandidateStatus, error) {
+	if machine.Status.ProviderStatus == nil || len(machine.Status.ProviderStatus.Raw) == 0 {
+		return nil, nil
+	}
+
+	providerStatus := map[string]json.RawMessage{}
+	if err := json.Unmarshal(machine.Status.ProviderStatus.Raw, &providerStatus); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal provider status: %w", err)
+	}
+	rawStatus, ok := providerStatus[providerStatusFallbackCandidateKey]
+	if !ok {
+		return nil, nil
+	}
+
+	status := &fallbackCandidateStatus{}
+	if err := json.Unmarshal(rawStatus, status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fallback candidate status: %w", err)
+	}
+	return status, nil
+}
+
+// setFallbackCandidateStatus records the given fallback candidate in the machine's
+// provider status, keeping all other fields of it.
+func setFallbackCandidateStatus(machine *clusterv1alpha1.Machine, status *fallbackCandidateStatus) error {
+	providerStatus := map[string]json.RawMessage{}
+	if machine.Status.ProviderStatus != nil && len(machine.Status.ProviderStatus.Raw) > 0 {
+		if err := json.Unmarshal(machine.Status.ProviderStatus.Raw, &providerStatus); err != nil {
+			return fmt.Errorf("failed to unmarshal provider status: %w", err)
+		}
+	}
+
+	rawStatus, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fallback candidate status: %w", err)
+	}
+	providerStatus[providerStatusFallbackCandidateKey] = rawStatus
+
+	rawProviderStatus, err := json.Marshal(providerStatus)
+	if err != nil {
+		return fmt.Errorf("failed to marshal provider status: %w", err)
+	}
+	machine.Status.ProviderStatus = &runtime.RawExtension{Raw: rawProviderStatus}
+	return nil
+}
+
+// getFallbackCandidates returns the fallback candidates of the given spec, if the
+// provider supports them.
+func getFallbackCandidates(prov cloudprovidertypes.Provider, spec clusterv1alpha1.MachineSpec) ([]cloudprovidertypes.FallbackCandidate, error) {
+	fallbackProvider, ok := prov.(cloudprovidertypes.FallbackProvider)
+	if !ok {
+		return nil, nil
+	}
+	candidates, err := fallbackProvider.FallbackCandidates(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fallback candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// nextFallbackCandidate returns the candidate following the one recorded for the
+// machine, or nil if all candidates were tried already.
+func nextFallbackCandidate(prov cloudprovidertypes.Provider, machine *clusterv1alpha1.Machine) (*fallbackCandidateStatus, error) {
+	candidates, err := getFallbackCandidates(prov, machine.Spec)
+	if err != nil {
+		return nil, err
+	}
+	current, err := getFallbackCandidateStatus(machine)
+	if err != nil {
+		return nil, err
+	}
+
+	next := 1
+	if current != nil {
+		next = current.Index + 1
+	}
+	if next > len(candidates) {
+		return nil, nil
+	}
+	return &fallbackCandidateStatus{Index: next, Name: candidates[next-1].Name}, nil
+}
+
+// applyFallbackCandidate returns a copy of the machine with the spec of the recorded
+// fallback candidate. If no candidate is recorded, the machine is returned as is.
+func applyFallbackCandidate(prov cloudprovidertypes.Provider, machine *clusterv1alpha1.Machine) (*clusterv1alpha1.Machine, error) {
+	status, err := getFallbackCandidateStatus(machine)
+	if err != nil || status == nil {
+		return machine, err
+	}
+
+	candidates, err := getFallbackCandidates(prov, machine.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if status.Index < 1 || status.Index > len(candidates) {
+		return nil, fmt.Errorf("fallback candidate %d (%s) is not configured", status.Index, status.Name)
+	}
+
+	candidateMachine := machine.DeepCopy()
+	candidateMachine.Spec = candidates[status.Index-1].Spec
+	return candidateMachine, nil
+}
+
+func isInsufficientResourcesError(err error) bool {
+	ok, reason, _ := cloudprovidererrors.IsTerminalError(err)
+	return ok && reason == common.InsufficientResourcesMachineError
+}
+
+// fallbackProvider passes machines with the spec of their recorded fallback
+// candidate to the actual provider, so that their instances are found and
+// cleaned up where they were created. The calls of the other optional interfaces
+// are forwarded unchanged.
+type fallbackProvider struct {
+	cloudprovidertypes.Provider
+	cloudprovider.OptionalInterfacesForwarder
+}
+
+func newFallbackProvider(prov cloudprovidertypes.Provider) cloudprovidertypes.Provider {
+	return &fallbackProvider{Provider: prov, OptionalInterfacesForwarder: cloudprovider.NewOptionalInterfacesForwarder(prov)}
+}
+
+func (p *fallbackProvider) FallbackCandidates(spec clusterv1alpha1.MachineSpec) ([]cloudprovidertypes.FallbackCandidate, error) {
+	return getFallbackCandidates(p.Provider, spec)
+}
+
+func (p *fallbackProvider) Get(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData) (instance.Instance, error) {
+	machine, err := applyFallbackCandidate(p.Provider, machine)
+	if err != nil {
+		return nil, err
+	}
+	return p.Provider.Get(ctx, log, machine, data)
+}
+
+func (p *fallbackProvider) Create(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData, userdata string) (instance.Instance, error) {
+	machine, err := applyFallbackCandidate(p.Provider, machine)
+	if err != nil {
+		return nil, err
+	}
+	return p.Provider.Create(ctx, log, machine, data, userdata)
+}
+
+func (p *fallbackProvider) Cleanup(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, data *cloudprovidertypes.ProviderData) (bool, error) {
+	machine, err := applyFallbackCandidate(p.Provider, machine)
+	if err != nil {
+		return false, err
+	}
+	return p.Provider.Cleanup(ctx, log, machine, data)
+}
+
+func (p *fallbackProvider) MigrateUID(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, newUID types.UID) error {
+	machine, err := applyFallbackCandidate(p.Provider, machine)
+	if err != nil {
+		return err
+	}
+	return p.Provider.MigrateUID(ctx, log, machine, newUID)
+}
+
+func (p *fallbackProvider) MachineMetricsLabels(machine *clusterv1alpha1.Machine) (map[string]string, error) {
+	machine, err := applyFallbackCandidate(p.Provider, machine)
+	if err != nil {
+		return nil, err
+	}
+	return p.Provider.MachineMetricsLabels(machine)
+}
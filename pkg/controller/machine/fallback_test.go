@@ -0,0 +1,262 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap"
+
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeZoneSpec struct {
+	Zone      string   `json:"zone"`
+	Fallbacks []string `json:"fallbacks,omitempty"`
+}
+
+// fakeFallbackProvider creates instances in all zones except the full ones.
+type fakeFallbackProvider struct {
+	cloudprovidertypes.Provider
+	fullZones map[string]bool
+	createdIn []string
+}
+
+func fakeZoneProviderSpec(t *testing.T, spec fakeZoneSpec) clusterv1alpha1.ProviderSpec {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("failed to marshal provider spec: %v", err)
+	}
+	return clusterv1alpha1.ProviderSpec{Value: &runtime.RawExtension{Raw: raw}}
+}
+
+func (p *fakeFallbackProvider) FallbackCandidates(spec clusterv1alpha1.MachineSpec) ([]cloudprovidertypes.FallbackCandidate, error) {
+	zoneSpec := fakeZoneSpec{}
+	if err := json.Unmarshal(spec.ProviderSpec.Value.Raw, &zoneSpec); err != nil {
+		return nil, err
+	}
+
+	var candidates []cloudprovidertypes.FallbackCandidate
+	for _, zone := range zoneSpec.Fallbacks {
+		candidate := cloudprovidertypes.FallbackCandidate{Name: "zone=" + zone, Spec: spec}
+		raw, err := json.Marshal(fakeZoneSpec{Zone: zone})
+		if err != nil {
+			return nil, err
+		}
+		candidate.Spec.ProviderSpec.Value = &runtime.RawExtension{Raw: raw}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+func (p *fakeFallbackProvider) Create(_ context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData, _ string) (instance.Instance, error) {
+	zoneSpec := fakeZoneSpec{}
+	if err := json.Unmarshal(machine.Spec.ProviderSpec.Value.Raw, &zoneSpec); err != nil {
+		return nil, err
+	}
+	if p.fullZones[zoneSpec.Zone] {
+		return nil, cloudprovidererrors.TerminalError{
+			Reason:  common.InsufficientResourcesMachineError,
+			Message: "no capacity in zone " + zoneSpec.Zone,
+		}
+	}
+	p.createdIn = append(p.createdIn, zoneSpec.Zone)
+	return &fakeInstance{name: machine.Name}, nil
+}
+
+func TestCreateProviderInstanceFallback(t *testing.T) {
+	tests := []struct {
+		name              string
+		fallbacks         []string
+		fullZones         map[string]bool
+		expectErr         bool
+		expectCreatedIn   string
+		expectFallbackIdx int
+	}{
+		{
+			name:            "no fallback needed",
+			fallbacks:       []string{"b", "c"},
+			fullZones:       map[string]bool{},
+			expectCreatedIn: "a",
+		},
+		{
+			name:              "second fallback is used",
+			fallbacks:         []string{"b", "c"},
+			fullZones:         map[string]bool{"a": true, "b": true},
+			expectCreatedIn:   "c",
+			expectFallbackIdx: 2,
+		},
+		{
+			name:              "all fallbacks are full",
+			fallbacks:         []string{"b"},
+			fullZones:         map[string]bool{"a": true, "b": true},
+			expectErr:         true,
+			expectFallbackIdx: 1,
+		},
+		{
+			name:      "no fallbacks configured",
+			fullZones: map[string]bool{"a": true},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			machine := &clusterv1alpha1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-machine",
+					Namespace: metav1.NamespaceSystem,
+				},
+				Spec: clusterv1alpha1.MachineSpec{
+					ProviderSpec: fakeZoneProviderSpec(t, fakeZoneSpec{Zone: "a", Fallbacks: test.fallbacks}),
+				},
+			}
+
+			client := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(machine).
+				Build()
+
+			reconciler := Reconciler{
+				client:       client,
+				recorder:     &record.FakeRecorder{},
+				providerData: &cloudprovidertypes.ProviderData{Update: cloudprovidertypes.GetMachineUpdater(ctx, client)},
+			}
+
+			prov := &fakeFallbackProvider{fullZones: test.fullZones}
+			_, err := reconciler.createProviderInstance(ctx, zap.NewNop().Sugar(), newFallbackProvider(prov), machine, "")
+			if (err != nil) != test.expectErr {
+				t.Fatalf("expected error: %t, got %v", test.expectErr, err)
+			}
+			if test.expectErr && !isInsufficientResourcesError(err) {
+				t.Errorf("expected insufficient resources error, got %v", err)
+			}
+
+			if !test.expectErr && (len(prov.createdIn) != 1 || prov.createdIn[0] != test.expectCreatedIn) {
+				t.Errorf("expected instance to be created in zone %q, got %v", test.expectCreatedIn, prov.createdIn)
+			}
+
+			updatedMachine := &clusterv1alpha1.Machine{}
+			if err := client.Get(ctx, types.NamespacedName{Namespace: machine.Namespace, Name: machine.Name}, updatedMachine); err != nil {
+				t.Fatalf("failed to get machine: %v", err)
+			}
+			status, err := getFallbackCandidateStatus(updatedMachine)
+			if err != nil {
+				t.Fatalf("failed to get fallback candidate status: %v", err)
+			}
+			recordedIdx := 0
+			if status != nil {
+				recordedIdx = status.Index
+			}
+			if recordedIdx != test.expectFallbackIdx {
+				t.Errorf("expected fallback candidate %d to be recorded, got %d", test.expectFallbackIdx, recordedIdx)
+			}
+		})
+	}
+}
+
+func TestSetFallbackCandidateStatusKeepsProviderStatus(t *testing.T) {
+	machine := &clusterv1alpha1.Machine{
+		Status: clusterv1alpha1.MachineStatus{
+			ProviderStatus: &runtime.RawExtension{Raw: []byte(`{"instanceID":"i-123"}`)},
+		},
+	}
+
+	if err := setFallbackCandidateStatus(machine, &fallbackCandidateStatus{Index: 1, Name: "zone=b"}); err != nil {
+		t.Fatalf("failed to set fallback candidate status: %v", err)
+	}
+
+	providerStatus := map[string]interface{}{}
+	if err := json.Unmarshal(machine.Status.ProviderStatus.Raw, &providerStatus); err != nil {
+		t.Fatalf("failed to unmarshal provider status: %v", err)
+	}
+	if providerStatus["instanceID"] != "i-123" {
+		t.Errorf("expected instanceID to be kept, got %v", providerStatus)
+	}
+
+	status, err := getFallbackCandidateStatus(machine)
+	if err != nil {
+		t.Fatalf("failed to get fallback candidate status: %v", err)
+	}
+	if status == nil || status.Index != 1 || status.Name != "zone=b" {
+		t.Errorf("expected fallback candidate 1 (zone=b), got %+v", status)
+	}
+}
+
+type fakeAccountProvider struct {
+	cloudprovidertypes.Provider
+}
+
+func (p *fakeAccountProvider) AccountID(_ clusterv1alpha1.MachineSpec) (string, error) {
+	return "account", nil
+}
+
+func TestFallbackProviderForwardsOptionalInterfaces(t *testing.T) {
+	prov := newFallbackProvider(&fakeAccountProvider{})
+
+	identifier, ok := prov.(cloudprovidertypes.AccountIdentifier)
+	if !ok {
+		t.Fatal("expected the fallback provider to implement cloudprovidertypes.AccountIdentifier")
+	}
+	accountID, err := identifier.AccountID(clusterv1alpha1.MachineSpec{})
+	if err != nil {
+		t.Fatalf("failed to get the account ID: %v", err)
+	}
+	if accountID != "account" {
+		t.Errorf("expected account ID %q, got %q", "account", accountID)
+	}
+
+	for name, implements := range map[string]bool{
+		"InstanceLister":     isInstanceLister(prov),
+		"AttributesProvider": isAttributesProvider(prov),
+		"Planner":            isPlanner(prov),
+	} {
+		if !implements {
+			t.Errorf("expected the fallback provider to implement cloudprovidertypes.%s", name)
+		}
+	}
+}
+
+func isInstanceLister(prov cloudprovidertypes.Provider) bool {
+	_, ok := prov.(cloudprovidertypes.InstanceLister)
+	return ok
+}
+
+func isAttributesProvider(prov cloudprovidertypes.Provider) bool {
+	_, ok := prov.(cloudprovidertypes.AttributesProvider)
+	return ok
+}
+
+func isPlanner(prov cloudprovidertypes.Provider) bool {
+	_, ok := prov.(cloudprovidertypes.Planner)
+	return ok
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// lifecycleHooks returns the annotations of the machine with the given hook prefix.
+func lifecycleHooks(machine *clusterv1alpha1.Machine, prefix string) []string {
+	var hooks []string
+	for key := range machine.Annotations {
+		if key == prefix || strings.HasPrefix(key, prefix+"/") {
+			hooks = append(hooks, key)
+		}
+	}
+	sort.Strings(hooks)
+	return hooks
+}
+
+// waitForLifecycleHooks returns whether the deletion of the machine may proceed past the
+// stage of the given hook prefix. While hooks are present, conditionType is false.
+func (r *Reconciler) waitForLifecycleHooks(log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, prefix string, conditionType corev1.NodeConditionType) (bool, error) {
+	hooks := lifecycleHooks(machine, prefix)
+	if len(hooks) == 0 {
+		// Machines which never waited for a hook do not get the condition at all.
+		if getMachineCondition(machine, conditionType) == nil {
+			return true, nil
+		}
+		if err := r.setMachineConditionTrue(machine, conditionType); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	message := fmt.Sprintf("Waiting for lifecycle hooks %s", strings.Join(hooks, ", "))
+	if condition := getMachineCondition(machine, conditionType); condition == nil || condition.Status != corev1.ConditionFalse || condition.Message != message {
+		log.Infow("Waiting for lifecycle hooks before continuing deletion", "hooks", hooks)
+		r.recorder.Event(machine, corev1.EventTypeNormal, common.WaitingExternalHookReason, message)
+	}
+
+	return false, r.setMachineConditionFalse(machine, conditionType, common.WaitingExternalHookReason, message)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWaitForLifecycleHooks(t *testing.T) {
+	ctx := context.Background()
+
+	machine := &clusterv1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-machine",
+			Namespace: metav1.NamespaceSystem,
+			Annotations: map[string]string{
+				common.PreDrainDeleteHookAnnotationPrefix + "/storage":   "storage-operator",
+				common.PreTerminateDeleteHookAnnotationPrefix + "/lb":    "lb-operator",
+				common.PreDrainDeleteHookAnnotationPrefix + "-unrelated": "",
+			},
+		},
+	}
+
+	client := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(machine).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := Reconciler{
+		client:       client,
+		recorder:     recorder,
+		providerData: &cloudprovidertypes.ProviderData{Update: cloudprovidertypes.GetMachineUpdater(ctx, client)},
+	}
+	log := zap.NewNop().Sugar()
+
+	proceed, err := reconciler.waitForLifecycleHooks(log, machine, common.PreDrainDeleteHookAnnotationPrefix, common.PreDrainDeleteHookSucceededCondition)
+	if err != nil {
+		t.Fatalf("failed to wait for lifecycle hooks: %v", err)
+	}
+	if proceed {
+		t.Fatal("expected deletion to wait for the pre-drain hook")
+	}
+	condition := getMachineCondition(machine, common.PreDrainDeleteHookSucceededCondition)
+	if condition == nil || condition.Status != corev1.ConditionFalse || condition.Reason != common.WaitingExternalHookReason {
+		t.Errorf("expected %s condition to be false while waiting, got %+v", common.PreDrainDeleteHookSucceededCondition, condition)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expected one event about the wait, got %d", len(recorder.Events))
+	}
+
+	// Waiting again does not emit another event.
+	if _, err := reconciler.waitForLifecycleHooks(log, machine, common.PreDrainDeleteHookAnnotationPrefix, common.PreDrainDeleteHookSucceededCondition); err != nil {
+		t.Fatalf("failed to wait for lifecycle hooks: %v", err)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expected no further event while still waiting, got %d events", len(recorder.Events))
+	}
+
+	delete(machine.Annotations, common.PreDrainDeleteHookAnnotationPrefix+"/storage")
+	if err := client.Update(ctx, machine); err != nil {
+		t.Fatalf("failed to remove hook: %v", err)
+	}
+
+	proceed, err = reconciler.waitForLifecycleHooks(log, machine, common.PreDrainDeleteHookAnnotationPrefix, common.PreDrainDeleteHookSucceededCondition)
+	if err != nil {
+		t.Fatalf("failed to wait for lifecycle hooks: %v", err)
+	}
+	if !proceed {
+		t.Fatal("expected deletion to proceed once the pre-drain hook was removed")
+	}
+
+	updatedMachine := &clusterv1alpha1.Machine{}
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(machine), updatedMachine); err != nil {
+		t.Fatalf("failed to get machine: %v", err)
+	}
+	condition = getMachineCondition(updatedMachine, common.PreDrainDeleteHookSucceededCondition)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		t.Errorf("expected %s condition to be true after the hook was removed, got %+v", common.PreDrainDeleteHookSucceededCondition, condition)
+	}
+
+	proceed, err = reconciler.waitForLifecycleHooks(log, machine, common.PreTerminateDeleteHookAnnotationPrefix, common.PreTerminateDeleteHookSucceededCondition)
+	if err != nil {
+		t.Fatalf("failed to wait for lifecycle hooks: %v", err)
+	}
+	if proceed {
+		t.Error("expected deletion to wait for the pre-terminate hook")
+	}
+}
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -46,6 +47,10 @@ func NewMachineControllerMetrics() *MetricsCollection {
 			Name: metricsPrefix + "workers",
 			Help: "The number of running machine controller workers",
 		}),
+		ShardWorkers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: metricsPrefix + "shard_workers",
+			Help: "The number of machine controller workers per shard",
+		}, []string{"shard"}),
 		Errors: prometheus.NewCounter(prometheus.CounterOpts{
 			Name: metricsPrefix + "errors_total",
 			Help: "The total number or unexpected errors the controller encountered",
@@ -60,6 +65,12 @@ func NewMachineControllerMetrics() *MetricsCollection {
 			Help:    "Histogram of times spent from deleting a Machine to be removed from cluster and cloud provider",
 			Buckets: prometheus.ExponentialBuckets(32, 1.5, 10),
 		}),
+		PhaseDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    metricsPrefix + "phase_duration_seconds",
+			Help:    "Histogram of times spent in the phases of the Machine lifecycle",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{"phase"}),
+		ProviderCalls: cloudprovider.NewMetrics(),
 	}
 
 	// Set default values, so that these metrics always show up
@@ -70,12 +81,14 @@ func NewMachineControllerMetrics() *MetricsCollection {
 }
 
 type MachineCollector struct {
-	ctx    context.Context
-	client ctrlruntimeclient.Client
-
-	machines       *prometheus.Desc
-	machineCreated *prometheus.Desc
-	machineDeleted *prometheus.Desc
+	ctx              context.Context
+	client           ctrlruntimeclient.Client
+	configVarSources *configvar.Sources
+
+	machines        *prometheus.Desc
+	machinesByPhase *prometheus.Desc
+	machineCreated  *prometheus.Desc
+	machineDeleted  *prometheus.Desc
 }
 
 type machineMetricLabels struct {
@@ -85,10 +98,11 @@ type machineMetricLabels struct {
 	ProviderLabels  map[string]string
 }
 
-// Counter turns a label collection into a Prometheus counter.
-func (l *machineMetricLabels) Counter(value uint) prometheus.Counter {
+// Metric turns a label collection into a Prometheus gauge of the number of machines.
+// The label names depend on the cloud provider, so each label collection has its own
+// descriptor.
+func (l *machineMetricLabels) Metric(value uint) (prometheus.Metric, error) {
 	labels := make(map[string]string)
-	labelNames := make([]string, 0)
 
 	if len(l.KubeletVersion) > 0 {
 		labels["kubelet_version"] = l.KubeletVersion
@@ -106,24 +120,24 @@ func (l *machineMetricLabels) Counter(value uint) prometheus.Counter {
 		labels[k] = v
 	}
 
+	labelNames := make([]string, 0, len(labels))
 	for k := range labels {
 		labelNames = append(labelNames, k)
 	}
+	sort.Strings(labelNames)
 
-	counterVec := prometheus.NewCounterVec(prometheus.CounterOpts{
-		Name: metricsPrefix + "machines_total",
-		Help: "Total number of machines",
-	}, labelNames)
-
-	counter := counterVec.With(labels)
-	counter.Add(float64(value))
+	labelValues := make([]string, 0, len(labelNames))
+	for _, name := range labelNames {
+		labelValues = append(labelValues, labels[name])
+	}
 
-	return counter
+	desc := prometheus.NewDesc(metricsPrefix+"machines_total", "Total number of machines", labelNames, nil)
+	return prometheus.NewConstMetric(desc, prometheus.GaugeValue, float64(value), labelValues...)
 }
 
-func NewMachineCollector(ctx context.Context, client ctrlruntimeclient.Client) *MachineCollector {
+func NewMachineCollector(ctx context.Context, client ctrlruntimeclient.Client, configVarSources *configvar.Sources) *MachineCollector {
 	// Start periodically calling the providers SetMetricsForMachines in a dedicated go routine
-	configResolver := configvar.NewResolver(ctx, client)
+	configResolver := configvar.NewResolver(ctx, client, configvar.WithSources(configVarSources))
 	go func() {
 		metricGatheringExecutor := func() {
 			machines := &clusterv1alpha1.MachineList{}
@@ -171,14 +185,20 @@ func NewMachineCollector(ctx context.Context, client ctrlruntimeclient.Client) *
 	}()
 
 	return &MachineCollector{
-		ctx:    ctx,
-		client: client,
+		ctx:              ctx,
+		client:           client,
+		configVarSources: configVarSources,
 
 		machines: prometheus.NewDesc(
 			metricsPrefix+"machines",
 			"The number of machines managed by this machine controller",
 			[]string{}, nil,
 		),
+		machinesByPhase: prometheus.NewDesc(
+			metricsPrefix+"machines_by_phase",
+			"The number of machines per lifecycle phase",
+			[]string{"phase"}, nil,
+		),
 		machineCreated: prometheus.NewDesc(
 			metricsPrefix+"machine_created",
 			"Timestamp of the machine's creation time",
@@ -195,6 +215,7 @@ func NewMachineCollector(ctx context.Context, client ctrlruntimeclient.Client) *
 // Describe implements the prometheus.Collector interface.
 func (mc MachineCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- mc.machines
+	ch <- mc.machinesByPhase
 	ch <- mc.machineCreated
 	ch <- mc.machineDeleted
 }
@@ -206,10 +227,13 @@ func (mc MachineCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	configResolver := configvar.NewResolver(mc.ctx, mc.client)
+	configResolver := configvar.NewResolver(mc.ctx, mc.client, configvar.WithSources(mc.configVarSources))
 	machineCountByLabels := make(map[*machineMetricLabels]uint)
+	machineCountByPhase := make(map[string]int)
 
 	for _, machine := range machines.Items {
+		machineCountByPhase[machinePhase(&machine)]++
+
 		ch <- prometheus.MustNewConstMetric(
 			mc.machineCreated,
 			prometheus.GaugeValue,
@@ -238,7 +262,7 @@ func (mc MachineCollector) Collect(ch chan<- prometheus.Metric) {
 			continue
 		}
 
-		labels, err := provider.MachineMetricsLabels(&machine)
+		labels, err := newFallbackProvider(provider).MachineMetricsLabels(&machine)
 		if err != nil {
 			utilruntime.HandleError(fmt.Errorf("failed to determine machine metrics labels: %w", err))
 			continue
@@ -273,6 +297,21 @@ func (mc MachineCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	for info, count := range machineCountByLabels {
-		ch <- info.Counter(count)
+		metric, err := info.Metric(count)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to create machines metric: %w", err))
+			continue
+		}
+		ch <- metric
+	}
+
+	// All phases are reported, so that the number of machines drops to zero when a phase is left.
+	for _, phase := range machinePhases {
+		ch <- prometheus.MustNewConstMetric(
+			mc.machinesByPhase,
+			prometheus.GaugeValue,
+			float64(machineCountByPhase[phase]),
+			phase,
+		)
 	}
 }
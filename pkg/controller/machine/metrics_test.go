@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+)
+
+func TestMachineMetricLabelsMetric(t *testing.T) {
+	labels := machineMetricLabels{
+		KubeletVersion:  "1.33.0",
+		CloudProvider:   providerconfig.CloudProviderAWS,
+		OperatingSystem: providerconfig.OperatingSystemUbuntu,
+		ProviderLabels:  map[string]string{"size": "t3.medium"},
+	}
+
+	metric, err := labels.Metric(3)
+	if err != nil {
+		t.Fatalf("failed to create metric: %v", err)
+	}
+
+	expected := `
+# HELP machine_controller_machines_total Total number of machines
+# TYPE machine_controller_machines_total gauge
+machine_controller_machines_total{kubelet_version="1.33.0",os="ubuntu",provider="aws",size="t3.medium"} 3
+`
+	if err := testutil.CollectAndCompare(prometheus.CollectorFunc(func(ch chan<- prometheus.Metric) { ch <- metric }), strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+
+	labels.ProviderLabels = map[string]string{"invalid-label": "value"}
+	if _, err := labels.Metric(1); err == nil {
+		t.Error("expected invalid provider labels to be rejected")
+	}
+}
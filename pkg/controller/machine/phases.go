@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Phases of the machine lifecycle, whose durations are observed.
+const (
+	phaseBootstrapWait  = "bootstrap_wait"
+	phaseInstanceCreate = "instance_create"
+	phaseAddressWait    = "address_wait"
+	phaseNodeJoin       = "node_join"
+	phaseDrain          = "drain"
+	phaseCleanup        = "cleanup"
+)
+
+// provisioningPhases end when their condition becomes true. They start when the condition
+// of the previous phase became true, the first one starts with the creation of the machine.
+var provisioningPhases = []struct {
+	name      string
+	condition corev1.NodeConditionType
+}{
+	{name: phaseBootstrapWait, condition: common.BootstrapReadyCondition},
+	{name: phaseInstanceCreate, condition: common.InstanceProvisionedCondition},
+	{name: phaseAddressWait, condition: common.AddressesAssignedCondition},
+	{name: phaseNodeJoin, condition: common.NodeJoinedCondition},
+}
+
+// deprovisioningPhases start when their condition changes to the from status and end when it
+// changes to the to status.
+var deprovisioningPhases = []struct {
+	name      string
+	condition corev1.NodeConditionType
+	from, to  corev1.ConditionStatus
+}{
+	{name: phaseDrain, condition: common.DrainingCondition, from: corev1.ConditionTrue, to: corev1.ConditionFalse},
+	{name: phaseCleanup, condition: common.InstanceDeletedCondition, from: corev1.ConditionFalse, to: corev1.ConditionTrue},
+}
+
+type phaseDuration struct {
+	phase    string
+	duration time.Duration
+}
+
+// completedPhases returns the durations of the lifecycle phases which were completed by the
+// update of the machine from old to updated. Phases whose start is unknown are skipped.
+func completedPhases(old, updated *clusterv1alpha1.Machine) []phaseDuration {
+	var result []phaseDuration
+
+	start := updated.CreationTimestamp
+	for _, phase := range provisioningPhases {
+		condition := getMachineCondition(updated, phase.condition)
+		if condition == nil || condition.Status != corev1.ConditionTrue {
+			break
+		}
+		if oldCondition := getMachineCondition(old, phase.condition); (oldCondition == nil || oldCondition.Status != corev1.ConditionTrue) && !start.IsZero() {
+			result = append(result, phaseDuration{phase: phase.name, duration: condition.LastTransitionTime.Sub(start.Time)})
+		}
+		start = condition.LastTransitionTime
+	}
+
+	for _, phase := range deprovisioningPhases {
+		oldCondition := getMachineCondition(old, phase.condition)
+		condition := getMachineCondition(updated, phase.condition)
+		if oldCondition == nil || condition == nil || oldCondition.Status != phase.from || condition.Status != phase.to {
+			continue
+		}
+		result = append(result, phaseDuration{phase: phase.name, duration: condition.LastTransitionTime.Sub(oldCondition.LastTransitionTime.Time)})
+	}
+
+	return result
+}
+
+// Lifecycle phases of a machine, as reported by the machines_by_phase metric.
+var machinePhases = []string{"pending", "provisioning", "waiting_for_addresses", "joining", "running", "failed", "draining", "deleting"}
+
+// machinePhase returns the current lifecycle phase of the machine, which is derived from its
+// lifecycle conditions.
+func machinePhase(machine *clusterv1alpha1.Machine) string {
+	isTrue := func(conditionType corev1.NodeConditionType) bool {
+		condition := getMachineCondition(machine, conditionType)
+		return condition != nil && condition.Status == corev1.ConditionTrue
+	}
+
+	switch {
+	case machine.DeletionTimestamp != nil && isTrue(common.DrainingCondition):
+		return "draining"
+	case machine.DeletionTimestamp != nil:
+		return "deleting"
+	case machine.Status.ErrorReason != nil:
+		return "failed"
+	// Machines created before the lifecycle conditions were introduced only have a NodeRef.
+	case isTrue(common.NodeJoinedCondition) || machine.Status.NodeRef != nil:
+		return "running"
+	case isTrue(common.AddressesAssignedCondition):
+		return "joining"
+	case isTrue(common.InstanceProvisionedCondition):
+		return "waiting_for_addresses"
+	case isTrue(common.BootstrapReadyCondition):
+		return "provisioning"
+	default:
+		return "pending"
+	}
+}
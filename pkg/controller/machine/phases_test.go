@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompletedPhases(t *testing.T) {
+	created := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) metav1.Time {
+		return metav1.NewTime(created.Add(offset))
+	}
+	newMachine := func(conditions ...corev1.NodeCondition) *clusterv1alpha1.Machine {
+		return &clusterv1alpha1.Machine{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+			Status:     clusterv1alpha1.MachineStatus{Conditions: conditions},
+		}
+	}
+	condition := func(conditionType corev1.NodeConditionType, status corev1.ConditionStatus, offset time.Duration) corev1.NodeCondition {
+		return corev1.NodeCondition{Type: conditionType, Status: status, LastTransitionTime: at(offset)}
+	}
+
+	tests := []struct {
+		name     string
+		old      *clusterv1alpha1.Machine
+		updated  *clusterv1alpha1.Machine
+		expected []phaseDuration
+	}{
+		{
+			name:     "bootstrap wait starts with the creation",
+			old:      newMachine(condition(common.BootstrapReadyCondition, corev1.ConditionFalse, 0)),
+			updated:  newMachine(condition(common.BootstrapReadyCondition, corev1.ConditionTrue, 20*time.Second)),
+			expected: []phaseDuration{{phase: phaseBootstrapWait, duration: 20 * time.Second}},
+		},
+		{
+			name: "phases completed by one update are all observed",
+			old: newMachine(
+				condition(common.BootstrapReadyCondition, corev1.ConditionTrue, 20*time.Second),
+				condition(common.InstanceProvisionedCondition, corev1.ConditionFalse, 20*time.Second),
+			),
+			updated: newMachine(
+				condition(common.BootstrapReadyCondition, corev1.ConditionTrue, 20*time.Second),
+				condition(common.InstanceProvisionedCondition, corev1.ConditionTrue, time.Minute),
+				condition(common.AddressesAssignedCondition, corev1.ConditionTrue, time.Minute),
+			),
+			expected: []phaseDuration{
+				{phase: phaseInstanceCreate, duration: 40 * time.Second},
+				{phase: phaseAddressWait, duration: 0},
+			},
+		},
+		{
+			name:    "phases without a known start are skipped",
+			old:     newMachine(),
+			updated: newMachine(condition(common.NodeJoinedCondition, corev1.ConditionTrue, time.Minute)),
+		},
+		{
+			name:    "phases which were already completed are not observed again",
+			old:     newMachine(condition(common.BootstrapReadyCondition, corev1.ConditionTrue, 20*time.Second)),
+			updated: newMachine(condition(common.BootstrapReadyCondition, corev1.ConditionTrue, 20*time.Second)),
+		},
+		{
+			name: "drain and cleanup end with the transition of their condition",
+			old: newMachine(
+				condition(common.DrainingCondition, corev1.ConditionTrue, time.Hour),
+				condition(common.InstanceDeletedCondition, corev1.ConditionFalse, time.Hour),
+			),
+			updated: newMachine(
+				condition(common.DrainingCondition, corev1.ConditionFalse, time.Hour+time.Minute),
+				condition(common.InstanceDeletedCondition, corev1.ConditionTrue, time.Hour+2*time.Minute),
+			),
+			expected: []phaseDuration{
+				{phase: phaseDrain, duration: time.Minute},
+				{phase: phaseCleanup, duration: 2 * time.Minute},
+			},
+		},
+		{
+			name:    "skipped drains are not observed",
+			old:     newMachine(),
+			updated: newMachine(condition(common.DrainingCondition, corev1.ConditionFalse, time.Hour)),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := completedPhases(test.old, test.updated)
+			if len(result) != len(test.expected) {
+				t.Fatalf("expected phases %v, got %v", test.expected, result)
+			}
+			for i := range result {
+				if result[i] != test.expected[i] {
+					t.Errorf("expected phases %v, got %v", test.expected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestMachinePhase(t *testing.T) {
+	now := metav1.Now()
+	reason := common.InvalidConfigurationMachineError
+	conditions := func(conditionTypes ...corev1.NodeConditionType) []corev1.NodeCondition {
+		var result []corev1.NodeCondition
+		for _, conditionType := range conditionTypes {
+			result = append(result, corev1.NodeCondition{Type: conditionType, Status: corev1.ConditionTrue})
+		}
+		return result
+	}
+
+	tests := []struct {
+		expected string
+		machine  clusterv1alpha1.Machine
+	}{
+		{expected: "pending"},
+		{expected: "provisioning", machine: clusterv1alpha1.Machine{Status: clusterv1alpha1.MachineStatus{Conditions: conditions(common.BootstrapReadyCondition)}}},
+		{expected: "waiting_for_addresses", machine: clusterv1alpha1.Machine{Status: clusterv1alpha1.MachineStatus{Conditions: conditions(common.BootstrapReadyCondition, common.InstanceProvisionedCondition)}}},
+		{expected: "joining", machine: clusterv1alpha1.Machine{Status: clusterv1alpha1.MachineStatus{Conditions: conditions(common.InstanceProvisionedCondition, common.AddressesAssignedCondition)}}},
+		{expected: "running", machine: clusterv1alpha1.Machine{Status: clusterv1alpha1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: "node"}}}},
+		{expected: "failed", machine: clusterv1alpha1.Machine{Status: clusterv1alpha1.MachineStatus{ErrorReason: &reason}}},
+		{expected: "draining", machine: clusterv1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}, Status: clusterv1alpha1.MachineStatus{Conditions: conditions(common.NodeJoinedCondition, common.DrainingCondition)}}},
+		{expected: "deleting", machine: clusterv1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}, Status: clusterv1alpha1.MachineStatus{Conditions: conditions(common.NodeJoinedCondition)}}},
+	}
+
+	for _, test := range tests {
+		if phase := machinePhase(&test.machine); phase != test.expected {
+			t.Errorf("expected phase %q, got %q", test.expected, phase)
+		}
+	}
+}
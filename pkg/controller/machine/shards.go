@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ProviderWorkerCounts is a flag.Value collecting "<cloud-provider>=<workers>" pairs.
+// Machines of these cloud providers are reconciled by a dedicated shard with its own
+// queue and workers, so a slow cloud provider does not block the others.
+// The flag can be given multiple times.
+type ProviderWorkerCounts map[providerconfig.CloudProvider]int
+
+func (c ProviderWorkerCounts) String() string {
+	pairs := make([]string, 0, len(c))
+	for provider, workers := range c {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", provider, workers))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (c ProviderWorkerCounts) Set(value string) error {
+	provider, workers, found := strings.Cut(value, "=")
+	if !found || provider == "" {
+		return fmt.Errorf("invalid provider worker count %q, expected <cloud-provider>=<workers>", value)
+	}
+	if _, exists := c[providerconfig.CloudProvider(provider)]; exists {
+		return fmt.Errorf("worker count for cloud provider %q specified more than once", provider)
+	}
+	return c.set(providerconfig.CloudProvider(provider), workers)
+}
+
+// AddFromConfigMap adds the worker counts from the data of the ConfigMap, whose keys are
+// cloud providers and whose values are worker counts. Worker counts which are already set,
+// e.g. by flag, take precedence.
+func (c ProviderWorkerCounts) AddFromConfigMap(configMap *corev1.ConfigMap) error {
+	for provider, workers := range configMap.Data {
+		if _, exists := c[providerconfig.CloudProvider(provider)]; exists {
+			continue
+		}
+		if err := c.set(providerconfig.CloudProvider(provider), workers); err != nil {
+			return fmt.Errorf("invalid entry in ConfigMap %s/%s: %w", configMap.Namespace, configMap.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c ProviderWorkerCounts) set(provider providerconfig.CloudProvider, workers string) error {
+	count, err := strconv.Atoi(strings.TrimSpace(workers))
+	if err != nil || count < 1 {
+		return fmt.Errorf("invalid worker count %q for cloud provider %q, expected a positive number", workers, provider)
+	}
+	c[provider] = count
+	return nil
+}
+
+// shard is a controller with its own queue and workers, which reconciles the machines
+// of a subset of the cloud providers.
+type shard struct {
+	name    string
+	workers int
+	// contains returns whether the machines of the cloud provider belong to the shard.
+	contains func(providerconfig.CloudProvider) bool
+}
+
+// shards returns a dedicated shard per cloud provider with a worker count, and a default
+// shard with numWorkers workers for all other cloud providers.
+func shards(numWorkers int, providerWorkers ProviderWorkerCounts) []shard {
+	result := []shard{{
+		name:    ControllerName,
+		workers: numWorkers,
+		contains: func(provider providerconfig.CloudProvider) bool {
+			_, dedicated := providerWorkers[provider]
+			return !dedicated
+		},
+	}}
+
+	providers := make([]providerconfig.CloudProvider, 0, len(providerWorkers))
+	for provider := range providerWorkers {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i] < providers[j] })
+
+	for _, provider := range providers {
+		result = append(result, shard{
+			name:    fmt.Sprintf("%s-%s", ControllerName, provider),
+			workers: providerWorkers[provider],
+			contains: func(p providerconfig.CloudProvider) bool {
+				return p == provider
+			},
+		})
+	}
+
+	return result
+}
+
+// containsMachine returns whether the machine belongs to the shard. Machines with an invalid
+// provider spec belong to the default shard, which reports the error.
+func (s shard) containsMachine(machine *clusterv1alpha1.Machine) bool {
+	var provider providerconfig.CloudProvider
+	if config, err := providerconfig.GetConfig(machine.Spec.ProviderSpec); err == nil {
+		provider = config.CloudProvider
+	}
+	return s.contains(provider)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestProviderWorkerCounts(t *testing.T) {
+	counts := ProviderWorkerCounts{}
+	if err := counts.Set("vsphere=2"); err != nil {
+		t.Fatalf("failed to set worker count: %v", err)
+	}
+	for _, invalid := range []string{"vsphere=3", "aws", "aws=0", "aws=many", "=1"} {
+		if err := counts.Set(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+
+	configMap := &corev1.ConfigMap{Data: map[string]string{
+		"vsphere": "10",
+		"aws":     " 20 ",
+	}}
+	if err := counts.AddFromConfigMap(configMap); err != nil {
+		t.Fatalf("failed to add worker counts from ConfigMap: %v", err)
+	}
+	if counts[providerconfig.CloudProviderVsphere] != 2 {
+		t.Errorf("expected worker count of flag to take precedence, got %d", counts[providerconfig.CloudProviderVsphere])
+	}
+	if counts[providerconfig.CloudProviderAWS] != 20 {
+		t.Errorf("expected worker count 20 from ConfigMap, got %d", counts[providerconfig.CloudProviderAWS])
+	}
+	if expected := "aws=20,vsphere=2"; counts.String() != expected {
+		t.Errorf("expected %q, got %q", expected, counts.String())
+	}
+}
+
+func TestShards(t *testing.T) {
+	result := shards(5, ProviderWorkerCounts{
+		providerconfig.CloudProviderVsphere:   2,
+		providerconfig.CloudProviderOpenstack: 3,
+	})
+
+	expected := []struct {
+		name      string
+		workers   int
+		providers []providerconfig.CloudProvider
+	}{
+		{name: ControllerName, workers: 5, providers: []providerconfig.CloudProvider{providerconfig.CloudProviderAWS, ""}},
+		{name: ControllerName + "-openstack", workers: 3, providers: []providerconfig.CloudProvider{providerconfig.CloudProviderOpenstack}},
+		{name: ControllerName + "-vsphere", workers: 2, providers: []providerconfig.CloudProvider{providerconfig.CloudProviderVsphere}},
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d shards, got %d", len(expected), len(result))
+	}
+
+	for i, e := range expected {
+		if result[i].name != e.name || result[i].workers != e.workers {
+			t.Errorf("expected shard %d to be %s with %d workers, got %s with %d workers", i, e.name, e.workers, result[i].name, result[i].workers)
+		}
+		// Every provider must belong to exactly one shard.
+		for _, provider := range e.providers {
+			for j, s := range result {
+				if s.contains(provider) != (i == j) {
+					t.Errorf("expected provider %q to belong to shard %s only, but shard %s contains it: %t", provider, e.name, s.name, s.contains(provider))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/controller/util"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+)
+
+// rolloutBlueGreen implements the logic for the BlueGreen strategy. The new machine set is scaled up
+// to all desired replicas next to the old machine sets, which are only scaled down once all new
+// machines are available.
+func (r *ReconcileMachineDeployment) rolloutBlueGreen(ctx context.Context, log *zap.SugaredLogger, d *clusterv1alpha1.MachineDeployment, msList []*clusterv1alpha1.MachineSet) error {
+	newMS, oldMSs, err := r.getAllMachineSetsAndSyncRevision(ctx, log, d, msList, true)
+	if err != nil {
+		return err
+	}
+
+	// newMS can be nil if there are changes, but no replacement of existing machines is needed.
+	if newMS == nil {
+		return nil
+	}
+
+	allMSs := append(oldMSs, newMS)
+
+	if _, err := r.scaleMachineSet(ctx, newMS, *(d.Spec.Replicas), d); err != nil {
+		return err
+	}
+
+	if err := r.syncDeploymentStatus(ctx, allMSs, newMS, d); err != nil {
+		return err
+	}
+
+	if newMS.Status.AvailableReplicas < *(d.Spec.Replicas) {
+		log.Debugw("Waiting for new machines to become available", "available", newMS.Status.AvailableReplicas, "desired", *(d.Spec.Replicas))
+		return nil
+	}
+
+	for _, ms := range util.FilterActiveMachineSets(oldMSs) {
+		if _, err := r.scaleMachineSet(ctx, ms, 0, d); err != nil {
+			return err
+		}
+	}
+
+	if err := r.syncDeploymentStatus(ctx, allMSs, newMS, d); err != nil {
+		return err
+	}
+
+	if util.DeploymentComplete(d, &d.Status) {
+		if err := r.cleanupDeployment(ctx, log, oldMSs, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRolloutBlueGreen(t *testing.T) {
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	strategy := &clusterv1alpha1.MachineDeploymentStrategy{Type: common.BlueGreenMachineDeploymentStrategyType}
+
+	t.Run("new machines are created next to the old ones", func(t *testing.T) {
+		md := rolloutTestDeployment(strategy, 2, "1")
+		oldMS := rolloutTestMachineSet(md, "old", "1", rolloutTestTemplate("1.33.0"), 2)
+
+		r, client, _ := newRolloutTestReconciler(t, md, oldMS)
+
+		if err := r.rolloutBlueGreen(ctx, log, md, []*clusterv1alpha1.MachineSet{oldMS}); err != nil {
+			t.Fatalf("failed to roll out: %v", err)
+		}
+
+		machineSets := &clusterv1alpha1.MachineSetList{}
+		if err := client.List(ctx, machineSets, ctrlruntimeclient.InNamespace(md.Namespace)); err != nil {
+			t.Fatalf("failed to list machine sets: %v", err)
+		}
+		if len(machineSets.Items) != 2 {
+			t.Fatalf("expected a new machine set to be created, got %d machine sets", len(machineSets.Items))
+		}
+		for _, ms := range machineSets.Items {
+			if *ms.Spec.Replicas != 2 {
+				t.Errorf("expected machine set %s to have 2 replicas, got %d", ms.Name, *ms.Spec.Replicas)
+			}
+		}
+	})
+
+	t.Run("old machines are removed once the new ones are available", func(t *testing.T) {
+		md := rolloutTestDeployment(strategy, 2, "2")
+		oldMS := rolloutTestMachineSet(md, "old", "1", rolloutTestTemplate("1.33.0"), 2)
+		newMS := rolloutTestMachineSet(md, "new", "2", rolloutTestTemplate("1.34.0"), 2)
+
+		r, client, _ := newRolloutTestReconciler(t, md, oldMS, newMS)
+
+		if err := r.rolloutBlueGreen(ctx, log, md, []*clusterv1alpha1.MachineSet{oldMS, newMS}); err != nil {
+			t.Fatalf("failed to roll out: %v", err)
+		}
+
+		if replicas := *getMachineSet(t, client, oldMS).Spec.Replicas; replicas != 0 {
+			t.Errorf("expected old machine set to be scaled down, got %d replicas", replicas)
+		}
+		if replicas := *getMachineSet(t, client, newMS).Spec.Replicas; replicas != 2 {
+			t.Errorf("expected new machine set to keep its replicas, got %d", replicas)
+		}
+	})
+
+	t.Run("old machines are kept while the new ones are not available", func(t *testing.T) {
+		md := rolloutTestDeployment(strategy, 2, "2")
+		oldMS := rolloutTestMachineSet(md, "old", "1", rolloutTestTemplate("1.33.0"), 2)
+		newMS := rolloutTestMachineSet(md, "new", "2", rolloutTestTemplate("1.34.0"), 2)
+		newMS.Status.AvailableReplicas = 1
+
+		r, client, _ := newRolloutTestReconciler(t, md, oldMS, newMS)
+
+		if err := r.rolloutBlueGreen(ctx, log, md, []*clusterv1alpha1.MachineSet{oldMS, newMS}); err != nil {
+			t.Fatalf("failed to roll out: %v", err)
+		}
+
+		if replicas := *getMachineSet(t, client, oldMS).Spec.Replicas; replicas != 2 {
+			t.Errorf("expected old machine set to keep its replicas, got %d", replicas)
+		}
+	})
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/controller/util"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// rolloutCanary implements the logic for the Canary strategy. The new machine set is scaled to the
+// canary replicas first. Once the nodes of all canary machines were Ready for the soak period and the
+// revision was approved, the remaining machines are replaced using a rolling update. If the node of a
+// canary machine is not Ready within the progress deadline, the deployment is rolled back to the
+// previous revision.
+func (r *ReconcileMachineDeployment) rolloutCanary(ctx context.Context, log *zap.SugaredLogger, d *clusterv1alpha1.MachineDeployment, msList []*clusterv1alpha1.MachineSet) (reconcile.Result, error) {
+	newMS, oldMSs, err := r.getAllMachineSetsAndSyncRevision(ctx, log, d, msList, true)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Without old machines there is nothing to protect, e.g. when the deployment is created.
+	if newMS == nil || len(util.FilterActiveMachineSets(oldMSs)) == 0 || newMS.Annotations[util.CanaryPromotedAnnotation] == "true" {
+		return reconcile.Result{}, r.rolloutRolling(ctx, log, d, msList)
+	}
+
+	log = log.With("newmachineset", ctrlruntimeclient.ObjectKeyFromObject(newMS))
+	allMSs := append(oldMSs, newMS)
+
+	canaryReplicas, err := util.CanaryReplicas(d)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to calculate canary replicas")
+	}
+
+	// The old machine sets keep their replicas while the canary machines soak.
+	if _, err := r.scaleMachineSet(ctx, newMS, canaryReplicas, d); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.syncDeploymentStatus(ctx, allMSs, newMS, d); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	machines, err := r.getMachinesForMachineSet(ctx, newMS)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	nodes, err := r.getNodesForMachines(ctx, machines)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	status := evaluateCanary(machines, nodes, canaryReplicas,
+		time.Duration(*d.Spec.ProgressDeadlineSeconds)*time.Second,
+		time.Duration(*d.Spec.Strategy.Canary.SoakSeconds)*time.Second,
+		time.Now())
+
+	if len(status.failed) > 0 {
+		return reconcile.Result{}, r.rollbackCanary(ctx, log, d, newMS, oldMSs, status.failed)
+	}
+
+	if status.soakRemaining > 0 {
+		log.Debugw("Waiting for canary machines to soak", "remaining", status.soakRemaining)
+		return reconcile.Result{RequeueAfter: status.soakRemaining}, nil
+	}
+
+	// Changes of the annotation trigger a reconcile, so there is no need to requeue.
+	revision := newMS.Annotations[util.RevisionAnnotation]
+	if !d.Spec.Strategy.Canary.AutoPromote && d.Annotations[util.CanaryApprovedRevisionAnnotation] != revision {
+		log.Debugw("Waiting for canary to be approved", "revision", revision)
+		r.recorder.Eventf(d, corev1.EventTypeNormal, "CanaryAwaitingApproval", "Canary machines of machine set %s passed the soak period, set the %s annotation to %q to replace the remaining machines", newMS.Name, util.CanaryApprovedRevisionAnnotation, revision)
+		return reconcile.Result{}, nil
+	}
+
+	// Mark the machine set as promoted, so the rolling update is not interrupted if a canary
+	// machine becomes unready later on.
+	msCopy := newMS.DeepCopy()
+	if msCopy.Annotations == nil {
+		msCopy.Annotations = map[string]string{}
+	}
+	msCopy.Annotations[util.CanaryPromotedAnnotation] = "true"
+	if err := r.Update(ctx, msCopy); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to promote machine set %s", newMS.Name)
+	}
+
+	log.Infow("Promoted canary MachineSet", "machines", len(machines))
+	r.recorder.Eventf(d, corev1.EventTypeNormal, "CanaryPromoted", "Canary machines of machine set %s passed the soak period and were promoted, replacing the remaining machines", newMS.Name)
+
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// canaryStatus is the state of the canary machines of a deployment.
+type canaryStatus struct {
+	// failed contains the names of the machines whose nodes were not Ready within the progress deadline.
+	failed []string
+	// soakRemaining is the time until the nodes of all canary machines were Ready for the soak period.
+	// It is zero once the canary machines passed the soak period.
+	soakRemaining time.Duration
+}
+
+// evaluateCanary calculates the state of the canary machines. nodes contains the nodes of the machines
+// that joined the cluster, keyed by machine name.
+func evaluateCanary(machines []*clusterv1alpha1.Machine, nodes map[string]*corev1.Node, canaryReplicas int32, progressDeadline, soak time.Duration, now time.Time) canaryStatus {
+	status := canaryStatus{}
+
+	// Not all canary machines have been created yet.
+	if int32(len(machines)) < canaryReplicas {
+		status.soakRemaining = soak
+	}
+
+	for _, machine := range machines {
+		node, joined := nodes[machine.Name]
+		if !joined {
+			if now.Sub(machine.CreationTimestamp.Time) > progressDeadline {
+				status.failed = append(status.failed, machine.Name)
+				continue
+			}
+			status.soakRemaining = max(status.soakRemaining, soak)
+			continue
+		}
+
+		ready := getNodeReadyCondition(node)
+		if ready == nil || ready.Status != corev1.ConditionTrue {
+			if now.Sub(machine.CreationTimestamp.Time) > progressDeadline {
+				status.failed = append(status.failed, machine.Name)
+				continue
+			}
+			status.soakRemaining = max(status.soakRemaining, soak)
+			continue
+		}
+		status.soakRemaining = max(status.soakRemaining, soak-now.Sub(ready.LastTransitionTime.Time))
+	}
+
+	return status
+}
+
+func getNodeReadyCondition(node *corev1.Node) *corev1.NodeCondition {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == corev1.NodeReady {
+			return &node.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// getNodesForMachines returns the nodes of the machines that joined the cluster, keyed by machine name.
+func (r *ReconcileMachineDeployment) getNodesForMachines(ctx context.Context, machines []*clusterv1alpha1.Machine) (map[string]*corev1.Node, error) {
+	nodes := map[string]*corev1.Node{}
+	for _, machine := range machines {
+		if machine.Status.NodeRef == nil {
+			continue
+		}
+
+		node := &corev1.Node{}
+		if err := r.Get(ctx, types.NamespacedName{Name: machine.Status.NodeRef.Name}, node); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get node %s of machine %s", machine.Status.NodeRef.Name, machine.Name)
+		}
+		nodes[machine.Name] = node
+	}
+	return nodes, nil
+}
+
+// rollbackCanary reverts the deployment's template to the template of the previous revision. The machine
+// set of the previous revision becomes the new machine set again and the rolling update replaces the failed
+// canary machines.
+func (r *ReconcileMachineDeployment) rollbackCanary(ctx context.Context, log *zap.SugaredLogger, d *clusterv1alpha1.MachineDeployment, newMS *clusterv1alpha1.MachineSet, oldMSs []*clusterv1alpha1.MachineSet, failed []string) error {
+	previous := findPreviousMachineSet(log, oldMSs)
+	if previous == nil {
+		r.recorder.Eventf(d, corev1.EventTypeWarning, "CanaryFailed", "Nodes of machines %s of machine set %s were not Ready in time and there is no previous revision to roll back to", strings.Join(failed, ", "), newMS.Name)
+		return nil
+	}
+
+	// The previous revision was serving before, so rolling back to it does not need another canary.
+	if previous.Annotations[util.CanaryPromotedAnnotation] != "true" {
+		msCopy := previous.DeepCopy()
+		if msCopy.Annotations == nil {
+			msCopy.Annotations = map[string]string{}
+		}
+		msCopy.Annotations[util.CanaryPromotedAnnotation] = "true"
+		if err := r.Update(ctx, msCopy); err != nil {
+			return errors.Wrapf(err, "failed to promote machine set %s", previous.Name)
+		}
+	}
+
+	if err := r.updateMachineDeployment(ctx, d, func(md *clusterv1alpha1.MachineDeployment) {
+		util.SetFromMachineSetTemplate(md, *previous.Spec.Template.DeepCopy())
+	}); err != nil {
+		return errors.Wrap(err, "failed to roll back template")
+	}
+
+	log.Infow("Rolled back canary", "machineset", ctrlruntimeclient.ObjectKeyFromObject(previous), "failed", failed)
+	r.recorder.Eventf(d, corev1.EventTypeWarning, "CanaryRollback", "Nodes of machines %s of machine set %s were not Ready in time, rolled back to the template of machine set %s", strings.Join(failed, ", "), newMS.Name, previous.Name)
+
+	return nil
+}
+
+// findPreviousMachineSet returns the old machine set with the highest revision.
+func findPreviousMachineSet(log *zap.SugaredLogger, oldMSs []*clusterv1alpha1.MachineSet) *clusterv1alpha1.MachineSet {
+	var (
+		previous    *clusterv1alpha1.MachineSet
+		maxRevision int64
+	)
+	for _, ms := range oldMSs {
+		if ms.DeletionTimestamp != nil {
+			continue
+		}
+		revision, err := util.Revision(ms)
+		if err != nil {
+			log.Debugw("Couldn't parse revision for MachineSet", "machineset", ctrlruntimeclient.ObjectKeyFromObject(ms), zap.Error(err))
+			continue
+		}
+		if previous == nil || revision > maxRevision {
+			previous = ms
+			maxRevision = revision
+		}
+	}
+	return previous
+}
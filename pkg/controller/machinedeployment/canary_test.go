@@ -0,0 +1,422 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/controller/util"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newRolloutTestReconciler returns a reconciler using a fake client with the given objects.
+func newRolloutTestReconciler(t *testing.T, objs ...ctrlruntimeclient.Object) (*ReconcileMachineDeployment, ctrlruntimeclient.Client, *record.FakeRecorder) {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add api to scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core api to scheme: %v", err)
+	}
+	client := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&clusterv1alpha1.MachineDeployment{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	return &ReconcileMachineDeployment{
+		Client:   client,
+		scheme:   scheme,
+		recorder: recorder,
+	}, client, recorder
+}
+
+func rolloutTestTemplate(kubelet string) clusterv1alpha1.MachineTemplateSpec {
+	return clusterv1alpha1.MachineTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "bar"}},
+		Spec: clusterv1alpha1.MachineSpec{
+			Versions: clusterv1alpha1.MachineVersionInfo{Kubelet: kubelet},
+		},
+	}
+}
+
+// rolloutTestDeployment returns a defaulted deployment with the template of kubelet 1.34.0.
+func rolloutTestDeployment(strategy *clusterv1alpha1.MachineDeploymentStrategy, replicas int32, revision string) *clusterv1alpha1.MachineDeployment {
+	md := &clusterv1alpha1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "md",
+			Namespace:   metav1.NamespaceSystem,
+			UID:         "md",
+			Annotations: map[string]string{util.RevisionAnnotation: revision},
+		},
+		Spec: clusterv1alpha1.MachineDeploymentSpec{
+			Replicas: ptr.To(replicas),
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar"}},
+			Template: rolloutTestTemplate("1.34.0"),
+			Strategy: strategy,
+		},
+	}
+	clusterv1alpha1.PopulateDefaultsMachineDeployment(md)
+	return md
+}
+
+// rolloutTestMachineSet returns a machine set of the deployment with all of its replicas available.
+func rolloutTestMachineSet(md *clusterv1alpha1.MachineDeployment, uid types.UID, revision string, tpl clusterv1alpha1.MachineTemplateSpec, replicas int32) *clusterv1alpha1.MachineSet {
+	tpl.Labels = util.CloneAndAddLabel(tpl.Labels, util.DefaultMachineDeploymentUniqueLabelKey, string(uid))
+	return &clusterv1alpha1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "md-" + string(uid),
+			Namespace:       md.Namespace,
+			UID:             uid,
+			Labels:          tpl.Labels,
+			Annotations:     map[string]string{util.RevisionAnnotation: revision},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(md, controllerKind)},
+		},
+		Spec: clusterv1alpha1.MachineSetSpec{
+			Replicas: ptr.To(replicas),
+			Selector: metav1.LabelSelector{MatchLabels: tpl.Labels},
+			Template: tpl,
+		},
+		Status: clusterv1alpha1.MachineSetStatus{
+			Replicas:          replicas,
+			ReadyReplicas:     replicas,
+			AvailableReplicas: replicas,
+		},
+	}
+}
+
+func getMachineSet(t *testing.T, client ctrlruntimeclient.Client, ms *clusterv1alpha1.MachineSet) *clusterv1alpha1.MachineSet {
+	t.Helper()
+	updated := &clusterv1alpha1.MachineSet{}
+	if err := client.Get(context.Background(), ctrlruntimeclient.ObjectKeyFromObject(ms), updated); err != nil {
+		t.Fatalf("failed to get machine set: %v", err)
+	}
+	return updated
+}
+
+func hasEvent(recorder *record.FakeRecorder, reason string) bool {
+	for {
+		select {
+		case event := <-recorder.Events:
+			if strings.Contains(event, " "+reason+" ") {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
+
+func TestEvaluateCanary(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	progressDeadline := 10 * time.Minute
+	soak := 5 * time.Minute
+
+	machine := func(name string, age time.Duration) *clusterv1alpha1.Machine {
+		return &clusterv1alpha1.Machine{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(now.Add(-age)),
+		}}
+	}
+	node := func(status corev1.ConditionStatus, readyFor time.Duration) *corev1.Node {
+		return &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+			Type:               corev1.NodeReady,
+			Status:             status,
+			LastTransitionTime: metav1.NewTime(now.Add(-readyFor)),
+		}}}}
+	}
+
+	testCases := []struct {
+		name           string
+		machines       []*clusterv1alpha1.Machine
+		nodes          map[string]*corev1.Node
+		canaryReplicas int32
+		expected       canaryStatus
+	}{
+		{
+			name:           "machines not created yet",
+			canaryReplicas: 1,
+			expected:       canaryStatus{soakRemaining: soak},
+		},
+		{
+			name:           "machine joining",
+			machines:       []*clusterv1alpha1.Machine{machine("a", time.Minute)},
+			canaryReplicas: 1,
+			expected:       canaryStatus{soakRemaining: soak},
+		},
+		{
+			name:           "machine not joined within progress deadline",
+			machines:       []*clusterv1alpha1.Machine{machine("a", 11*time.Minute), machine("b", time.Minute)},
+			canaryReplicas: 2,
+			expected:       canaryStatus{failed: []string{"a"}, soakRemaining: soak},
+		},
+		{
+			name:           "node not ready yet",
+			machines:       []*clusterv1alpha1.Machine{machine("a", 9*time.Minute)},
+			nodes:          map[string]*corev1.Node{"a": node(corev1.ConditionFalse, 8*time.Minute)},
+			canaryReplicas: 1,
+			expected:       canaryStatus{soakRemaining: soak},
+		},
+		{
+			name:           "node not ready within progress deadline",
+			machines:       []*clusterv1alpha1.Machine{machine("a", 11*time.Minute)},
+			nodes:          map[string]*corev1.Node{"a": node(corev1.ConditionFalse, 8*time.Minute)},
+			canaryReplicas: 1,
+			expected:       canaryStatus{failed: []string{"a"}},
+		},
+		{
+			name:     "nodes soaking",
+			machines: []*clusterv1alpha1.Machine{machine("a", 11*time.Minute), machine("b", 11*time.Minute)},
+			nodes: map[string]*corev1.Node{
+				"a": node(corev1.ConditionTrue, 4*time.Minute),
+				"b": node(corev1.ConditionTrue, 2*time.Minute),
+			},
+			canaryReplicas: 2,
+			expected:       canaryStatus{soakRemaining: 3 * time.Minute},
+		},
+		{
+			name:           "nodes soaked",
+			machines:       []*clusterv1alpha1.Machine{machine("a", 11*time.Minute)},
+			nodes:          map[string]*corev1.Node{"a": node(corev1.ConditionTrue, 6*time.Minute)},
+			canaryReplicas: 1,
+			expected:       canaryStatus{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := evaluateCanary(tc.machines, tc.nodes, tc.canaryReplicas, progressDeadline, soak, now)
+			if !reflect.DeepEqual(status, tc.expected) {
+				t.Errorf("expected %+v, got %+v", tc.expected, status)
+			}
+		})
+	}
+}
+
+func canaryStrategy(autoPromote bool) *clusterv1alpha1.MachineDeploymentStrategy {
+	return &clusterv1alpha1.MachineDeploymentStrategy{
+		Type: common.CanaryMachineDeploymentStrategyType,
+		Canary: &clusterv1alpha1.MachineCanaryDeployment{
+			SoakSeconds: ptr.To[int32](0),
+			AutoPromote: autoPromote,
+		},
+	}
+}
+
+func TestRolloutCanaryPromotion(t *testing.T) {
+	testCases := []struct {
+		name            string
+		autoPromote     bool
+		approved        string
+		expectPromotion bool
+	}{
+		{
+			name: "waits for approval",
+		},
+		{
+			name:     "approval of another revision is ignored",
+			approved: "1",
+		},
+		{
+			name:            "promoted once approved",
+			approved:        "2",
+			expectPromotion: true,
+		},
+		{
+			name:            "promoted automatically",
+			autoPromote:     true,
+			expectPromotion: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			md := rolloutTestDeployment(canaryStrategy(tc.autoPromote), 2, "2")
+			if tc.approved != "" {
+				md.Annotations[util.CanaryApprovedRevisionAnnotation] = tc.approved
+			}
+			oldMS := rolloutTestMachineSet(md, "old", "1", rolloutTestTemplate("1.33.0"), 2)
+			newMS := rolloutTestMachineSet(md, "new", "2", rolloutTestTemplate("1.34.0"), 1)
+
+			machine := &clusterv1alpha1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "canary",
+					Namespace:       md.Namespace,
+					Labels:          newMS.Spec.Template.Labels,
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(newMS, clusterv1alpha1.SchemeGroupVersion.WithKind("MachineSet"))},
+				},
+				Status: clusterv1alpha1.MachineStatus{NodeRef: &corev1.ObjectReference{Kind: "Node", Name: "canary"}},
+			}
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "canary"},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute)),
+				}}},
+			}
+
+			r, client, recorder := newRolloutTestReconciler(t, md, oldMS, newMS, machine, node)
+
+			result, err := r.rolloutCanary(ctx, zap.NewNop().Sugar(), md, []*clusterv1alpha1.MachineSet{oldMS, newMS})
+			if err != nil {
+				t.Fatalf("failed to roll out: %v", err)
+			}
+
+			promoted := getMachineSet(t, client, newMS).Annotations[util.CanaryPromotedAnnotation] == "true"
+			if promoted != tc.expectPromotion {
+				t.Errorf("expected canary to be promoted: %t, got %t", tc.expectPromotion, promoted)
+			}
+			if result.Requeue != tc.expectPromotion {
+				t.Errorf("expected requeue after promotion: %t, got %t", tc.expectPromotion, result.Requeue)
+			}
+			if !tc.expectPromotion && !hasEvent(recorder, "CanaryAwaitingApproval") {
+				t.Error("expected an event asking for approval")
+			}
+			if replicas := *getMachineSet(t, client, oldMS).Spec.Replicas; replicas != 2 {
+				t.Errorf("expected old machine set to keep its replicas, got %d", replicas)
+			}
+		})
+	}
+}
+
+func TestRollbackCanary(t *testing.T) {
+	ctx := context.Background()
+
+	md := rolloutTestDeployment(canaryStrategy(false), 2, "3")
+	olderMS := rolloutTestMachineSet(md, "older", "1", rolloutTestTemplate("1.32.0"), 0)
+	oldMS := rolloutTestMachineSet(md, "old", "2", rolloutTestTemplate("1.33.0"), 2)
+	newMS := rolloutTestMachineSet(md, "new", "3", rolloutTestTemplate("1.34.0"), 1)
+
+	r, client, recorder := newRolloutTestReconciler(t, md, olderMS, oldMS, newMS)
+
+	if err := r.rollbackCanary(ctx, zap.NewNop().Sugar(), md, newMS, []*clusterv1alpha1.MachineSet{olderMS, oldMS}, []string{"canary"}); err != nil {
+		t.Fatalf("failed to roll back: %v", err)
+	}
+
+	updated := &clusterv1alpha1.MachineDeployment{}
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(md), updated); err != nil {
+		t.Fatalf("failed to get machine deployment: %v", err)
+	}
+	if kubelet := updated.Spec.Template.Spec.Versions.Kubelet; kubelet != "1.33.0" {
+		t.Errorf("expected template of the previous revision with kubelet 1.33.0, got %q", kubelet)
+	}
+	if _, ok := updated.Spec.Template.Labels[util.DefaultMachineDeploymentUniqueLabelKey]; ok {
+		t.Error("expected hash label not to be copied into the deployment")
+	}
+	if getMachineSet(t, client, oldMS).Annotations[util.CanaryPromotedAnnotation] != "true" {
+		t.Error("expected previous machine set to be promoted, so it does not need another canary")
+	}
+	if !hasEvent(recorder, "CanaryRollback") {
+		t.Error("expected a rollback event")
+	}
+}
+
+func TestRollbackCanaryWithoutPreviousRevision(t *testing.T) {
+	ctx := context.Background()
+
+	md := rolloutTestDeployment(canaryStrategy(false), 2, "1")
+	newMS := rolloutTestMachineSet(md, "new", "1", rolloutTestTemplate("1.34.0"), 1)
+
+	r, client, recorder := newRolloutTestReconciler(t, md, newMS)
+
+	if err := r.rollbackCanary(ctx, zap.NewNop().Sugar(), md, newMS, nil, []string{"canary"}); err != nil {
+		t.Fatalf("failed to roll back: %v", err)
+	}
+
+	updated := &clusterv1alpha1.MachineDeployment{}
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(md), updated); err != nil {
+		t.Fatalf("failed to get machine deployment: %v", err)
+	}
+	if kubelet := updated.Spec.Template.Spec.Versions.Kubelet; kubelet != "1.34.0" {
+		t.Errorf("expected template to be unchanged, got kubelet %q", kubelet)
+	}
+	if !hasEvent(recorder, "CanaryFailed") {
+		t.Error("expected an event about the failed canary")
+	}
+}
+
+func TestGetNewMachineSetCapsCanaryReplicas(t *testing.T) {
+	testCases := []struct {
+		name             string
+		withOldMachines  bool
+		expectedReplicas int32
+	}{
+		{
+			name:             "new machines are capped to the canary replicas",
+			withOldMachines:  true,
+			expectedReplicas: 2,
+		},
+		{
+			name:             "machines of a new deployment are not capped",
+			expectedReplicas: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			strategy := canaryStrategy(false)
+			strategy.Canary.Replicas = ptr.To(intstr.FromInt(2))
+			strategy.RollingUpdate = &clusterv1alpha1.MachineRollingUpdateDeployment{MaxSurge: ptr.To(intstr.FromInt(3))}
+			md := rolloutTestDeployment(strategy, 3, "1")
+
+			var (
+				objs   = []ctrlruntimeclient.Object{md}
+				oldMSs []*clusterv1alpha1.MachineSet
+			)
+			if tc.withOldMachines {
+				oldMS := rolloutTestMachineSet(md, "old", "1", rolloutTestTemplate("1.33.0"), 3)
+				objs = append(objs, oldMS)
+				oldMSs = append(oldMSs, oldMS)
+			}
+
+			r, _, _ := newRolloutTestReconciler(t, objs...)
+
+			newMS, err := r.getNewMachineSet(ctx, zap.NewNop().Sugar(), md, oldMSs, oldMSs, true)
+			if err != nil {
+				t.Fatalf("failed to get new machine set: %v", err)
+			}
+			if replicas := *newMS.Spec.Replicas; replicas != tc.expectedReplicas {
+				t.Errorf("expected new machine set with %d replicas, got %d", tc.expectedReplicas, replicas)
+			}
+		})
+	}
+}
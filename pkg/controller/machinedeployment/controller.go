@@ -182,9 +182,22 @@ func (r *ReconcileMachineDeployment) reconcile(ctx context.Context, log *zap.Sug
 		return reconcile.Result{}, r.sync(ctx, log, d, msList)
 	}
 
+	// Rollback is not re-entrant in case the underlying machine sets are updated with a new
+	// revision, so we should ensure that we won't proceed to update machine sets until we make
+	// sure that the deployment has cleaned up its rollback spec in subsequent enqueues.
+	if d.Spec.RollbackTo != nil {
+		return reconcile.Result{}, r.rollback(ctx, log, d, msList)
+	}
+
 	switch d.Spec.Strategy.Type {
 	case common.RollingUpdateMachineDeploymentStrategyType:
 		return reconcile.Result{}, r.rolloutRolling(ctx, log, d, msList)
+	case common.InPlaceMachineDeploymentStrategyType:
+		return reconcile.Result{}, r.rolloutInPlace(ctx, log, d, msList)
+	case common.CanaryMachineDeploymentStrategyType:
+		return r.rolloutCanary(ctx, log, d, msList)
+	case common.BlueGreenMachineDeploymentStrategyType:
+		return reconcile.Result{}, r.rolloutBlueGreen(ctx, log, d, msList)
 	}
 
 	return reconcile.Result{}, errors.Errorf("unexpected deployment strategy type: %s", d.Spec.Strategy.Type)
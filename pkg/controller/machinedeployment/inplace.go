@@ -0,0 +1,256 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/controller/util"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rolloutInPlace implements the logic for the InPlace strategy. If the template only changed
+// in labels, annotations, including kubelet flags, or taints, the current machine set, its machines
+// and their nodes are updated in place. All other changes are rolled out using a rolling update.
+func (r *ReconcileMachineDeployment) rolloutInPlace(ctx context.Context, log *zap.SugaredLogger, d *clusterv1alpha1.MachineDeployment, msList []*clusterv1alpha1.MachineSet) error {
+	if util.FindNewMachineSet(d, msList) == nil {
+		if ms := findInPlaceUpdatableMachineSet(d, msList); ms != nil {
+			if err := r.updateMachineSetInPlace(ctx, log.With("machineset", ctrlruntimeclient.ObjectKeyFromObject(ms)), d, ms); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The updated machine set is now the new machine set, so the rolling update only takes
+	// care of scaling, status and cleanup. If nothing was updated in place, the ProviderSpec
+	// or the Versions changed and the machines are replaced.
+	return r.rolloutRolling(ctx, log, d, msList)
+}
+
+// findInPlaceUpdatableMachineSet returns the newest machine set whose template only differs in fields
+// that can be updated in place from the deployment's template.
+func findInPlaceUpdatableMachineSet(d *clusterv1alpha1.MachineDeployment, msList []*clusterv1alpha1.MachineSet) *clusterv1alpha1.MachineSet {
+	sorted := make([]*clusterv1alpha1.MachineSet, len(msList))
+	copy(sorted, msList)
+	sort.Sort(sort.Reverse(util.MachineSetsByCreationTimestamp(sorted)))
+
+	for _, ms := range sorted {
+		if util.EqualIgnoreInPlaceFields(&ms.Spec.Template, &d.Spec.Template) {
+			return ms
+		}
+	}
+	return nil
+}
+
+// updateMachineSetInPlace applies the changes between the machine set's template and the deployment's
+// template to all machines of the machine set and their nodes, then updates the machine set's template.
+// The machine set is updated last, so that a failed attempt is retried with the same diff.
+func (r *ReconcileMachineDeployment) updateMachineSetInPlace(ctx context.Context, log *zap.SugaredLogger, d *clusterv1alpha1.MachineDeployment, ms *clusterv1alpha1.MachineSet) error {
+	oldTemplate := ms.Spec.Template.DeepCopy()
+	newTemplate := d.Spec.Template.DeepCopy()
+	// Keep the hash label, it is part of the machine set's selector.
+	newTemplate.Labels = util.CloneAndAddLabel(newTemplate.Labels, util.DefaultMachineDeploymentUniqueLabelKey, oldTemplate.Labels[util.DefaultMachineDeploymentUniqueLabelKey])
+
+	machines, err := r.getMachinesForMachineSet(ctx, ms)
+	if err != nil {
+		return err
+	}
+
+	for _, machine := range machines {
+		machineLog := log.With("machine", ctrlruntimeclient.ObjectKeyFromObject(machine))
+		if err := r.updateMachineInPlace(ctx, machineLog, machine, oldTemplate, newTemplate); err != nil {
+			return errors.Wrapf(err, "failed to update machine %s in place", machine.Name)
+		}
+	}
+
+	ms.Spec.Template = *newTemplate
+	if err := r.Update(ctx, ms); err != nil {
+		return errors.Wrapf(err, "failed to update template of machine set %s", ms.Name)
+	}
+
+	log.Infow("Updated MachineSet in place", "machines", len(machines))
+	r.recorder.Eventf(d, corev1.EventTypeNormal, "InPlaceUpdate", "Updated %d machines of machine set %s in place", len(machines), ms.Name)
+
+	return nil
+}
+
+// getMachinesForMachineSet returns all machines controlled by the given machine set that are not being deleted.
+func (r *ReconcileMachineDeployment) getMachinesForMachineSet(ctx context.Context, ms *clusterv1alpha1.MachineSet) ([]*clusterv1alpha1.Machine, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&ms.Spec.Selector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse machine set %q label selector", ms.Name)
+	}
+
+	machineList := &clusterv1alpha1.MachineList{}
+	if err := r.List(ctx, machineList, ctrlruntimeclient.InNamespace(ms.Namespace), ctrlruntimeclient.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, errors.Wrap(err, "failed to list machines")
+	}
+
+	machines := make([]*clusterv1alpha1.Machine, 0, len(machineList.Items))
+	for idx := range machineList.Items {
+		machine := &machineList.Items[idx]
+		if machine.DeletionTimestamp != nil || !metav1.IsControlledBy(machine, ms) {
+			continue
+		}
+		machines = append(machines, machine)
+	}
+
+	return machines, nil
+}
+
+// updateMachineInPlace patches the machine and its node with the changes between the old and the new template.
+// Labels, annotations and taints not managed by the template are left untouched.
+func (r *ReconcileMachineDeployment) updateMachineInPlace(ctx context.Context, log *zap.SugaredLogger, machine *clusterv1alpha1.Machine, oldTemplate, newTemplate *clusterv1alpha1.MachineTemplateSpec) error {
+	updated := machine.DeepCopy()
+	changed := applyMapChanges(&updated.Labels, oldTemplate.Labels, newTemplate.Labels)
+	changed = applyMapChanges(&updated.Annotations, oldTemplate.Annotations, newTemplate.Annotations) || changed
+	changed = applyMapChanges(&updated.Spec.Labels, oldTemplate.Spec.Labels, newTemplate.Spec.Labels) || changed
+	changed = applyMapChanges(&updated.Spec.Annotations, oldTemplate.Spec.Annotations, newTemplate.Spec.Annotations) || changed
+	changed = applyTaintChanges(&updated.Spec.Taints, oldTemplate.Spec.Taints, newTemplate.Spec.Taints) || changed
+
+	if changed {
+		if err := r.Patch(ctx, updated, ctrlruntimeclient.MergeFrom(machine)); err != nil {
+			return err
+		}
+		log.Debug("Updated Machine in place")
+		r.recorder.Event(updated, corev1.EventTypeNormal, "InPlaceUpdate", "Updated labels/annotations/taints in place")
+	}
+
+	// The node is always reconciled, as a previous attempt might have
+	// updated the machine, but failed to update the node.
+	if machine.Status.NodeRef == nil {
+		return nil
+	}
+
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: machine.Status.NodeRef.Name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get node %s", machine.Status.NodeRef.Name)
+	}
+
+	updatedNode := node.DeepCopy()
+	changed = applyMapChanges(&updatedNode.Labels, oldTemplate.Spec.Labels, newTemplate.Spec.Labels)
+	changed = applyMapChanges(&updatedNode.Annotations, oldTemplate.Spec.Annotations, newTemplate.Spec.Annotations) || changed
+	changed = applyMapChanges(&updatedNode.Annotations, kubeletFlags(oldTemplate.Annotations), kubeletFlags(newTemplate.Annotations)) || changed
+	changed = applyTaintChanges(&updatedNode.Spec.Taints, oldTemplate.Spec.Taints, newTemplate.Spec.Taints) || changed
+
+	if !changed {
+		return nil
+	}
+
+	if err := r.Patch(ctx, updatedNode, ctrlruntimeclient.MergeFrom(node)); err != nil {
+		return errors.Wrapf(err, "failed to patch node %s", node.Name)
+	}
+	log.Debugw("Updated Node in place", "node", node.Name)
+
+	return nil
+}
+
+// kubeletFlags returns the annotations holding kubelet flags. They are set on the
+// machine, but also mirrored to the node so the change is visible on the node.
+func kubeletFlags(annotations map[string]string) map[string]string {
+	flags := map[string]string{}
+	for k, v := range annotations {
+		if strings.HasPrefix(k, common.KubeletFlagsGroupAnnotationPrefixV1+"/") {
+			flags[k] = v
+		}
+	}
+	return flags
+}
+
+// applyMapChanges removes all keys from current which were in oldDesired but are not in newDesired
+// and sets all keys of newDesired. It returns true if current was changed.
+func applyMapChanges(current *map[string]string, oldDesired, newDesired map[string]string) bool {
+	changed := false
+	for k := range oldDesired {
+		if _, exists := newDesired[k]; exists {
+			continue
+		}
+		if _, exists := (*current)[k]; exists {
+			delete(*current, k)
+			changed = true
+		}
+	}
+
+	for k, v := range newDesired {
+		if value, exists := (*current)[k]; exists && value == v {
+			continue
+		}
+		if *current == nil {
+			*current = map[string]string{}
+		}
+		(*current)[k] = v
+		changed = true
+	}
+
+	return changed
+}
+
+// applyTaintChanges works like applyMapChanges for taints, which are identified by key and effect.
+func applyTaintChanges(current *[]corev1.Taint, oldDesired, newDesired []corev1.Taint) bool {
+	var result []corev1.Taint
+	for _, t := range *current {
+		// Taints managed by the template are replaced by the new ones below.
+		if findTaint(oldDesired, t) != nil || findTaint(newDesired, t) != nil {
+			continue
+		}
+		result = append(result, t)
+	}
+	result = append(result, newDesired...)
+
+	if taintsEqual(*current, result) {
+		return false
+	}
+	*current = result
+	return true
+}
+
+func findTaint(taints []corev1.Taint, taint corev1.Taint) *corev1.Taint {
+	for i := range taints {
+		if taints[i].MatchTaint(&taint) {
+			return &taints[i]
+		}
+	}
+	return nil
+}
+
+func taintsEqual(a, b []corev1.Taint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, t := range a {
+		found := findTaint(b, t)
+		if found == nil || found.Value != t.Value {
+			return false
+		}
+	}
+	return true
+}
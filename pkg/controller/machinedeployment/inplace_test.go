@@ -0,0 +1,274 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestApplyMapChanges(t *testing.T) {
+	testCases := []struct {
+		name          string
+		current       map[string]string
+		oldDesired    map[string]string
+		newDesired    map[string]string
+		expected      map[string]string
+		expectChanged bool
+	}{
+		{
+			name:          "no changes",
+			current:       map[string]string{"a": "1", "other": "x"},
+			oldDesired:    map[string]string{"a": "1"},
+			newDesired:    map[string]string{"a": "1"},
+			expected:      map[string]string{"a": "1", "other": "x"},
+			expectChanged: false,
+		},
+		{
+			name:          "added, changed and removed keys",
+			current:       map[string]string{"a": "1", "b": "2", "other": "x"},
+			oldDesired:    map[string]string{"a": "1", "b": "2"},
+			newDesired:    map[string]string{"a": "3", "c": "4"},
+			expected:      map[string]string{"a": "3", "c": "4", "other": "x"},
+			expectChanged: true,
+		},
+		{
+			name:          "nil current",
+			oldDesired:    nil,
+			newDesired:    map[string]string{"a": "1"},
+			expected:      map[string]string{"a": "1"},
+			expectChanged: true,
+		},
+		{
+			name:          "keys not managed by the template are kept",
+			current:       map[string]string{"other": "x"},
+			oldDesired:    map[string]string{"a": "1"},
+			newDesired:    nil,
+			expected:      map[string]string{"other": "x"},
+			expectChanged: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := tc.current
+			changed := applyMapChanges(&current, tc.oldDesired, tc.newDesired)
+			if changed != tc.expectChanged {
+				t.Errorf("expected changed to be %t, got %t", tc.expectChanged, changed)
+			}
+			if !apiequality.Semantic.DeepEqual(current, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, current)
+			}
+		})
+	}
+}
+
+func TestApplyTaintChanges(t *testing.T) {
+	foo := corev1.Taint{Key: "foo", Value: "1", Effect: corev1.TaintEffectNoSchedule}
+	fooUpdated := corev1.Taint{Key: "foo", Value: "2", Effect: corev1.TaintEffectNoSchedule}
+	bar := corev1.Taint{Key: "bar", Effect: corev1.TaintEffectNoExecute}
+	unmanaged := corev1.Taint{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoSchedule}
+
+	testCases := []struct {
+		name          string
+		current       []corev1.Taint
+		oldDesired    []corev1.Taint
+		newDesired    []corev1.Taint
+		expected      []corev1.Taint
+		expectChanged bool
+	}{
+		{
+			name:          "no changes",
+			current:       []corev1.Taint{unmanaged, foo},
+			oldDesired:    []corev1.Taint{foo},
+			newDesired:    []corev1.Taint{foo},
+			expected:      []corev1.Taint{unmanaged, foo},
+			expectChanged: false,
+		},
+		{
+			name:          "value changed",
+			current:       []corev1.Taint{unmanaged, foo},
+			oldDesired:    []corev1.Taint{foo},
+			newDesired:    []corev1.Taint{fooUpdated},
+			expected:      []corev1.Taint{unmanaged, fooUpdated},
+			expectChanged: true,
+		},
+		{
+			name:          "taint added and removed",
+			current:       []corev1.Taint{foo, unmanaged},
+			oldDesired:    []corev1.Taint{foo},
+			newDesired:    []corev1.Taint{bar},
+			expected:      []corev1.Taint{unmanaged, bar},
+			expectChanged: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := tc.current
+			changed := applyTaintChanges(&current, tc.oldDesired, tc.newDesired)
+			if changed != tc.expectChanged {
+				t.Errorf("expected changed to be %t, got %t", tc.expectChanged, changed)
+			}
+			if !taintsEqual(current, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, current)
+			}
+		})
+	}
+}
+
+func TestFindInPlaceUpdatableMachineSet(t *testing.T) {
+	template := func(kubelet string, labels map[string]string, taints ...corev1.Taint) clusterv1alpha1.MachineTemplateSpec {
+		return clusterv1alpha1.MachineTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec: clusterv1alpha1.MachineSpec{
+				ObjectMeta:   metav1.ObjectMeta{Labels: labels},
+				Taints:       taints,
+				ProviderSpec: clusterv1alpha1.ProviderSpec{Value: &runtime.RawExtension{Raw: []byte(`{"cloudProvider":"fake"}`)}},
+				Versions:     clusterv1alpha1.MachineVersionInfo{Kubelet: kubelet},
+			},
+		}
+	}
+	machineSet := func(tpl clusterv1alpha1.MachineTemplateSpec) []*clusterv1alpha1.MachineSet {
+		return []*clusterv1alpha1.MachineSet{{Spec: clusterv1alpha1.MachineSetSpec{Template: tpl}}}
+	}
+	taint := corev1.Taint{Key: "foo", Effect: corev1.TaintEffectNoSchedule}
+	withKubeletFlag := func(tpl clusterv1alpha1.MachineTemplateSpec) clusterv1alpha1.MachineTemplateSpec {
+		common.SetKubeletFlags(&tpl, map[common.KubeletFlags]string{common.ExternalCloudProviderKubeletFlag: "true"})
+		return tpl
+	}
+	withNodeKubeletFlag := func(tpl clusterv1alpha1.MachineTemplateSpec) clusterv1alpha1.MachineTemplateSpec {
+		common.SetKubeletFlags(&tpl.Spec, map[common.KubeletFlags]string{common.ExternalCloudProviderKubeletFlag: "true"})
+		return tpl
+	}
+
+	testCases := []struct {
+		name          string
+		current       clusterv1alpha1.MachineTemplateSpec
+		desired       clusterv1alpha1.MachineTemplateSpec
+		expectInPlace bool
+	}{
+		{
+			name:          "labels and taints changed",
+			current:       template("1.33.0", map[string]string{"a": "1"}),
+			desired:       template("1.33.0", map[string]string{"a": "2"}, taint),
+			expectInPlace: true,
+		},
+		{
+			name:          "versions changed",
+			current:       template("1.33.0", map[string]string{"a": "1"}),
+			desired:       template("1.34.0", map[string]string{"a": "2"}),
+			expectInPlace: false,
+		},
+		{
+			name:          "kubelet flags of the machine changed",
+			current:       template("1.33.0", map[string]string{"a": "1"}),
+			desired:       withKubeletFlag(template("1.33.0", map[string]string{"a": "1"})),
+			expectInPlace: true,
+		},
+		{
+			name:          "kubelet flags of the node changed",
+			current:       template("1.33.0", map[string]string{"a": "1"}),
+			desired:       withNodeKubeletFlag(template("1.33.0", map[string]string{"a": "1"})),
+			expectInPlace: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &clusterv1alpha1.MachineDeployment{Spec: clusterv1alpha1.MachineDeploymentSpec{Template: tc.desired}}
+			ms := findInPlaceUpdatableMachineSet(d, machineSet(tc.current))
+			if (ms != nil) != tc.expectInPlace {
+				t.Errorf("expected in-place update to be %t, got %t", tc.expectInPlace, ms != nil)
+			}
+		})
+	}
+}
+
+func TestUpdateMachineInPlaceKubeletFlags(t *testing.T) {
+	flagKey := common.KubeletFlagsGroupAnnotationPrefixV1 + "/" + string(common.ExternalCloudProviderKubeletFlag)
+
+	testCases := []struct {
+		name string
+		// setFlag sets the kubelet flag in one of the annotation maps of the template.
+		setFlag func(tpl *clusterv1alpha1.MachineTemplateSpec)
+		// machineAnnotations returns the annotation map of the machine the flag is expected in.
+		machineAnnotations func(machine *clusterv1alpha1.Machine) map[string]string
+	}{
+		{
+			name: "kubelet flags of the machine",
+			setFlag: func(tpl *clusterv1alpha1.MachineTemplateSpec) {
+				common.SetKubeletFlags(tpl, map[common.KubeletFlags]string{common.ExternalCloudProviderKubeletFlag: "true"})
+			},
+			machineAnnotations: func(machine *clusterv1alpha1.Machine) map[string]string { return machine.Annotations },
+		},
+		{
+			name: "kubelet flags of the node",
+			setFlag: func(tpl *clusterv1alpha1.MachineTemplateSpec) {
+				common.SetKubeletFlags(&tpl.Spec, map[common.KubeletFlags]string{common.ExternalCloudProviderKubeletFlag: "true"})
+			},
+			machineAnnotations: func(machine *clusterv1alpha1.Machine) map[string]string { return machine.Spec.Annotations },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			machine := &clusterv1alpha1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine", Namespace: "kube-system"},
+				Status:     clusterv1alpha1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: "node"}},
+			}
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}
+			r, client, _ := newRolloutTestReconciler(t, machine, node)
+
+			oldTemplate := &clusterv1alpha1.MachineTemplateSpec{}
+			newTemplate := oldTemplate.DeepCopy()
+			tc.setFlag(newTemplate)
+
+			if err := r.updateMachineInPlace(context.Background(), zap.NewNop().Sugar(), machine, oldTemplate, newTemplate); err != nil {
+				t.Fatalf("failed to update machine in place: %v", err)
+			}
+
+			updatedMachine := &clusterv1alpha1.Machine{}
+			if err := client.Get(context.Background(), ctrlruntimeclient.ObjectKeyFromObject(machine), updatedMachine); err != nil {
+				t.Fatalf("failed to get machine: %v", err)
+			}
+			if value := tc.machineAnnotations(updatedMachine)[flagKey]; value != "true" {
+				t.Errorf("expected kubelet flag to be set on the machine, got %q", value)
+			}
+
+			updatedNode := &corev1.Node{}
+			if err := client.Get(context.Background(), types.NamespacedName{Name: node.Name}, updatedNode); err != nil {
+				t.Fatalf("failed to get node: %v", err)
+			}
+			if value := updatedNode.Annotations[flagKey]; value != "true" {
+				t.Errorf("expected kubelet flag to be set on the node, got %q", value)
+			}
+		})
+	}
+}
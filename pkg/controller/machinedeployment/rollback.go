@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	dutil "k8c.io/machine-controller/pkg/controller/util"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rollback the deployment to the specified revision. In any case cleanup the rollback spec.
+// The template is updated like any other change of the deployment, so it is validated by the
+// admission webhook.
+func (r *ReconcileMachineDeployment) rollback(ctx context.Context, log *zap.SugaredLogger, d *clusterv1alpha1.MachineDeployment, msList []*clusterv1alpha1.MachineSet) error {
+	newMS, allOldMSs, err := r.getAllMachineSetsAndSyncRevision(ctx, log, d, msList, true)
+	if err != nil {
+		return err
+	}
+
+	allMSs := allOldMSs
+	if newMS != nil {
+		allMSs = append(allMSs, newMS)
+	}
+
+	revision := d.Spec.RollbackTo.Revision
+	// If rollback revision is 0, rollback to the last revision.
+	if revision == 0 {
+		if revision = dutil.LastRevision(log, allMSs); revision == 0 {
+			// If we still can't find the last revision, gives up rollback.
+			r.recorder.Event(d, corev1.EventTypeWarning, dutil.RollbackRevisionNotFound, "Unable to find last revision.")
+			return r.clearRollbackTo(ctx, d)
+		}
+	}
+
+	for _, ms := range allMSs {
+		v, err := dutil.Revision(ms)
+		if err != nil {
+			log.Debugw("Failed to parse revision for MachineSet", "machineset", ctrlruntimeclient.ObjectKeyFromObject(ms), zap.Error(err))
+			continue
+		}
+		if v == revision {
+			return r.rollbackToTemplate(ctx, log, d, ms, revision)
+		}
+	}
+
+	r.recorder.Eventf(d, corev1.EventTypeWarning, dutil.RollbackRevisionNotFound, "Unable to find revision %d to roll back to.", revision)
+	return r.clearRollbackTo(ctx, d)
+}
+
+// rollbackToTemplate copies the template of the machine set into the deployment and clears the rollback
+// spec. If the template is rejected by the admission webhook, only the rollback spec is cleared.
+func (r *ReconcileMachineDeployment) rollbackToTemplate(ctx context.Context, log *zap.SugaredLogger, d *clusterv1alpha1.MachineDeployment, ms *clusterv1alpha1.MachineSet, revision int64) error {
+	if dutil.EqualIgnoreHash(&d.Spec.Template, &ms.Spec.Template) {
+		r.recorder.Eventf(d, corev1.EventTypeWarning, dutil.RollbackTemplateUnchanged, "The rollback revision %d contains the same template as current deployment.", revision)
+		return r.clearRollbackTo(ctx, d)
+	}
+
+	err := r.updateMachineDeployment(ctx, d, func(md *clusterv1alpha1.MachineDeployment) {
+		dutil.SetFromMachineSetTemplate(md, *ms.Spec.Template.DeepCopy())
+		// Set the deployment's annotations to the machine set's, so the change cause of the
+		// revision is restored.
+		dutil.SetDeploymentAnnotationsTo(md, ms)
+		md.Spec.RollbackTo = nil
+	})
+	switch {
+	case apierrors.IsInvalid(err) || apierrors.IsForbidden(err) || apierrors.IsBadRequest(err):
+		r.recorder.Eventf(d, corev1.EventTypeWarning, dutil.RollbackRejected, "The template of revision %d was rejected: %v", revision, err)
+		return r.clearRollbackTo(ctx, d)
+	case err != nil:
+		return errors.Wrapf(err, "failed to roll back to revision %d", revision)
+	}
+
+	log.Infow("Rolled back MachineDeployment", "revision", revision, "machineset", ctrlruntimeclient.ObjectKeyFromObject(ms))
+	r.recorder.Eventf(d, corev1.EventTypeNormal, dutil.RollbackDone, "Rolled back deployment %q to revision %d", d.Name, revision)
+
+	return nil
+}
+
+// clearRollbackTo removes the rollback spec from the deployment.
+func (r *ReconcileMachineDeployment) clearRollbackTo(ctx context.Context, d *clusterv1alpha1.MachineDeployment) error {
+	return r.updateMachineDeployment(ctx, d, func(md *clusterv1alpha1.MachineDeployment) {
+		md.Spec.RollbackTo = nil
+	})
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/controller/util"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRollback(t *testing.T) {
+	ctx := context.Background()
+
+	template := func(kubelet string) clusterv1alpha1.MachineTemplateSpec {
+		return clusterv1alpha1.MachineTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "bar"}},
+			Spec: clusterv1alpha1.MachineSpec{
+				Versions: clusterv1alpha1.MachineVersionInfo{Kubelet: kubelet},
+			},
+		}
+	}
+
+	md := &clusterv1alpha1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "md",
+			Namespace: metav1.NamespaceSystem,
+			UID:       "md",
+			Annotations: map[string]string{
+				util.RevisionAnnotation:    "2",
+				util.ChangeCauseAnnotation: "upgrade kubelet",
+			},
+		},
+		Spec: clusterv1alpha1.MachineDeploymentSpec{
+			Replicas:   ptr.To[int32](1),
+			Selector:   metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar"}},
+			Template:   template("1.34.0"),
+			RollbackTo: &clusterv1alpha1.RollbackConfig{},
+		},
+	}
+	clusterv1alpha1.PopulateDefaultsMachineDeployment(md)
+
+	machineSet := func(uid types.UID, revision, changeCause string, tpl clusterv1alpha1.MachineTemplateSpec) *clusterv1alpha1.MachineSet {
+		tpl.Labels = util.CloneAndAddLabel(tpl.Labels, util.DefaultMachineDeploymentUniqueLabelKey, string(uid))
+		return &clusterv1alpha1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "md-" + string(uid),
+				Namespace: md.Namespace,
+				UID:       uid,
+				Annotations: map[string]string{
+					util.RevisionAnnotation:    revision,
+					util.ChangeCauseAnnotation: changeCause,
+				},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(md, controllerKind)},
+			},
+			Spec: clusterv1alpha1.MachineSetSpec{
+				Replicas: ptr.To[int32](0),
+				Template: tpl,
+			},
+		}
+	}
+	oldMS := machineSet("old", "1", "initial", template("1.33.0"))
+	newMS := machineSet("new", "2", "upgrade kubelet", template("1.34.0"))
+
+	scheme := runtime.NewScheme()
+	if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add api to scheme: %v", err)
+	}
+	client := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(md, oldMS, newMS).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileMachineDeployment{
+		Client:   client,
+		scheme:   scheme,
+		recorder: recorder,
+	}
+
+	if err := r.rollback(ctx, zap.NewNop().Sugar(), md, []*clusterv1alpha1.MachineSet{oldMS, newMS}); err != nil {
+		t.Fatalf("failed to roll back: %v", err)
+	}
+
+	updated := &clusterv1alpha1.MachineDeployment{}
+	if err := client.Get(ctx, ctrlruntimeclient.ObjectKeyFromObject(md), updated); err != nil {
+		t.Fatalf("failed to get machine deployment: %v", err)
+	}
+	if updated.Spec.RollbackTo != nil {
+		t.Errorf("expected rollback spec to be cleared, got %+v", updated.Spec.RollbackTo)
+	}
+	if kubelet := updated.Spec.Template.Spec.Versions.Kubelet; kubelet != "1.33.0" {
+		t.Errorf("expected template of revision 1 with kubelet 1.33.0, got %q", kubelet)
+	}
+	if _, ok := updated.Spec.Template.Labels[util.DefaultMachineDeploymentUniqueLabelKey]; ok {
+		t.Error("expected hash label not to be copied into the deployment")
+	}
+	if changeCause := updated.Annotations[util.ChangeCauseAnnotation]; changeCause != "initial" {
+		t.Errorf("expected change cause of revision 1, got %q", changeCause)
+	}
+	if len(recorder.Events) != 1 {
+		t.Errorf("expected one rollback event, got %d", len(recorder.Events))
+	}
+
+	history := util.RolloutHistory(zap.NewNop().Sugar(), []*clusterv1alpha1.MachineSet{newMS, oldMS})
+	if len(history) != 2 || history[0].Revision != 1 || history[0].ChangeCause != "initial" || history[1].MachineSet != newMS {
+		t.Errorf("unexpected rollout history: %+v", history)
+	}
+}
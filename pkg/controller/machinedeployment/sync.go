@@ -27,6 +27,7 @@ import (
 	"go.uber.org/zap"
 
 	dutil "k8c.io/machine-controller/pkg/controller/util"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -168,6 +169,15 @@ func (r *ReconcileMachineDeployment) getNewMachineSet(ctx context.Context, log *
 		return nil, err
 	}
 
+	// A canary deployment only replaces the canary machines until they passed the soak period.
+	if d.Spec.Strategy.Type == common.CanaryMachineDeploymentStrategyType && len(dutil.FilterActiveMachineSets(oldMSs)) > 0 {
+		canaryReplicas, err := dutil.CanaryReplicas(d)
+		if err != nil {
+			return nil, err
+		}
+		newReplicasCount = min(newReplicasCount, canaryReplicas)
+	}
+
 	*(newMS.Spec.Replicas) = newReplicasCount
 
 	// Set new machine set's annotation
@@ -347,6 +357,7 @@ func calculateStatus(allMSs []*clusterv1alpha1.MachineSet, newMS *clusterv1alpha
 		ReadyReplicas:       dutil.GetReadyReplicaCountForMachineSets(allMSs),
 		AvailableReplicas:   availableReplicas,
 		UnavailableReplicas: unavailableReplicas,
+		Conditions:          dutil.SummarizeMachineSetConditions(allMSs),
 	}
 
 	return status
@@ -0,0 +1,367 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+
+	controllerutil "k8c.io/machine-controller/pkg/controller/util"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// ControllerName is the name of the MachineHealthCheck controller.
+	ControllerName = "machinehealthcheck-controller"
+)
+
+var (
+	machineSetKind = clusterv1alpha1.SchemeGroupVersion.WithKind("MachineSet")
+
+	// defaultMaxUnhealthy is used if a MachineHealthCheck does not set maxUnhealthy.
+	defaultMaxUnhealthy = intstr.FromString("100%")
+
+	// defaultNodeStartupTimeout is used if a MachineHealthCheck does not set nodeStartupTimeout.
+	defaultNodeStartupTimeout = 10 * time.Minute
+)
+
+type reconciler struct {
+	ctrlruntimeclient.Client
+	log      *zap.SugaredLogger
+	recorder record.EventRecorder
+	metrics  *Metrics
+}
+
+// Add creates a new MachineHealthCheck controller and adds it to the Manager.
+// It lists Machines by util.MachineNodeNameIndex, which must be added to the Manager.
+func Add(mgr manager.Manager, log *zap.SugaredLogger, metrics *Metrics) error {
+	r := &reconciler{
+		Client:   mgr.GetClient(),
+		log:      log.Named(ControllerName),
+		recorder: mgr.GetEventRecorderFor(ControllerName),
+		metrics:  metrics,
+	}
+
+	_, err := builder.ControllerManagedBy(mgr).
+		Named(ControllerName).
+		WithOptions(controller.Options{
+			LogConstructor: func(*reconcile.Request) logr.Logger {
+				// we log ourselves
+				return zapr.NewLogger(zap.NewNop())
+			},
+		}).
+		For(&clusterv1alpha1.MachineHealthCheck{}).
+		Watches(&clusterv1alpha1.Machine{}, handler.EnqueueRequestsFromMapFunc(r.machineToMachineHealthChecks)).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.nodeToMachineHealthChecks)).
+		Build(r)
+
+	return err
+}
+
+// Reconcile checks the machines selected by a MachineHealthCheck and remediates unhealthy ones.
+//
+// +kubebuilder:rbac:groups=cluster.k8s.io,resources=machinehealthchecks;machinehealthchecks/status,verbs=get;list;watch;update;patch
+func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := r.log.With("machinehealthcheck", request.NamespacedName)
+	log.Debug("Reconciling")
+
+	mhc := &clusterv1alpha1.MachineHealthCheck{}
+	if err := r.Get(ctx, request.NamespacedName, mhc); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.metrics.delete(request.Name, request.Namespace)
+			return reconcile.Result{}, nil
+		}
+		log.Errorw("Failed to get MachineHealthCheck", zap.Error(err))
+		return reconcile.Result{}, err
+	}
+
+	if mhc.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.reconcile(ctx, log, mhc)
+	if err != nil {
+		log.Errorw("Reconciling failed", zap.Error(err))
+		r.recorder.Eventf(mhc, corev1.EventTypeWarning, "ReconcileError", "%v", err)
+	}
+
+	return result, err
+}
+
+func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, mhc *clusterv1alpha1.MachineHealthCheck) (reconcile.Result, error) {
+	machines, err := r.getMachinesForHealthCheck(ctx, mhc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	now := time.Now()
+	var (
+		unhealthy []*clusterv1alpha1.Machine
+		reasons   = map[string]string{}
+		nextCheck time.Duration
+	)
+	for _, machine := range machines {
+		isUnhealthy, reason, next, err := r.checkMachine(ctx, machine, mhc, now)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to check machine %s: %w", machine.Name, err)
+		}
+		if isUnhealthy {
+			unhealthy = append(unhealthy, machine)
+			reasons[machine.Name] = reason
+		}
+		if next > 0 && (nextCheck == 0 || next < nextCheck) {
+			nextCheck = next
+		}
+	}
+
+	maxUnhealthy, err := getMaxUnhealthy(mhc, len(machines))
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get maxUnhealthy: %w", err)
+	}
+
+	r.metrics.ExpectedMachines.WithLabelValues(mhc.Name, mhc.Namespace).Set(float64(len(machines)))
+	r.metrics.UnhealthyMachines.WithLabelValues(mhc.Name, mhc.Namespace).Set(float64(len(unhealthy)))
+
+	newStatus := clusterv1alpha1.MachineHealthCheckStatus{
+		ExpectedMachines:   int32(len(machines)),
+		CurrentHealthy:     int32(len(machines) - len(unhealthy)),
+		ObservedGeneration: mhc.Generation,
+	}
+	if remediationsAllowed := maxUnhealthy - len(unhealthy); remediationsAllowed > 0 {
+		newStatus.RemediationsAllowed = int32(remediationsAllowed)
+	}
+	if err := r.updateStatus(ctx, mhc, newStatus); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if len(unhealthy) > maxUnhealthy {
+		log.Infow("Skipping remediation, too many machines are unhealthy", "unhealthy", len(unhealthy), "maxUnhealthy", maxUnhealthy)
+		r.recorder.Eventf(mhc, corev1.EventTypeWarning, "RemediationRestricted", "Remediation skipped, %d of %d machines are unhealthy, but at most %d may be", len(unhealthy), len(machines), maxUnhealthy)
+		r.metrics.RemediationsRestricted.WithLabelValues(mhc.Name, mhc.Namespace).Inc()
+		return reconcile.Result{RequeueAfter: nextCheck}, nil
+	}
+
+	for _, machine := range unhealthy {
+		machineLog := log.With("machine", ctrlruntimeclient.ObjectKeyFromObject(machine))
+		if err := r.remediate(ctx, machineLog, mhc, machine, reasons[machine.Name]); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: nextCheck}, nil
+}
+
+// getMachinesForHealthCheck returns all machines selected by the health check that are not being deleted.
+func (r *reconciler) getMachinesForHealthCheck(ctx context.Context, mhc *clusterv1alpha1.MachineHealthCheck) ([]*clusterv1alpha1.Machine, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&mhc.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse label selector: %w", err)
+	}
+
+	// An empty selector matches nothing, not everything.
+	if selector.Empty() {
+		return nil, nil
+	}
+
+	machineList := &clusterv1alpha1.MachineList{}
+	if err := r.List(ctx, machineList, ctrlruntimeclient.InNamespace(mhc.Namespace), ctrlruntimeclient.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list machines: %w", err)
+	}
+
+	machines := make([]*clusterv1alpha1.Machine, 0, len(machineList.Items))
+	for idx := range machineList.Items {
+		if machineList.Items[idx].DeletionTimestamp != nil {
+			continue
+		}
+		machines = append(machines, &machineList.Items[idx])
+	}
+
+	return machines, nil
+}
+
+// checkMachine returns whether the machine is unhealthy and why. If the machine is healthy, but one
+// of the conditions or the node startup timeout might time out later on, the duration until then
+// is returned.
+func (r *reconciler) checkMachine(ctx context.Context, machine *clusterv1alpha1.Machine, mhc *clusterv1alpha1.MachineHealthCheck, now time.Time) (bool, string, time.Duration, error) {
+	if machine.Status.NodeRef == nil {
+		unhealthy, next := checkNodeStartup(machine, mhc, now)
+		return unhealthy, "node did not join the cluster", next, nil
+	}
+
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: machine.Status.NodeRef.Name}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			unhealthy, next := checkNodeStartup(machine, mhc, now)
+			return unhealthy, fmt.Sprintf("node %s not found", machine.Status.NodeRef.Name), next, nil
+		}
+		return false, "", 0, err
+	}
+
+	unhealthy, reason, next := checkNodeConditions(node, mhc.Spec.UnhealthyConditions, now)
+	return unhealthy, reason, next, nil
+}
+
+// checkNodeStartup returns whether a machine without a node has been without one for longer than
+// the node startup timeout. Otherwise it returns the duration until the timeout is reached. The
+// time is measured from the creation of the machine or the last time its node joined or was lost,
+// whichever is later.
+func checkNodeStartup(machine *clusterv1alpha1.Machine, mhc *clusterv1alpha1.MachineHealthCheck, now time.Time) (bool, time.Duration) {
+	timeout := defaultNodeStartupTimeout
+	if mhc.Spec.NodeStartupTimeout != nil {
+		timeout = mhc.Spec.NodeStartupTimeout.Duration
+	}
+	if timeout == 0 {
+		return false, 0
+	}
+
+	since := machine.CreationTimestamp.Time
+	for _, condition := range machine.Status.Conditions {
+		if condition.Type == common.NodeJoinedCondition && condition.LastTransitionTime.After(since) {
+			since = condition.LastTransitionTime.Time
+		}
+	}
+
+	if elapsed := now.Sub(since); elapsed < timeout {
+		return false, timeout - elapsed
+	}
+	return true, 0
+}
+
+// checkNodeConditions returns whether any of the conditions was observed on the node for longer
+// than its timeout. Otherwise it returns the shortest duration until one of them times out.
+func checkNodeConditions(node *corev1.Node, conditions []clusterv1alpha1.UnhealthyCondition, now time.Time) (bool, string, time.Duration) {
+	var next time.Duration
+	for _, c := range conditions {
+		for _, nodeCondition := range node.Status.Conditions {
+			if nodeCondition.Type != c.Type || nodeCondition.Status != c.Status {
+				continue
+			}
+
+			elapsed := now.Sub(nodeCondition.LastTransitionTime.Time)
+			if elapsed >= c.Timeout.Duration {
+				return true, fmt.Sprintf("condition %s=%s for more than %v", c.Type, c.Status, c.Timeout.Duration), 0
+			}
+			if remaining := c.Timeout.Duration - elapsed; next == 0 || remaining < next {
+				next = remaining
+			}
+		}
+	}
+
+	return false, "", next
+}
+
+// getMaxUnhealthy returns the absolute number of machines that may be unhealthy.
+func getMaxUnhealthy(mhc *clusterv1alpha1.MachineHealthCheck, total int) (int, error) {
+	maxUnhealthy := mhc.Spec.MaxUnhealthy
+	if maxUnhealthy == nil {
+		maxUnhealthy = &defaultMaxUnhealthy
+	}
+	return intstr.GetScaledValueFromIntOrPercent(maxUnhealthy, total, false)
+}
+
+// remediate deletes the machine, if it is owned by a MachineSet. Other machines
+// would not be replaced, so they are only reported.
+func (r *reconciler) remediate(ctx context.Context, log *zap.SugaredLogger, mhc *clusterv1alpha1.MachineHealthCheck, machine *clusterv1alpha1.Machine, reason string) error {
+	owner := metav1.GetControllerOf(machine)
+	if owner == nil || owner.Kind != machineSetKind.Kind || owner.APIVersion != machineSetKind.GroupVersion().String() {
+		log.Infow("Machine is unhealthy, but not owned by a MachineSet, skipping remediation", "reason", reason)
+		r.recorder.Eventf(machine, corev1.EventTypeWarning, "MachineUnhealthy", "Machine is unhealthy (%s), but not owned by a MachineSet", reason)
+		return nil
+	}
+
+	log.Infow("Deleting unhealthy Machine", "reason", reason)
+	if err := r.Delete(ctx, machine); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete unhealthy machine %s: %w", machine.Name, err)
+	}
+
+	r.recorder.Eventf(machine, corev1.EventTypeNormal, "MachineRemediated", "Deleted unhealthy machine: %s", reason)
+	r.recorder.Eventf(mhc, corev1.EventTypeNormal, "MachineRemediated", "Deleted unhealthy machine %s: %s", machine.Name, reason)
+	r.metrics.Remediations.WithLabelValues(mhc.Name, mhc.Namespace).Inc()
+
+	return nil
+}
+
+func (r *reconciler) updateStatus(ctx context.Context, mhc *clusterv1alpha1.MachineHealthCheck, newStatus clusterv1alpha1.MachineHealthCheckStatus) error {
+	if mhc.Status == newStatus {
+		return nil
+	}
+
+	mhc.Status = newStatus
+	if err := r.Status().Update(ctx, mhc); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	return nil
+}
+
+// machineToMachineHealthChecks enqueues all MachineHealthChecks selecting the machine.
+func (r *reconciler) machineToMachineHealthChecks(ctx context.Context, o ctrlruntimeclient.Object) []reconcile.Request {
+	mhcList := &clusterv1alpha1.MachineHealthCheckList{}
+	if err := r.List(ctx, mhcList, ctrlruntimeclient.InNamespace(o.GetNamespace())); err != nil {
+		r.log.Errorw("Failed to list MachineHealthChecks", zap.Error(err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, mhc := range mhcList.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&mhc.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(labels.Set(o.GetLabels())) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: mhc.Namespace, Name: mhc.Name}})
+	}
+
+	return requests
+}
+
+// nodeToMachineHealthChecks enqueues the MachineHealthChecks selecting the machine of the node.
+func (r *reconciler) nodeToMachineHealthChecks(ctx context.Context, o ctrlruntimeclient.Object) []reconcile.Request {
+	machines := &clusterv1alpha1.MachineList{}
+	if err := r.List(ctx, machines, ctrlruntimeclient.MatchingFields{controllerutil.MachineNodeNameIndex: o.GetName()}); err != nil {
+		r.log.Errorw("Failed to list Machines", zap.Error(err))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for idx := range machines.Items {
+		requests = append(requests, r.machineToMachineHealthChecks(ctx, &machines.Items[idx])...)
+	}
+
+	return requests
+}
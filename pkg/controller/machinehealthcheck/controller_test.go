@@ -0,0 +1,313 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	controllerutil "k8c.io/machine-controller/pkg/controller/util"
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	if err := clusterv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		panic(fmt.Sprintf("failed to add clusterv1alpha1 api to scheme: %v", err))
+	}
+}
+
+var readyUnknown = clusterv1alpha1.UnhealthyCondition{
+	Type:    corev1.NodeReady,
+	Status:  corev1.ConditionUnknown,
+	Timeout: metav1.Duration{Duration: 5 * time.Minute},
+}
+
+func testNode(name string, ready corev1.ConditionStatus, since time.Duration) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{
+				Type:               corev1.NodeReady,
+				Status:             ready,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+			}},
+		},
+	}
+}
+
+func testMachine(name string, ownedByMachineSet bool) *clusterv1alpha1.Machine {
+	machine := &clusterv1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+			Labels:    map[string]string{"pool": "workers"},
+		},
+		Status: clusterv1alpha1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{Name: name},
+		},
+	}
+	if ownedByMachineSet {
+		machine.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(&clusterv1alpha1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers", UID: "ms-uid"},
+		}, machineSetKind)}
+	}
+	return machine
+}
+
+func TestCheckNodeConditions(t *testing.T) {
+	tests := []struct {
+		name            string
+		node            *corev1.Node
+		expectUnhealthy bool
+		expectNextCheck bool
+	}{
+		{
+			name:            "ready node is healthy",
+			node:            testNode("node", corev1.ConditionTrue, time.Hour),
+			expectUnhealthy: false,
+		},
+		{
+			name:            "unknown node within timeout is healthy",
+			node:            testNode("node", corev1.ConditionUnknown, time.Minute),
+			expectUnhealthy: false,
+			expectNextCheck: true,
+		},
+		{
+			name:            "unknown node after timeout is unhealthy",
+			node:            testNode("node", corev1.ConditionUnknown, 10*time.Minute),
+			expectUnhealthy: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			unhealthy, _, next := checkNodeConditions(test.node, []clusterv1alpha1.UnhealthyCondition{readyUnknown}, time.Now())
+			if unhealthy != test.expectUnhealthy {
+				t.Errorf("expected unhealthy to be %t, got %t", test.expectUnhealthy, unhealthy)
+			}
+			if (next > 0) != test.expectNextCheck {
+				t.Errorf("expected next check to be scheduled: %t, got %v", test.expectNextCheck, next)
+			}
+		})
+	}
+}
+
+func withoutNode(machine *clusterv1alpha1.Machine, created, nodeLost time.Duration) *clusterv1alpha1.Machine {
+	machine.CreationTimestamp = metav1.NewTime(time.Now().Add(-created))
+	machine.Status.NodeRef = nil
+	if nodeLost > 0 {
+		machine.Status.Conditions = []corev1.NodeCondition{{
+			Type:               common.NodeJoinedCondition,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-nodeLost)),
+		}}
+	}
+	return machine
+}
+
+func TestCheckNodeStartup(t *testing.T) {
+	tests := []struct {
+		name               string
+		machine            *clusterv1alpha1.Machine
+		nodeStartupTimeout *metav1.Duration
+		expectUnhealthy    bool
+		expectNextCheck    bool
+	}{
+		{
+			name:            "new machine is healthy",
+			machine:         withoutNode(testMachine("machine", true), time.Minute, 0),
+			expectNextCheck: true,
+		},
+		{
+			name:            "machine without node after default timeout is unhealthy",
+			machine:         withoutNode(testMachine("machine", true), time.Hour, 0),
+			expectUnhealthy: true,
+		},
+		{
+			name:            "machine which recently lost its node is healthy",
+			machine:         withoutNode(testMachine("machine", true), time.Hour, time.Minute),
+			expectNextCheck: true,
+		},
+		{
+			name:               "configured timeout is used",
+			machine:            withoutNode(testMachine("machine", true), time.Hour, 0),
+			nodeStartupTimeout: &metav1.Duration{Duration: 2 * time.Hour},
+			expectNextCheck:    true,
+		},
+		{
+			name:               "zero timeout disables the check",
+			machine:            withoutNode(testMachine("machine", true), time.Hour, 0),
+			nodeStartupTimeout: &metav1.Duration{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mhc := &clusterv1alpha1.MachineHealthCheck{Spec: clusterv1alpha1.MachineHealthCheckSpec{NodeStartupTimeout: test.nodeStartupTimeout}}
+			unhealthy, next := checkNodeStartup(test.machine, mhc, time.Now())
+			if unhealthy != test.expectUnhealthy {
+				t.Errorf("expected unhealthy to be %t, got %t", test.expectUnhealthy, unhealthy)
+			}
+			if (next > 0) != test.expectNextCheck {
+				t.Errorf("expected next check to be scheduled: %t, got %v", test.expectNextCheck, next)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxUnhealthy   *intstr.IntOrString
+		objects        []ctrlruntimeclient.Object
+		expectDeletion map[string]bool
+	}{
+		{
+			name: "unhealthy machine owned by a machineset gets deleted",
+			objects: []ctrlruntimeclient.Object{
+				testMachine("healthy", true), testNode("healthy", corev1.ConditionTrue, time.Hour),
+				testMachine("unhealthy", true), testNode("unhealthy", corev1.ConditionUnknown, time.Hour),
+			},
+			expectDeletion: map[string]bool{"healthy": false, "unhealthy": true},
+		},
+		{
+			name: "machine with deleted node gets deleted",
+			objects: []ctrlruntimeclient.Object{
+				testMachine("no-node", true),
+			},
+			expectDeletion: map[string]bool{"no-node": true},
+		},
+		{
+			name: "machine with deleted node within node startup timeout is kept",
+			objects: []ctrlruntimeclient.Object{
+				withoutNode(testMachine("no-node", true), time.Hour, time.Minute),
+			},
+			expectDeletion: map[string]bool{"no-node": false},
+		},
+		{
+			name: "machine whose node did not join within node startup timeout gets deleted",
+			objects: []ctrlruntimeclient.Object{
+				withoutNode(testMachine("no-node", true), time.Hour, 0),
+			},
+			expectDeletion: map[string]bool{"no-node": true},
+		},
+		{
+			name: "unhealthy machine without machineset is kept",
+			objects: []ctrlruntimeclient.Object{
+				testMachine("unowned", false), testNode("unowned", corev1.ConditionUnknown, time.Hour),
+			},
+			expectDeletion: map[string]bool{"unowned": false},
+		},
+		{
+			name:         "maxUnhealthy stops remediation",
+			maxUnhealthy: &intstr.IntOrString{Type: intstr.String, StrVal: "40%"},
+			objects: []ctrlruntimeclient.Object{
+				testMachine("healthy", true), testNode("healthy", corev1.ConditionTrue, time.Hour),
+				testMachine("unhealthy-1", true), testNode("unhealthy-1", corev1.ConditionUnknown, time.Hour),
+				testMachine("unhealthy-2", true), testNode("unhealthy-2", corev1.ConditionUnknown, time.Hour),
+			},
+			expectDeletion: map[string]bool{"healthy": false, "unhealthy-1": false, "unhealthy-2": false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mhc := &clusterv1alpha1.MachineHealthCheck{
+				ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: metav1.NamespaceSystem},
+				Spec: clusterv1alpha1.MachineHealthCheckSpec{
+					Selector:            metav1.LabelSelector{MatchLabels: map[string]string{"pool": "workers"}},
+					UnhealthyConditions: []clusterv1alpha1.UnhealthyCondition{readyUnknown},
+					MaxUnhealthy:        test.maxUnhealthy,
+				},
+			}
+
+			client := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(append(test.objects, mhc)...).
+				WithStatusSubresource(mhc).
+				Build()
+
+			r := &reconciler{
+				Client:   client,
+				log:      zap.NewNop().Sugar(),
+				recorder: &record.FakeRecorder{},
+				metrics:  NewMetrics(),
+			}
+
+			if _, err := r.reconcile(ctx, r.log, mhc); err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			for name, expectDeletion := range test.expectDeletion {
+				err := client.Get(ctx, types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: name}, &clusterv1alpha1.Machine{})
+				if wasDeleted := apierrors.IsNotFound(err); wasDeleted != expectDeletion {
+					t.Errorf("machine %s was deleted: %t, but expected deletion: %t", name, wasDeleted, expectDeletion)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeToMachineHealthChecks(t *testing.T) {
+	ctx := context.Background()
+
+	newMHC := func(name, pool string) *clusterv1alpha1.MachineHealthCheck {
+		return &clusterv1alpha1.MachineHealthCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceSystem},
+			Spec: clusterv1alpha1.MachineHealthCheckSpec{
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"pool": pool}},
+			},
+		}
+	}
+
+	client := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(testMachine("worker", true), newMHC("workers", "workers"), newMHC("other", "other")).
+		WithIndex(&clusterv1alpha1.Machine{}, controllerutil.MachineNodeNameIndex, controllerutil.IndexMachineByNodeName).
+		Build()
+
+	r := &reconciler{
+		Client: client,
+		log:    zap.NewNop().Sugar(),
+	}
+
+	requests := r.nodeToMachineHealthChecks(ctx, testNode("worker", corev1.ConditionTrue, time.Hour))
+	if len(requests) != 1 || requests[0].Name != "workers" {
+		t.Errorf("expected only MachineHealthCheck workers to be enqueued, got %v", requests)
+	}
+
+	if requests := r.nodeToMachineHealthChecks(ctx, testNode("unknown", corev1.ConditionTrue, time.Hour)); len(requests) != 0 {
+		t.Errorf("expected no MachineHealthCheck to be enqueued for a node without machine, got %v", requests)
+	}
+}
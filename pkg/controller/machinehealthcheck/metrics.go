@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsPrefix = "machine_health_check_"
+
+// Metrics is a struct of all metrics used by the MachineHealthCheck controller.
+type Metrics struct {
+	ExpectedMachines       *prometheus.GaugeVec
+	UnhealthyMachines      *prometheus.GaugeVec
+	Remediations           *prometheus.CounterVec
+	RemediationsRestricted *prometheus.CounterVec
+}
+
+// NewMetrics creates new Metrics for the MachineHealthCheck controller.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		ExpectedMachines: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: metricsPrefix + "expected_machines",
+			Help: "The number of machines selected by a machine health check",
+		}, []string{"name", "namespace"}),
+		UnhealthyMachines: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: metricsPrefix + "unhealthy_machines",
+			Help: "The number of unhealthy machines selected by a machine health check",
+		}, []string{"name", "namespace"}),
+		Remediations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metricsPrefix + "remediations_total",
+			Help: "The total number of machines deleted by a machine health check",
+		}, []string{"name", "namespace"}),
+		RemediationsRestricted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metricsPrefix + "remediations_restricted_total",
+			Help: "The total number of times remediation was skipped because more machines than maxUnhealthy were unhealthy",
+		}, []string{"name", "namespace"}),
+	}
+}
+
+// MustRegister registers all metrics with the given registerer.
+func (m *Metrics) MustRegister(registerer prometheus.Registerer) {
+	registerer.MustRegister(
+		m.ExpectedMachines,
+		m.UnhealthyMachines,
+		m.Remediations,
+		m.RemediationsRestricted,
+	)
+}
+
+// delete removes all series of the given machine health check.
+func (m *Metrics) delete(name, namespace string) {
+	m.ExpectedMachines.DeleteLabelValues(name, namespace)
+	m.UnhealthyMachines.DeleteLabelValues(name, namespace)
+	m.Remediations.DeleteLabelValues(name, namespace)
+	m.RemediationsRestricted.DeleteLabelValues(name, namespace)
+}
@@ -23,9 +23,11 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	controllerutil "k8c.io/machine-controller/pkg/controller/util"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -68,6 +70,7 @@ func (c *ReconcileMachineSet) calculateStatus(ctx context.Context, log *zap.Suga
 	newStatus.FullyLabeledReplicas = int32(fullyLabeledReplicasCount)
 	newStatus.ReadyReplicas = int32(readyReplicasCount)
 	newStatus.AvailableReplicas = int32(availableReplicasCount)
+	newStatus.Conditions = controllerutil.SummarizeMachineConditions(filteredMachines)
 	return newStatus
 }
 
@@ -80,6 +83,7 @@ func updateMachineSetStatus(ctx context.Context, log *zap.SugaredLogger, c ctrlr
 		ms.Status.FullyLabeledReplicas == newStatus.FullyLabeledReplicas &&
 		ms.Status.ReadyReplicas == newStatus.ReadyReplicas &&
 		ms.Status.AvailableReplicas == newStatus.AvailableReplicas &&
+		equality.Semantic.DeepEqual(ms.Status.Conditions, newStatus.Conditions) &&
 		ms.Generation == ms.Status.ObservedGeneration {
 		return ms, nil
 	}
@@ -20,13 +20,18 @@ import (
 	"context"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"path"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
 
+	controllerutil "k8c.io/machine-controller/pkg/controller/util"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 
 	certificatesv1 "k8s.io/api/certificates/v1"
@@ -51,6 +56,14 @@ const (
 
 	nodeGroup          = "system:nodes"
 	authenticatedGroup = "system:authenticated"
+
+	// pendingRetryInterval is the interval in which CSRs of nodes without a machine are retried.
+	// The node of a machine is usually set shortly after the node registered.
+	pendingRetryInterval = 10 * time.Second
+	// pendingTimeout is the time after which CSRs of nodes without a machine are not retried anymore.
+	// These nodes are not managed by machine-controller and their CSRs are left to other approvers.
+	// CSRs with addresses the machine does not have (yet) are denied after this timeout.
+	pendingTimeout = 15 * time.Minute
 )
 
 var (
@@ -59,17 +72,48 @@ var (
 		certificatesv1.UsageKeyEncipherment,
 		certificatesv1.UsageServerAuth,
 	}
+
+	allowedClientUsages = []certificatesv1.KeyUsage{
+		certificatesv1.UsageDigitalSignature,
+		certificatesv1.UsageKeyEncipherment,
+		certificatesv1.UsageClientAuth,
+	}
 )
 
+// Options configure which CSRs the NodeCSRApprover approves.
+type Options struct {
+	// ApproveClientCertificates enables the approval of kubelet client certificate renewals of
+	// nodes with a machine.
+	ApproveClientCertificates bool
+	// AllowedSANPatterns are glob patterns of DNS names and IP addresses which are allowed in
+	// serving certificates in addition to the addresses of the machine, e.g. "*.compute.internal".
+	AllowedSANPatterns []string
+	// ServingSignerNames are the names of external signers whose CSRs are validated like kubelet
+	// serving CSRs in addition to kubernetes.io/kubelet-serving.
+	ServingSignerNames []string
+}
+
 type reconciler struct {
 	ctrlruntimeclient.Client
-	log *zap.SugaredLogger
+	log     *zap.SugaredLogger
+	metrics *Metrics
+	options Options
 	// Have to use the typed client because csr approval is a subresource
 	// the dynamic client does not approve
 	certClient certificatesv1client.CertificateSigningRequestInterface
+
+	// pending contains the names of the CSRs waiting for the machine of their node.
+	pending     sets.Set[string]
+	pendingLock sync.Mutex
 }
 
-func Add(mgr manager.Manager, log *zap.SugaredLogger) error {
+func Add(mgr manager.Manager, log *zap.SugaredLogger, metrics *Metrics, options Options) error {
+	for _, pattern := range options.AllowedSANPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid SAN pattern %q: %w", pattern, err)
+		}
+	}
+
 	certClient, err := certificatesv1client.NewForConfig(mgr.GetConfig())
 	if err != nil {
 		return fmt.Errorf("failed to create certificate client: %w", err)
@@ -78,7 +122,10 @@ func Add(mgr manager.Manager, log *zap.SugaredLogger) error {
 	rec := &reconciler{
 		Client:     mgr.GetClient(),
 		log:        log.Named(ControllerName),
+		metrics:    metrics,
+		options:    options,
 		certClient: certClient.CertificateSigningRequests(),
+		pending:    sets.New[string](),
 	}
 
 	_, err = builder.ControllerManagedBy(mgr).
@@ -103,69 +150,135 @@ func (r *reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	csr := &certificatesv1.CertificateSigningRequest{}
 	if err := r.Get(ctx, request.NamespacedName, csr); err != nil {
 		if apierrors.IsNotFound(err) {
+			r.setPending(request.Name, false)
 			return reconcile.Result{}, nil
 		}
 		log.Errorw("Failed to get CertificateSigningRequest", zap.Error(err))
 		return reconcile.Result{}, err
 	}
 
-	err := r.reconcile(ctx, log, csr)
+	result, err := r.reconcile(ctx, log, csr)
 	if err != nil {
 		log.Errorw("Reconciling failed", zap.Error(err))
 	}
 
-	return reconcile.Result{}, err
+	return result, err
 }
 
-func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, csr *certificatesv1.CertificateSigningRequest) error {
-	// If CSR is approved, skip it
+func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, csr *certificatesv1.CertificateSigningRequest) (reconcile.Result, error) {
+	// If CSR is approved or denied, skip it
 	for _, condition := range csr.Status.Conditions {
-		if condition.Type == certificatesv1.CertificateApproved {
-			log.Debug("CSR already approved, skipping reconciling")
-			return nil
+		if condition.Type == certificatesv1.CertificateApproved || condition.Type == certificatesv1.CertificateDenied {
+			log.Debug("CSR already approved or denied, skipping reconciling")
+			r.setPending(csr.Name, false)
+			return reconcile.Result{}, nil
 		}
 	}
 
+	if !r.handlesSigner(csr.Spec.SignerName) {
+		log.Debugw("Skipping reconciling CSR because of its signer", "signer", csr.Spec.SignerName)
+		return reconcile.Result{}, nil
+	}
+
 	// Validate the CSR object and get the node name
 	nodeName, err := r.validateCSRObject(csr)
 	if err != nil {
 		log.Debugw("Skipping reconciling CSR because object is invalid", zap.Error(err))
-		return nil
+		return reconcile.Result{}, nil
 	}
+	nodeLog := log.With("node", nodeName)
 
 	// Get machine name for the appropriate node
 	machine, found, err := r.getMachineForNode(ctx, nodeName)
 	if err != nil {
-		return fmt.Errorf("failed to get machine for node '%s': %w", nodeName, err)
+		return reconcile.Result{}, fmt.Errorf("failed to get machine for node '%s': %w", nodeName, err)
 	}
 	if !found {
-		return fmt.Errorf("no machine found for given node '%s'", nodeName)
+		if time.Since(csr.CreationTimestamp.Time) > pendingTimeout {
+			nodeLog.Infow("No machine found for node, leaving CSR to other approvers", "timeout", pendingTimeout)
+			r.setPending(csr.Name, false)
+			return reconcile.Result{}, nil
+		}
+		nodeLog.Debug("No machine found for node yet, retrying later")
+		r.setPending(csr.Name, true)
+		return reconcile.Result{RequeueAfter: pendingRetryInterval}, nil
+	}
+
+	// The CSR belongs to a machine, so it is either approved or denied.
+	if err := r.validateCertificateRequest(csr, machine); err != nil {
+		// The addresses of the machine are updated from the node and can lag behind the
+		// kubelet requesting its serving certificate, so unknown addresses are retried.
+		var sanErr unknownSANError
+		if errors.As(err, &sanErr) && time.Since(csr.CreationTimestamp.Time) <= pendingTimeout {
+			nodeLog.Debugw("Address not known for machine yet, retrying later", zap.Error(err))
+			r.setPending(csr.Name, true)
+			return reconcile.Result{RequeueAfter: pendingRetryInterval}, nil
+		}
+		if err := r.deny(ctx, nodeLog, csr, err); err != nil {
+			return reconcile.Result{}, err
+		}
+	} else if err := r.approve(ctx, nodeLog, csr); err != nil {
+		return reconcile.Result{}, err
+	}
+	r.setPending(csr.Name, false)
+
+	return reconcile.Result{}, nil
+}
+
+// handlesSigner returns whether CSRs of the signer are reconciled.
+func (r *reconciler) handlesSigner(signerName string) bool {
+	switch signerName {
+	case certificatesv1.KubeletServingSignerName:
+		return true
+	case certificatesv1.KubeAPIServerClientKubeletSignerName:
+		return r.options.ApproveClientCertificates
+	}
+	for _, name := range r.options.ServingSignerNames {
+		if signerName == name {
+			return true
+		}
 	}
+	return false
+}
 
+// validateCertificateRequest parses the certificate request of the CSR and validates it against the machine.
+func (r *reconciler) validateCertificateRequest(csr *certificatesv1.CertificateSigningRequest, machine clusterv1alpha1.Machine) error {
 	// Parse the certificate request
 	csrBlock, rest := pem.Decode(csr.Spec.Request)
 	if csrBlock == nil {
-		return fmt.Errorf("no certificate request found for the given CSR")
+		return errors.New("no certificate request found for the given CSR")
 	}
 	if len(rest) != 0 {
-		return fmt.Errorf("found more than one PEM encoded block in the result")
+		return errors.New("found more than one PEM encoded block in the result")
 	}
 	certRequest, err := x509.ParseCertificateRequest(csrBlock.Bytes)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to parse the x509 certificate request: %w", err)
 	}
 
 	// Validate the certificate request
-	if err := r.validateX509CSR(csr, certRequest, machine); err != nil {
+	if csr.Spec.SignerName == certificatesv1.KubeAPIServerClientKubeletSignerName {
+		err = r.validateClientX509CSR(csr, certRequest)
+	} else {
+		err = r.validateX509CSR(csr, certRequest, machine)
+	}
+	if err != nil {
 		return fmt.Errorf("error validating the x509 certificate request: %w", err)
 	}
 
-	// Approve CSR
-	nodeLog := log.With("node", nodeName)
-	nodeLog.Debug("Approving CSR")
+	return nil
+}
+
+func (r *reconciler) approve(ctx context.Context, log *zap.SugaredLogger, csr *certificatesv1.CertificateSigningRequest) error {
+	reason := "machine-controller NodeCSRApprover controller approved node serving cert"
+	if csr.Spec.SignerName == certificatesv1.KubeAPIServerClientKubeletSignerName {
+		reason = "machine-controller NodeCSRApprover controller approved node client cert"
+	}
+
+	log.Debug("Approving CSR")
 	approvalCondition := certificatesv1.CertificateSigningRequestCondition{
 		Type:   certificatesv1.CertificateApproved,
-		Reason: "machine-controller NodeCSRApprover controller approved node serving cert",
+		Reason: reason,
 		Status: corev1.ConditionTrue,
 	}
 	csr.Status.Conditions = append(csr.Status.Conditions, approvalCondition)
@@ -173,11 +286,45 @@ func (r *reconciler) reconcile(ctx context.Context, log *zap.SugaredLogger, csr
 	if _, err := r.certClient.UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
 		return fmt.Errorf("failed to approve CSR %q: %w", csr.Name, err)
 	}
+	r.metrics.Approved.WithLabelValues(csr.Spec.SignerName).Inc()
+
+	log.Info("Successfully approved CSR")
+	return nil
+}
+
+// deny denies the CSR, so the node does not wait for a certificate which is never approved.
+func (r *reconciler) deny(ctx context.Context, log *zap.SugaredLogger, csr *certificatesv1.CertificateSigningRequest, reason error) error {
+	log.Debugw("Denying CSR", zap.Error(reason))
+	denialCondition := certificatesv1.CertificateSigningRequestCondition{
+		Type:    certificatesv1.CertificateDenied,
+		Reason:  "machine-controller NodeCSRApprover controller denied node cert",
+		Message: reason.Error(),
+		Status:  corev1.ConditionTrue,
+	}
+	csr.Status.Conditions = append(csr.Status.Conditions, denialCondition)
+
+	if _, err := r.certClient.UpdateApproval(ctx, csr.Name, csr, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to deny CSR %q: %w", csr.Name, err)
+	}
+	r.metrics.Denied.WithLabelValues(csr.Spec.SignerName).Inc()
 
-	nodeLog.Info("Successfully approved CSR")
+	log.Infow("Denied CSR", "reason", reason.Error())
 	return nil
 }
 
+// setPending records whether the CSR is waiting for the machine of its node.
+func (r *reconciler) setPending(name string, pending bool) {
+	r.pendingLock.Lock()
+	defer r.pendingLock.Unlock()
+
+	if pending {
+		r.pending.Insert(name)
+	} else {
+		r.pending.Delete(name)
+	}
+	r.metrics.Pending.Set(float64(r.pending.Len()))
+}
+
 // validateCSRObject valides the CSR object and returns name of the node that requested the certificate.
 func (r *reconciler) validateCSRObject(csr *certificatesv1.CertificateSigningRequest) (string, error) {
 	// Get and validate the node name.
@@ -198,9 +345,13 @@ func (r *reconciler) validateCSRObject(csr *certificatesv1.CertificateSigningReq
 	}
 
 	// Check that present usages matching allowed usages
+	usages := allowedUsages
+	if csr.Spec.SignerName == certificatesv1.KubeAPIServerClientKubeletSignerName {
+		usages = allowedClientUsages
+	}
 	for _, usage := range csr.Spec.Usages {
-		if !isUsageInUsageList(usage, allowedUsages) {
-			return "", fmt.Errorf("usage %v is not in the list of allowed usages (%v)", usage, allowedUsages)
+		if !isUsageInUsageList(usage, usages) {
+			return "", fmt.Errorf("usage %v is not in the list of allowed usages (%v)", usage, usages)
 		}
 	}
 
@@ -210,17 +361,8 @@ func (r *reconciler) validateCSRObject(csr *certificatesv1.CertificateSigningReq
 // validateX509CSR validates the certificate request by comparing CN with username,
 // and organization with groups.
 func (r *reconciler) validateX509CSR(csr *certificatesv1.CertificateSigningRequest, certReq *x509.CertificateRequest, machine clusterv1alpha1.Machine) error {
-	// Validate Subject CommonName.
-	if certReq.Subject.CommonName != csr.Spec.Username {
-		return fmt.Errorf("commonName '%s' is different then CSR username '%s'", certReq.Subject.CommonName, csr.Spec.Username)
-	}
-
-	// Validate Subject Organization.
-	if len(certReq.Subject.Organization) != 1 {
-		return fmt.Errorf("expected only one organization but got %d instead", len(certReq.Subject.Organization))
-	}
-	if certReq.Subject.Organization[0] != nodeGroup {
-		return fmt.Errorf("organization '%s' doesn't match node group '%s'", certReq.Subject.Organization[0], nodeGroup)
+	if err := validateX509Subject(csr, certReq); err != nil {
+		return err
 	}
 
 	machineAddressSet := sets.NewString(machine.Status.NodeRef.Name)
@@ -233,8 +375,8 @@ func (r *reconciler) validateX509CSR(csr *certificatesv1.CertificateSigningReque
 		if len(dns) == 0 {
 			continue
 		}
-		if !machineAddressSet.Has(dns) {
-			return fmt.Errorf("dns name '%s' cannot be associated with node '%s'", dns, machine.Status.NodeRef.Name)
+		if !machineAddressSet.Has(dns) && !r.isAllowedSAN(dns) {
+			return unknownSANError{kind: "dns name", san: dns, node: machine.Status.NodeRef.Name}
 		}
 	}
 
@@ -243,19 +385,73 @@ func (r *reconciler) validateX509CSR(csr *certificatesv1.CertificateSigningReque
 		if len(ip) == 0 {
 			continue
 		}
-		if !machineAddressSet.Has(ip.String()) {
-			return fmt.Errorf("ip address '%v' cannot be associated with node '%s'", ip, machine.Status.NodeRef.Name)
+		if !machineAddressSet.Has(ip.String()) && !r.isAllowedSAN(ip.String()) {
+			return unknownSANError{kind: "ip address", san: ip.String(), node: machine.Status.NodeRef.Name}
 		}
 	}
 
 	return nil
 }
 
+// unknownSANError is returned for SANs which are neither addresses of the machine nor allowed.
+type unknownSANError struct {
+	kind string
+	san  string
+	node string
+}
+
+func (e unknownSANError) Error() string {
+	return fmt.Sprintf("%s '%s' cannot be associated with node '%s'", e.kind, e.san, e.node)
+}
+
+// validateClientX509CSR validates the certificate request of a kubelet client certificate, which
+// must not contain any SANs.
+func (r *reconciler) validateClientX509CSR(csr *certificatesv1.CertificateSigningRequest, certReq *x509.CertificateRequest) error {
+	if err := validateX509Subject(csr, certReq); err != nil {
+		return err
+	}
+
+	if len(certReq.DNSNames) > 0 || len(certReq.IPAddresses) > 0 || len(certReq.EmailAddresses) > 0 || len(certReq.URIs) > 0 {
+		return errors.New("client certificates must not contain subject alternative names")
+	}
+
+	return nil
+}
+
+// validateX509Subject validates the certificate request by comparing CN with username,
+// and organization with groups.
+func validateX509Subject(csr *certificatesv1.CertificateSigningRequest, certReq *x509.CertificateRequest) error {
+	// Validate Subject CommonName.
+	if certReq.Subject.CommonName != csr.Spec.Username {
+		return fmt.Errorf("commonName '%s' is different then CSR username '%s'", certReq.Subject.CommonName, csr.Spec.Username)
+	}
+
+	// Validate Subject Organization.
+	if len(certReq.Subject.Organization) != 1 {
+		return fmt.Errorf("expected only one organization but got %d instead", len(certReq.Subject.Organization))
+	}
+	if certReq.Subject.Organization[0] != nodeGroup {
+		return fmt.Errorf("organization '%s' doesn't match node group '%s'", certReq.Subject.Organization[0], nodeGroup)
+	}
+
+	return nil
+}
+
+// isAllowedSAN returns whether the DNS name or IP address matches one of the allowed SAN patterns.
+func (r *reconciler) isAllowedSAN(san string) bool {
+	for _, pattern := range r.options.AllowedSANPatterns {
+		if matched, err := path.Match(pattern, san); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *reconciler) getMachineForNode(ctx context.Context, nodeName string) (clusterv1alpha1.Machine, bool, error) {
-	// List all Machines in all namespaces.
+	// Look up the Machines of the node in all namespaces.
 	machines := &clusterv1alpha1.MachineList{}
-	if err := r.List(ctx, machines); err != nil {
-		return clusterv1alpha1.Machine{}, false, fmt.Errorf("failed to list all machine objects: %w", err)
+	if err := r.List(ctx, machines, ctrlruntimeclient.MatchingFields{controllerutil.MachineNodeNameIndex: nodeName}); err != nil {
+		return clusterv1alpha1.Machine{}, false, fmt.Errorf("failed to list machine objects: %w", err)
 	}
 
 	for _, machine := range machines.Items {
@@ -264,7 +460,7 @@ func (r *reconciler) getMachineForNode(ctx context.Context, nodeName string) (cl
 		}
 	}
 
-	return clusterv1alpha1.Machine{}, false, fmt.Errorf("failed to get machine for given node name '%s'", nodeName)
+	return clusterv1alpha1.Machine{}, false, nil
 }
 
 func isUsageInUsageList(usage certificatesv1.KeyUsage, usageList []certificatesv1.KeyUsage) bool {
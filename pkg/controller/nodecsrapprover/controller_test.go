@@ -17,16 +17,31 @@ limitations under the License.
 package nodecsrapprover
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"fmt"
+	"net"
 	"testing"
+	"time"
 
+	"go.uber.org/zap"
+
+	controllerutil "k8c.io/machine-controller/pkg/controller/util"
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 
 	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 /*
@@ -672,3 +687,185 @@ func TestValidateX509CSR(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcile(t *testing.T) {
+	const nodeName = "ip-192-0-2-10.eu-west-3.compute.internal"
+
+	newMachine := func(name, nodeName string) *clusterv1alpha1.Machine {
+		return &clusterv1alpha1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceSystem},
+			Status: clusterv1alpha1.MachineStatus{
+				NodeRef: &corev1.ObjectReference{Kind: "Node", Name: nodeName},
+				Addresses: []corev1.NodeAddress{
+					{Address: "192.0.2.10", Type: corev1.NodeInternalIP},
+				},
+			},
+		}
+	}
+
+	newCSR := func(signerName, nodeName string, created time.Time, template x509.CertificateRequest) *certificatesv1.CertificateSigningRequest {
+		usages := allowedUsages
+		if signerName == certificatesv1.KubeAPIServerClientKubeletSignerName {
+			usages = allowedClientUsages
+		}
+		template.Subject = pkix.Name{CommonName: nodeUserPrefix + nodeName, Organization: []string{nodeGroup}}
+		return &certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "csr", CreationTimestamp: metav1.NewTime(created)},
+			Spec: certificatesv1.CertificateSigningRequestSpec{
+				Request:    newCertificateRequest(t, &template),
+				SignerName: signerName,
+				Usages:     usages,
+				Username:   nodeUserPrefix + nodeName,
+				Groups:     []string{nodeGroup, authenticatedGroup},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		csr       *certificatesv1.CertificateSigningRequest
+		options   Options
+		condition certificatesv1.RequestConditionType
+		pending   bool
+	}{
+		{
+			name: "serving csr with machine addresses is approved",
+			csr: newCSR(certificatesv1.KubeletServingSignerName, nodeName, time.Now(), x509.CertificateRequest{
+				DNSNames:    []string{nodeName},
+				IPAddresses: []net.IP{net.ParseIP("192.0.2.10")},
+			}),
+			condition: certificatesv1.CertificateApproved,
+		},
+		{
+			name: "serving csr with allowed dns name is approved",
+			csr: newCSR(certificatesv1.KubeletServingSignerName, nodeName, time.Now(), x509.CertificateRequest{
+				DNSNames: []string{"node.eu-west-3.example.com"},
+			}),
+			options:   Options{AllowedSANPatterns: []string{"*.eu-west-3.example.com"}},
+			condition: certificatesv1.CertificateApproved,
+		},
+		{
+			name: "serving csr with unknown ip address is pending",
+			csr: newCSR(certificatesv1.KubeletServingSignerName, nodeName, time.Now(), x509.CertificateRequest{
+				IPAddresses: []net.IP{net.ParseIP("192.0.2.11")},
+			}),
+			options: Options{AllowedSANPatterns: []string{"*.eu-west-3.example.com"}},
+			pending: true,
+		},
+		{
+			name: "serving csr with unknown ip address is denied after the timeout",
+			csr: newCSR(certificatesv1.KubeletServingSignerName, nodeName, time.Now().Add(-2*pendingTimeout), x509.CertificateRequest{
+				IPAddresses: []net.IP{net.ParseIP("192.0.2.11")},
+			}),
+			options:   Options{AllowedSANPatterns: []string{"*.eu-west-3.example.com"}},
+			condition: certificatesv1.CertificateDenied,
+		},
+		{
+			name: "serving csr of external signer is approved",
+			csr: newCSR("example.com/kubelet-serving", nodeName, time.Now(), x509.CertificateRequest{
+				DNSNames: []string{nodeName},
+			}),
+			options:   Options{ServingSignerNames: []string{"example.com/kubelet-serving"}},
+			condition: certificatesv1.CertificateApproved,
+		},
+		{
+			name: "serving csr of unknown signer is ignored",
+			csr: newCSR("example.com/kubelet-serving", nodeName, time.Now(), x509.CertificateRequest{
+				DNSNames: []string{nodeName},
+			}),
+		},
+		{
+			name:      "client csr is approved",
+			csr:       newCSR(certificatesv1.KubeAPIServerClientKubeletSignerName, nodeName, time.Now(), x509.CertificateRequest{}),
+			options:   Options{ApproveClientCertificates: true},
+			condition: certificatesv1.CertificateApproved,
+		},
+		{
+			name: "client csr is ignored unless enabled",
+			csr:  newCSR(certificatesv1.KubeAPIServerClientKubeletSignerName, nodeName, time.Now(), x509.CertificateRequest{}),
+		},
+		{
+			name: "client csr with subject alternative names is denied",
+			csr: newCSR(certificatesv1.KubeAPIServerClientKubeletSignerName, nodeName, time.Now(), x509.CertificateRequest{
+				DNSNames: []string{nodeName},
+			}),
+			options:   Options{ApproveClientCertificates: true},
+			condition: certificatesv1.CertificateDenied,
+		},
+		{
+			name: "csr of node without machine is pending",
+			csr: newCSR(certificatesv1.KubeletServingSignerName, "unknown-node", time.Now(), x509.CertificateRequest{
+				DNSNames: []string{"unknown-node"},
+			}),
+			pending: true,
+		},
+		{
+			name: "csr of node without machine is left to other approvers after the timeout",
+			csr: newCSR(certificatesv1.KubeletServingSignerName, "unknown-node", time.Now().Add(-2*pendingTimeout), x509.CertificateRequest{
+				DNSNames: []string{"unknown-node"},
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := clusterv1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to add api to scheme: %v", err)
+			}
+			client := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(newMachine("machine", nodeName), newMachine("other-machine", "other-node")).
+				WithIndex(&clusterv1alpha1.Machine{}, controllerutil.MachineNodeNameIndex, controllerutil.IndexMachineByNodeName).
+				Build()
+			kubeClient := kubefake.NewClientset(tc.csr)
+
+			r := &reconciler{
+				Client:     client,
+				log:        zap.NewNop().Sugar(),
+				metrics:    NewMetrics(),
+				options:    tc.options,
+				certClient: kubeClient.CertificatesV1().CertificateSigningRequests(),
+				pending:    sets.New[string](),
+			}
+
+			result, err := r.reconcile(context.Background(), r.log, tc.csr.DeepCopy())
+			if err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+
+			var conditions []certificatesv1.CertificateSigningRequestCondition
+			for _, action := range kubeClient.Actions() {
+				if action.GetVerb() == "update" && action.GetSubresource() == "approval" {
+					conditions = action.(clienttesting.UpdateAction).GetObject().(*certificatesv1.CertificateSigningRequest).Status.Conditions
+				}
+			}
+			switch {
+			case tc.condition == "" && len(conditions) > 0:
+				t.Errorf("expected csr to be left untouched, got conditions %+v", conditions)
+			case tc.condition != "" && (len(conditions) != 1 || conditions[0].Type != tc.condition):
+				t.Errorf("expected csr to get condition %s, got %+v", tc.condition, conditions)
+			}
+
+			if pending := r.pending.Has(tc.csr.Name); pending != tc.pending {
+				t.Errorf("expected csr to be pending: %t, got %t", tc.pending, pending)
+			}
+			if requeued := result.RequeueAfter > 0; requeued != tc.pending {
+				t.Errorf("expected csr to be requeued: %t, got %t", tc.pending, requeued)
+			}
+		})
+	}
+}
+
+func newCertificateRequest(t *testing.T, template *x509.CertificateRequest) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate request: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
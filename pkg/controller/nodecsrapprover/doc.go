@@ -16,6 +16,8 @@ limitations under the License.
 
 /*
 Package nodecsrapprover contains a controller responsible for autoapproving CSRs created by nodes
-for serving certificates.
+for serving certificates and, optionally, for renewing their client certificates. CSRs of nodes
+whose machine is known but which do not match the machine are denied. CSRs containing addresses
+the machine does not have are retried first, as the addresses of a machine can lag behind its node.
 */
 package nodecsrapprover
@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecsrapprover
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsPrefix = "machine_controller_node_csr_"
+
+// Metrics is a struct of all metrics used by the NodeCSRApprover controller.
+type Metrics struct {
+	Approved *prometheus.CounterVec
+	Denied   *prometheus.CounterVec
+	Pending  prometheus.Gauge
+}
+
+// NewMetrics creates new Metrics for the NodeCSRApprover controller.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		Approved: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metricsPrefix + "approved_total",
+			Help: "The total number of CertificateSigningRequests of nodes approved by the NodeCSRApprover",
+		}, []string{"signer"}),
+		Denied: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metricsPrefix + "denied_total",
+			Help: "The total number of CertificateSigningRequests of nodes denied by the NodeCSRApprover",
+		}, []string{"signer"}),
+		Pending: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: metricsPrefix + "pending",
+			Help: "The number of CertificateSigningRequests of nodes waiting for the machine of their node",
+		}),
+	}
+}
+
+// MustRegister registers all metrics with the given registerer.
+func (m *Metrics) MustRegister(registerer prometheus.Registerer) {
+	registerer.MustRegister(
+		m.Approved,
+		m.Denied,
+		m.Pending,
+	)
+}
@@ -0,0 +1,384 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphancollector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/cloudprovider"
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig"
+	"k8c.io/machine-controller/sdk/providerconfig/configvar"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// ControllerName is the name of the orphan collector.
+const ControllerName = "orphan-collector"
+
+// Mode defines what the collector does with orphaned instances.
+type Mode string
+
+const (
+	// ModeDryRun only reports orphaned instances by events and metrics.
+	ModeDryRun Mode = "dry-run"
+	// ModeDelete additionally deletes instances that were orphaned for longer than the grace period.
+	ModeDelete Mode = "delete"
+)
+
+// Options configure the orphan collector.
+type Options struct {
+	Mode Mode
+	// Interval is the time between two collections.
+	Interval time.Duration
+	// GracePeriod is the time an instance must have been orphaned before it gets deleted.
+	// It protects instances of Machines that were just created and are not in the cache yet.
+	GracePeriod time.Duration
+	// ClusterID identifies the cluster. Only instances tagged with it are collected, as
+	// instances of other clusters sharing the same account have no Machine in this cluster.
+	ClusterID string
+	// StateConfigMap keeps the times instances were found orphaned, so that the grace period
+	// does not restart with the machine-controller.
+	StateConfigMap types.NamespacedName
+	// ConfigVarSources resolve config vars referencing values outside of the cluster.
+	ConfigVarSources *configvar.Sources
+}
+
+type providerGetter func(providerconfig.CloudProvider, providerconfig.ConfigVarResolver) (cloudprovidertypes.Provider, error)
+
+// orphan is an instance that was found without a Machine.
+type orphan struct {
+	since   time.Time
+	deleted bool
+}
+
+// scope is a distinct provider spec used to list instances.
+type scope struct {
+	provider providerconfig.CloudProvider
+	spec     clusterv1alpha1.MachineSpec
+	// object is the Machine or MachineDeployment the spec was taken from.
+	// Events about orphaned instances are emitted for it.
+	object ctrlruntimeclient.Object
+}
+
+type collector struct {
+	client      ctrlruntimeclient.Client
+	log         *zap.SugaredLogger
+	recorder    record.EventRecorder
+	metrics     *Metrics
+	options     Options
+	getProvider providerGetter
+
+	// orphans contains the currently orphaned instances by provider and instance ID. It is
+	// loaded from the state ConfigMap by the first collection.
+	orphans map[string]*orphan
+}
+
+// Add creates a new orphan collector and adds it to the Manager.
+func Add(mgr manager.Manager, log *zap.SugaredLogger, metrics *Metrics, options Options) error {
+	switch options.Mode {
+	case ModeDryRun, ModeDelete:
+	default:
+		return fmt.Errorf("invalid mode %q, must be one of %q or %q", options.Mode, ModeDryRun, ModeDelete)
+	}
+	// Without a cluster ID the instances of other clusters sharing an account cannot be
+	// told apart from orphaned ones.
+	if options.ClusterID == "" {
+		return errors.New("a cluster ID is required to only collect instances of this cluster")
+	}
+	if options.StateConfigMap.Namespace == "" || options.StateConfigMap.Name == "" {
+		return errors.New("a state ConfigMap is required")
+	}
+
+	return mgr.Add(&collector{
+		client:      mgr.GetClient(),
+		log:         log.Named(ControllerName),
+		recorder:    mgr.GetEventRecorderFor(ControllerName),
+		metrics:     metrics,
+		options:     options,
+		getProvider: cloudprovider.ForProvider,
+	})
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *collector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs a collection every interval until the context is done.
+// Start is part of manager.Runnable.
+func (c *collector) Start(ctx context.Context) error {
+	c.log.Infow("Starting orphan collector", "mode", c.options.Mode, "interval", c.options.Interval, "gracePeriod", c.options.GracePeriod)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.collect(ctx, time.Now()); err != nil {
+			c.metrics.Errors.Inc()
+			c.log.Errorw("Failed to collect orphaned instances", zap.Error(err))
+		}
+	}, c.options.Interval)
+
+	return nil
+}
+
+func (c *collector) collect(ctx context.Context, now time.Time) error {
+	if c.orphans == nil {
+		if err := c.loadOrphans(ctx); err != nil {
+			return err
+		}
+	}
+
+	machines := &clusterv1alpha1.MachineList{}
+	if err := c.client.List(ctx, machines); err != nil {
+		return fmt.Errorf("failed to list machines: %w", err)
+	}
+	machineDeployments := &clusterv1alpha1.MachineDeploymentList{}
+	if err := c.client.List(ctx, machineDeployments); err != nil {
+		return fmt.Errorf("failed to list machine deployments: %w", err)
+	}
+
+	knownUIDs := sets.New[types.UID]()
+	scopes := map[string]*scope{}
+	for i := range machines.Items {
+		machine := &machines.Items[i]
+		knownUIDs.Insert(machine.UID)
+		addScope(scopes, machine.Spec, machine)
+	}
+	for i := range machineDeployments.Items {
+		machineDeployment := &machineDeployments.Items[i]
+		addScope(scopes, machineDeployment.Spec.Template.Spec, machineDeployment)
+	}
+
+	var errs []error
+	seen := sets.New[string]()
+	orphansPerProvider := map[providerconfig.CloudProvider]int{}
+	for _, sc := range scopes {
+		prov, err := c.getProvider(sc.provider, configvar.NewResolver(ctx, c.client, configvar.WithSources(c.options.ConfigVarSources)))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get cloud provider %q: %w", sc.provider, err))
+			continue
+		}
+		lister, ok := prov.(cloudprovidertypes.InstanceLister)
+		if !ok {
+			continue
+		}
+
+		instances, err := lister.ListInstances(ctx, c.log, sc.spec, c.options.ClusterID)
+		if err != nil {
+			if !errors.Is(err, cloudprovidererrors.ErrNotSupported) {
+				errs = append(errs, fmt.Errorf("failed to list instances of cloud provider %q: %w", sc.provider, err))
+			}
+			continue
+		}
+
+		for _, inst := range instances {
+			key := orphanKey(sc.provider, inst.ID())
+			if knownUIDs.Has(inst.MachineUID) || seen.Has(key) {
+				continue
+			}
+			seen.Insert(key)
+			orphansPerProvider[sc.provider]++
+
+			if err := c.handleOrphan(ctx, prov, sc, inst, key, now); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	// Forget instances that are gone or have a Machine again, so they get a new
+	// grace period if they are orphaned later on.
+	for key := range c.orphans {
+		if !seen.Has(key) {
+			delete(c.orphans, key)
+		}
+	}
+
+	c.metrics.OrphanedInstances.Reset()
+	for provider, count := range orphansPerProvider {
+		c.metrics.OrphanedInstances.WithLabelValues(string(provider)).Set(float64(count))
+	}
+
+	if err := c.saveOrphans(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	return kerrors.NewAggregate(errs)
+}
+
+// orphanKey returns the key of an instance in the orphans and the state ConfigMap.
+func orphanKey(provider providerconfig.CloudProvider, instanceID string) string {
+	return fmt.Sprintf("%s.%s", provider, instanceID)
+}
+
+// loadOrphans reads the orphaned instances from the state ConfigMap.
+func (c *collector) loadOrphans(ctx context.Context) error {
+	configMap := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, c.options.StateConfigMap, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			c.orphans = map[string]*orphan{}
+			return nil
+		}
+		return fmt.Errorf("failed to get state ConfigMap %s: %w", c.options.StateConfigMap, err)
+	}
+
+	orphans := make(map[string]*orphan, len(configMap.Data))
+	for key, value := range configMap.Data {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.log.Warnw("Ignoring invalid orphaned since time in state ConfigMap", "key", key, zap.Error(err))
+			continue
+		}
+		orphans[key] = &orphan{since: since}
+	}
+	c.orphans = orphans
+
+	return nil
+}
+
+// saveOrphans writes the orphaned instances to the state ConfigMap, if they changed.
+func (c *collector) saveOrphans(ctx context.Context) error {
+	data := make(map[string]string, len(c.orphans))
+	for key, o := range c.orphans {
+		data[key] = o.since.UTC().Format(time.RFC3339)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, c.options.StateConfigMap, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get state ConfigMap %s: %w", c.options.StateConfigMap, err)
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: c.options.StateConfigMap.Namespace,
+				Name:      c.options.StateConfigMap.Name,
+			},
+			Data: data,
+		}
+		if err := c.client.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create state ConfigMap %s: %w", c.options.StateConfigMap, err)
+		}
+		return nil
+	}
+
+	if (len(configMap.Data) == 0 && len(data) == 0) || equality.Semantic.DeepEqual(configMap.Data, data) {
+		return nil
+	}
+	configMap.Data = data
+	if err := c.client.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update state ConfigMap %s: %w", c.options.StateConfigMap, err)
+	}
+	return nil
+}
+
+func (c *collector) handleOrphan(ctx context.Context, prov cloudprovidertypes.Provider, sc *scope, inst cloudprovidertypes.TaggedInstance, key string, now time.Time) error {
+	log := c.log.With("provider", sc.provider, "instance", inst.ID(), "name", inst.Name(), "machineUID", inst.MachineUID)
+
+	o, ok := c.orphans[key]
+	if !ok {
+		o = &orphan{since: now}
+		c.orphans[key] = o
+		log.Info("Found orphaned instance")
+		c.recorder.Eventf(sc.object, corev1.EventTypeWarning, "OrphanedInstance", "Instance %s (%s) belongs to machine %s which does not exist", inst.Name(), inst.ID(), inst.MachineUID)
+	}
+
+	if c.options.Mode != ModeDelete || now.Sub(o.since) < c.options.GracePeriod {
+		return nil
+	}
+
+	// Cleanup might only trigger the deletion, so it is called again as long as the
+	// instance is listed.
+	if _, err := prov.Cleanup(ctx, log, orphanMachine(sc, inst, now), c.providerData(ctx)); err != nil {
+		return fmt.Errorf("failed to delete orphaned instance %s: %w", inst.ID(), err)
+	}
+	if !o.deleted {
+		o.deleted = true
+		log.Info("Deleted orphaned instance")
+		c.metrics.Deletions.WithLabelValues(string(sc.provider)).Inc()
+		c.recorder.Eventf(sc.object, corev1.EventTypeNormal, "OrphanedInstanceDeleted", "Deleted instance %s (%s) of machine %s which does not exist", inst.Name(), inst.ID(), inst.MachineUID)
+	}
+
+	return nil
+}
+
+// providerData returns the data passed to the provider when deleting an orphaned
+// instance. As its machine does not exist, there is nothing to update.
+func (c *collector) providerData(ctx context.Context) *cloudprovidertypes.ProviderData {
+	return &cloudprovidertypes.ProviderData{
+		Ctx:    ctx,
+		Client: c.client,
+		Update: func(*clusterv1alpha1.Machine, ...cloudprovidertypes.MachineModifier) error {
+			return nil
+		},
+	}
+}
+
+// addScope adds the provider spec of the given machine spec to the scopes, unless
+// an identical one exists already.
+func addScope(scopes map[string]*scope, spec clusterv1alpha1.MachineSpec, object ctrlruntimeclient.Object) {
+	if spec.ProviderSpec.Value == nil {
+		return
+	}
+	providerConfig, err := providerconfig.GetConfig(spec.ProviderSpec)
+	if err != nil {
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s", providerConfig.CloudProvider, spec.ProviderSpec.Value.Raw)
+	if _, ok := scopes[key]; !ok {
+		scopes[key] = &scope{
+			provider: providerConfig.CloudProvider,
+			spec:     spec,
+			object:   object,
+		}
+	}
+}
+
+// orphanMachine returns a Machine for the given orphaned instance, which lets the
+// provider find and delete the instance.
+func orphanMachine(sc *scope, inst cloudprovidertypes.TaggedInstance, now time.Time) *clusterv1alpha1.Machine {
+	deletionTimestamp := metav1.NewTime(now)
+	machine := &clusterv1alpha1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              inst.Name(),
+			Namespace:         sc.object.GetNamespace(),
+			UID:               inst.MachineUID,
+			DeletionTimestamp: &deletionTimestamp,
+		},
+		Spec: *sc.spec.DeepCopy(),
+	}
+	machine.Spec.Name = inst.Name()
+	return machine
+}
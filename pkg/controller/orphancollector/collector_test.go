@@ -0,0 +1,190 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphancollector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/instance"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func init() {
+	if err := clusterv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		panic(fmt.Sprintf("failed to add clusterv1alpha1 api to scheme: %v", err))
+	}
+}
+
+type fakeInstance struct {
+	name string
+	id   string
+}
+
+func (i *fakeInstance) Name() string {
+	return i.name
+}
+
+func (i *fakeInstance) ID() string {
+	return i.id
+}
+
+func (i *fakeInstance) ProviderID() string {
+	return ""
+}
+
+func (i *fakeInstance) Status() instance.Status {
+	return instance.StatusRunning
+}
+
+func (i *fakeInstance) Addresses() map[string]corev1.NodeAddressType {
+	return nil
+}
+
+// fakeProvider lists the given instances and records the deleted ones.
+type fakeProvider struct {
+	cloudprovidertypes.Provider
+	instances []cloudprovidertypes.TaggedInstance
+	deleted   []types.UID
+}
+
+func (p *fakeProvider) ListInstances(_ context.Context, _ *zap.SugaredLogger, _ clusterv1alpha1.MachineSpec, clusterID string) ([]cloudprovidertypes.TaggedInstance, error) {
+	if clusterID != "cluster-id" {
+		return nil, fmt.Errorf("expected instances of cluster cluster-id to be listed, got %q", clusterID)
+	}
+	return p.instances, nil
+}
+
+func (p *fakeProvider) Cleanup(_ context.Context, _ *zap.SugaredLogger, machine *clusterv1alpha1.Machine, _ *cloudprovidertypes.ProviderData) (bool, error) {
+	p.deleted = append(p.deleted, machine.UID)
+	return true, nil
+}
+
+func taggedInstance(id string, machineUID types.UID) cloudprovidertypes.TaggedInstance {
+	return cloudprovidertypes.TaggedInstance{
+		Instance:   &fakeInstance{name: "instance-" + id, id: id},
+		MachineUID: machineUID,
+	}
+}
+
+func TestCollect(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          Mode
+		instances     []cloudprovidertypes.TaggedInstance
+		expectDeleted []types.UID
+		expectOrphans int
+	}{
+		{
+			name:          "instances of existing machines are kept",
+			mode:          ModeDelete,
+			instances:     []cloudprovidertypes.TaggedInstance{taggedInstance("1", "existing-uid")},
+			expectOrphans: 0,
+		},
+		{
+			name:          "orphaned instances are only reported in dry-run mode",
+			mode:          ModeDryRun,
+			instances:     []cloudprovidertypes.TaggedInstance{taggedInstance("1", "existing-uid"), taggedInstance("2", "orphaned-uid")},
+			expectOrphans: 1,
+		},
+		{
+			name:          "orphaned instances are deleted after the grace period in delete mode",
+			mode:          ModeDelete,
+			instances:     []cloudprovidertypes.TaggedInstance{taggedInstance("1", "existing-uid"), taggedInstance("2", "orphaned-uid")},
+			expectDeleted: []types.UID{"orphaned-uid"},
+			expectOrphans: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			machine := &clusterv1alpha1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "existing-machine",
+					Namespace: metav1.NamespaceSystem,
+					UID:       "existing-uid",
+				},
+				Spec: clusterv1alpha1.MachineSpec{
+					ProviderSpec: clusterv1alpha1.ProviderSpec{
+						Value: &runtime.RawExtension{Raw: []byte(`{"cloudProvider":"fake","cloudProviderSpec":{}}`)},
+					},
+				},
+			}
+
+			client := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(machine).
+				Build()
+
+			prov := &fakeProvider{instances: test.instances}
+			newCollector := func() *collector {
+				return &collector{
+					client:   client,
+					log:      zap.NewNop().Sugar(),
+					recorder: &record.FakeRecorder{},
+					metrics:  NewMetrics(),
+					options: Options{
+						Mode:           test.mode,
+						GracePeriod:    time.Hour,
+						ClusterID:      "cluster-id",
+						StateConfigMap: types.NamespacedName{Namespace: metav1.NamespaceSystem, Name: "orphan-collector"},
+					},
+					getProvider: func(providerconfig.CloudProvider, providerconfig.ConfigVarResolver) (cloudprovidertypes.Provider, error) {
+						return prov, nil
+					},
+				}
+			}
+
+			now := time.Now()
+			if err := newCollector().collect(ctx, now); err != nil {
+				t.Fatalf("failed to collect: %v", err)
+			}
+			if len(prov.deleted) > 0 {
+				t.Fatalf("expected no instance to be deleted within the grace period, got %v", prov.deleted)
+			}
+
+			// The grace period must not restart with the collector.
+			c := newCollector()
+			if err := c.collect(ctx, now.Add(2*time.Hour)); err != nil {
+				t.Fatalf("failed to collect: %v", err)
+			}
+			if fmt.Sprint(prov.deleted) != fmt.Sprint(test.expectDeleted) {
+				t.Errorf("expected deleted instances of machines %v, got %v", test.expectDeleted, prov.deleted)
+			}
+
+			if len(c.orphans) != test.expectOrphans {
+				t.Errorf("expected %d orphaned instances, got %d", test.expectOrphans, len(c.orphans))
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package orphancollector contains a collector that periodically lists the instances
+tagged with a machine UID at the cloud providers and reports or deletes those whose
+Machine no longer exists, e.g. because its finalizer was removed by hand.
+
+Instances are listed with the provider specs of the existing Machines and
+MachineDeployments, so instances of credentials or locations no longer used by any
+of them are not found. Only instances tagged with the ID of the cluster are listed,
+so instances of other clusters sharing the same account are never collected.
+*/
+package orphancollector
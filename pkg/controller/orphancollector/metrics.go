@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphancollector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsPrefix = "machine_controller_"
+
+// Metrics is a struct of all metrics used by the orphan collector.
+type Metrics struct {
+	OrphanedInstances *prometheus.GaugeVec
+	Deletions         *prometheus.CounterVec
+	Errors            prometheus.Counter
+}
+
+// NewMetrics creates new Metrics for the orphan collector.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		OrphanedInstances: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: metricsPrefix + "orphaned_instances",
+			Help: "The number of instances at the cloud provider whose machine does not exist anymore",
+		}, []string{"provider"}),
+		Deletions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metricsPrefix + "orphaned_instance_deletions_total",
+			Help: "The total number of orphaned instances deleted by the orphan collector",
+		}, []string{"provider"}),
+		Errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: metricsPrefix + "orphan_collector_errors_total",
+			Help: "The total number of failed orphan collections",
+		}),
+	}
+}
+
+// MustRegister registers all metrics with the given registerer.
+func (m *Metrics) MustRegister(registerer prometheus.Registerer) {
+	registerer.MustRegister(
+		m.OrphanedInstances,
+		m.Deletions,
+		m.Errors,
+	)
+}
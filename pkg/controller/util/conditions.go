@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetMachineCondition sets the given condition, only updating its LastTransitionTime
+// if its status changes.
+func SetMachineCondition(machine *clusterv1alpha1.Machine, conditionType corev1.NodeConditionType, status corev1.ConditionStatus, reason, message string) {
+	var condition *corev1.NodeCondition
+	for i := range machine.Status.Conditions {
+		if machine.Status.Conditions[i].Type == conditionType {
+			condition = &machine.Status.Conditions[i]
+			break
+		}
+	}
+	if condition == nil {
+		machine.Status.Conditions = append(machine.Status.Conditions, corev1.NodeCondition{Type: conditionType})
+		condition = &machine.Status.Conditions[len(machine.Status.Conditions)-1]
+	}
+	if condition.Status != status {
+		condition.Status = status
+		condition.LastTransitionTime = metav1.Now()
+	}
+	condition.Reason = reason
+	condition.Message = message
+}
+
+// SummarizeMachineConditions aggregates the lifecycle conditions of the given machines.
+func SummarizeMachineConditions(machines []*clusterv1alpha1.Machine) []clusterv1alpha1.MachineConditionSummary {
+	summaries := conditionSummaries{}
+	for _, machine := range machines {
+		for _, condition := range machine.Status.Conditions {
+			summary := clusterv1alpha1.MachineConditionSummary{
+				Type:               condition.Type,
+				Reason:             condition.Reason,
+				LastTransitionTime: condition.LastTransitionTime,
+			}
+			if condition.Message != "" {
+				summary.Message = fmt.Sprintf("%s: %s", machine.Name, condition.Message)
+			}
+
+			switch condition.Status {
+			case corev1.ConditionTrue:
+				summary.TrueReplicas = 1
+			case corev1.ConditionFalse:
+				summary.FalseReplicas = 1
+			default:
+				summary.UnknownReplicas = 1
+			}
+
+			summaries.add(summary)
+		}
+	}
+	return summaries.list()
+}
+
+// SummarizeMachineSetConditions aggregates the condition summaries of the given machine sets.
+func SummarizeMachineSetConditions(machineSets []*clusterv1alpha1.MachineSet) []clusterv1alpha1.MachineConditionSummary {
+	summaries := conditionSummaries{}
+	for _, ms := range machineSets {
+		if ms == nil {
+			continue
+		}
+		for _, summary := range ms.Status.Conditions {
+			summaries.add(summary)
+		}
+	}
+	return summaries.list()
+}
+
+type conditionSummaries map[corev1.NodeConditionType]*clusterv1alpha1.MachineConditionSummary
+
+// add adds the counts of in to the summary of its type. The reason and message are taken from
+// the condition which transitioned last, ties are broken by message and reason to keep the
+// summary stable between reconciliations.
+func (s conditionSummaries) add(in clusterv1alpha1.MachineConditionSummary) {
+	summary, exists := s[in.Type]
+	if !exists {
+		summary = &clusterv1alpha1.MachineConditionSummary{Type: in.Type}
+		s[in.Type] = summary
+	}
+
+	summary.TrueReplicas += in.TrueReplicas
+	summary.FalseReplicas += in.FalseReplicas
+	summary.UnknownReplicas += in.UnknownReplicas
+
+	newer := summary.LastTransitionTime.Before(&in.LastTransitionTime)
+	if summary.LastTransitionTime.Equal(&in.LastTransitionTime) {
+		newer = !exists || in.Message < summary.Message || (in.Message == summary.Message && in.Reason < summary.Reason)
+	}
+	if newer {
+		summary.Reason = in.Reason
+		summary.Message = in.Message
+		summary.LastTransitionTime = in.LastTransitionTime
+	}
+}
+
+// list returns the summaries of the lifecycle conditions in the order in which a machine reaches them.
+func (s conditionSummaries) list() []clusterv1alpha1.MachineConditionSummary {
+	var result []clusterv1alpha1.MachineConditionSummary
+	for _, conditionType := range common.MachineLifecycleConditions {
+		if summary, exists := s[conditionType]; exists {
+			result = append(result, *summary)
+		}
+	}
+	return result
+}
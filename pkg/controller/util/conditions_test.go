@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"k8c.io/machine-controller/sdk/apis/cluster/common"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSummarizeConditions(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(earlier.Add(time.Minute))
+
+	newMachine := func(name string, conditions ...corev1.NodeCondition) *clusterv1alpha1.Machine {
+		return &clusterv1alpha1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     clusterv1alpha1.MachineStatus{Conditions: conditions},
+		}
+	}
+
+	machines := []*clusterv1alpha1.Machine{
+		newMachine("machine-a",
+			corev1.NodeCondition{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			corev1.NodeCondition{Type: common.NodeJoinedCondition, Status: corev1.ConditionTrue, LastTransitionTime: earlier},
+			corev1.NodeCondition{Type: common.InstanceProvisionedCondition, Status: corev1.ConditionTrue, LastTransitionTime: earlier},
+		),
+		newMachine("machine-b",
+			corev1.NodeCondition{Type: common.NodeJoinedCondition, Status: corev1.ConditionFalse, LastTransitionTime: later, Reason: common.WaitingForNodeReason, Message: "No node was found for the instance"},
+			corev1.NodeCondition{Type: common.InstanceProvisionedCondition, Status: corev1.ConditionTrue, LastTransitionTime: earlier},
+		),
+	}
+
+	expected := []clusterv1alpha1.MachineConditionSummary{
+		{
+			Type:               common.InstanceProvisionedCondition,
+			TrueReplicas:       2,
+			LastTransitionTime: earlier,
+		},
+		{
+			Type:               common.NodeJoinedCondition,
+			TrueReplicas:       1,
+			FalseReplicas:      1,
+			Reason:             common.WaitingForNodeReason,
+			Message:            "machine-b: No node was found for the instance",
+			LastTransitionTime: later,
+		},
+	}
+
+	summaries := SummarizeMachineConditions(machines)
+	if !equality.Semantic.DeepEqual(summaries, expected) {
+		t.Fatalf("unexpected machine set conditions:\nexpected: %+v\ngot:      %+v", expected, summaries)
+	}
+
+	// The summary must not depend on the order of the machines.
+	if reversed := SummarizeMachineConditions([]*clusterv1alpha1.Machine{machines[1], machines[0]}); !equality.Semantic.DeepEqual(reversed, expected) {
+		t.Errorf("expected summary to be independent of the machine order, got %+v", reversed)
+	}
+
+	machineSets := []*clusterv1alpha1.MachineSet{
+		{Status: clusterv1alpha1.MachineSetStatus{Conditions: summaries}},
+		{Status: clusterv1alpha1.MachineSetStatus{Conditions: []clusterv1alpha1.MachineConditionSummary{
+			{Type: common.NodeJoinedCondition, TrueReplicas: 3, LastTransitionTime: earlier},
+		}}},
+		nil,
+	}
+
+	expected[1].TrueReplicas = 4
+	if merged := SummarizeMachineSetConditions(machineSets); !equality.Semantic.DeepEqual(merged, expected) {
+		t.Errorf("unexpected machine deployment conditions:\nexpected: %+v\ngot:      %+v", expected, merged)
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MachineNodeNameIndex indexes Machines by the name of their node. It must be added once
+// with AddMachineNodeNameIndex before the controllers listing Machines by it are started.
+const MachineNodeNameIndex = "status.nodeRef.name"
+
+// AddMachineNodeNameIndex adds MachineNodeNameIndex to the field indexer.
+func AddMachineNodeNameIndex(ctx context.Context, indexer ctrlruntimeclient.FieldIndexer) error {
+	if err := indexer.IndexField(ctx, &clusterv1alpha1.Machine{}, MachineNodeNameIndex, IndexMachineByNodeName); err != nil {
+		return fmt.Errorf("failed to add index for the node name of machines: %w", err)
+	}
+	return nil
+}
+
+// IndexMachineByNodeName returns the name of the node of a Machine for MachineNodeNameIndex.
+func IndexMachineByNodeName(obj ctrlruntimeclient.Object) []string {
+	machine, ok := obj.(*clusterv1alpha1.Machine)
+	if !ok || machine.Status.NodeRef == nil || machine.Status.NodeRef.Name == "" {
+		return nil
+	}
+	return []string{machine.Status.NodeRef.Name}
+}
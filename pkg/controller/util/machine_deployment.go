@@ -46,9 +46,16 @@ const (
 	RevisionHistoryAnnotation              = sdkclustercommon.RevisionHistoryAnnotation
 	DesiredReplicasAnnotation              = sdkclustercommon.DesiredReplicasAnnotation
 	MaxReplicasAnnotation                  = sdkclustercommon.MaxReplicasAnnotation
+	CanaryPromotedAnnotation               = sdkclustercommon.CanaryPromotedAnnotation
+	CanaryApprovedRevisionAnnotation       = sdkclustercommon.CanaryApprovedRevisionAnnotation
+	ChangeCauseAnnotation                  = sdkclustercommon.ChangeCauseAnnotation
 	FailedMSCreateReason                   = sdkclustercommon.FailedMSCreateReason
 	FoundNewMSReason                       = sdkclustercommon.FoundNewMSReason
 	PausedDeployReason                     = sdkclustercommon.PausedDeployReason
+	RollbackRevisionNotFound               = sdkclustercommon.RollbackRevisionNotFound
+	RollbackTemplateUnchanged              = sdkclustercommon.RollbackTemplateUnchanged
+	RollbackRejected                       = sdkclustercommon.RollbackRejected
+	RollbackDone                           = sdkclustercommon.RollbackDone
 	MinimumReplicasAvailable               = sdkclustercommon.MinimumReplicasAvailable
 	MinimumReplicasUnavailable             = sdkclustercommon.MinimumReplicasUnavailable
 )
@@ -129,6 +136,55 @@ func MaxRevision(log *zap.SugaredLogger, allMSs []*clusterv1alpha1.MachineSet) i
 	return maxRev
 }
 
+// LastRevision finds the second max revision number in all machine sets (the last revision).
+func LastRevision(log *zap.SugaredLogger, allMSs []*clusterv1alpha1.MachineSet) int64 {
+	maxRev, secMaxRev := int64(0), int64(0)
+	for _, ms := range allMSs {
+		if v, err := Revision(ms); err != nil {
+			log.Debugw("Failed to parse revision for MachineSet", "machineset", ctrlruntimeclient.ObjectKeyFromObject(ms), zap.Error(err))
+		} else if v >= maxRev {
+			secMaxRev = maxRev
+			maxRev = v
+		} else if v > secMaxRev {
+			secMaxRev = v
+		}
+	}
+	return secMaxRev
+}
+
+// RolloutRevision is a revision of a machine deployment.
+type RolloutRevision struct {
+	Revision    int64
+	MachineSet  *clusterv1alpha1.MachineSet
+	ChangeCause string
+}
+
+// RolloutHistory returns the revisions served by the machine sets, sorted by revision. A machine set
+// which was rolled back to serves multiple revisions.
+func RolloutHistory(log *zap.SugaredLogger, allMSs []*clusterv1alpha1.MachineSet) []RolloutRevision {
+	history := []RolloutRevision{}
+	for _, ms := range allMSs {
+		revisions := []string{ms.Annotations[RevisionAnnotation]}
+		if revisionHistory := ms.Annotations[RevisionHistoryAnnotation]; revisionHistory != "" {
+			revisions = append(revisions, strings.Split(revisionHistory, ",")...)
+		}
+		for _, revision := range revisions {
+			v, err := strconv.ParseInt(revision, 10, 64)
+			if err != nil {
+				log.Debugw("Failed to parse revision for MachineSet", "machineset", ctrlruntimeclient.ObjectKeyFromObject(ms), "revision", revision, zap.Error(err))
+				continue
+			}
+			history = append(history, RolloutRevision{
+				Revision:    v,
+				MachineSet:  ms,
+				ChangeCause: ms.Annotations[ChangeCauseAnnotation],
+			})
+		}
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Revision < history[j].Revision })
+	return history
+}
+
 // Revision returns the revision number of the input object.
 func Revision(obj runtime.Object) (int64, error) {
 	acc, err := meta.Accessor(obj)
@@ -148,6 +204,8 @@ var annotationsToSkip = map[string]bool{
 	RevisionHistoryAnnotation:          true,
 	DesiredReplicasAnnotation:          true,
 	MaxReplicasAnnotation:              true,
+	CanaryPromotedAnnotation:           true,
+	CanaryApprovedRevisionAnnotation:   true,
 }
 
 // skipCopyAnnotation returns true if we should skip copying the annotation with the given annotation key
@@ -179,6 +237,38 @@ func copyDeploymentAnnotationsToMachineSet(deployment *clusterv1alpha1.MachineDe
 	return msAnnotationsChanged
 }
 
+// SetFromMachineSetTemplate sets the desired machine template from a machine set template to the given deployment.
+func SetFromMachineSetTemplate(deployment *clusterv1alpha1.MachineDeployment, template clusterv1alpha1.MachineTemplateSpec) *clusterv1alpha1.MachineDeployment {
+	deployment.Spec.Template.ObjectMeta = template.ObjectMeta
+	deployment.Spec.Template.Spec = template.Spec
+	deployment.Spec.Template.ObjectMeta.Labels = CloneAndRemoveLabel(
+		deployment.Spec.Template.ObjectMeta.Labels,
+		DefaultMachineDeploymentUniqueLabelKey)
+	return deployment
+}
+
+// SetDeploymentAnnotationsTo sets deployment's annotations as given machine set's annotations.
+// This action should be done if and only if the deployment is rolling back to this machine set.
+// Note that apply and revision annotations are not changed.
+func SetDeploymentAnnotationsTo(deployment *clusterv1alpha1.MachineDeployment, rollbackToMS *clusterv1alpha1.MachineSet) {
+	deployment.Annotations = getSkippedAnnotations(deployment.Annotations)
+	for k, v := range rollbackToMS.Annotations {
+		if !skipCopyAnnotation(k) {
+			deployment.Annotations[k] = v
+		}
+	}
+}
+
+func getSkippedAnnotations(annotations map[string]string) map[string]string {
+	skippedAnnotations := make(map[string]string)
+	for k, v := range annotations {
+		if skipCopyAnnotation(k) {
+			skippedAnnotations[k] = v
+		}
+	}
+	return skippedAnnotations
+}
+
 // GetDesiredReplicasAnnotation returns the number of desired replicas.
 func GetDesiredReplicasAnnotation(log *zap.SugaredLogger, ms *clusterv1alpha1.MachineSet) (int32, bool) {
 	return getIntFromAnnotation(log, ms, DesiredReplicasAnnotation)
@@ -333,8 +423,12 @@ func MaxUnavailable(deployment clusterv1alpha1.MachineDeployment) int32 {
 	return maxUnavailable
 }
 
-// MaxSurge returns the maximum surge machines a rolling deployment can take.
+// MaxSurge returns the maximum surge machines a rolling deployment can take. A blue-green
+// deployment brings up all desired machines before removing the old ones.
 func MaxSurge(deployment clusterv1alpha1.MachineDeployment) int32 {
+	if deployment.Spec.Strategy.Type == sdkclustercommon.BlueGreenMachineDeploymentStrategyType {
+		return *(deployment.Spec.Replicas)
+	}
 	if !IsRollingUpdate(&deployment) {
 		return int32(0)
 	}
@@ -404,6 +498,22 @@ func EqualIgnoreHash(template1, template2 *clusterv1alpha1.MachineTemplateSpec)
 	return apiequality.Semantic.DeepEqual(t1Copy, t2Copy)
 }
 
+// EqualIgnoreInPlaceFields returns true if two given machineTemplateSpec only differ in fields that can be
+// updated in place, i.e. labels, annotations and taints of the Machine and its Node. Kubelet flags are
+// stored as annotations of the Machine or its Node and are thus updated in place as well.
+func EqualIgnoreInPlaceFields(template1, template2 *clusterv1alpha1.MachineTemplateSpec) bool {
+	t1Copy := template1.DeepCopy()
+	t2Copy := template2.DeepCopy()
+	for _, t := range []*clusterv1alpha1.MachineTemplateSpec{t1Copy, t2Copy} {
+		t.Labels = nil
+		t.Annotations = nil
+		t.Spec.Labels = nil
+		t.Spec.Annotations = nil
+		t.Spec.Taints = nil
+	}
+	return apiequality.Semantic.DeepEqual(t1Copy, t2Copy)
+}
+
 // FindNewMachineSet returns the new MS this given deployment targets (the one with the same machine template).
 func FindNewMachineSet(deployment *clusterv1alpha1.MachineDeployment, msList []*clusterv1alpha1.MachineSet) *clusterv1alpha1.MachineSet {
 	sort.Sort(MachineSetsByCreationTimestamp(msList))
@@ -485,9 +595,30 @@ func GetAvailableReplicaCountForMachineSets(machineSets []*clusterv1alpha1.Machi
 	return totalAvailableReplicas
 }
 
-// IsRollingUpdate returns true if the strategy type is a rolling update.
+// IsRollingUpdate returns true if the strategy type is a rolling update. This is also the case for
+// in-place updates, which fall back to a rolling update for changes that cannot be applied in place,
+// and for canary updates, which replace the remaining machines using a rolling update.
 func IsRollingUpdate(deployment *clusterv1alpha1.MachineDeployment) bool {
-	return deployment.Spec.Strategy.Type == sdkclustercommon.RollingUpdateMachineDeploymentStrategyType
+	return deployment.Spec.Strategy.Type == sdkclustercommon.RollingUpdateMachineDeploymentStrategyType ||
+		deployment.Spec.Strategy.Type == sdkclustercommon.InPlaceMachineDeploymentStrategyType ||
+		deployment.Spec.Strategy.Type == sdkclustercommon.CanaryMachineDeploymentStrategyType
+}
+
+// CanaryReplicas returns the number of machines a canary deployment replaces first. It is at
+// least one and at most the number of desired replicas.
+func CanaryReplicas(deployment *clusterv1alpha1.MachineDeployment) (int32, error) {
+	desired := *(deployment.Spec.Replicas)
+	if deployment.Spec.Strategy.Canary == nil || deployment.Spec.Strategy.Canary.Replicas == nil {
+		return integer.Int32Min(1, desired), nil
+	}
+	replicas, err := intstrutil.GetValueFromIntOrPercent(deployment.Spec.Strategy.Canary.Replicas, int(desired), true)
+	if err != nil {
+		return 0, err
+	}
+	if replicas < 1 {
+		replicas = 1
+	}
+	return integer.Int32Min(int32(replicas), desired), nil
 }
 
 // DeploymentComplete considers a deployment to be complete once all of its desired replicas
@@ -523,6 +654,9 @@ func NewMSNewReplicas(deployment *clusterv1alpha1.MachineDeployment, allMSs []*c
 		// Do not exceed the number of desired replicas.
 		scaleUpCount = integer.Int32Min(scaleUpCount, *(deployment.Spec.Replicas)-*(newMS.Spec.Replicas))
 		return *(newMS.Spec.Replicas) + scaleUpCount, nil
+	case sdkclustercommon.BlueGreenMachineDeploymentStrategyType:
+		// The new machine set is scaled up completely before the old ones are scaled down.
+		return *(deployment.Spec.Replicas), nil
 	default:
 		// Check if we can scale up.
 		maxSurge, err := intstrutil.GetValueFromIntOrPercent(deployment.Spec.Strategy.RollingUpdate.MaxSurge, int(*(deployment.Spec.Replicas)), true)
@@ -630,6 +764,22 @@ func CloneAndAddLabel(labels map[string]string, labelKey, labelValue string) map
 	return newLabels
 }
 
+// Clones the given map and returns a new map with the given key removed.
+// Returns the given map, if labelKey is empty.
+func CloneAndRemoveLabel(labels map[string]string, labelKey string) map[string]string {
+	if labelKey == "" {
+		// Don't need to remove a label.
+		return labels
+	}
+	// Clone.
+	newLabels := map[string]string{}
+	for key, value := range labels {
+		newLabels[key] = value
+	}
+	delete(newLabels, labelKey)
+	return newLabels
+}
+
 // Clones the given selector and returns a new selector with the given key and value added.
 // Returns the given selector, if labelKey is empty.
 func CloneSelectorAndAddLabel(selector *metav1.LabelSelector, labelKey, labelValue string) *metav1.LabelSelector {
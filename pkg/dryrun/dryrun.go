@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dryrun tells what the machine controller would do for a machine spec, without
+// creating anything at the cloud provider.
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/cloudprovider"
+	cloudprovidererrors "k8c.io/machine-controller/pkg/cloudprovider/errors"
+	cloudprovidertypes "k8c.io/machine-controller/pkg/cloudprovider/types"
+	"k8c.io/machine-controller/pkg/cloudprovider/util"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/bootstrap"
+	"k8c.io/machine-controller/sdk/providerconfig"
+	"k8c.io/machine-controller/sdk/providerconfig/configvar"
+	"k8c.io/machine-controller/sdk/userdata"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Result is the outcome of a dry run.
+type Result struct {
+	CloudProvider   providerconfig.CloudProvider   `json:"cloudProvider"`
+	OperatingSystem providerconfig.OperatingSystem `json:"operatingSystem"`
+	// Spec is the machine spec with the defaults of the cloud provider applied.
+	Spec clusterv1alpha1.MachineSpec `json:"spec"`
+	// Plan describes the instance that would be created. It is nil if the cloud provider
+	// does not implement cloudprovidertypes.Planner.
+	Plan *cloudprovidertypes.Plan `json:"plan,omitempty"`
+	// BootstrapSecret is the secret the userdata of the machine would be read from.
+	BootstrapSecret string `json:"bootstrapSecret,omitempty"`
+	// UserdataSize is the size of the rendered userdata in bytes. It is nil if the
+	// bootstrap secret does not exist yet.
+	UserdataSize *int `json:"userdataSize,omitempty"`
+}
+
+// Run defaults and validates the spec like the machine controller does before creating an
+// instance, and returns the plan of the cloud provider for it. machineDeployment is the name
+// of the MachineDeployment in the namespace the machine would belong to, which is used to find
+// its bootstrap secret. It may be empty, in which case the userdata size is not reported.
+func Run(ctx context.Context, log *zap.SugaredLogger, client ctrlruntimeclient.Client, namespace, machineDeployment string, spec clusterv1alpha1.MachineSpec) (*Result, error) {
+	providerConfig, err := providerconfig.GetConfig(spec.ProviderSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read machine.spec.providerSpec: %w", err)
+	}
+	if err := providerConfig.OperatingSystem.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to get OS '%s': %w", providerConfig.OperatingSystem, err)
+	}
+
+	// Apply the defaults of the webhook, which the machine controller relies on.
+	providerConfig.OperatingSystemSpec, err = userdata.DefaultOperatingSystemSpec(providerConfig.OperatingSystem, providerConfig.OperatingSystemSpec)
+	if err != nil {
+		return nil, err
+	}
+	rawConfig, err := json.Marshal(providerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to json marshal machine.spec.providerSpec: %w", err)
+	}
+	spec.ProviderSpec.Value = &runtime.RawExtension{Raw: rawConfig}
+
+	prov, err := cloudprovider.ForProvider(providerConfig.CloudProvider, configvar.NewResolver(ctx, client))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cloud provider %q: %w", providerConfig.CloudProvider, err)
+	}
+
+	defaultedSpec, err := prov.AddDefaults(log, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to default machineSpec: %w", err)
+	}
+	if err := prov.Validate(ctx, log, defaultedSpec); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	result := &Result{
+		CloudProvider:   providerConfig.CloudProvider,
+		OperatingSystem: providerConfig.OperatingSystem,
+		Spec:            defaultedSpec,
+	}
+
+	if planner, ok := prov.(cloudprovidertypes.Planner); ok {
+		result.Plan, err = planner.Plan(ctx, log, defaultedSpec)
+		if err != nil && !errors.Is(err, cloudprovidererrors.ErrNotSupported) {
+			return nil, fmt.Errorf("failed to plan instance: %w", err)
+		}
+	}
+
+	if machineDeployment != "" {
+		result.BootstrapSecret = fmt.Sprintf(bootstrap.CloudConfigSecretNamePattern, machineDeployment, namespace, bootstrap.BootstrapCloudConfig)
+		result.UserdataSize, err = userdataSize(ctx, client, result.BootstrapSecret)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// userdataSize returns the size of the userdata in the bootstrap secret, or nil if the
+// secret does not exist.
+func userdataSize(ctx context.Context, client ctrlruntimeclient.Client, name string) (*int, error) {
+	secret := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Name: name, Namespace: util.CloudInitNamespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get bootstrap secret %s/%s: %w", util.CloudInitNamespace, name, err)
+	}
+
+	size := len(secret.Data["cloud-config"])
+	return &size, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/pkg/cloudprovider/provider/fake"
+	"k8c.io/machine-controller/pkg/cloudprovider/util"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func fakeMachineSpec(t *testing.T, passValidation bool) clusterv1alpha1.MachineSpec {
+	t.Helper()
+
+	cloudProviderSpec, err := json.Marshal(fake.CloudProviderSpec{PassValidation: passValidation})
+	if err != nil {
+		t.Fatalf("failed to marshal fake cloud provider spec: %v", err)
+	}
+	rawConfig, err := json.Marshal(providerconfig.Config{
+		CloudProvider:       providerconfig.CloudProviderFake,
+		CloudProviderSpec:   runtime.RawExtension{Raw: cloudProviderSpec},
+		OperatingSystem:     providerconfig.OperatingSystemUbuntu,
+		OperatingSystemSpec: runtime.RawExtension{Raw: []byte("{}")},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal providerconfig: %v", err)
+	}
+
+	return clusterv1alpha1.MachineSpec{
+		ProviderSpec: clusterv1alpha1.ProviderSpec{Value: &runtime.RawExtension{Raw: rawConfig}},
+		Versions:     clusterv1alpha1.MachineVersionInfo{Kubelet: "1.30.0"},
+	}
+}
+
+func TestRun(t *testing.T) {
+	bootstrapSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "workers-kube-system-bootstrap-config", Namespace: util.CloudInitNamespace},
+		Data:       map[string][]byte{"cloud-config": []byte("#cloud-config\nhostname: <MACHINE_NAME>\n")},
+	}
+	client := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(bootstrapSecret).Build()
+	log := zap.NewNop().Sugar()
+
+	spec := fakeMachineSpec(t, true)
+	originalRaw := string(spec.ProviderSpec.Value.Raw)
+
+	result, err := Run(context.Background(), log, client, "kube-system", "workers", spec)
+	if err != nil {
+		t.Fatalf("failed to run dry run: %v", err)
+	}
+	if result.CloudProvider != providerconfig.CloudProviderFake || result.OperatingSystem != providerconfig.OperatingSystemUbuntu {
+		t.Errorf("unexpected cloud provider %q or operating system %q", result.CloudProvider, result.OperatingSystem)
+	}
+	if result.Plan != nil {
+		t.Errorf("expected no plan for a cloud provider without planner, got %+v", result.Plan)
+	}
+	if result.BootstrapSecret != bootstrapSecret.Name {
+		t.Errorf("expected bootstrap secret %q, got %q", bootstrapSecret.Name, result.BootstrapSecret)
+	}
+	if result.UserdataSize == nil || *result.UserdataSize != len(bootstrapSecret.Data["cloud-config"]) {
+		t.Errorf("expected userdata size %d, got %v", len(bootstrapSecret.Data["cloud-config"]), result.UserdataSize)
+	}
+	if string(spec.ProviderSpec.Value.Raw) != originalRaw {
+		t.Error("expected the provider spec of the caller to be left untouched")
+	}
+
+	// Without a bootstrap secret the userdata size is unknown.
+	result, err = Run(context.Background(), log, client, "kube-system", "other", spec)
+	if err != nil {
+		t.Fatalf("failed to run dry run: %v", err)
+	}
+	if result.UserdataSize != nil {
+		t.Errorf("expected no userdata size without bootstrap secret, got %d", *result.UserdataSize)
+	}
+
+	if _, err := Run(context.Background(), log, client, "kube-system", "workers", fakeMachineSpec(t, false)); err == nil {
+		t.Error("expected an invalid spec to fail the dry run")
+	}
+}
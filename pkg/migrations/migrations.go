@@ -147,7 +147,8 @@ func MigrateProviderConfigToProviderSpecIfNecessary(ctx context.Context, log *za
 func MigrateMachinesv1Alpha1MachineToClusterv1Alpha1MachineIfNecessary(
 	ctx context.Context, log *zap.SugaredLogger,
 	client ctrlruntimeclient.Client,
-	providerData *cloudprovidertypes.ProviderData) error {
+	providerData *cloudprovidertypes.ProviderData,
+	configVarSources *configvar.Sources) error {
 	var (
 		cachePopulatingInterval = 15 * time.Second
 		cachePopulatingTimeout  = 10 * time.Minute
@@ -190,7 +191,7 @@ func MigrateMachinesv1Alpha1MachineToClusterv1Alpha1MachineIfNecessary(
 		return fmt.Errorf("error when checking for existence of 'machines.cluster.k8s.io' crd: %w", err)
 	}
 
-	if err := migrateMachines(ctx, log, client, providerData); err != nil {
+	if err := migrateMachines(ctx, log, client, providerData, configVarSources); err != nil {
 		return fmt.Errorf("failed to migrate machines: %w", err)
 	}
 	crdLog.Info("Attempting to delete CRD")
@@ -201,7 +202,7 @@ func MigrateMachinesv1Alpha1MachineToClusterv1Alpha1MachineIfNecessary(
 	return nil
 }
 
-func migrateMachines(ctx context.Context, log *zap.SugaredLogger, client ctrlruntimeclient.Client, providerData *cloudprovidertypes.ProviderData) error {
+func migrateMachines(ctx context.Context, log *zap.SugaredLogger, client ctrlruntimeclient.Client, providerData *cloudprovidertypes.ProviderData, configVarSources *configvar.Sources) error {
 	log.Info("Starting migration for machine.machines.k8s.io/v1alpha1 to machine.cluster.k8s.io/v1alpha1")
 
 	// Get machinesv1Alpha1Machines
@@ -235,7 +236,7 @@ func migrateMachines(ctx context.Context, log *zap.SugaredLogger, client ctrlrun
 		if err != nil {
 			return fmt.Errorf("failed to get provider config: %w", err)
 		}
-		configResolver := configvar.NewResolver(ctx, client)
+		configResolver := configvar.NewResolver(ctx, client, configvar.WithSources(configVarSources))
 		prov, err := cloudprovider.ForProvider(providerConfig.CloudProvider, configResolver)
 		if err != nil {
 			return fmt.Errorf("failed to get cloud provider %q: %w", providerConfig.CloudProvider, err)
@@ -19,74 +19,177 @@ package eviction
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
 	"k8c.io/machine-controller/pkg/node/nodemanager"
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	nodetypes "k8c.io/machine-controller/sdk/node"
 
 	corev1 "k8s.io/api/core/v1"
-	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	ctrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// defaultWorkers is the number of pods which are evicted concurrently.
+	defaultWorkers = 10
+)
+
+// defaultBackoff is used to retry the eviction of a pod when the API server is overloaded
+// or fails temporarily.
+var defaultBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
 type NodeEviction struct {
 	nodeManager *nodemanager.NodeManager
 	nodeName    string
 	kubeClient  kubernetes.Interface
+	policy      Policy
+	workers     int
+	backoff     wait.Backoff
+}
+
+// Policy configures how the pods of a node are drained. The zero value evicts all pods.
+type Policy struct {
+	// GracePeriodSeconds overrides the termination grace period of the pods if set.
+	GracePeriodSeconds *int64
+	// SkipSelector selects the pods which are not evicted.
+	SkipSelector labels.Selector
+	// DeleteSelector selects the pods which are deleted instead of evicted.
+	DeleteSelector labels.Selector
+	// EmptyDir configures how pods with emptyDir volumes are handled.
+	EmptyDir clusterv1alpha1.EmptyDirDrainPolicy
+	// Force deletes all pods instead of evicting them, ignoring their PodDisruptionBudgets.
+	Force bool
+}
+
+// NewPolicy returns the Policy for the drain policy of a MachineDeployment, which may be nil.
+func NewPolicy(drainPolicy *clusterv1alpha1.MachineDrainPolicy) (Policy, error) {
+	policy := Policy{}
+	if drainPolicy == nil {
+		return policy, nil
+	}
+
+	policy.GracePeriodSeconds = drainPolicy.PodGracePeriodSeconds
+	policy.EmptyDir = drainPolicy.EmptyDir
+
+	var err error
+	if drainPolicy.SkipPodSelector != nil {
+		if policy.SkipSelector, err = metav1.LabelSelectorAsSelector(drainPolicy.SkipPodSelector); err != nil {
+			return policy, fmt.Errorf("invalid skipPodSelector: %w", err)
+		}
+	}
+	if drainPolicy.DeletePodSelector != nil {
+		if policy.DeleteSelector, err = metav1.LabelSelectorAsSelector(drainPolicy.DeletePodSelector); err != nil {
+			return policy, fmt.Errorf("invalid deletePodSelector: %w", err)
+		}
+	}
+
+	return policy, nil
+}
+
+// BlockedPod is a pod which could not be evicted.
+type BlockedPod struct {
+	Namespace string
+	Name      string
+	// Reason describes why the pod could not be evicted.
+	Reason string
+	// PodDisruptionBudgets contains the names of the PodDisruptionBudgets preventing the eviction.
+	PodDisruptionBudgets []string
+}
+
+func (p BlockedPod) String() string {
+	if len(p.PodDisruptionBudgets) > 0 {
+		return fmt.Sprintf("%s/%s (PodDisruptionBudget %s)", p.Namespace, p.Name, strings.Join(p.PodDisruptionBudgets, ", "))
+	}
+	return fmt.Sprintf("%s/%s (%s)", p.Namespace, p.Name, p.Reason)
+}
+
+// Result is the outcome of a drain attempt.
+type Result struct {
+	// Pending is true if pods remain on the node, so the drain needs to be retried later.
+	Pending bool
+	// Blocked contains the pods which could not be evicted.
+	Blocked []BlockedPod
 }
 
 // New returns a new NodeEviction.
-func New(nodeName string, client ctrlruntimeclient.Client, kubeClient kubernetes.Interface) *NodeEviction {
+func New(nodeName string, client ctrlruntimeclient.Client, kubeClient kubernetes.Interface, policy Policy) *NodeEviction {
 	return &NodeEviction{
 		nodeManager: nodemanager.New(client, nodeName),
 		nodeName:    nodeName,
 		kubeClient:  kubeClient,
+		policy:      policy,
+		workers:     defaultWorkers,
+		backoff:     defaultBackoff,
 	}
 }
 
 // Run executes the eviction.
-func (ne *NodeEviction) Run(ctx context.Context, log *zap.SugaredLogger) (bool, error) {
+func (ne *NodeEviction) Run(ctx context.Context, log *zap.SugaredLogger) (Result, error) {
 	nodeLog := log.With("node", ne.nodeName)
 
 	node, err := ne.nodeManager.GetNode(ctx)
 	if err != nil {
-		return false, fmt.Errorf("failed to get node from lister: %w", err)
+		return Result{}, fmt.Errorf("failed to get node from lister: %w", err)
 	}
 	if _, exists := node.Annotations[nodetypes.SkipEvictionAnnotationKey]; exists {
 		nodeLog.Infof("Skipping eviction for node as it has a %s annotation", nodetypes.SkipEvictionAnnotationKey)
-		return false, nil
+		return Result{}, nil
 	}
 
 	nodeLog.Info("Starting to evict node")
 
 	if err := ne.nodeManager.CordonNode(ctx, node); err != nil {
-		return false, fmt.Errorf("failed to cordon node %s: %w", ne.nodeName, err)
+		return Result{}, fmt.Errorf("failed to cordon node %s: %w", ne.nodeName, err)
 	}
 	nodeLog.Debug("Successfully cordoned node")
 
 	podsToEvict, err := ne.getFilteredPods(ctx)
 	if err != nil {
-		return false, fmt.Errorf("failed to get Pods to evict for node %s: %w", ne.nodeName, err)
+		return Result{}, fmt.Errorf("failed to get Pods to evict for node %s: %w", ne.nodeName, err)
 	}
 	nodeLog.Debugf("Found %d pods to evict for node", len(podsToEvict))
 
 	if len(podsToEvict) == 0 {
-		return false, nil
+		return Result{}, nil
 	}
 
 	// If we arrived here we have pods to evict, so tell the controller to retry later
-	if errs := ne.evictPods(ctx, nodeLog, podsToEvict); len(errs) > 0 {
-		return true, fmt.Errorf("failed to evict pods, errors encountered: %v", errs)
+	result := Result{Pending: true}
+
+	var podsToDelete []corev1.Pod
+	podsToEvict, podsToDelete, result.Blocked = ne.partitionPods(podsToEvict)
+
+	errs := ne.deletePods(ctx, nodeLog, podsToDelete)
+
+	blocked, err := ne.evictPods(ctx, nodeLog, podsToEvict)
+	result.Blocked = append(result.Blocked, blocked...)
+	sortBlockedPods(result.Blocked)
+	errs = append(errs, err)
+
+	if err := kerrors.NewAggregate(errs); err != nil {
+		return result, fmt.Errorf("failed to evict pods: %w", err)
 	}
 	nodeLog.Debug("Successfully created evictions for all pods on node")
 
-	return true, nil
+	return result, nil
 }
 
 func (ne *NodeEviction) getFilteredPods(ctx context.Context) ([]corev1.Pod, error) {
@@ -111,62 +214,221 @@ func (ne *NodeEviction) getFilteredPods(ctx context.Context) ([]corev1.Pod, erro
 		if _, found := candidatePod.Annotations[corev1.MirrorPodAnnotationKey]; found {
 			continue
 		}
+		if ne.policy.SkipSelector != nil && ne.policy.SkipSelector.Matches(labels.Set(candidatePod.Labels)) {
+			continue
+		}
+		if ne.policy.EmptyDir == clusterv1alpha1.EmptyDirDrainPolicySkip && hasEmptyDir(&candidatePod) {
+			continue
+		}
 		filteredPods = append(filteredPods, candidatePod)
 	}
 
 	return filteredPods, nil
 }
 
-func (ne *NodeEviction) evictPods(ctx context.Context, log *zap.SugaredLogger, pods []corev1.Pod) []error {
-	errCh := make(chan error, len(pods))
-	retErrs := []error{}
+// partitionPods splits the pods into the pods to evict, the pods to delete and the pods which
+// are blocked by the policy.
+func (ne *NodeEviction) partitionPods(pods []corev1.Pod) ([]corev1.Pod, []corev1.Pod, []BlockedPod) {
+	var (
+		podsToEvict  []corev1.Pod
+		podsToDelete []corev1.Pod
+		blocked      []BlockedPod
+	)
+	for _, pod := range pods {
+		switch {
+		case ne.policy.Force || (ne.policy.DeleteSelector != nil && ne.policy.DeleteSelector.Matches(labels.Set(pod.Labels))):
+			podsToDelete = append(podsToDelete, pod)
+		case ne.policy.EmptyDir == clusterv1alpha1.EmptyDirDrainPolicyBlock && hasEmptyDir(&pod):
+			blocked = append(blocked, BlockedPod{Namespace: pod.Namespace, Name: pod.Name, Reason: "pod has emptyDir volumes"})
+		default:
+			podsToEvict = append(podsToEvict, pod)
+		}
+	}
+	return podsToEvict, podsToDelete, blocked
+}
 
-	var wg sync.WaitGroup
-	var isDone bool
-	defer func() { isDone = true }()
+func hasEmptyDir(pod *corev1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
 
-	wg.Add(len(pods))
-	for _, pod := range pods {
-		go func(p corev1.Pod) {
+// evictPods evicts the pods with a bounded number of workers. Pods whose eviction is prevented by
+// a PodDisruptionBudget are returned, all other failures are returned as an aggregated error.
+// The eviction stops early if the context is cancelled.
+func (ne *NodeEviction) evictPods(ctx context.Context, log *zap.SugaredLogger, pods []corev1.Pod) ([]BlockedPod, error) {
+	if len(pods) == 0 {
+		return nil, nil
+	}
+
+	var (
+		wg      sync.WaitGroup
+		lock    sync.Mutex
+		blocked []BlockedPod
+		errs    []error
+	)
+
+	backoff := ne.backoff
+	if backoff.Steps == 0 {
+		backoff = defaultBackoff
+	}
+
+	queue := make(chan *corev1.Pod)
+	workers := min(max(ne.workers, 1), len(pods))
+	wg.Add(workers)
+	for range workers {
+		go func() {
 			defer wg.Done()
-			for {
-				if isDone {
-					return
+			for pod := range queue {
+				blockedPod, err := ne.evictPodWithBackoff(ctx, log, pod, backoff)
+				lock.Lock()
+				if blockedPod != nil {
+					blocked = append(blocked, *blockedPod)
 				}
-				err := ne.evictPod(ctx, &p)
-				if err == nil || apierrors.IsNotFound(err) {
-					log.Debugw("Successfully evicted pod on node", "pod", ctrlruntimeclient.ObjectKeyFromObject(&p))
-					return
-				} else if apierrors.IsTooManyRequests(err) {
-					// PDB prevents eviction, return and make the controller retry later
-					return
+				if err != nil {
+					errs = append(errs, err)
 				}
-				errCh <- fmt.Errorf("error evicting pod %s/%s on node %s: %w", p.Namespace, p.Name, ne.nodeName, err)
+				lock.Unlock()
 			}
-		}(pod)
+		}()
+	}
+
+enqueue:
+	for i := range pods {
+		select {
+		case queue <- &pods[i]:
+		case <-ctx.Done():
+			break enqueue
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("eviction of pods on node %s was aborted: %w", ne.nodeName, err))
+	}
+
+	return blocked, kerrors.NewAggregate(errs)
+}
+
+// evictPodWithBackoff evicts the pod, retrying with an exponential backoff while the API server is
+// overloaded or fails temporarily. It returns the pod as blocked if a PodDisruptionBudget prevents
+// its eviction.
+func (ne *NodeEviction) evictPodWithBackoff(ctx context.Context, log *zap.SugaredLogger, pod *corev1.Pod, backoff wait.Backoff) (*BlockedPod, error) {
+	podLog := log.With("pod", ctrlruntimeclient.ObjectKeyFromObject(pod))
+
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func(ctx context.Context) (bool, error) {
+		lastErr = ne.evictPod(ctx, pod)
+		switch {
+		case lastErr == nil || apierrors.IsNotFound(lastErr) || isDisruptionBudgetError(lastErr):
+			return true, nil
+		case isRetryableError(lastErr):
+			podLog.Debugw("Failed to evict pod, retrying", zap.Error(lastErr))
+			return false, nil
+		default:
+			return false, lastErr
+		}
+	})
+
+	switch {
+	case ctx.Err() != nil:
+		// Reported once for all pods by evictPods.
+		return nil, nil
+	case wait.Interrupted(err):
+		return nil, fmt.Errorf("error evicting pod %s/%s on node %s after %d attempts: %w", pod.Namespace, pod.Name, ne.nodeName, backoff.Steps, lastErr)
+	case err != nil:
+		return nil, fmt.Errorf("error evicting pod %s/%s on node %s: %w", pod.Namespace, pod.Name, ne.nodeName, err)
+	case isDisruptionBudgetError(lastErr):
+		// PDB prevents eviction, report it and make the controller retry later
+		pdbs, pdbErr := ne.getPodDisruptionBudgets(ctx, pod)
+		if pdbErr != nil {
+			podLog.Debugw("Failed to get PodDisruptionBudgets of pod", zap.Error(pdbErr))
+		}
+		return &BlockedPod{Namespace: pod.Namespace, Name: pod.Name, Reason: lastErr.Error(), PodDisruptionBudgets: pdbs}, nil
 	}
 
-	finished := make(chan struct{})
-	go func() { wg.Wait(); finished <- struct{}{} }()
+	podLog.Debug("Successfully evicted pod on node")
+	return nil, nil
+}
 
-	select {
-	case <-finished:
-		log.Debug("All goroutines for eviction pods on node finished")
-		break
-	case err := <-errCh:
-		log.Debugw("Got an error from eviction goroutine for node", zap.Error(err))
-		retErrs = append(retErrs, err)
+// isDisruptionBudgetError returns whether the eviction was rejected because it would violate a
+// PodDisruptionBudget. The API server rejects these evictions with a 429 like it does when
+// throttling requests, but adds a DisruptionBudget cause.
+func isDisruptionBudgetError(err error) bool {
+	if !apierrors.IsTooManyRequests(err) {
+		return false
 	}
+	_, found := apierrors.StatusCause(err, policyv1.DisruptionBudgetCause)
+	return found
+}
 
-	return retErrs
+// isRetryableError returns whether the eviction failed because the API server is overloaded
+// or failed temporarily.
+func isRetryableError(err error) bool {
+	return apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err)
 }
 
 func (ne *NodeEviction) evictPod(ctx context.Context, pod *corev1.Pod) error {
-	eviction := &policyv1beta1.Eviction{
+	eviction := &policyv1.Eviction{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      pod.Name,
 			Namespace: pod.Namespace,
 		},
 	}
-	return ne.kubeClient.PolicyV1beta1().Evictions(eviction.Namespace).Evict(ctx, eviction)
+	if ne.policy.GracePeriodSeconds != nil {
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: ne.policy.GracePeriodSeconds}
+	}
+	return ne.kubeClient.PolicyV1().Evictions(eviction.Namespace).Evict(ctx, eviction)
+}
+
+// deletePods deletes the pods without respecting their PodDisruptionBudgets.
+func (ne *NodeEviction) deletePods(ctx context.Context, log *zap.SugaredLogger, pods []corev1.Pod) []error {
+	var errs []error
+	for _, pod := range pods {
+		err := ne.kubeClient.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: ne.policy.GracePeriodSeconds})
+		if err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error deleting pod %s/%s on node %s: %w", pod.Namespace, pod.Name, ne.nodeName, err))
+			continue
+		}
+		log.Debugw("Successfully deleted pod on node", "pod", ctrlruntimeclient.ObjectKeyFromObject(&pod))
+	}
+	return errs
+}
+
+// getPodDisruptionBudgets returns the names of the PodDisruptionBudgets selecting the pod.
+func (ne *NodeEviction) getPodDisruptionBudgets(ctx context.Context, pod *corev1.Pod) ([]string, error) {
+	pdbs, err := ne.kubeClient.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PodDisruptionBudgets: %w", err)
+	}
+
+	var names []string
+	for _, pdb := range pdbs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			names = append(names, pdb.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func sortBlockedPods(pods []BlockedPod) {
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
 }
@@ -18,14 +18,27 @@ package eviction
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
+	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
+
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
 )
 
 // Unfortunately we can not directly test `EvictNode` as a List with a fieldSelector
@@ -59,8 +72,8 @@ func TestEvictPods(t *testing.T) {
 		client := kubefake.NewClientset(test.Pods...)
 		t.Run(test.Name, func(t *testing.T) {
 			ne := &NodeEviction{kubeClient: client, nodeName: "node1"}
-			if errs := ne.evictPods(context.Background(), zap.NewNop().Sugar(), literalPods); len(errs) > 0 {
-				t.Fatalf("Got unexpected errors=%v when running evictPods", errs)
+			if _, err := ne.evictPods(context.Background(), zap.NewNop().Sugar(), literalPods); err != nil {
+				t.Fatalf("Got unexpected error=%v when running evictPods", err)
 			}
 
 			actions := client.Actions()
@@ -78,3 +91,190 @@ func TestEvictPods(t *testing.T) {
 		})
 	}
 }
+
+func TestEvictPodsReportsPodDisruptionBudgets(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "n1", Name: "pod1", Labels: map[string]string{"app": "db"}},
+		Spec: corev1.PodSpec{NodeName: "node1"}}
+	pdb := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Namespace: "n1", Name: "db"},
+		Spec: policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}}}
+	otherPDB := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Namespace: "n1", Name: "web"},
+		Spec: policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}}}
+
+	client := kubefake.NewClientset(pod, pdb, otherPDB)
+	client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		return true, nil, newDisruptionBudgetError()
+	})
+
+	ne := &NodeEviction{kubeClient: client, nodeName: "node1"}
+	blocked, err := ne.evictPods(context.Background(), zap.NewNop().Sugar(), []corev1.Pod{*pod})
+	if err != nil {
+		t.Fatalf("Got unexpected error=%v when running evictPods", err)
+	}
+	if len(blocked) != 1 || blocked[0].Name != "pod1" || !reflect.DeepEqual(blocked[0].PodDisruptionBudgets, []string{"db"}) {
+		t.Errorf("Expected pod1 to be reported as blocked by PodDisruptionBudget db, got %+v", blocked)
+	}
+}
+
+func TestPartitionPods(t *testing.T) {
+	pod := func(name string, labels map[string]string, emptyDir bool) corev1.Pod {
+		p := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "n1", Name: name, Labels: labels}}
+		if emptyDir {
+			p.Spec.Volumes = []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+		}
+		return p
+	}
+	pods := []corev1.Pod{
+		pod("evict", nil, false),
+		pod("delete", map[string]string{"drain": "delete"}, false),
+		pod("emptydir", nil, true),
+	}
+
+	policy, err := NewPolicy(&clusterv1alpha1.MachineDrainPolicy{
+		DeletePodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"drain": "delete"}},
+		EmptyDir:          clusterv1alpha1.EmptyDirDrainPolicyBlock,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	ne := &NodeEviction{nodeName: "node1", policy: policy}
+	toEvict, toDelete, blocked := ne.partitionPods(pods)
+	if len(toEvict) != 1 || toEvict[0].Name != "evict" {
+		t.Errorf("Expected only pod evict to be evicted, got %v", toEvict)
+	}
+	if len(toDelete) != 1 || toDelete[0].Name != "delete" {
+		t.Errorf("Expected only pod delete to be deleted, got %v", toDelete)
+	}
+	if len(blocked) != 1 || blocked[0].Name != "emptydir" {
+		t.Errorf("Expected only pod emptydir to be blocked, got %v", blocked)
+	}
+
+	// Once the drain timeout is exceeded, all pods are deleted.
+	ne.policy.Force = true
+	if toEvict, toDelete, blocked := ne.partitionPods(pods); len(toEvict) != 0 || len(toDelete) != 3 || len(blocked) != 0 {
+		t.Errorf("Expected all pods to be deleted, got %d to evict, %d to delete and %d blocked", len(toEvict), len(toDelete), len(blocked))
+	}
+}
+
+func TestEvictPodsConcurrently(t *testing.T) {
+	var pods []runtime.Object
+	for i := range 100 {
+		pods = append(pods, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "n1", Name: fmt.Sprintf("pod%d", i)}})
+	}
+	pods = append(pods,
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "n1", Name: "failing"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "n1", Name: "protected"}},
+	)
+
+	var (
+		lock     sync.Mutex
+		attempts = map[string]int{}
+	)
+	client := kubefake.NewClientset(pods...)
+	client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		name := action.(clienttesting.CreateAction).GetObject().(*policyv1.Eviction).Name
+
+		lock.Lock()
+		defer lock.Unlock()
+		attempts[name]++
+
+		switch {
+		case name == "failing":
+			return true, nil, apierrors.NewInternalError(errors.New("etcd is unavailable"))
+		case name == "protected":
+			return true, nil, newDisruptionBudgetError()
+		case attempts[name] == 1:
+			return true, nil, apierrors.NewTooManyRequests("too many requests, please try again later", 1)
+		case attempts[name] == 2:
+			return true, nil, apierrors.NewInternalError(errors.New("etcd is unavailable"))
+		}
+		return true, nil, nil
+	})
+
+	var literalPods []corev1.Pod
+	for _, pod := range pods {
+		literalPods = append(literalPods, *(pod.(*corev1.Pod)))
+	}
+
+	ne := &NodeEviction{
+		kubeClient: client,
+		nodeName:   "node1",
+		workers:    5,
+		backoff:    wait.Backoff{Duration: time.Millisecond, Factor: 2, Steps: 4},
+	}
+	blocked, err := ne.evictPods(context.Background(), zap.NewNop().Sugar(), literalPods)
+
+	var aggregate kerrors.Aggregate
+	if !errors.As(err, &aggregate) || len(aggregate.Errors()) != 1 || !strings.Contains(err.Error(), "n1/failing") {
+		t.Errorf("Expected only the eviction of pod failing to fail, got %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].Name != "protected" {
+		t.Errorf("Expected only pod protected to be blocked, got %+v", blocked)
+	}
+
+	for _, pod := range literalPods {
+		expected := 3
+		switch pod.Name {
+		case "failing":
+			expected = ne.backoff.Steps
+		case "protected":
+			expected = 1
+		}
+		if attempts[pod.Name] != expected {
+			t.Errorf("Expected %d eviction attempts for pod %s, got %d", expected, pod.Name, attempts[pod.Name])
+		}
+	}
+}
+
+func TestEvictPodsStopsWhenCancelled(t *testing.T) {
+	var pods []corev1.Pod
+	for i := range 100 {
+		pods = append(pods, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "n1", Name: fmt.Sprintf("pod%d", i)}})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	evictions := 0
+	client := kubefake.NewClientset()
+	client.PrependReactor("create", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		// The fake clientset serializes reactors, so no lock is needed.
+		evictions++
+		if evictions == 5 {
+			cancel()
+		}
+		return true, nil, apierrors.NewTooManyRequests("too many requests, please try again later", 1)
+	})
+
+	ne := &NodeEviction{
+		kubeClient: client,
+		nodeName:   "node1",
+		workers:    2,
+		backoff:    wait.Backoff{Duration: time.Minute, Steps: 10},
+	}
+	_, err := ne.evictPods(ctx, zap.NewNop().Sugar(), pods)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected eviction to be aborted, got %v", err)
+	}
+	if evictions >= len(pods) {
+		t.Errorf("Expected eviction to stop after the context was cancelled, but %d evictions were attempted", evictions)
+	}
+}
+
+func newDisruptionBudgetError() error {
+	err := apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+	err.ErrStatus.Details.Causes = append(err.ErrStatus.Details.Causes, metav1.StatusCause{
+		Type:    policyv1.DisruptionBudgetCause,
+		Message: "The disruption budget db needs 1 healthy pods and has 1 currently",
+	})
+	return err
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsource
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"k8c.io/machine-controller/sdk/providerconfig/configvar"
+)
+
+func NewFlags(flagset *flag.FlagSet) *Flags {
+	settings := Flags{
+		FlagSet:         flagset,
+		execAllowedArgs: AllowedArgsFlag{},
+	}
+
+	settings.StringVar(&settings.fileDirs, "secret-file-dirs", "", "Comma-separated list of directories config vars can read files from, e.g. secrets mounted by a CSI driver")
+	settings.StringVar(&settings.vaultAddress, "vault-address", "", "Address of the HashiCorp Vault server config vars can read secrets from")
+	settings.StringVar(&settings.vaultTokenFile, "vault-token-file", "", "File containing the Vault token. If empty, the Vault Kubernetes auth method is used")
+	settings.StringVar(&settings.vaultKubernetesRole, "vault-kubernetes-role", "", "Role used for the Vault Kubernetes auth method")
+	settings.StringVar(&settings.vaultKubernetesAuthMount, "vault-kubernetes-auth-mount", "kubernetes", "Path the Vault Kubernetes auth method is mounted at")
+	settings.StringVar(&settings.vaultKubernetesTokenFile, "vault-kubernetes-token-file", "/var/run/secrets/kubernetes.io/serviceaccount/token", "Service account token used for the Vault Kubernetes auth method, e.g. a projected token")
+	settings.StringVar(&settings.execPluginDir, "secret-exec-plugin-dir", "", "Directory containing exec plugins config vars can read secrets from")
+	settings.DurationVar(&settings.execTimeout, "secret-exec-timeout", 30*time.Second, "Maximum runtime of an exec plugin")
+	settings.Var(settings.execAllowedArgs, "secret-exec-allowed-args", "Arguments an exec plugin may be run with, in <plugin>=<arg>[,<arg>...] format. Plugins are run without arguments unless they are allowed. Can be given multiple times.")
+	settings.DurationVar(&settings.cacheTTL, "secret-cache-ttl", 5*time.Minute, "How long secrets read from files, Vault or exec plugins are cached. Set to 0 to disable caching")
+
+	return &settings
+}
+
+type Flags struct {
+	fileDirs                 string
+	vaultAddress             string
+	vaultTokenFile           string
+	vaultKubernetesRole      string
+	vaultKubernetesAuthMount string
+	vaultKubernetesTokenFile string
+	execPluginDir            string
+	execTimeout              time.Duration
+	execAllowedArgs          AllowedArgsFlag
+	cacheTTL                 time.Duration
+
+	*flag.FlagSet
+}
+
+// Sources returns the configured secret sources for config vars, which log every read of
+// a secret from its source. Reads served from the cache are only logged at debug level, as
+// they happen on every reconciliation.
+func (flags *Flags) Sources(log *zap.SugaredLogger) *configvar.Sources {
+	auditLog := log.Named("secret-audit")
+	sources := map[configvar.SourceKind]configvar.Source{}
+
+	if flags.fileDirs != "" {
+		sources[configvar.SourceKindFile] = &configvar.FileSource{
+			AllowedDirs: strings.Split(flags.fileDirs, ","),
+		}
+		log.Infow("Registered file secret source", "dirs", flags.fileDirs)
+	}
+
+	if flags.vaultAddress != "" {
+		sources[configvar.SourceKindVault] = &configvar.VaultSource{
+			Address:             flags.vaultAddress,
+			TokenFile:           flags.vaultTokenFile,
+			KubernetesRole:      flags.vaultKubernetesRole,
+			KubernetesAuthMount: flags.vaultKubernetesAuthMount,
+			KubernetesTokenFile: flags.vaultKubernetesTokenFile,
+		}
+		log.Infow("Registered Vault secret source", "address", flags.vaultAddress)
+	}
+
+	if flags.execPluginDir != "" {
+		sources[configvar.SourceKindExec] = &configvar.ExecSource{
+			Dir:         flags.execPluginDir,
+			Timeout:     flags.execTimeout,
+			AllowedArgs: flags.execAllowedArgs,
+		}
+		log.Infow("Registered exec secret source", "dir", flags.execPluginDir)
+	}
+
+	return configvar.NewSources(sources, configvar.SourcesOptions{
+		CacheTTL: flags.cacheTTL,
+		Auditor: func(record configvar.AuditRecord) {
+			if record.Err != nil {
+				auditLog.Infow("Failed to read secret", "source", record.Source, "reference", record.Reference, zap.Error(record.Err))
+				return
+			}
+			if record.Cached {
+				auditLog.Debugw("Read secret from cache", "source", record.Source, "reference", record.Reference)
+				return
+			}
+			auditLog.Infow("Read secret", "source", record.Source, "reference", record.Reference)
+		},
+	})
+}
+
+// AllowedArgsFlag is a flag.Value collecting "<plugin>=<arg>[,<arg>...]" pairs.
+// The flag can be given multiple times, also for the same plugin.
+type AllowedArgsFlag map[string][]string
+
+func (f AllowedArgsFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for plugin, args := range f {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", plugin, strings.Join(args, ",")))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+func (f AllowedArgsFlag) Set(value string) error {
+	plugin, args, found := strings.Cut(value, "=")
+	if !found || plugin == "" || args == "" {
+		return fmt.Errorf("invalid allowed exec plugin arguments %q, expected <plugin>=<arg>[,<arg>...]", value)
+	}
+	f[plugin] = append(f[plugin], strings.Split(args, ",")...)
+	return nil
+}
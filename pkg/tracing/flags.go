@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func NewFlags(flagset *flag.FlagSet) *Flags {
+	settings := Flags{
+		FlagSet: flagset,
+	}
+
+	settings.StringVar(&settings.endpoint, "tracing-otlp-endpoint", "", "URL of an OTLP/HTTP endpoint traces are exported to, e.g. http://otel-collector:4318/v1/traces. If empty, tracing is disabled")
+	settings.Float64Var(&settings.samplingRatio, "tracing-sampling-ratio", 1, "Ratio of Machine lifecycles which are traced, between 0 and 1")
+
+	return &settings
+}
+
+type Flags struct {
+	endpoint      string
+	samplingRatio float64
+
+	*flag.FlagSet
+}
+
+// Setup installs the global tracer provider, which exports spans to the configured OTLP
+// endpoint. The returned function flushes and stops the export. If no endpoint is
+// configured, Setup does nothing.
+func (flags *Flags) Setup(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	if flags.endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if flags.samplingRatio < 0 || flags.samplingRatio > 1 {
+		return nil, fmt.Errorf("sampling ratio must be between 0 and 1, got %v", flags.samplingRatio)
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(flags.endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+		// Reconciliations are linked to the first trace of their Machine, so the sampling
+		// decision is only made once per Machine lifecycle.
+		sdktrace.WithSampler(sdktrace.ParentBased(linkBasedSampler{root: sdktrace.TraceIDRatioBased(flags.samplingRatio)})),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
+
+// linkBasedSampler samples root spans like the first span they are linked to. Root spans
+// without links are sampled by the root sampler.
+type linkBasedSampler struct {
+	root sdktrace.Sampler
+}
+
+func (s linkBasedSampler) ShouldSample(parameters sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, link := range parameters.Links {
+		if !link.SpanContext.IsValid() {
+			continue
+		}
+		decision := sdktrace.Drop
+		if link.SpanContext.IsSampled() {
+			decision = sdktrace.RecordAndSample
+		}
+		return sdktrace.SamplingResult{
+			Decision:   decision,
+			Tracestate: trace.SpanContextFromContext(parameters.ParentContext).TraceState(),
+		}
+	}
+	return s.root.ShouldSample(parameters)
+}
+
+func (s linkBasedSampler) Description() string {
+	return fmt.Sprintf("LinkBased{root:%s}", s.root.Description())
+}
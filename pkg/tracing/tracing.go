@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing provides the OpenTelemetry tracing of the machine-controller.
+// Spans are only exported if an OTLP endpoint is configured, otherwise the
+// global no-op tracer provider is used.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// AnnotationTraceContext holds the W3C trace context of the first traced reconciliation
+	// of a Machine, which the traces of all later reconciliations are linked to.
+	AnnotationTraceContext = "machine-controller.kubermatic.io/trace-context"
+
+	tracerName = "k8c.io/machine-controller"
+
+	traceparentHeader = "traceparent"
+)
+
+// Start starts a span as a child of the span in ctx.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End records the error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// StartLinked starts a span as root of a new trace. If the annotations hold a valid trace
+// context, the span is linked to it, so that the traces of an object are related without
+// a single trace growing over the whole lifetime of the object.
+func StartLinked(ctx context.Context, annotations map[string]string, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	opts = append(opts, trace.WithNewRoot())
+	if link, ok := annotatedLink(annotations); ok {
+		opts = append(opts, trace.WithLinks(link))
+	}
+	return Start(ctx, name, opts...)
+}
+
+func annotatedLink(annotations map[string]string) (trace.Link, bool) {
+	traceContext := annotations[AnnotationTraceContext]
+	if traceContext == "" {
+		return trace.Link{}, false
+	}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{traceparentHeader: traceContext})
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return trace.Link{}, false
+	}
+	return trace.Link{SpanContext: spanContext}, true
+}
+
+// TraceContext returns the W3C trace context of the span in ctx, or an empty string if
+// tracing is disabled. The trace context includes the sampling decision, so it is kept by
+// the spans linked to it.
+func TraceContext(ctx context.Context) string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ""
+	}
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier[traceparentHeader]
+}
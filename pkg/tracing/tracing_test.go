@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTraceContext(t *testing.T) {
+	ctx, span := Start(context.Background(), "disabled")
+	if traceContext := TraceContext(ctx); traceContext != "" {
+		t.Errorf("expected no trace context without tracer provider, got %q", traceContext)
+	}
+	End(span, nil)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer func() {
+		if err := provider.Shutdown(context.Background()); err != nil {
+			t.Errorf("failed to shut down tracer provider: %v", err)
+		}
+	}()
+
+	ctx, first := Start(context.Background(), "first")
+	annotations := map[string]string{AnnotationTraceContext: TraceContext(ctx)}
+	End(first, nil)
+
+	// A later reconciliation starts a new trace linked to the annotated one.
+	_, second := StartLinked(context.Background(), annotations, "second")
+	End(second, errors.New("failed"))
+
+	// Objects without annotation start a new trace without links.
+	_, third := StartLinked(context.Background(), nil, "third")
+	End(third, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	if spans[1].SpanContext().TraceID() == spans[0].SpanContext().TraceID() || spans[1].Parent().IsValid() {
+		t.Errorf("expected span %q to start a new trace", spans[1].Name())
+	}
+	if links := spans[1].Links(); len(links) != 1 || !links[0].SpanContext.Equal(spans[0].SpanContext().WithRemote(true)) {
+		t.Errorf("expected span %q to be linked to span %q, got links %v", spans[1].Name(), spans[0].Name(), links)
+	}
+	if spans[1].Status().Code != codes.Error {
+		t.Errorf("expected span %q to record the error, got status %v", spans[1].Name(), spans[1].Status())
+	}
+	if spans[2].SpanContext().TraceID() == spans[0].SpanContext().TraceID() || len(spans[2].Links()) != 0 {
+		t.Errorf("expected span %q to start a new trace without links", spans[2].Name())
+	}
+}
+
+func TestLinkBasedSampler(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithSampler(sdktrace.ParentBased(linkBasedSampler{root: sdktrace.AlwaysSample()})),
+	)
+	otel.SetTracerProvider(provider)
+	defer func() {
+		if err := provider.Shutdown(context.Background()); err != nil {
+			t.Errorf("failed to shut down tracer provider: %v", err)
+		}
+	}()
+
+	ctx, sampled := Start(context.Background(), "sampled")
+	sampledAnnotations := map[string]string{AnnotationTraceContext: TraceContext(ctx)}
+	End(sampled, nil)
+
+	// The trace context of a lifecycle which was not sampled.
+	unsampledAnnotations := map[string]string{AnnotationTraceContext: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00"}
+
+	_, linkedToSampled := StartLinked(context.Background(), sampledAnnotations, "linked-to-sampled")
+	End(linkedToSampled, nil)
+	_, linkedToUnsampled := StartLinked(context.Background(), unsampledAnnotations, "linked-to-unsampled")
+	End(linkedToUnsampled, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "sampled" || spans[1].Name() != "linked-to-sampled" {
+		names := make([]string, 0, len(spans))
+		for _, span := range spans {
+			names = append(names, span.Name())
+		}
+		t.Errorf("expected spans linked to unsampled traces to be dropped, got spans %v", names)
+	}
+}
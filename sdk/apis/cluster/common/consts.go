@@ -19,6 +19,7 @@ package common
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -124,6 +125,21 @@ const (
 	// Replace the old MachineSet by new one using rolling update
 	// i.e. gradually scale down the old MachineSet and scale up the new one.
 	RollingUpdateMachineDeploymentStrategyType MachineDeploymentStrategyType = "RollingUpdate"
+
+	// Update labels, annotations, including kubelet flags, and taints of the existing
+	// Machines and their Nodes in place. Changes to anything else, e.g. the ProviderSpec
+	// or the Versions, fall back to a rolling update.
+	InPlaceMachineDeploymentStrategyType MachineDeploymentStrategyType = "InPlace"
+
+	// Replace a few machines first and wait for their Nodes to be Ready for a soak period,
+	// then replace the remaining machines using a rolling update once the rollout was approved.
+	// If the Nodes of the new machines are not Ready in time, the MachineDeployment is rolled
+	// back to the previous revision.
+	CanaryMachineDeploymentStrategyType MachineDeploymentStrategyType = "Canary"
+
+	// Bring up a full new MachineSet and only scale down the old MachineSets once all
+	// new machines are available.
+	BlueGreenMachineDeploymentStrategyType MachineDeploymentStrategyType = "BlueGreen"
 )
 
 type KubeletFlags string
@@ -147,6 +163,16 @@ const (
 	// is machinedeployment.spec.replicas + maxSurge. Used by the underlying machine sets to estimate their
 	// proportions in case the deployment has surge replicas.
 	MaxReplicasAnnotation = "machinedeployment.clusters.k8s.io/max-replicas"
+	// CanaryPromotedAnnotation is set on the new machine set of a machine deployment with the Canary
+	// strategy once its canary machines passed the soak period and the remaining machines are replaced.
+	CanaryPromotedAnnotation = "machinedeployment.clusters.k8s.io/canary-promoted"
+	// CanaryApprovedRevisionAnnotation is set on a machine deployment with the Canary strategy to
+	// approve the rollout of the given revision once its canary machines passed the soak period.
+	// It is not needed if the canary strategy has autoPromote enabled.
+	CanaryApprovedRevisionAnnotation = "machinedeployment.clusters.k8s.io/canary-approved-revision"
+	// ChangeCauseAnnotation records the reason of a change of a machine deployment. It is copied to
+	// the machine sets and listed in the rollout history.
+	ChangeCauseAnnotation = "kubernetes.io/change-cause"
 
 	// FailedMSCreateReason is added in a machine deployment when it cannot create a new machine set.
 	FailedMSCreateReason = "MachineSetCreateError"
@@ -155,6 +181,16 @@ const (
 	// PausedDeployReason is added in a deployment when it is paused. Lack of progress shouldn't be
 	// estimated once a deployment is paused.
 	PausedDeployReason = "DeploymentPaused"
+	// RollbackRevisionNotFound is added in a deployment when the revision to roll back to does not exist.
+	RollbackRevisionNotFound = "DeploymentRollbackRevisionNotFound"
+	// RollbackTemplateUnchanged is added in a deployment when the revision to roll back to has the same
+	// template as the deployment.
+	RollbackTemplateUnchanged = "DeploymentRollbackTemplateUnchanged"
+	// RollbackRejected is added in a deployment when the template of the revision to roll back to is
+	// rejected by the admission webhook.
+	RollbackRejected = "DeploymentRollbackRejected"
+	// RollbackDone is added in a deployment when it was rolled back to a previous revision.
+	RollbackDone = "DeploymentRollback"
 
 	// MinimumReplicasAvailable is added in a deployment when it has its minimum replicas required available.
 	MinimumReplicasAvailable = "MinimumReplicasAvailable"
@@ -163,6 +199,94 @@ const (
 	MinimumReplicasUnavailable = "MinimumReplicasUnavailable"
 )
 
+const (
+	// PreDrainDeleteHookAnnotationPrefix is the prefix of annotations that pause the deletion of a
+	// Machine before its node is drained, e.g. "pre-drain.delete.hook.machine.cluster.k8s.io/storage".
+	// The external system owning the hook removes its annotation once it is done.
+	PreDrainDeleteHookAnnotationPrefix = "pre-drain.delete.hook.machine.cluster.k8s.io"
+	// PreTerminateDeleteHookAnnotationPrefix is the prefix of annotations that pause the deletion of a
+	// Machine after its node was drained and before its instance is deleted at the cloud provider.
+	PreTerminateDeleteHookAnnotationPrefix = "pre-terminate.delete.hook.machine.cluster.k8s.io"
+
+	// PreDrainDeleteHookSucceededCondition is false while the deletion of a Machine waits for
+	// pre-drain hooks.
+	PreDrainDeleteHookSucceededCondition corev1.NodeConditionType = "PreDrainDeleteHookSucceeded"
+	// PreTerminateDeleteHookSucceededCondition is false while the deletion of a Machine waits for
+	// pre-terminate hooks.
+	PreTerminateDeleteHookSucceededCondition corev1.NodeConditionType = "PreTerminateDeleteHookSucceeded"
+	// WaitingExternalHookReason is the reason of lifecycle hook conditions while hooks are present.
+	WaitingExternalHookReason = "WaitingExternalHook"
+)
+
+const (
+	// BootstrapReadyCondition is true once the bootstrap data for the instance of a Machine exists.
+	BootstrapReadyCondition corev1.NodeConditionType = "BootstrapReady"
+	// InstanceProvisionedCondition is true once the instance of a Machine exists at the cloud provider.
+	InstanceProvisionedCondition corev1.NodeConditionType = "InstanceProvisioned"
+	// AddressesAssignedCondition is true once the cloud provider reports addresses for the instance of a Machine.
+	AddressesAssignedCondition corev1.NodeConditionType = "AddressesAssigned"
+	// NodeJoinedCondition is true once the Node of a Machine joined the cluster.
+	NodeJoinedCondition corev1.NodeConditionType = "NodeJoined"
+	// DrainingCondition is true while the Node of a deleted Machine is drained.
+	DrainingCondition corev1.NodeConditionType = "Draining"
+	// InstanceDeletedCondition is true once the instance of a deleted Machine is gone at the cloud provider.
+	InstanceDeletedCondition corev1.NodeConditionType = "InstanceDeleted"
+	// WorkflowSucceededCondition is true once the workflow, which provisions the server of a bare-metal
+	// Machine, succeeded. It is not a lifecycle condition, as only some cloud providers use workflows.
+	WorkflowSucceededCondition corev1.NodeConditionType = "WorkflowSucceeded"
+
+	// WaitingForBootstrapDataReason is used while the bootstrap secret of a Machine does not exist yet.
+	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+	// BootstrapDataOutdatedReason is used while the bootstrap secret of a Machine was not yet
+	// updated for the revision of its MachineDeployment.
+	BootstrapDataOutdatedReason = "BootstrapDataOutdated"
+	// InstanceNotFoundReason is used while no instance exists for a Machine.
+	InstanceNotFoundReason = "InstanceNotFound"
+	// InstanceCreationFailedReason is used when the creation of an instance failed.
+	InstanceCreationFailedReason = "InstanceCreationFailed"
+	// InstanceGetFailedReason is used when an instance could not be retrieved from the cloud provider.
+	InstanceGetFailedReason = "InstanceGetFailed"
+	// WaitingForAddressesReason is used while the cloud provider reports no addresses for an instance.
+	WaitingForAddressesReason = "WaitingForAddresses"
+	// WaitingForNodeReason is used while no Node was found for the instance of a Machine.
+	WaitingForNodeReason = "WaitingForNode"
+	// EvictingPodsReason is used while pods are evicted from the Node of a deleted Machine.
+	EvictingPodsReason = "EvictingPods"
+	// DrainBlockedReason is used while pods, e.g. because of their PodDisruptionBudgets, block the drain of
+	// the Node of a deleted Machine.
+	DrainBlockedReason = "DrainBlocked"
+	// DrainTimeoutExceededReason is used while the remaining pods are deleted from the Node of a deleted Machine
+	// because the drain timeout of its MachineDeployment was exceeded.
+	DrainTimeoutExceededReason = "DrainTimeoutExceeded"
+	// WaitingForVolumesReason is used while pods with volumes are deleted from the Node of a deleted Machine.
+	WaitingForVolumesReason = "WaitingForVolumes"
+	// DrainCompletedReason is used once the Node of a deleted Machine was drained.
+	DrainCompletedReason = "DrainCompleted"
+	// DrainSkippedReason is used when the Node of a deleted Machine was not drained.
+	DrainSkippedReason = "DrainSkipped"
+	// InstanceDeletingReason is used while the instance of a deleted Machine is still being deleted.
+	InstanceDeletingReason = "InstanceDeleting"
+	// InstanceDeletionFailedReason is used when the deletion of an instance failed.
+	InstanceDeletionFailedReason = "InstanceDeletionFailed"
+	// WorkflowPendingReason is used while the provisioning workflow of a Machine did not start yet.
+	WorkflowPendingReason = "WorkflowPending"
+	// WorkflowRunningReason is used while the provisioning workflow of a Machine is running.
+	WorkflowRunningReason = "WorkflowRunning"
+	// WorkflowFailedReason is used when the provisioning workflow of a Machine failed.
+	WorkflowFailedReason = "WorkflowFailed"
+)
+
+// MachineLifecycleConditions are the conditions that describe the lifecycle of a Machine, in the
+// order in which they are reached. They are aggregated into the MachineSet and MachineDeployment status.
+var MachineLifecycleConditions = []corev1.NodeConditionType{
+	BootstrapReadyCondition,
+	InstanceProvisionedCondition,
+	AddressesAssignedCondition,
+	NodeJoinedCondition,
+	DrainingCondition,
+	InstanceDeletedCondition,
+}
+
 const (
 	SystemReservedKubeletConfig       = "SystemReserved"
 	KubeReservedKubeletConfig         = "KubeReserved"
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -56,3 +57,33 @@ type MachineClassRef struct {
 	// +optional
 	Provider string `json:"provider,omitempty"`
 }
+
+// MachineConditionSummary aggregates a lifecycle condition of the machines of a MachineSet or MachineDeployment.
+type MachineConditionSummary struct {
+	// Type is the type of the machine condition.
+	Type corev1.NodeConditionType `json:"type"`
+
+	// TrueReplicas is the number of machines for which the condition is true.
+	// +optional
+	TrueReplicas int32 `json:"trueReplicas,omitempty"`
+
+	// FalseReplicas is the number of machines for which the condition is false.
+	// +optional
+	FalseReplicas int32 `json:"falseReplicas,omitempty"`
+
+	// UnknownReplicas is the number of machines for which the condition is unknown.
+	// +optional
+	UnknownReplicas int32 `json:"unknownReplicas,omitempty"`
+
+	// Reason is the reason of the condition which transitioned last.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the message of the condition which transitioned last, prefixed with the name of its machine.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time the condition transitioned for any of the machines.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
@@ -54,8 +54,12 @@ func PopulateDefaultsMachineDeployment(d *MachineDeployment) {
 		d.Spec.Strategy.Type = common.RollingUpdateMachineDeploymentStrategyType
 	}
 
-	// Default RollingUpdate strategy only if strategy type is RollingUpdate.
-	if d.Spec.Strategy.Type == common.RollingUpdateMachineDeploymentStrategyType {
+	// Default RollingUpdate strategy only if strategy type is RollingUpdate, InPlace or Canary,
+	// the latter two use it for changes which cannot be applied in place and for the machines
+	// replaced after the canary machines.
+	if d.Spec.Strategy.Type == common.RollingUpdateMachineDeploymentStrategyType ||
+		d.Spec.Strategy.Type == common.InPlaceMachineDeploymentStrategyType ||
+		d.Spec.Strategy.Type == common.CanaryMachineDeploymentStrategyType {
 		if d.Spec.Strategy.RollingUpdate == nil {
 			d.Spec.Strategy.RollingUpdate = &MachineRollingUpdateDeployment{}
 		}
@@ -69,6 +73,20 @@ func PopulateDefaultsMachineDeployment(d *MachineDeployment) {
 		}
 	}
 
+	if d.Spec.Strategy.Type == common.CanaryMachineDeploymentStrategyType {
+		if d.Spec.Strategy.Canary == nil {
+			d.Spec.Strategy.Canary = &MachineCanaryDeployment{}
+		}
+		if d.Spec.Strategy.Canary.Replicas == nil {
+			ios1 := intstr.FromInt(1)
+			d.Spec.Strategy.Canary.Replicas = &ios1
+		}
+		if d.Spec.Strategy.Canary.SoakSeconds == nil {
+			d.Spec.Strategy.Canary.SoakSeconds = new(int32)
+			*d.Spec.Strategy.Canary.SoakSeconds = 600
+		}
+	}
+
 	if len(d.Namespace) == 0 {
 		d.Namespace = metav1.NamespaceDefault
 	}
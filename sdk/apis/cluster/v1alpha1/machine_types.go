@@ -185,7 +185,8 @@ type MachineStatus struct {
 	// +optional
 	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
 
-	// Conditions lists the conditions synced from the node conditions of the corresponding node-object.
+	// Conditions lists the conditions synced from the node conditions of the corresponding node-object
+	// as well as the lifecycle conditions of the machine, e.g. InstanceProvisioned or NodeJoined.
 	// Machine-controller is responsible for keeping conditions up-to-date.
 	// MachineSet controller will be taking these conditions as a signal to decide if
 	// machine is healthy or needs to be replaced.
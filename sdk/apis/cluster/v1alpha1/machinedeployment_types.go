@@ -66,28 +66,137 @@ type MachineDeploymentSpec struct {
 	// reason will be surfaced in the deployment status. Note that progress will
 	// not be estimated during the time a deployment is paused. Defaults to 600s.
 	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// The config this deployment is rolling back to. The template of the
+	// machine set with the given revision is copied into the deployment,
+	// afterwards this field is cleared.
+	// +optional
+	RollbackTo *RollbackConfig `json:"rollbackTo,omitempty"`
+
+	// The policy to drain the Nodes of the deployment's machines when they
+	// are deleted. Changing it does not replace the machines.
+	// +optional
+	DrainPolicy *MachineDrainPolicy `json:"drainPolicy,omitempty"`
 }
 
 /// [MachineDeploymentSpec]
 
+// / [RollbackConfig]
+// RollbackConfig describes the revision a deployment is rolled back to.
+type RollbackConfig struct {
+	// The revision to rollback to. If set to 0, rollback to the last revision.
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+}
+
+/// [RollbackConfig]
+
+// EmptyDirDrainPolicy describes how pods with emptyDir volumes are drained.
+type EmptyDirDrainPolicy string
+
+const (
+	// EmptyDirDrainPolicyEvict evicts pods with emptyDir volumes like all other pods.
+	EmptyDirDrainPolicyEvict EmptyDirDrainPolicy = "Evict"
+	// EmptyDirDrainPolicySkip leaves pods with emptyDir volumes on the node.
+	EmptyDirDrainPolicySkip EmptyDirDrainPolicy = "Skip"
+	// EmptyDirDrainPolicyBlock does not evict pods with emptyDir volumes and reports them as
+	// blocking the drain until the drain timeout is exceeded.
+	EmptyDirDrainPolicyBlock EmptyDirDrainPolicy = "Block"
+)
+
+// / [MachineDrainPolicy]
+// MachineDrainPolicy describes how the Node of a deleted machine is drained.
+type MachineDrainPolicy struct {
+	// The grace period in seconds given to evicted and deleted pods. If
+	// unset, the termination grace period of the pod is used.
+	// +optional
+	PodGracePeriodSeconds *int64 `json:"podGracePeriodSeconds,omitempty"`
+
+	// Pods matching this selector are not evicted.
+	// +optional
+	SkipPodSelector *metav1.LabelSelector `json:"skipPodSelector,omitempty"`
+
+	// Pods matching this selector are deleted right away instead of being
+	// evicted, i.e. without respecting their PodDisruptionBudgets.
+	// +optional
+	DeletePodSelector *metav1.LabelSelector `json:"deletePodSelector,omitempty"`
+
+	// How pods with emptyDir volumes are handled. Can be "Evict", "Skip" or
+	// "Block". Block does not evict them and reports them as blocking the
+	// drain until the timeout is exceeded.
+	// Defaults to Evict.
+	// +optional
+	EmptyDir EmptyDirDrainPolicy `json:"emptyDir,omitempty"`
+
+	// The maximum time to drain the Node, measured from the transition of the
+	// Draining condition of the Machine to True. Afterwards, the remaining pods are
+	// deleted without respecting their PodDisruptionBudgets. Independent of
+	// it, the drain is skipped once the machine deletion is older than the
+	// -skip-eviction-after flag of the machine-controller.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+/// [MachineDrainPolicy]
+
 // / [MachineDeploymentStrategy]
 // MachineDeploymentStrategy describes how to replace existing machines
 // with new ones.
 type MachineDeploymentStrategy struct {
-	// Type of deployment. Currently the only supported strategy is
-	// "RollingUpdate".
+	// Type of deployment. Can be "RollingUpdate", "InPlace", "Canary" or
+	// "BlueGreen". InPlace updates labels, annotations, including kubelet flags,
+	// and taints of existing machines without replacing them and falls back to
+	// a rolling update for all other changes, e.g. of the ProviderSpec or the
+	// Versions. Canary replaces a few machines first and continues
+	// with a rolling update once their Nodes were Ready for a soak period and the rollout was approved.
+	// BlueGreen brings up all new machines before the old ones are deleted.
 	// Default is RollingUpdate.
 	// +optional
 	Type common.MachineDeploymentStrategyType `json:"type,omitempty"`
 
 	// Rolling update config params. Present only if
-	// MachineDeploymentStrategyType = RollingUpdate.
+	// MachineDeploymentStrategyType = RollingUpdate, InPlace or Canary.
 	// +optional
 	RollingUpdate *MachineRollingUpdateDeployment `json:"rollingUpdate,omitempty"`
+
+	// Canary config params. Present only if
+	// MachineDeploymentStrategyType = Canary.
+	// +optional
+	Canary *MachineCanaryDeployment `json:"canary,omitempty"`
 }
 
 /// [MachineDeploymentStrategy]
 
+// / [MachineCanaryDeployment]
+// Spec to control the desired behavior of a canary deployment.
+type MachineCanaryDeployment struct {
+	// The number of machines that are replaced first. Value can be an
+	// absolute number (ex: 1) or a percentage of desired machines (ex: 10%).
+	// Absolute number is calculated from percentage by rounding up.
+	// Defaults to 1.
+	// +optional
+	Replicas *intstr.IntOrString `json:"replicas,omitempty"`
+
+	// The number of seconds the Nodes of the canary machines must be Ready
+	// before the remaining machines can be replaced. If the Nodes of the
+	// canary machines are not Ready within ProgressDeadlineSeconds, the
+	// deployment is rolled back to the previous revision.
+	// Defaults to 600.
+	// +optional
+	SoakSeconds *int32 `json:"soakSeconds,omitempty"`
+
+	// Whether the remaining machines are replaced as soon as the canary
+	// machines passed the soak period. Otherwise the rollout waits until
+	// the revision is approved by setting the
+	// "machinedeployment.clusters.k8s.io/canary-approved-revision"
+	// annotation of the MachineDeployment to the revision of the rollout.
+	// Defaults to false.
+	// +optional
+	AutoPromote bool `json:"autoPromote,omitempty"`
+}
+
+/// [MachineCanaryDeployment]
+
 // / [MachineRollingUpdateDeployment]
 // Spec to control the desired behavior of rolling update.
 type MachineRollingUpdateDeployment struct {
@@ -158,6 +267,10 @@ type MachineDeploymentStatus struct {
 	// that still have not been created.
 	// +optional
 	UnavailableReplicas int32 `json:"unavailableReplicas,omitempty" protobuf:"varint,5,opt,name=unavailableReplicas"`
+
+	// Conditions aggregates the lifecycle conditions of the machines targeted by this deployment.
+	// +optional
+	Conditions []MachineConditionSummary `json:"conditions,omitempty"`
 }
 
 /// [MachineDeploymentStatus]
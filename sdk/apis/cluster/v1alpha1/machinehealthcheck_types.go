@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// / [MachineHealthCheck]
+// MachineHealthCheck checks the Nodes of the selected Machines and deletes
+// unhealthy Machines owned by a MachineSet, so that they get replaced.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=mhc
+// +kubebuilder:subresource:status
+type MachineHealthCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MachineHealthCheckSpec   `json:"spec,omitempty"`
+	Status MachineHealthCheckStatus `json:"status,omitempty"`
+}
+
+/// [MachineHealthCheck]
+
+// / [MachineHealthCheckSpec]
+// MachineHealthCheckSpec defines the desired state of MachineHealthCheck.
+type MachineHealthCheckSpec struct {
+	// Selector is a label query over the machines to check.
+	// An empty selector matches no machines.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// UnhealthyConditions contains the node conditions that mark a machine
+	// as unhealthy once any of them was observed for longer than its timeout.
+	UnhealthyConditions []UnhealthyCondition `json:"unhealthyConditions"`
+
+	// MaxUnhealthy is the maximum number or percentage of selected machines
+	// that may be unhealthy. If more machines are unhealthy, no machine is
+	// remediated, as this usually indicates a problem outside of the machines.
+	// Defaults to 100%.
+	// +optional
+	MaxUnhealthy *intstr.IntOrString `json:"maxUnhealthy,omitempty"`
+
+	// NodeStartupTimeout is the time a machine may be without a Node, measured
+	// from its creation or the last time its Node joined or was lost. Machines
+	// without a Node for longer are unhealthy. Defaults to 10 minutes, 0
+	// disables the check.
+	// +optional
+	NodeStartupTimeout *metav1.Duration `json:"nodeStartupTimeout,omitempty"`
+}
+
+/// [MachineHealthCheckSpec]
+
+// / [UnhealthyCondition]
+// UnhealthyCondition is a node condition that marks a machine as unhealthy
+// once it was in the given status for at least the given timeout.
+type UnhealthyCondition struct {
+	Type    corev1.NodeConditionType `json:"type"`
+	Status  corev1.ConditionStatus   `json:"status"`
+	Timeout metav1.Duration          `json:"timeout"`
+}
+
+/// [UnhealthyCondition]
+
+// / [MachineHealthCheckStatus]
+// MachineHealthCheckStatus defines the observed state of MachineHealthCheck.
+type MachineHealthCheckStatus struct {
+	// ExpectedMachines is the number of machines selected by this health check.
+	// +optional
+	ExpectedMachines int32 `json:"expectedMachines,omitempty"`
+
+	// CurrentHealthy is the number of selected machines that are healthy.
+	// +optional
+	CurrentHealthy int32 `json:"currentHealthy,omitempty"`
+
+	// RemediationsAllowed is the number of further machines that may become
+	// unhealthy before remediation is stopped by MaxUnhealthy.
+	// +optional
+	RemediationsAllowed int32 `json:"remediationsAllowed,omitempty"`
+
+	// ObservedGeneration reflects the generation of the most recently observed MachineHealthCheck.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+/// [MachineHealthCheckStatus]
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MachineHealthCheckList contains a list of MachineHealthCheck.
+type MachineHealthCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MachineHealthCheck `json:"items"`
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolicyEnforcementAction defines what happens to objects violating a MachinePolicy.
+type PolicyEnforcementAction string
+
+const (
+	// PolicyEnforcementActionDeny rejects objects violating the policy.
+	PolicyEnforcementActionDeny PolicyEnforcementAction = "Deny"
+	// PolicyEnforcementActionWarn admits objects violating the policy, but returns
+	// the violations as admission warnings.
+	PolicyEnforcementActionWarn PolicyEnforcementAction = "Warn"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// / [MachinePolicy]
+// MachinePolicy restricts the Machines and MachineDeployments which can be created
+// in its namespace. An object has to satisfy all MachinePolicies of its namespace.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=mp
+type MachinePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MachinePolicySpec `json:"spec,omitempty"`
+}
+
+/// [MachinePolicy]
+
+// / [MachinePolicySpec]
+// MachinePolicySpec defines the restrictions of a MachinePolicy.
+//
+// The allowed values are glob patterns as understood by path.Match, e.g. "t3.*".
+// An empty list allows any value. Values the cloud provider chooses at runtime,
+// e.g. the default image of the operating system, are not restricted.
+type MachinePolicySpec struct {
+	// EnforcementAction defines whether violations are denied or only returned as
+	// admission warnings. Defaults to Deny.
+	// +optional
+	EnforcementAction PolicyEnforcementAction `json:"enforcementAction,omitempty"`
+
+	// AllowedCloudProviders are the allowed cloud providers, e.g. "aws".
+	// +optional
+	AllowedCloudProviders []string `json:"allowedCloudProviders,omitempty"`
+
+	// AllowedInstanceTypes are the allowed instance types, flavors or sizes.
+	// +optional
+	AllowedInstanceTypes []string `json:"allowedInstanceTypes,omitempty"`
+
+	// AllowedImages are the allowed images, AMIs or templates.
+	// +optional
+	AllowedImages []string `json:"allowedImages,omitempty"`
+
+	// AllowedRegions are the allowed regions, locations or datacenters.
+	// +optional
+	AllowedRegions []string `json:"allowedRegions,omitempty"`
+
+	// AllowedZones are the allowed availability zones.
+	// +optional
+	AllowedZones []string `json:"allowedZones,omitempty"`
+
+	// AllowedTags maps the allowed tag keys of the cloud provider instances to the
+	// allowed values. If set, tags with other keys are not allowed. A key without
+	// values allows any value.
+	// +optional
+	AllowedTags map[string][]string `json:"allowedTags,omitempty"`
+
+	// RequiredTags are the tag keys every cloud provider instance must have.
+	// +optional
+	RequiredTags []string `json:"requiredTags,omitempty"`
+
+	// RequiredLabels are the labels every Machine must have. An empty value
+	// allows any value.
+	// +optional
+	RequiredLabels map[string]string `json:"requiredLabels,omitempty"`
+
+	// MaxReplicas is the maximum number of replicas of a MachineDeployment.
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+/// [MachinePolicySpec]
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MachinePolicyList contains a list of MachinePolicy.
+type MachinePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MachinePolicy `json:"items"`
+}
@@ -163,6 +163,10 @@ type MachineSetStatus struct {
 	ErrorReason *common.MachineSetStatusError `json:"errorReason,omitempty"`
 	// +optional
 	ErrorMessage *string `json:"errorMessage,omitempty"`
+
+	// Conditions aggregates the lifecycle conditions of the machines of this MachineSet.
+	// +optional
+	Conditions []MachineConditionSummary `json:"conditions,omitempty"`
 }
 
 /// [MachineSetStatus]
@@ -56,6 +56,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&MachineClassList{},
 		&MachineDeployment{},
 		&MachineDeploymentList{},
+		&MachineHealthCheck{},
+		&MachineHealthCheckList{},
+		&MachinePolicy{},
+		&MachinePolicyList{},
 		&MachineSet{},
 		&MachineSetList{},
 	)
@@ -28,6 +28,10 @@ type RawConfig struct {
 	AssumeRoleARN        providerconfig.ConfigVarString `json:"assumeRoleARN,omitempty"`
 	AssumeRoleExternalID providerconfig.ConfigVarString `json:"assumeRoleExternalID,omitempty"`
 
+	// WebIdentityRoleARN is the IAM role the machine-controller assumes with its service account
+	// token instead of using the access key. The role is assumed before AssumeRoleARN.
+	WebIdentityRoleARN providerconfig.ConfigVarString `json:"webIdentityRoleARN,omitempty"`
+
 	Region             providerconfig.ConfigVarString   `json:"region"`
 	AvailabilityZone   providerconfig.ConfigVarString   `json:"availabilityZone,omitempty"`
 	VpcID              providerconfig.ConfigVarString   `json:"vpcId"`
@@ -45,6 +49,19 @@ type RawConfig struct {
 
 	IsSpotInstance     *bool               `json:"isSpotInstance,omitempty"`
 	SpotInstanceConfig *SpotInstanceConfig `json:"spotInstanceConfig,omitempty"`
+
+	// Fallbacks are tried in order if AWS reports insufficient capacity
+	// for the configured instance type or availability zone.
+	Fallbacks []Fallback `json:"fallbacks,omitempty"`
+}
+
+// Fallback overrides the instance type and/or availability zone of a machine.
+// Unset fields keep their configured value. As subnets belong to a single
+// availability zone, changing the zone usually requires setting a subnet, too.
+type Fallback struct {
+	InstanceType     providerconfig.ConfigVarString `json:"instanceType,omitempty"`
+	AvailabilityZone providerconfig.ConfigVarString `json:"availabilityZone,omitempty"`
+	SubnetID         providerconfig.ConfigVarString `json:"subnetId,omitempty"`
 }
 
 type SpotInstanceConfig struct {
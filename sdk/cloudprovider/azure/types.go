@@ -34,6 +34,10 @@ type RawConfig struct {
 	TenantID       providerconfig.ConfigVarString `json:"tenantID,omitempty"`
 	ClientID       providerconfig.ConfigVarString `json:"clientID,omitempty"`
 	ClientSecret   providerconfig.ConfigVarString `json:"clientSecret,omitempty"`
+	// UseWorkloadIdentity makes the machine-controller exchange its service account token for
+	// an access token of the client instead of using the client secret. The client needs a
+	// federated credential trusting the service account.
+	UseWorkloadIdentity providerconfig.ConfigVarBool `json:"useWorkloadIdentity,omitempty"`
 
 	Location                    providerconfig.ConfigVarString `json:"location"`
 	ResourceGroup               providerconfig.ConfigVarString `json:"resourceGroup"`
@@ -62,6 +66,17 @@ type RawConfig struct {
 	Tags           map[string]string              `json:"tags,omitempty"`
 
 	SecurityProfile *SecurityProfile `json:"securityProfile,omitempty"`
+
+	// Fallbacks are tried in order if Azure reports insufficient capacity
+	// for the configured VM size or zones.
+	Fallbacks []Fallback `json:"fallbacks,omitempty"`
+}
+
+// Fallback overrides the VM size and/or zones of a machine.
+// Unset fields keep their configured value.
+type Fallback struct {
+	VMSize providerconfig.ConfigVarString `json:"vmSize,omitempty"`
+	Zones  []string                       `json:"zones,omitempty"`
 }
 
 // ImagePlan contains azure OS Plan fields for the marketplace images.
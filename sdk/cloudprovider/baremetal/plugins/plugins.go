@@ -18,4 +18,7 @@ package plugins
 
 type Driver string
 
-const Tinkerbell Driver = "tinkerbell"
+const (
+	Tinkerbell Driver = "tinkerbell"
+	Redfish    Driver = "redfish"
+)
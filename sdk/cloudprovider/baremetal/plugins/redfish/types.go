@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	providerconfigtypes "k8c.io/machine-controller/sdk/providerconfig"
+)
+
+// BootSource is the device a server boots from once after it was powered on.
+type BootSource string
+
+const (
+	// BootSourcePXE boots the server from the network.
+	BootSourcePXE BootSource = "Pxe"
+	// BootSourceVirtualMedia boots the server from an ISO, which is inserted as virtual CD by the BMC.
+	BootSourceVirtualMedia BootSource = "VirtualMedia"
+)
+
+// RedfishPluginSpec defines the required information for the Redfish plugin.
+type RedfishPluginSpec struct {
+	// Endpoint is the URL of the Redfish service of the BMC, e.g. https://10.0.0.10.
+	Endpoint providerconfigtypes.ConfigVarString `json:"endpoint"`
+
+	// Auth contains the credentials of the BMC.
+	Auth Auth `json:"auth,omitempty"`
+
+	// SystemID is the ID of the computer system managed by the BMC. It can be omitted if the
+	// BMC manages a single system.
+	SystemID string `json:"systemId,omitempty"`
+
+	// InsecureSkipTLSVerify disables the verification of the certificate of the BMC. Many BMCs
+	// use self-signed certificates.
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+
+	// BootSource is the device the server boots from once after it was powered on. Defaults to Pxe.
+	BootSource BootSource `json:"bootSource,omitempty"`
+
+	// ISOURL is the URL of the ISO, which is inserted as virtual media if the boot source is VirtualMedia.
+	// The URL is a Go template, in which {{ .MachineName }} and {{ .MachineUID }} are replaced with the
+	// name and UID of the machine. The userdata is not part of the ISO, it is passed by a config drive,
+	// which is inserted as additional virtual media.
+	ISOURL providerconfigtypes.ConfigVarString `json:"isoUrl,omitempty"`
+}
+
+// Auth contains the credentials of the BMC.
+type Auth struct {
+	Username providerconfigtypes.ConfigVarString `json:"username,omitempty"`
+	Password providerconfigtypes.ConfigVarString `json:"password,omitempty"`
+}
+
+type Config struct {
+	Endpoint              string
+	Username              string
+	Password              string
+	SystemID              string
+	InsecureSkipTLSVerify bool
+	BootSource            BootSource
+	ISOURL                string
+}
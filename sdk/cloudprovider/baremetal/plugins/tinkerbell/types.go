@@ -19,6 +19,7 @@ package tinkerbell
 import (
 	providerconfigtypes "k8c.io/machine-controller/sdk/providerconfig"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 )
@@ -40,7 +41,63 @@ type TinkerbellPluginSpec struct {
 	// HardwareRef specifies the unique identifier of a single hardware object in the user-cluster
 	// that corresponds to the machine deployment. This ensures a one-to-one mapping between a deployment
 	// and a hardware object in the Tinkerbell cluster.
-	HardwareRef types.NamespacedName `json:"hardwareRef"`
+	// Either HardwareRef or HardwareSelector must be specified.
+	HardwareRef types.NamespacedName `json:"hardwareRef,omitempty"`
+
+	// HardwareSelector selects a pool of hardware objects in the Tinkerbell cluster. Each machine claims
+	// any free hardware object of the pool, so the machine deployment can have more than one replica.
+	HardwareSelector *HardwareSelector `json:"hardwareSelector,omitempty"`
+
+	// Template customizes the Tinkerbell template of the workflow, which provisions the hardware.
+	// The built-in preset for the operating system of the machine is used if it is not specified.
+	Template *TemplateSpec `json:"template,omitempty"`
+}
+
+// TemplatePreset is a built-in Tinkerbell template.
+type TemplatePreset string
+
+const (
+	// TemplatePresetUbuntu streams an Ubuntu cloud image and configures it with cloud-init.
+	TemplatePresetUbuntu TemplatePreset = "ubuntu"
+	// TemplatePresetFlatcar streams a Flatcar image and configures it with Ignition.
+	TemplatePresetFlatcar TemplatePreset = "flatcar"
+	// TemplatePresetRHEL streams a RHEL or Rocky Linux cloud image and configures it with cloud-init.
+	TemplatePresetRHEL TemplatePreset = "rhel"
+)
+
+// TemplateSpec selects the Tinkerbell template of the workflow. At most one of its fields must be specified.
+//
+// Custom templates are rendered by Tinkerbell for the hardware of the workflow. Besides .Hardware,
+// they can use these variables: device_1 (MAC address), hardware_name, disk (first disk), partition_number
+// (root partition of the image), os_image (URL of the image), cloud_init_script (base64 encoded userdata),
+// interface_name, cidr, ns (nameserver) and default_route.
+type TemplateSpec struct {
+	// Preset is the name of a built-in template: ubuntu, flatcar or rhel, which also supports Rocky Linux.
+	Preset TemplatePreset `json:"preset,omitempty"`
+
+	// Data is a custom Tinkerbell template.
+	Data string `json:"data,omitempty"`
+
+	// ConfigMapRef references a key of a ConfigMap in the Tinkerbell cluster holding a custom Tinkerbell template.
+	ConfigMapRef *ConfigMapKeyRef `json:"configMapRef,omitempty"`
+}
+
+// ConfigMapKeyRef references a key of a ConfigMap.
+type ConfigMapKeyRef struct {
+	// Namespace of the ConfigMap. Defaults to the namespace of the hardware object.
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+}
+
+// HardwareSelector selects hardware objects by namespace and labels. At least one of them must be specified.
+type HardwareSelector struct {
+	// Namespace restricts the pool to the hardware objects in this namespace.
+	// Hardware objects in all namespaces are selected if it is empty.
+	Namespace string `json:"namespace,omitempty"`
+
+	// LabelSelector restricts the pool to the hardware objects matching the selector.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
 }
 
 // Auth.
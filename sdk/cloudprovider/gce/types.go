@@ -52,6 +52,26 @@ type CloudProviderSpec struct {
 	MinCPUPlatform               providerconfig.ConfigVarString  `json:"minCPUPlatform,omitempty"`
 	GuestOSFeatures              []string                        `json:"guestOSFeatures,omitempty"`
 	ProjectID                    providerconfig.ConfigVarString  `json:"projectID,omitempty"`
+
+	// WorkloadIdentityPoolProvider is the full resource name of a workload identity pool provider, e.g.
+	// //iam.googleapis.com/projects/<number>/locations/global/workloadIdentityPools/<pool>/providers/<provider>.
+	// If set, the machine-controller exchanges its service account token for GCP credentials instead
+	// of using ServiceAccount, and ProjectID is required.
+	WorkloadIdentityPoolProvider providerconfig.ConfigVarString `json:"workloadIdentityPoolProvider,omitempty"`
+	// WorkloadIdentityServiceAccount is the email of the GCP service account impersonated with the
+	// federated credentials. It is also attached to the instances.
+	WorkloadIdentityServiceAccount providerconfig.ConfigVarString `json:"workloadIdentityServiceAccount,omitempty"`
+
+	// Fallbacks are tried in order if GCE reports insufficient capacity
+	// for the configured machine type or zone.
+	Fallbacks []Fallback `json:"fallbacks,omitempty"`
+}
+
+// Fallback overrides the machine type and/or zone of a machine.
+// Unset fields keep their configured value.
+type Fallback struct {
+	MachineType providerconfig.ConfigVarString `json:"machineType,omitempty"`
+	Zone        providerconfig.ConfigVarString `json:"zone,omitempty"`
 }
 
 // UpdateProviderSpec updates the given provider spec with changed
@@ -0,0 +1,62 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"k8c.io/machine-controller/sdk/jsonutil"
+	"k8c.io/machine-controller/sdk/providerconfig"
+)
+
+// RawConfig is the spec of the simulator, which simulates the instances of a cloud provider
+// for scale and chaos testing.
+type RawConfig struct {
+	// CreateLatency is how long instances are creating before they are running, e.g. "30s".
+	CreateLatency string `json:"createLatency,omitempty"`
+	// DeleteLatency is how long instances are deleting before they are gone, e.g. "10s".
+	DeleteLatency string `json:"deleteLatency,omitempty"`
+	// CIDR is the network the addresses of the instances are assigned from. Defaults to 10.0.0.0/8.
+	CIDR string `json:"cidr,omitempty"`
+	// StateConfigMap is the ConfigMap the instances are kept in, so that they survive restarts
+	// of the machine-controller. If empty, the instances are kept in memory.
+	StateConfigMap *StateConfigMapRef `json:"stateConfigMap,omitempty"`
+	// Faults are injected into the calls to the simulator.
+	Faults Faults `json:"faults,omitempty"`
+}
+
+// StateConfigMapRef references the ConfigMap the instances of the simulator are kept in.
+type StateConfigMapRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Faults are the ratios, between 0 and 1, of the calls to the simulator failing in the given way.
+type Faults struct {
+	// QuotaExceeded is the ratio of creations failing because the quota of the account is exceeded.
+	QuotaExceeded float64 `json:"quotaExceeded,omitempty"`
+	// RateLimited is the ratio of calls failing with HTTP status 429 Too Many Requests.
+	RateLimited float64 `json:"rateLimited,omitempty"`
+	// Terminal is the ratio of creations failing with a terminal error.
+	Terminal float64 `json:"terminal,omitempty"`
+	// SilentCreateFailure is the ratio of creations reporting success without creating an instance.
+	SilentCreateFailure float64 `json:"silentCreateFailure,omitempty"`
+}
+
+func GetConfig(pconfig providerconfig.Config) (*RawConfig, error) {
+	rawConfig := &RawConfig{}
+
+	return rawConfig, jsonutil.StrictUnmarshal(pconfig.CloudProviderSpec.Raw, rawConfig)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+)
+
+const defaultExecTimeout = 30 * time.Second
+
+// ExecSource runs plugins and uses their output as value of config vars.
+type ExecSource struct {
+	// Dir is the directory containing the plugins. Plugins outside of it cannot be run.
+	Dir string
+	// Timeout is the maximum runtime of a plugin, defaults to 30 seconds.
+	Timeout time.Duration
+	// AllowedArgs are the arguments each plugin may be run with, by plugin name. The args
+	// are taken from the Machine, so plugins cannot be run with any other argument.
+	AllowedArgs map[string][]string
+}
+
+var _ Source = &ExecSource{}
+
+func (s *ExecSource) Get(ctx context.Context, configVar providerconfig.ConfigVarString) (string, error) {
+	ref := configVar.ExecRef
+	if ref == nil {
+		return "", errors.New("no exec reference given")
+	}
+	if ref.Plugin == "" || ref.Plugin != filepath.Base(ref.Plugin) || ref.Plugin == "." || ref.Plugin == ".." {
+		return "", fmt.Errorf("invalid plugin name %q, it must be a file name", ref.Plugin)
+	}
+	for _, arg := range ref.Args {
+		if !slices.Contains(s.AllowedArgs[ref.Plugin], arg) {
+			return "", fmt.Errorf("argument %q is not allowed for plugin %q", arg, ref.Plugin)
+		}
+	}
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultExecTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, filepath.Join(s.Dir, ref.Plugin), ref.Args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("plugin %q failed: %w: %s", ref.Plugin, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
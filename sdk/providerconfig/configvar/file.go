@@ -0,0 +1,88 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+)
+
+// FileSource reads config vars from files, e.g. secrets mounted by the Secrets Store CSI driver.
+type FileSource struct {
+	// AllowedDirs are the directories files can be read from.
+	AllowedDirs []string
+}
+
+var _ Source = &FileSource{}
+
+func (s *FileSource) Get(_ context.Context, configVar providerconfig.ConfigVarString) (string, error) {
+	ref := configVar.FileKeyRef
+	if ref == nil {
+		return "", errors.New("no file reference given")
+	}
+	if !filepath.IsAbs(ref.Path) {
+		return "", fmt.Errorf("path %q is not absolute", ref.Path)
+	}
+
+	// Mounted secrets are usually symlinks, so the target has to be checked.
+	path, err := filepath.EvalSymlinks(ref.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if !s.allowed(path) {
+		return "", fmt.Errorf("path %q is not in an allowed directory", ref.Path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if ref.Key == "" {
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return "", fmt.Errorf("failed to parse file as JSON object: %w", err)
+	}
+	value, ok := data[ref.Key].(string)
+	if !ok {
+		return "", fmt.Errorf("file has no string key %q", ref.Key)
+	}
+	return value, nil
+}
+
+func (s *FileSource) allowed(path string) bool {
+	for _, dir := range s.AllowedDirs {
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			continue
+		}
+		if path == resolved || strings.HasPrefix(path, resolved+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
@@ -33,13 +33,31 @@ import (
 type Resolver struct {
 	ctx    context.Context
 	client ctrlruntimeclient.Client
+	// sources resolve config vars referencing values outside of the cluster. If nil,
+	// such config vars cannot be resolved.
+	sources *Sources
 }
 
-func NewResolver(ctx context.Context, client ctrlruntimeclient.Client) *Resolver {
-	return &Resolver{
+// Option configures a Resolver.
+type Option func(*Resolver)
+
+// WithSources makes the resolver read config vars referencing values outside of the
+// cluster from the given sources, which also audit the reads of Secrets.
+func WithSources(sources *Sources) Option {
+	return func(r *Resolver) {
+		r.sources = sources
+	}
+}
+
+func NewResolver(ctx context.Context, client ctrlruntimeclient.Client, opts ...Option) *Resolver {
+	r := &Resolver{
 		ctx:    ctx,
 		client: client,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 var _ providerconfig.ConfigVarResolver = &Resolver{}
@@ -80,7 +98,9 @@ func (r *Resolver) GetStringValue(configVar providerconfig.ConfigVarString) (str
 	if configVar.SecretKeyRef.Name != "" && configVar.SecretKeyRef.Namespace != "" && configVar.SecretKeyRef.Key != "" {
 		secret := &corev1.Secret{}
 		name := types.NamespacedName{Namespace: configVar.SecretKeyRef.Namespace, Name: configVar.SecretKeyRef.Name}
-		if err := r.client.Get(r.ctx, name, secret); err != nil {
+		err := r.client.Get(r.ctx, name, secret)
+		r.sources.audit(AuditRecord{Source: sourceKindSecret, Reference: fmt.Sprintf("%s#%s", name, configVar.SecretKeyRef.Key), Err: err})
+		if err != nil {
 			return "", fmt.Errorf("error retrieving secret '%s' from namespace '%s': '%w'", configVar.SecretKeyRef.Name, configVar.SecretKeyRef.Namespace, err)
 		}
 		if val, ok := secret.Data[configVar.SecretKeyRef.Key]; ok {
@@ -102,6 +122,11 @@ func (r *Resolver) GetStringValue(configVar providerconfig.ConfigVarString) (str
 		return "", fmt.Errorf("configmap '%s' in namespace '%s' has no key '%s'", configVar.ConfigMapKeyRef.Name, configVar.ConfigMapKeyRef.Namespace, configVar.ConfigMapKeyRef.Key)
 	}
 
+	// Values outside of the cluster are read from the registered sources
+	if kind, reference, ok := sourceReference(configVar); ok {
+		return r.sources.get(r.ctx, kind, reference, configVar)
+	}
+
 	return configVar.Value, nil
 }
 
@@ -124,7 +149,9 @@ func (r *Resolver) GetBoolValue(configVar providerconfig.ConfigVarBool) (bool, b
 	if configVar.SecretKeyRef.Name != "" && configVar.SecretKeyRef.Namespace != "" && configVar.SecretKeyRef.Key != "" {
 		secret := &corev1.Secret{}
 		name := types.NamespacedName{Namespace: configVar.SecretKeyRef.Namespace, Name: configVar.SecretKeyRef.Name}
-		if err := r.client.Get(r.ctx, name, secret); err != nil {
+		err := r.client.Get(r.ctx, name, secret)
+		r.sources.audit(AuditRecord{Source: sourceKindSecret, Reference: fmt.Sprintf("%s#%s", name, configVar.SecretKeyRef.Key), Err: err})
+		if err != nil {
 			return false, false, fmt.Errorf("error retrieving secret '%s' from namespace '%s': '%w'", configVar.SecretKeyRef.Name, configVar.SecretKeyRef.Namespace, err)
 		}
 		if val, ok := secret.Data[configVar.SecretKeyRef.Key]; ok {
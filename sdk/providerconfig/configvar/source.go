@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+// SourceKind identifies a source of config vars outside of the cluster.
+type SourceKind string
+
+const (
+	SourceKindFile  SourceKind = "file"
+	SourceKindVault SourceKind = "vault"
+	SourceKindExec  SourceKind = "exec"
+
+	// sourceKindSecret is used to audit reads of Kubernetes Secrets.
+	sourceKindSecret SourceKind = "secret"
+)
+
+// Source resolves config vars which reference a value outside of the cluster.
+type Source interface {
+	Get(ctx context.Context, configVar providerconfig.ConfigVarString) (string, error)
+}
+
+// AuditRecord describes a single read of a secret.
+type AuditRecord struct {
+	Source SourceKind
+	// Reference identifies the secret within the source, it never contains the value.
+	Reference string
+	// Cached is true if the value was served from the cache.
+	Cached bool
+	Err    error
+}
+
+// SourcesOptions configure Sources.
+type SourcesOptions struct {
+	// CacheTTL is how long values of sources are cached. Values are not cached if it is zero.
+	CacheTTL time.Duration
+	// Auditor is called for every read of a secret, if set.
+	Auditor func(AuditRecord)
+}
+
+// Sources are the sources of config vars outside of the cluster, together with the cache of
+// their values and the auditor of all secret reads. They are passed to resolvers with
+// WithSources, all resolvers sharing the same Sources share their cache.
+type Sources struct {
+	sources    map[SourceKind]Source
+	cacheTTL   time.Duration
+	auditor    func(AuditRecord)
+	valueCache *cache.Expiring
+}
+
+// NewSources returns Sources serving config vars of the given kinds from the given sources.
+func NewSources(sources map[SourceKind]Source, opts SourcesOptions) *Sources {
+	return &Sources{
+		sources:    sources,
+		cacheTTL:   opts.CacheTTL,
+		auditor:    opts.Auditor,
+		valueCache: cache.NewExpiring(),
+	}
+}
+
+// audit records the read of a secret. It does nothing for nil Sources.
+func (s *Sources) audit(record AuditRecord) {
+	if s == nil || s.auditor == nil {
+		return
+	}
+	s.auditor(record)
+}
+
+// sourceReference returns the kind of the source the config var references and a description
+// of the reference, or false if it does not reference a source.
+func sourceReference(configVar providerconfig.ConfigVarString) (SourceKind, string, bool) {
+	switch {
+	case configVar.FileKeyRef != nil:
+		reference := configVar.FileKeyRef.Path
+		if configVar.FileKeyRef.Key != "" {
+			reference += "#" + configVar.FileKeyRef.Key
+		}
+		return SourceKindFile, reference, true
+	case configVar.VaultKeyRef != nil:
+		return SourceKindVault, fmt.Sprintf("%s/%s#%s", vaultMount(configVar.VaultKeyRef), strings.Trim(configVar.VaultKeyRef.Path, "/"), configVar.VaultKeyRef.Key), true
+	case configVar.ExecRef != nil:
+		return SourceKindExec, strings.Join(append([]string{configVar.ExecRef.Plugin}, configVar.ExecRef.Args...), " "), true
+	}
+	return "", "", false
+}
+
+func (s *Sources) get(ctx context.Context, kind SourceKind, reference string, configVar providerconfig.ConfigVarString) (string, error) {
+	var source Source
+	if s != nil {
+		source = s.sources[kind]
+	}
+	if source == nil {
+		err := fmt.Errorf("%s source is not configured", kind)
+		s.audit(AuditRecord{Source: kind, Reference: reference, Err: err})
+		return "", fmt.Errorf("failed to get %q: %w", reference, err)
+	}
+
+	key := fmt.Sprintf("%s:%s", kind, reference)
+	if s.cacheTTL > 0 {
+		if value, ok := s.valueCache.Get(key); ok {
+			s.audit(AuditRecord{Source: kind, Reference: reference, Cached: true})
+			return value.(string), nil
+		}
+	}
+
+	value, err := source.Get(ctx, configVar)
+	s.audit(AuditRecord{Source: kind, Reference: reference, Err: err})
+	if err != nil {
+		return "", fmt.Errorf("failed to get %q from %s source: %w", reference, kind, err)
+	}
+
+	if s.cacheTTL > 0 {
+		s.valueCache.Set(key, value, s.cacheTTL)
+	}
+	return value, nil
+}
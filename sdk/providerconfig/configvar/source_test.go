@@ -0,0 +1,319 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+	"k8c.io/machine-controller/sdk/providerconfig/configvar/vaulttest"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func writeFile(t *testing.T, path, content string, perm os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	allowedDir := t.TempDir()
+	otherDir := t.TempDir()
+
+	writeFile(t, filepath.Join(allowedDir, "token"), "my-token\n", 0600)
+	writeFile(t, filepath.Join(allowedDir, "credentials.json"), `{"accessKeyID":"AKIA","port":1}`, 0600)
+	writeFile(t, filepath.Join(otherDir, "token"), "other-token", 0600)
+	if err := os.Symlink(filepath.Join(otherDir, "token"), filepath.Join(allowedDir, "link")); err != nil {
+		t.Fatalf("failed to create symlink: %v", err)
+	}
+
+	source := &FileSource{AllowedDirs: []string{allowedDir}}
+
+	testCases := []struct {
+		name          string
+		ref           providerconfig.FileKeySelector
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "whole file",
+			ref:      providerconfig.FileKeySelector{Path: filepath.Join(allowedDir, "token")},
+			expected: "my-token",
+		},
+		{
+			name:     "key of JSON object",
+			ref:      providerconfig.FileKeySelector{Path: filepath.Join(allowedDir, "credentials.json"), Key: "accessKeyID"},
+			expected: "AKIA",
+		},
+		{
+			name:          "key is not a string",
+			ref:           providerconfig.FileKeySelector{Path: filepath.Join(allowedDir, "credentials.json"), Key: "port"},
+			expectedError: `no string key "port"`,
+		},
+		{
+			name:          "file outside of allowed directories",
+			ref:           providerconfig.FileKeySelector{Path: filepath.Join(otherDir, "token")},
+			expectedError: "not in an allowed directory",
+		},
+		{
+			name:          "symlink to file outside of allowed directories",
+			ref:           providerconfig.FileKeySelector{Path: filepath.Join(allowedDir, "link")},
+			expectedError: "not in an allowed directory",
+		},
+		{
+			name:          "relative path",
+			ref:           providerconfig.FileKeySelector{Path: "token"},
+			expectedError: "not absolute",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			ref := test.ref
+			value, err := source.Get(context.Background(), providerconfig.ConfigVarString{FileKeyRef: &ref})
+			if test.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", test.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get value: %v", err)
+			}
+			if value != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, value)
+			}
+		})
+	}
+}
+
+func TestVaultSource(t *testing.T) {
+	server := vaulttest.NewServer()
+	defer server.Close()
+
+	server.Put("secret", "cloud/aws", map[string]string{"accessKeyID": "AKIA"})
+	server.Put("kv", "openstack", map[string]string{"password": "secret"})
+	server.AddKubernetesRole("kubernetes", "machine-controller", "sa-token")
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "vault-token"), vaulttest.RootToken+"\n", 0600)
+	writeFile(t, filepath.Join(dir, "sa-token"), "sa-token", 0600)
+
+	awsRef := providerconfig.ConfigVarString{VaultKeyRef: &providerconfig.VaultKeySelector{Path: "cloud/aws", Key: "accessKeyID"}}
+	openstackRef := providerconfig.ConfigVarString{VaultKeyRef: &providerconfig.VaultKeySelector{Mount: "kv", Path: "openstack", Key: "password"}}
+
+	t.Run("token file", func(t *testing.T) {
+		source := &VaultSource{Address: server.URL, TokenFile: filepath.Join(dir, "vault-token")}
+		value, err := source.Get(context.Background(), awsRef)
+		if err != nil {
+			t.Fatalf("failed to get value: %v", err)
+		}
+		if value != "AKIA" {
+			t.Errorf("expected %q, got %q", "AKIA", value)
+		}
+
+		missingKey := providerconfig.ConfigVarString{VaultKeyRef: &providerconfig.VaultKeySelector{Path: "cloud/aws", Key: "secretAccessKey"}}
+		if _, err := source.Get(context.Background(), missingKey); err == nil {
+			t.Error("expected error for missing key")
+		}
+		missingSecret := providerconfig.ConfigVarString{VaultKeyRef: &providerconfig.VaultKeySelector{Path: "cloud/gce", Key: "serviceAccount"}}
+		if _, err := source.Get(context.Background(), missingSecret); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("kubernetes auth", func(t *testing.T) {
+		source := &VaultSource{
+			Address:             server.URL,
+			KubernetesRole:      "machine-controller",
+			KubernetesTokenFile: filepath.Join(dir, "sa-token"),
+		}
+		for i := 0; i < 2; i++ {
+			value, err := source.Get(context.Background(), openstackRef)
+			if err != nil {
+				t.Fatalf("failed to get value: %v", err)
+			}
+			if value != "secret" {
+				t.Errorf("expected %q, got %q", "secret", value)
+			}
+		}
+		if server.Logins() != 1 {
+			t.Errorf("expected the token to be reused, got %d logins", server.Logins())
+		}
+
+		// A revoked token must be replaced on the next read.
+		server.RevokeTokens()
+		if _, err := source.Get(context.Background(), openstackRef); err == nil {
+			t.Fatal("expected error for revoked token")
+		}
+		if _, err := source.Get(context.Background(), openstackRef); err != nil {
+			t.Fatalf("failed to get value after logging in again: %v", err)
+		}
+		if server.Logins() != 2 {
+			t.Errorf("expected a second login, got %d logins", server.Logins())
+		}
+
+		// Tokens without lease duration do not expire and are reused as well.
+		server.SetLeaseDuration(0)
+		defer server.SetLeaseDuration(3600)
+		nonExpiring := &VaultSource{
+			Address:             server.URL,
+			KubernetesRole:      "machine-controller",
+			KubernetesTokenFile: filepath.Join(dir, "sa-token"),
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := nonExpiring.Get(context.Background(), openstackRef); err != nil {
+				t.Fatalf("failed to get value with non-expiring token: %v", err)
+			}
+		}
+		if server.Logins() != 3 {
+			t.Errorf("expected the non-expiring token to be reused, got %d logins", server.Logins())
+		}
+
+		unknownRole := &VaultSource{
+			Address:             server.URL,
+			KubernetesRole:      "unknown",
+			KubernetesTokenFile: filepath.Join(dir, "sa-token"),
+		}
+		if _, err := unknownRole.Get(context.Background(), openstackRef); err == nil {
+			t.Error("expected login with unknown role to fail")
+		}
+	})
+}
+
+func TestExecSource(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "get-secret"), "#!/bin/sh\necho \"secret-$1\"\n", 0700)
+	writeFile(t, filepath.Join(dir, "fail"), "#!/bin/sh\necho 'no such secret' >&2\nexit 1\n", 0700)
+
+	source := &ExecSource{Dir: dir, Timeout: 10 * time.Second, AllowedArgs: map[string][]string{"get-secret": {"aws", "azure"}}}
+
+	value, err := source.Get(context.Background(), providerconfig.ConfigVarString{ExecRef: &providerconfig.ExecSelector{Plugin: "get-secret", Args: []string{"aws"}}})
+	if err != nil {
+		t.Fatalf("failed to get value: %v", err)
+	}
+	if value != "secret-aws" {
+		t.Errorf("expected %q, got %q", "secret-aws", value)
+	}
+
+	_, err = source.Get(context.Background(), providerconfig.ConfigVarString{ExecRef: &providerconfig.ExecSelector{Plugin: "fail"}})
+	if err == nil || !strings.Contains(err.Error(), "no such secret") {
+		t.Errorf("expected error with output of plugin, got %v", err)
+	}
+
+	for _, ref := range []providerconfig.ExecSelector{
+		{Plugin: "get-secret", Args: []string{"gce"}},
+		{Plugin: "get-secret", Args: []string{"aws", "--verbose"}},
+		{Plugin: "fail", Args: []string{"aws"}},
+	} {
+		if _, err := source.Get(context.Background(), providerconfig.ConfigVarString{ExecRef: &ref}); err == nil || !strings.Contains(err.Error(), "not allowed") {
+			t.Errorf("expected args %v of plugin %q to be rejected, got %v", ref.Args, ref.Plugin, err)
+		}
+	}
+
+	for _, plugin := range []string{"", "..", "../get-secret", "/bin/sh"} {
+		if _, err := source.Get(context.Background(), providerconfig.ConfigVarString{ExecRef: &providerconfig.ExecSelector{Plugin: plugin}}); err == nil {
+			t.Errorf("expected plugin %q to be rejected", plugin)
+		}
+	}
+}
+
+type countingSource struct {
+	calls int
+}
+
+func (s *countingSource) Get(_ context.Context, configVar providerconfig.ConfigVarString) (string, error) {
+	s.calls++
+	return "value-of-" + configVar.FileKeyRef.Path, nil
+}
+
+func TestResolverSources(t *testing.T) {
+	var records []AuditRecord
+	auditor := func(record AuditRecord) {
+		records = append(records, record)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "credentials"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+	client := fakectrlruntimeclient.NewClientBuilder().WithObjects(secret).Build()
+
+	fileRef := providerconfig.ConfigVarString{FileKeyRef: &providerconfig.FileKeySelector{Path: "/secrets/token"}}
+	if _, err := NewResolver(context.Background(), client).GetStringValue(fileRef); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Errorf("expected error for resolver without sources, got %v", err)
+	}
+
+	unconfigured := NewResolver(context.Background(), client, WithSources(NewSources(nil, SourcesOptions{Auditor: auditor})))
+	if _, err := unconfigured.GetStringValue(fileRef); err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Errorf("expected error for unconfigured source, got %v", err)
+	}
+
+	source := &countingSource{}
+	resolver := NewResolver(context.Background(), client, WithSources(NewSources(
+		map[SourceKind]Source{SourceKindFile: source},
+		SourcesOptions{CacheTTL: time.Minute, Auditor: auditor},
+	)))
+
+	for i := 0; i < 2; i++ {
+		value, err := resolver.GetStringValue(fileRef)
+		if err != nil {
+			t.Fatalf("failed to get value: %v", err)
+		}
+		if value != "value-of-/secrets/token" {
+			t.Errorf("unexpected value %q", value)
+		}
+	}
+	if source.calls != 1 {
+		t.Errorf("expected value to be cached, got %d calls", source.calls)
+	}
+
+	secretRef := providerconfig.ConfigVarString{SecretKeyRef: providerconfig.GlobalSecretKeySelector{
+		ObjectReference: corev1.ObjectReference{Namespace: "kube-system", Name: "credentials"},
+		Key:             "password",
+	}}
+	if _, err := resolver.GetStringValue(secretRef); err != nil {
+		t.Fatalf("failed to get value from secret: %v", err)
+	}
+
+	expected := []AuditRecord{
+		{Source: SourceKindFile, Reference: "/secrets/token"},
+		{Source: SourceKindFile, Reference: "/secrets/token"},
+		{Source: SourceKindFile, Reference: "/secrets/token", Cached: true},
+		{Source: sourceKindSecret, Reference: "kube-system/credentials#password"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d audit records, got %d: %+v", len(expected), len(records), records)
+	}
+	for i := range expected {
+		if records[i].Source != expected[i].Source || records[i].Reference != expected[i].Reference || records[i].Cached != expected[i].Cached {
+			t.Errorf("expected audit record %d to be %+v, got %+v", i, expected[i], records[i])
+		}
+	}
+	if records[0].Err == nil {
+		t.Error("expected audit record of unconfigured source to contain the error")
+	}
+}
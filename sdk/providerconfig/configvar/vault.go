@@ -0,0 +1,210 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"k8c.io/machine-controller/sdk/providerconfig"
+)
+
+const (
+	defaultVaultMount               = "secret"
+	defaultVaultKubernetesAuthMount = "kubernetes"
+)
+
+// VaultSource reads config vars from a HashiCorp Vault KV version 2 secrets engine.
+type VaultSource struct {
+	// Address is the URL of the Vault server.
+	Address string
+	// TokenFile is a file containing the Vault token. If empty, the source logs in
+	// using the Kubernetes auth method.
+	TokenFile string
+	// KubernetesRole is the role used for the Kubernetes auth method.
+	KubernetesRole string
+	// KubernetesAuthMount is the path the Kubernetes auth method is mounted at, defaults to "kubernetes".
+	KubernetesAuthMount string
+	// KubernetesTokenFile is a file containing a service account token, e.g. a projected token.
+	KubernetesTokenFile string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	lock        sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+var _ Source = &VaultSource{}
+
+type vaultResponse struct {
+	Errors []string `json:"errors"`
+	Data   struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+func vaultMount(ref *providerconfig.VaultKeySelector) string {
+	if ref.Mount == "" {
+		return defaultVaultMount
+	}
+	return strings.Trim(ref.Mount, "/")
+}
+
+func (s *VaultSource) Get(ctx context.Context, configVar providerconfig.ConfigVarString) (string, error) {
+	ref := configVar.VaultKeyRef
+	if ref == nil {
+		return "", errors.New("no Vault reference given")
+	}
+
+	token, err := s.clientToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, fmt.Sprintf("%s/data/%s", vaultMount(ref), strings.Trim(ref.Path, "/")), token, nil)
+	if err != nil {
+		if errors.Is(err, errVaultPermissionDenied) {
+			// The token might have been revoked, log in again on the next read.
+			s.resetToken()
+		}
+		return "", err
+	}
+
+	value, ok := resp.Data.Data[ref.Key].(string)
+	if !ok {
+		return "", fmt.Errorf("secret has no string key %q", ref.Key)
+	}
+	return value, nil
+}
+
+// clientToken returns the token from the token file, or logs in using the Kubernetes auth method.
+func (s *VaultSource) clientToken(ctx context.Context) (string, error) {
+	if s.TokenFile != "" {
+		token, err := os.ReadFile(s.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read Vault token: %w", err)
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Tokens without expiry, e.g. of root or periodic tokens without TTL, are kept until Vault
+	// denies them.
+	if s.token != "" && (s.tokenExpiry.IsZero() || time.Now().Before(s.tokenExpiry)) {
+		return s.token, nil
+	}
+
+	// Projected tokens are rotated by the kubelet, so the file is read on every login.
+	jwt, err := os.ReadFile(s.KubernetesTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read service account token: %w", err)
+	}
+
+	mount := s.KubernetesAuthMount
+	if mount == "" {
+		mount = defaultVaultKubernetesAuthMount
+	}
+	body, err := json.Marshal(map[string]string{
+		"role": s.KubernetesRole,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, fmt.Sprintf("auth/%s/login", strings.Trim(mount, "/")), "", body)
+	if err != nil {
+		return "", fmt.Errorf("failed to log in to Vault: %w", err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return "", errors.New("failed to log in to Vault: no client token returned")
+	}
+
+	s.token = resp.Auth.ClientToken
+	s.tokenExpiry = time.Time{}
+	if resp.Auth.LeaseDuration > 0 {
+		// Renew the token well before it expires.
+		s.tokenExpiry = time.Now().Add(time.Duration(resp.Auth.LeaseDuration) * time.Second / 2)
+	}
+	return s.token, nil
+}
+
+func (s *VaultSource) resetToken() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.token = ""
+}
+
+var errVaultPermissionDenied = errors.New("permission denied")
+
+func (s *VaultSource) do(ctx context.Context, method, path, token string, body []byte) (*vaultResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1/%s", strings.TrimSuffix(s.Address, "/"), path), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Vault: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	content, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault response: %w", err)
+	}
+
+	resp := &vaultResponse{}
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, resp); err != nil {
+			return nil, fmt.Errorf("failed to parse Vault response: %w", err)
+		}
+	}
+
+	switch {
+	case httpResp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("vault returned %w: %s", errVaultPermissionDenied, strings.Join(resp.Errors, ", "))
+	case httpResp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("secret %q not found in Vault", path)
+	case httpResp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("vault returned status %d: %s", httpResp.StatusCode, strings.Join(resp.Errors, ", "))
+	}
+	return resp, nil
+}
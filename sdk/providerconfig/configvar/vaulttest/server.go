@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The Machine Controller Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vaulttest provides a stand-in for a HashiCorp Vault server running in dev mode.
+// It serves the KV version 2 secrets engine and the Kubernetes auth method, which is
+// enough to test the Vault config var source without a real Vault.
+package vaulttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// RootToken is the root token of the server, like the one of "vault server -dev -dev-root-token-id=root".
+const RootToken = "root"
+
+type kubernetesRole struct {
+	mount string
+	role  string
+}
+
+type Server struct {
+	*httptest.Server
+
+	lock    sync.Mutex
+	secrets map[string]map[string]string
+	// roles maps Kubernetes auth roles to the service account token which can log in.
+	roles  map[kubernetesRole]string
+	tokens map[string]bool
+	logins int
+	// leaseDuration is the lease duration in seconds of the tokens issued by logins.
+	leaseDuration int
+}
+
+// NewServer starts a new server, which has to be closed by the caller.
+func NewServer() *Server {
+	s := &Server{
+		secrets: map[string]map[string]string{},
+		roles:   map[kubernetesRole]string{},
+		tokens:  map[string]bool{RootToken: true},
+
+		leaseDuration: 3600,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Put stores the secret at the path of the KV version 2 secrets engine at mount.
+func (s *Server) Put(mount, path string, data map[string]string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.secrets[mount+"/data/"+path] = data
+}
+
+// AddKubernetesRole allows logging in as role using the Kubernetes auth method at mount
+// with the given service account token.
+func (s *Server) AddKubernetesRole(mount, role, serviceAccountToken string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.roles[kubernetesRole{mount: mount, role: role}] = serviceAccountToken
+}
+
+// SetLeaseDuration sets the lease duration in seconds of the tokens issued by later logins.
+// A lease duration of 0 means the tokens do not expire.
+func (s *Server) SetLeaseDuration(seconds int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.leaseDuration = seconds
+}
+
+// RevokeTokens revokes all tokens except the root token.
+func (s *Server) RevokeTokens() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.tokens = map[string]bool{RootToken: true}
+}
+
+// Logins returns the number of successful logins.
+func (s *Server) Logins() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.logins
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	if strings.HasPrefix(path, "auth/") && strings.HasSuffix(path, "/login") && r.Method == http.MethodPost {
+		s.login(w, r, strings.TrimSuffix(strings.TrimPrefix(path, "auth/"), "/login"))
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeResponse(w, http.StatusMethodNotAllowed, map[string]interface{}{"errors": []string{}})
+		return
+	}
+	if !s.tokens[r.Header.Get("X-Vault-Token")] {
+		writeResponse(w, http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+	data, ok := s.secrets[path]
+	if !ok {
+		writeResponse(w, http.StatusNotFound, map[string]interface{}{"errors": []string{}})
+		return
+	}
+	writeResponse(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{
+			"data":     data,
+			"metadata": map[string]interface{}{"version": 1},
+		},
+	})
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request, mount string) {
+	body := struct {
+		Role string `json:"role"`
+		JWT  string `json:"jwt"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeResponse(w, http.StatusBadRequest, map[string]interface{}{"errors": []string{err.Error()}})
+		return
+	}
+
+	expected, ok := s.roles[kubernetesRole{mount: mount, role: body.Role}]
+	if !ok || expected != body.JWT {
+		writeResponse(w, http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+
+	s.logins++
+	token := fmt.Sprintf("s.token-%d", s.logins)
+	s.tokens[token] = true
+	writeResponse(w, http.StatusOK, map[string]interface{}{
+		"auth": map[string]interface{}{
+			"client_token":   token,
+			"lease_duration": s.leaseDuration,
+			"renewable":      true,
+		},
+	})
+}
+
+func writeResponse(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	clusterv1alpha1 "k8c.io/machine-controller/sdk/apis/cluster/v1alpha1"
 	"k8c.io/machine-controller/sdk/jsonutil"
@@ -69,6 +70,7 @@ const (
 	CloudProviderVultr               CloudProvider = "vultr"
 	CloudProviderVMwareCloudDirector CloudProvider = "vmware-cloud-director"
 	CloudProviderFake                CloudProvider = "fake"
+	CloudProviderSimulator           CloudProvider = "simulator"
 	CloudProviderEdge                CloudProvider = "edge"
 	CloudProviderAlibaba             CloudProvider = "alibaba"
 	CloudProviderAnexia              CloudProvider = "anexia"
@@ -106,6 +108,7 @@ var (
 		CloudProviderVsphere,
 		CloudProviderVMwareCloudDirector,
 		CloudProviderFake,
+		CloudProviderSimulator,
 		CloudProviderEdge,
 		CloudProviderAlibaba,
 		CloudProviderAnexia,
@@ -180,10 +183,48 @@ type GlobalObjectKeySelector struct {
 type GlobalSecretKeySelector GlobalObjectKeySelector
 type GlobalConfigMapKeySelector GlobalObjectKeySelector
 
+// FileKeySelector selects a file on the machine-controller's host, e.g. a secret mounted by
+// a CSI driver. Only files in the directories allowed by the machine-controller can be read.
+type FileKeySelector struct {
+	// Path is the absolute path of the file.
+	Path string `json:"path"`
+	// Key selects a field of the JSON object stored in the file. If empty, the whole
+	// content of the file is used.
+	Key string `json:"key,omitempty"`
+}
+
+// VaultKeySelector selects a key of a secret in a HashiCorp Vault KV version 2 secrets engine.
+type VaultKeySelector struct {
+	// Mount is the path the secrets engine is mounted at. Defaults to "secret".
+	Mount string `json:"mount,omitempty"`
+	// Path is the path of the secret within the secrets engine.
+	Path string `json:"path"`
+	// Key selects a key of the secret.
+	Key string `json:"key"`
+}
+
+// ExecSelector selects the output of an exec plugin. Only plugins in the plugin directory
+// of the machine-controller can be run.
+type ExecSelector struct {
+	// Plugin is the file name of the plugin.
+	Plugin string `json:"plugin"`
+	// Args are passed to the plugin.
+	Args []string `json:"args,omitempty"`
+}
+
 type ConfigVarString struct {
 	Value           string                     `json:"value,omitempty"`
 	SecretKeyRef    GlobalSecretKeySelector    `json:"secretKeyRef,omitempty"`
 	ConfigMapKeyRef GlobalConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// FileKeyRef reads the value from a file. It requires the machine-controller
+	// to allow the directory of the file.
+	FileKeyRef *FileKeySelector `json:"fileKeyRef,omitempty"`
+	// VaultKeyRef reads the value from HashiCorp Vault. It requires the machine-controller
+	// to be configured with a Vault address.
+	VaultKeyRef *VaultKeySelector `json:"vaultKeyRef,omitempty"`
+	// ExecRef reads the value from the output of an exec plugin. It requires the
+	// machine-controller to be configured with a plugin directory.
+	ExecRef *ExecSelector `json:"execRef,omitempty"`
 }
 
 // This type only exists to have the same fields as ConfigVarString but
@@ -209,37 +250,39 @@ func (configVarString ConfigVarString) MarshalJSON() ([]byte, error) {
 		configMapKeyRefEmpty = true
 	}
 
-	if secretKeyRefEmpty && configMapKeyRefEmpty {
-		return []byte(fmt.Sprintf(`"%s"`, configVarString.Value)), nil
+	refs := []struct {
+		name  string
+		empty bool
+		ref   interface{}
+	}{
+		{name: "secretKeyRef", empty: secretKeyRefEmpty, ref: configVarString.SecretKeyRef},
+		{name: "configMapKeyRef", empty: configMapKeyRefEmpty, ref: configVarString.ConfigMapKeyRef},
+		{name: "fileKeyRef", empty: configVarString.FileKeyRef == nil, ref: configVarString.FileKeyRef},
+		{name: "vaultKeyRef", empty: configVarString.VaultKeyRef == nil, ref: configVarString.VaultKeyRef},
+		{name: "execRef", empty: configVarString.ExecRef == nil, ref: configVarString.ExecRef},
 	}
 
-	buffer := bytes.NewBufferString("{")
-	if !secretKeyRefEmpty {
-		jsonVal, err := json.Marshal(configVarString.SecretKeyRef)
+	var fields []string
+	for _, ref := range refs {
+		if ref.empty {
+			continue
+		}
+		jsonVal, err := json.Marshal(ref.ref)
 		if err != nil {
 			return nil, err
 		}
-		fmt.Fprintf(buffer, `"secretKeyRef":%s`, string(jsonVal))
+		fields = append(fields, fmt.Sprintf(`"%s":%s`, ref.name, jsonVal))
 	}
 
-	if !configMapKeyRefEmpty {
-		var leadingComma string
-		if !secretKeyRefEmpty {
-			leadingComma = ","
-		}
-		jsonVal, err := json.Marshal(configVarString.ConfigMapKeyRef)
-		if err != nil {
-			return nil, err
-		}
-		fmt.Fprintf(buffer, `%s"configMapKeyRef":%s`, leadingComma, jsonVal)
+	if len(fields) == 0 {
+		return []byte(fmt.Sprintf(`"%s"`, configVarString.Value)), nil
 	}
 
 	if configVarString.Value != "" {
-		fmt.Fprintf(buffer, `,"value":"%s"`, configVarString.Value)
+		fields = append(fields, fmt.Sprintf(`"value":"%s"`, configVarString.Value))
 	}
 
-	buffer.WriteString("}")
-	return buffer.Bytes(), nil
+	return []byte("{" + strings.Join(fields, ",") + "}"), nil
 }
 
 func (configVarString *ConfigVarString) UnmarshalJSON(b []byte) error {
@@ -268,6 +311,9 @@ func (configVarString *ConfigVarString) UnmarshalJSON(b []byte) error {
 	configVarString.Value = cvsDummy.Value
 	configVarString.SecretKeyRef = cvsDummy.SecretKeyRef
 	configVarString.ConfigMapKeyRef = cvsDummy.ConfigMapKeyRef
+	configVarString.FileKeyRef = cvsDummy.FileKeyRef
+	configVarString.VaultKeyRef = cvsDummy.VaultKeyRef
+	configVarString.ExecRef = cvsDummy.ExecRef
 	return nil
 }
 
@@ -106,6 +106,18 @@ func TestConfigVarStringMarshalling(t *testing.T) {
 			cvs:      ConfigVarString{SecretKeyRef: GlobalSecretKeySelector{ObjectReference: corev1.ObjectReference{Namespace: "ns", Name: "name"}, Key: "key"}},
 			expected: `{"secretKeyRef":{"namespace":"ns","name":"name","key":"key"}}`,
 		},
+		{
+			cvs:      ConfigVarString{FileKeyRef: &FileKeySelector{Path: "/secrets/credentials.json", Key: "password"}},
+			expected: `{"fileKeyRef":{"path":"/secrets/credentials.json","key":"password"}}`,
+		},
+		{
+			cvs:      ConfigVarString{Value: "val", VaultKeyRef: &VaultKeySelector{Path: "cloud/aws", Key: "accessKeyID"}},
+			expected: `{"vaultKeyRef":{"path":"cloud/aws","key":"accessKeyID"},"value":"val"}`,
+		},
+		{
+			cvs:      ConfigVarString{ExecRef: &ExecSelector{Plugin: "get-secret", Args: []string{"aws"}}},
+			expected: `{"execRef":{"plugin":"get-secret","args":["aws"]}}`,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -178,6 +190,12 @@ func TestConfigVarStringMarshallingAndUnmarshalling(t *testing.T) {
 			ConfigMapKeyRef: GlobalConfigMapKeySelector{ObjectReference: corev1.ObjectReference{Namespace: "ns", Name: "name"}, Key: "key"},
 			SecretKeyRef:    GlobalSecretKeySelector{ObjectReference: corev1.ObjectReference{Namespace: "ns", Name: "name"}, Key: "key"},
 		},
+		{FileKeyRef: &FileKeySelector{Path: "/secrets/token"}},
+		{Value: "val", VaultKeyRef: &VaultKeySelector{Mount: "kv", Path: "cloud/aws", Key: "accessKeyID"}},
+		{
+			SecretKeyRef: GlobalSecretKeySelector{ObjectReference: corev1.ObjectReference{Namespace: "ns", Name: "name"}, Key: "key"},
+			ExecRef:      &ExecSelector{Plugin: "get-secret", Args: []string{"aws", "--region=eu-west-1"}},
+		},
 	}
 
 	for _, cvs := range testCases {